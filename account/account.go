@@ -32,6 +32,7 @@ type Account struct {
 	PublicKey  keypair.PublicKey
 	Address    common.Address
 	SigScheme  s.SignatureScheme
+	external   ExternalSigner //signer of account whose private key is outside of node, PrivateKey is nil
 }
 
 func NewAccount(encrypt string) *Account {
@@ -106,4 +107,5 @@ type AccountMetadata struct {
 	Key       []byte //PrivateKey in encrypted
 	EncAlg    string //Encrypt alg of private key
 	Hash      string //Hash alg
+	HDPath    string //Derivation path of HD account
 }
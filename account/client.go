@@ -36,6 +36,8 @@ import (
 type Client interface {
 	//NewAccount create a new account.
 	NewAccount(label string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error)
+	//NewHDAccount create account derived from seed of mnemonic by path
+	NewHDAccount(label string, seed []byte, path string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error)
 	//ImportAccount import a already exist account to wallet
 	ImportAccount(accMeta *AccountMetadata) error
 	//GetAccountByAddress return account object by address
@@ -146,6 +148,25 @@ func (this *ClientImpl) NewAccount(label string, typeCode keypair.KeyType, curve
 	if err != nil {
 		return nil, fmt.Errorf("generateKeyPair error:%s", err)
 	}
+	return this.newAccount(label, prvkey, pubkey, sigScheme, "", passwd)
+}
+
+func (this *ClientImpl) NewHDAccount(label string, seed []byte, path string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	prvkey, pubkey, err := DeriveKeyPair(seed, path, typeCode, curveCode)
+	if err != nil {
+		return nil, fmt.Errorf("deriveKeyPair error:%s", err)
+	}
+	address := types.AddressFromPubKey(pubkey)
+	if this.GetAccountMetadataByAddress(address.ToBase58()) != nil {
+		return nil, fmt.Errorf("account:%s of path:%s already exist", address.ToBase58(), path)
+	}
+	return this.newAccount(label, prvkey, pubkey, sigScheme, path, passwd)
+}
+
+func (this *ClientImpl) newAccount(label string, prvkey keypair.PrivateKey, pubkey keypair.PublicKey, sigScheme s.SignatureScheme, hdPath string, passwd []byte) (*Account, error) {
 	address := types.AddressFromPubKey(pubkey)
 	addressBase58 := address.ToBase58()
 	prvSecret, err := keypair.EncryptPrivateKey(prvkey, addressBase58, passwd)
@@ -157,6 +178,7 @@ func (this *ClientImpl) NewAccount(label string, typeCode keypair.KeyType, curve
 	accData.SetKeyPair(prvSecret)
 	accData.SigSch = sigScheme.Name()
 	accData.PubKey = hex.EncodeToString(keypair.SerializePublicKey(pubkey))
+	accData.HDPath = hdPath
 
 	err = this.addAccountData(accData)
 	if err != nil {
@@ -214,6 +236,7 @@ func (this *ClientImpl) ImportAccount(accMeta *AccountMetadata) error {
 	accData.Hash = accMeta.Hash
 	accData.Salt = accMeta.Salt
 	accData.Param = map[string]string{"curve": accMeta.Curve}
+	accData.HDPath = accMeta.HDPath
 
 	oldAccMeta := this.GetAccountMetadataByLabel(accData.Label)
 	if oldAccMeta != nil {
@@ -341,6 +364,7 @@ func (this *ClientImpl) getAccountMetadata(accData *AccountData) *AccountMetadat
 	accMeta.Hash = accData.Hash
 	accMeta.Curve = accData.Param["curve"]
 	accMeta.Salt = accData.Salt
+	accMeta.HDPath = accData.HDPath
 	return accMeta
 }
 
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	s "github.com/OnyxPay/OnyxChain-crypto/signature"
+	"github.com/OnyxPay/OnyxChain-crypto/vrf"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+)
+
+//ExternalSigner signs data with private key kept outside of the node, like HSM or remote signer
+type ExternalSigner interface {
+	PubKey() keypair.PublicKey
+	Scheme() s.SignatureScheme
+	Sign(data []byte) (*s.Signature, error)
+}
+
+//VrfSigner is implemented by external signer which can compute VRF, required by vbft consensus
+type VrfSigner interface {
+	Vrf(data []byte) (value []byte, proof []byte, err error)
+}
+
+//NewExternalAccount returns account which signs data by external signer
+func NewExternalAccount(signer ExternalSigner) *Account {
+	pub := signer.PubKey()
+	return &Account{
+		PublicKey: pub,
+		Address:   types.AddressFromPubKey(pub),
+		SigScheme: signer.Scheme(),
+		external:  signer,
+	}
+}
+
+//IsExternal returns whether the private key of account is kept by external signer
+func (this *Account) IsExternal() bool {
+	return this.external != nil
+}
+
+//SignData signs data by private key of account, or by external signer
+func (this *Account) SignData(data []byte) (*s.Signature, error) {
+	if this.external != nil {
+		return this.external.Sign(data)
+	}
+	return s.Sign(this.SigScheme, this.PrivateKey, data, nil)
+}
+
+//SupportVrf returns whether account can compute VRF
+func (this *Account) SupportVrf() bool {
+	if this.external != nil {
+		_, ok := this.external.(VrfSigner)
+		return ok && vrf.ValidatePublicKey(this.PublicKey)
+	}
+	return vrf.ValidatePrivateKey(this.PrivateKey) && vrf.ValidatePublicKey(this.PublicKey)
+}
+
+//Vrf computes VRF value and proof of data
+func (this *Account) Vrf(data []byte) ([]byte, []byte, error) {
+	if this.external != nil {
+		vs, ok := this.external.(VrfSigner)
+		if !ok {
+			return nil, nil, fmt.Errorf("external signer doesn't support vrf")
+		}
+		return vs.Vrf(data)
+	}
+	return vrf.Vrf(this.PrivateKey, data)
+}
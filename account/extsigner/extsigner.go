@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package extsigner provides external signer backends of account.Account, whose private keys never enter the node.
+//
+//Signer is addressed by uri:
+//  unix:///path/to/signer.sock?key=<key id>  remote signer over unix socket
+//
+//Remote signer speaks a line based json protocol, see Request and Response. HSM with PKCS#11 interface
+//can be plugged by a bridge daemon implementing the protocol. SoftHSM is an in memory implementation for tests.
+package extsigner
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+)
+
+const (
+	URI_SCHEME_UNIX   = "unix"
+	URI_SCHEME_PKCS11 = "pkcs11"
+)
+
+const DEFAULT_REQUEST_TIMEOUT = 10 * time.Second
+
+//Open returns external signer by uri
+func Open(uri string) (account.ExternalSigner, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer uri:%s error:%s", uri, err)
+	}
+	switch u.Scheme {
+	case URI_SCHEME_UNIX:
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid signer uri:%s, missing socket path", uri)
+		}
+		keyId := u.Query().Get("key")
+		if keyId == "" {
+			return nil, fmt.Errorf("invalid signer uri:%s, missing key", uri)
+		}
+		return NewRemoteSigner(u.Path, keyId, DEFAULT_REQUEST_TIMEOUT)
+	case URI_SCHEME_PKCS11:
+		return nil, fmt.Errorf("pkcs11 module cannot be loaded directly, please use a remote signer bridge with unix socket")
+	default:
+		return nil, fmt.Errorf("unsupported signer uri scheme:%s", u.Scheme)
+	}
+}
+
+//OpenAccount returns account signed by external signer of uri
+func OpenAccount(uri string) (*account.Account, error) {
+	signer, err := Open(uri)
+	if err != nil {
+		return nil, err
+	}
+	return account.NewExternalAccount(signer), nil
+}
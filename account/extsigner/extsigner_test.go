@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package extsigner
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-crypto/vrf"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftSigner(t *testing.T) {
+	hsm := NewSoftHSM()
+	pk, err := hsm.GenerateKey("k1", "SHA256withECDSA")
+	assert.Nil(t, err)
+	_, err = hsm.GenerateKey("k1", "")
+	assert.NotNil(t, err)
+
+	signer, err := hsm.Signer("k1")
+	assert.Nil(t, err)
+	acc := account.NewExternalAccount(signer)
+	assert.True(t, acc.IsExternal())
+	assert.Nil(t, acc.PrivateKey)
+	assert.True(t, keypair.ComparePublicKey(pk, acc.PublicKey))
+
+	data := []byte("test data")
+	sigData, err := signature.Sign(acc, data)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(pk, data, sigData))
+
+	_, err = hsm.Signer("k2")
+	assert.NotNil(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extsigner")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", path)
+	assert.Nil(t, err)
+
+	hsm := NewSoftHSM()
+	defer hsm.Close()
+	local := account.NewAccount("")
+	assert.Nil(t, hsm.ImportKey("validator", local))
+	go hsm.Serve(l)
+
+	_, err = Open("unix://" + path + "?key=unknown")
+	assert.NotNil(t, err)
+	_, err = Open("pkcs11:token=test")
+	assert.NotNil(t, err)
+
+	acc, err := OpenAccount("unix://" + path + "?key=validator")
+	assert.Nil(t, err)
+	assert.Equal(t, local.Address, acc.Address)
+	assert.Equal(t, local.SigScheme, acc.SigScheme)
+
+	data := []byte("test data")
+	sigData, err := signature.Sign(acc, data)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(local.PublicKey, data, sigData))
+
+	assert.True(t, acc.SupportVrf())
+	value, proof, err := acc.Vrf(data)
+	assert.Nil(t, err)
+	ok, err := vrf.Verify(local.PublicKey, data, value, proof)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package extsigner
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	s "github.com/OnyxPay/OnyxChain-crypto/signature"
+)
+
+const (
+	METHOD_PUBKEY = "pubkey"
+	METHOD_SIGN   = "sign"
+	METHOD_VRF    = "vrf"
+)
+
+//Request of remote signer protocol, one json object per line
+type Request struct {
+	Id     uint64 `json:"id"`
+	Method string `json:"method"`
+	Key    string `json:"key"`
+	Data   string `json:"data,omitempty"` //hex encoded data to sign
+}
+
+//Response of remote signer protocol, one json object per line
+type Response struct {
+	Id     uint64 `json:"id"`
+	PubKey string `json:"pub_key,omitempty"` //hex encoded serialized public key
+	Scheme string `json:"scheme,omitempty"`  //name of signature scheme
+	Sig    string `json:"sig,omitempty"`     //hex encoded serialized signature
+	Value  string `json:"value,omitempty"`   //hex encoded vrf value
+	Proof  string `json:"proof,omitempty"`   //hex encoded vrf proof
+	Error  string `json:"error,omitempty"`
+}
+
+//RemoteSigner signs data by remote signer listening on unix socket
+type RemoteSigner struct {
+	path    string
+	keyId   string
+	timeout time.Duration
+	pubKey  keypair.PublicKey
+	scheme  s.SignatureScheme
+	lock    sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	reqId   uint64
+}
+
+//NewRemoteSigner connects to remote signer and loads public key of keyId
+func NewRemoteSigner(path, keyId string, timeout time.Duration) (*RemoteSigner, error) {
+	signer := &RemoteSigner{
+		path:    path,
+		keyId:   keyId,
+		timeout: timeout,
+	}
+	rsp, err := signer.call(METHOD_PUBKEY, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(rsp.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key:%s", rsp.PubKey)
+	}
+	signer.pubKey, err = keypair.DeserializePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializePublicKey error:%s", err)
+	}
+	signer.scheme, err = s.GetScheme(rsp.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature scheme:%s", rsp.Scheme)
+	}
+	return signer, nil
+}
+
+func (this *RemoteSigner) PubKey() keypair.PublicKey {
+	return this.pubKey
+}
+
+func (this *RemoteSigner) Scheme() s.SignatureScheme {
+	return this.scheme
+}
+
+func (this *RemoteSigner) Sign(data []byte) (*s.Signature, error) {
+	rsp, err := this.call(METHOD_SIGN, data)
+	if err != nil {
+		return nil, err
+	}
+	sigData, err := hex.DecodeString(rsp.Sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature:%s", rsp.Sig)
+	}
+	sig, err := s.Deserialize(sigData)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize signature error:%s", err)
+	}
+	if !s.Verify(this.pubKey, data, sig) {
+		return nil, fmt.Errorf("remote signer returned invalid signature")
+	}
+	return sig, nil
+}
+
+func (this *RemoteSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	rsp, err := this.call(METHOD_VRF, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := hex.DecodeString(rsp.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf value:%s", rsp.Value)
+	}
+	proof, err := hex.DecodeString(rsp.Proof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf proof:%s", rsp.Proof)
+	}
+	return value, proof, nil
+}
+
+//Close closes connection to remote signer
+func (this *RemoteSigner) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.closeConn()
+}
+
+func (this *RemoteSigner) closeConn() error {
+	if this.conn == nil {
+		return nil
+	}
+	err := this.conn.Close()
+	this.conn = nil
+	this.reader = nil
+	return err
+}
+
+func (this *RemoteSigner) call(method string, data []byte) (*Response, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	rsp, err := this.roundTrip(method, data)
+	if err != nil {
+		//connection is not reusable after any io error
+		this.closeConn()
+		return nil, fmt.Errorf("remote signer %s error:%s", this.path, err)
+	}
+	if rsp.Error != "" {
+		return nil, fmt.Errorf("remote signer %s error:%s", this.path, rsp.Error)
+	}
+	return rsp, nil
+}
+
+func (this *RemoteSigner) roundTrip(method string, data []byte) (*Response, error) {
+	if this.conn == nil {
+		conn, err := net.DialTimeout("unix", this.path, this.timeout)
+		if err != nil {
+			return nil, err
+		}
+		this.conn = conn
+		this.reader = bufio.NewReader(conn)
+	}
+	this.reqId++
+	req := &Request{
+		Id:     this.reqId,
+		Method: method,
+		Key:    this.keyId,
+	}
+	if data != nil {
+		req.Data = hex.EncodeToString(data)
+	}
+	reqData, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	err = this.conn.SetDeadline(time.Now().Add(this.timeout))
+	if err != nil {
+		return nil, err
+	}
+	_, err = this.conn.Write(append(reqData, '\n'))
+	if err != nil {
+		return nil, err
+	}
+	line, err := this.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	rsp := &Response{}
+	err = json.Unmarshal(line, rsp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response:%s", err)
+	}
+	if rsp.Id != req.Id {
+		return nil, fmt.Errorf("response id:%d mismatch request id:%d", rsp.Id, req.Id)
+	}
+	return rsp, nil
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package extsigner
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	s "github.com/OnyxPay/OnyxChain-crypto/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+)
+
+//SoftHSM keeps keys in memory and acts as external signer. It's a stand-in of hardware signer for test,
+//and can serve the remote signer protocol on unix socket.
+type SoftHSM struct {
+	lock      sync.RWMutex
+	keys      map[string]*account.Account
+	listeners []net.Listener
+}
+
+func NewSoftHSM() *SoftHSM {
+	return &SoftHSM{
+		keys: make(map[string]*account.Account),
+	}
+}
+
+//GenerateKey generates key of keyId by signature scheme, like SHA256withECDSA
+func (this *SoftHSM) GenerateKey(keyId, scheme string) (keypair.PublicKey, error) {
+	if scheme != "" {
+		if _, err := s.GetScheme(scheme); err != nil {
+			return nil, fmt.Errorf("unsupported signature scheme:%s", scheme)
+		}
+	}
+	acc := account.NewAccount(scheme)
+	err := this.ImportKey(keyId, acc)
+	if err != nil {
+		return nil, err
+	}
+	return acc.PublicKey, nil
+}
+
+//ImportKey imports private key of account as keyId
+func (this *SoftHSM) ImportKey(keyId string, acc *account.Account) error {
+	if acc.IsExternal() {
+		return fmt.Errorf("cannot import external account")
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if _, ok := this.keys[keyId]; ok {
+		return fmt.Errorf("key:%s already exist", keyId)
+	}
+	this.keys[keyId] = acc
+	return nil
+}
+
+func (this *SoftHSM) getKey(keyId string) (*account.Account, error) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	acc, ok := this.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("cannot find key:%s", keyId)
+	}
+	return acc, nil
+}
+
+//Signer returns in process external signer of keyId
+func (this *SoftHSM) Signer(keyId string) (*SoftSigner, error) {
+	acc, err := this.getKey(keyId)
+	if err != nil {
+		return nil, err
+	}
+	return &SoftSigner{acc: acc}, nil
+}
+
+//Serve serves remote signer protocol on listener, blocks until listener closed
+func (this *SoftHSM) Serve(l net.Listener) error {
+	this.lock.Lock()
+	this.listeners = append(this.listeners, l)
+	this.lock.Unlock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go this.serveConn(conn)
+	}
+}
+
+//Close closes all listeners
+func (this *SoftHSM) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, l := range this.listeners {
+		l.Close()
+	}
+	this.listeners = nil
+}
+
+func (this *SoftHSM) serveConn(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		req := &Request{}
+		rsp := &Response{}
+		err = json.Unmarshal(line, req)
+		if err != nil {
+			rsp.Error = fmt.Sprintf("invalid request:%s", err)
+		} else {
+			rsp = this.handle(req)
+		}
+		data, err := json.Marshal(rsp)
+		if err != nil {
+			log.Errorf("SoftHSM marshal response error:%s", err)
+			return
+		}
+		_, err = conn.Write(append(data, '\n'))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (this *SoftHSM) handle(req *Request) *Response {
+	rsp := &Response{Id: req.Id}
+	acc, err := this.getKey(req.Key)
+	if err != nil {
+		rsp.Error = err.Error()
+		return rsp
+	}
+	data, err := hex.DecodeString(req.Data)
+	if err != nil {
+		rsp.Error = "invalid data"
+		return rsp
+	}
+	switch req.Method {
+	case METHOD_PUBKEY:
+		rsp.PubKey = hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+		rsp.Scheme = acc.SigScheme.Name()
+	case METHOD_SIGN:
+		sig, err := acc.SignData(data)
+		if err != nil {
+			rsp.Error = err.Error()
+			break
+		}
+		sigData, err := s.Serialize(sig)
+		if err != nil {
+			rsp.Error = err.Error()
+			break
+		}
+		rsp.Sig = hex.EncodeToString(sigData)
+	case METHOD_VRF:
+		value, proof, err := acc.Vrf(data)
+		if err != nil {
+			rsp.Error = err.Error()
+			break
+		}
+		rsp.Value = hex.EncodeToString(value)
+		rsp.Proof = hex.EncodeToString(proof)
+	default:
+		rsp.Error = fmt.Sprintf("unsupported method:%s", req.Method)
+	}
+	return rsp
+}
+
+//SoftSigner is in process external signer of SoftHSM key
+type SoftSigner struct {
+	acc *account.Account
+}
+
+func (this *SoftSigner) PubKey() keypair.PublicKey {
+	return this.acc.PublicKey
+}
+
+func (this *SoftSigner) Scheme() s.SignatureScheme {
+	return this.acc.SigScheme
+}
+
+func (this *SoftSigner) Sign(data []byte) (*s.Signature, error) {
+	return this.acc.SignData(data)
+}
+
+func (this *SoftSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	return this.acc.Vrf(data)
+}
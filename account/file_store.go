@@ -38,6 +38,7 @@ type AccountData struct {
 	SigSch    string `json:"signatureScheme"`
 	IsDefault bool   `json:"isDefault"`
 	Lock      bool   `json:"lock"`
+	HDPath    string `json:"hdPath,omitempty"` //derivation path of account derived from mnemonic
 }
 
 func (this *AccountData) SetKeyPair(keyinfo *keypair.ProtectedKey) {
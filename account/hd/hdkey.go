@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package hd implements hierarchical deterministic key derivation of BIP32, generalized to other curves by SLIP-0010,
+//BIP44 derivation path and BIP39 mnemonic.
+package hd
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+const (
+	//HARDENED_KEY_START is the index of first hardened child key
+	HARDENED_KEY_START = uint32(0x80000000)
+
+	//MIN_SEED_LEN and MAX_SEED_LEN are length limits of seed in bytes
+	MIN_SEED_LEN = 16
+	MAX_SEED_LEN = 64
+)
+
+//ExtendedKey is a private key with chain code which can derive child keys
+type ExtendedKey struct {
+	curve     elliptic.Curve
+	key       []byte
+	chainCode []byte
+	depth     uint8
+	index     uint32
+}
+
+//NewMasterKey derives master key from seed, curveSeed is the hmac key of curve, e.g. "Bitcoin seed"
+func NewMasterKey(seed []byte, curve elliptic.Curve, curveSeed string) (*ExtendedKey, error) {
+	if len(seed) < MIN_SEED_LEN || len(seed) > MAX_SEED_LEN {
+		return nil, fmt.Errorf("invalid seed length:%d", len(seed))
+	}
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, []byte(curveSeed))
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		if isValidKey(curve, sum[:32]) {
+			return &ExtendedKey{
+				curve:     curve,
+				key:       sum[:32],
+				chainCode: sum[32:],
+			}, nil
+		}
+		data = sum
+	}
+}
+
+//Child derives child key of index, index >= HARDENED_KEY_START derives hardened key
+func (this *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if this.depth == 0xff {
+		return nil, fmt.Errorf("cannot derive key deeper than 255")
+	}
+	var data []byte
+	if index >= HARDENED_KEY_START {
+		data = make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, this.key...)
+	} else {
+		data = make([]byte, 0, 37)
+		data = append(data, this.PublicKeyBytes()...)
+	}
+	data = append(data, uint32Bytes(index)...)
+
+	n := this.curve.Params().N
+	for {
+		mac := hmac.New(sha512.New, this.chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) < 0 {
+			k := new(big.Int).Add(il, new(big.Int).SetBytes(this.key))
+			k.Mod(k, n)
+			if k.Sign() != 0 {
+				return &ExtendedKey{
+					curve:     this.curve,
+					key:       paddedBytes(k, 32),
+					chainCode: sum[32:],
+					depth:     this.depth + 1,
+					index:     index,
+				}, nil
+			}
+		}
+		//invalid key, retry with 0x01 || IR || index as SLIP-0010 specified
+		data = make([]byte, 0, 37)
+		data = append(data, 1)
+		data = append(data, sum[32:]...)
+		data = append(data, uint32Bytes(index)...)
+	}
+}
+
+//Derive derives descendant key by path
+func (this *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	key := this
+	var err error
+	for _, index := range path {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+//Key returns 32 bytes private key
+func (this *ExtendedKey) Key() []byte {
+	return this.key
+}
+
+func (this *ExtendedKey) ChainCode() []byte {
+	return this.chainCode
+}
+
+func (this *ExtendedKey) Depth() uint8 {
+	return this.depth
+}
+
+func (this *ExtendedKey) Index() uint32 {
+	return this.index
+}
+
+//PublicKeyBytes returns compressed public key
+func (this *ExtendedKey) PublicKeyBytes() []byte {
+	x, y := this.curve.ScalarBaseMult(this.key)
+	buf := make([]byte, 0, 33)
+	if y.Bit(0) == 0 {
+		buf = append(buf, 0x02)
+	} else {
+		buf = append(buf, 0x03)
+	}
+	return append(buf, paddedBytes(x, 32)...)
+}
+
+func isValidKey(curve elliptic.Curve, key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() != 0 && k.Cmp(curve.Params().N) < 0
+}
+
+func uint32Bytes(i uint32) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, i)
+	return buf
+}
+
+func paddedBytes(i *big.Int, size int) []byte {
+	buf := make([]byte, size)
+	data := i.Bytes()
+	copy(buf[size-len(data):], data)
+	return buf
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hd
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//test vector 1 of SLIP-0010 for nist256p1
+func TestDeriveP256(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed, elliptic.P256(), "Nist256p1 seed")
+	assert.Nil(t, err)
+	assert.Equal(t, "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", hex.EncodeToString(master.ChainCode()))
+	assert.Equal(t, "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2", hex.EncodeToString(master.Key()))
+	assert.Equal(t, "0266874dc6ade47b3ecd096745ca09bcd29638dd52c2c12117b11ed3e458cfa9e8", hex.EncodeToString(master.PublicKeyBytes()))
+
+	key, err := master.Child(HARDENED_KEY_START)
+	assert.Nil(t, err)
+	assert.Equal(t, "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", hex.EncodeToString(key.ChainCode()))
+	assert.Equal(t, "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c", hex.EncodeToString(key.Key()))
+
+	path, err := ParsePath("m/0'/1")
+	assert.Nil(t, err)
+	key, err = master.Derive(path)
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(2), key.Depth())
+	assert.Equal(t, uint32(1), key.Index())
+	assert.Equal(t, "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c", hex.EncodeToString(key.ChainCode()))
+}
+
+func TestParsePath(t *testing.T) {
+	path, err := ParsePath(DefaultPath(3))
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{44 + HARDENED_KEY_START, 1024 + HARDENED_KEY_START, HARDENED_KEY_START, 0, 3}, path)
+	assert.Equal(t, "m/44'/1024'/0'/0/3", FormatPath(path))
+
+	path, err = ParsePath("m/1h/2H")
+	assert.Nil(t, err)
+	assert.Equal(t, "m/1'/2'", FormatPath(path))
+
+	for _, p := range []string{"", "44'/0", "m/a", "m/2147483648", "m//1"} {
+		_, err = ParsePath(p)
+		assert.NotNil(t, err, p)
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(DEFAULT_ENTROPY_BITS)
+	assert.Nil(t, err)
+	assert.True(t, IsMnemonicValid(mnemonic))
+
+	//test vector of BIP39
+	mnemonic = "abandon abandon abandon abandon abandon abandon  abandon abandon abandon abandon abandon about"
+	assert.True(t, IsMnemonicValid(mnemonic))
+	seed, err := NewSeed(mnemonic, "TREZOR")
+	assert.Nil(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	_, err = NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "")
+	assert.NotNil(t, err)
+}
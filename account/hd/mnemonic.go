@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+//DEFAULT_ENTROPY_BITS generates 24 words mnemonic
+const DEFAULT_ENTROPY_BITS = 256
+
+//NewMnemonic generates mnemonic of BIP39 english word list, bits must be multiple of 32 in [128, 256]
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", fmt.Errorf("new entropy error:%s", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+//NormalizeMnemonic trims spaces between words of mnemonic
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(mnemonic), " ")
+}
+
+//IsMnemonicValid returns whether mnemonic has valid words and checksum
+func IsMnemonicValid(mnemonic string) bool {
+	return bip39.IsMnemonicValid(NormalizeMnemonic(mnemonic))
+}
+
+//NewSeed returns 64 bytes seed of mnemonic and passphrase
+func NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(NormalizeMnemonic(mnemonic), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic:%s", err)
+	}
+	return seed, nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	PURPOSE_BIP44 = 44
+	//COIN_TYPE is the coin type of onyxchain in BIP44 path
+	COIN_TYPE = 1024
+)
+
+//DefaultPath returns BIP44 path m/44'/1024'/0'/0/index
+func DefaultPath(index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/0'/0/%d", PURPOSE_BIP44, COIN_TYPE, index)
+}
+
+//ParsePath parses derivation path like m/44'/1024'/0'/0/0, hardened index is marked by ' or h
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid path:%s, must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := false
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			hardened = true
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HARDENED_KEY_START {
+			return nil, fmt.Errorf("invalid path:%s, invalid index:%s", path, part)
+		}
+		if hardened {
+			index += uint64(HARDENED_KEY_START)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+//FormatPath formats indexes to derivation path, hardened index is marked by '
+func FormatPath(indexes []uint32) string {
+	path := "m"
+	for _, index := range indexes {
+		if index >= HARDENED_KEY_START {
+			path += fmt.Sprintf("/%d'", index-HARDENED_KEY_START)
+		} else {
+			path += fmt.Sprintf("/%d", index)
+		}
+	}
+	return path
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-crypto/ec"
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/account/hd"
+)
+
+//hmac keys of master key generation. SM2 is not covered by SLIP-0010, so its key is onyxchain specific
+var hdCurveSeeds = map[byte]string{
+	keypair.P256:      "Nist256p1 seed",
+	keypair.SECP256K1: "Bitcoin seed",
+	keypair.SM2P256V1: "SM2P256V1 seed",
+}
+
+//DeriveKeyPair derives key pair of path from seed of mnemonic. Support ECDSA with P-256 or secp256k1 curve, and SM2
+func DeriveKeyPair(seed []byte, path string, keyType keypair.KeyType, curveCode byte) (keypair.PrivateKey, keypair.PublicKey, error) {
+	var alg ec.ECAlgorithm
+	switch keyType {
+	case keypair.PK_ECDSA:
+		if curveCode != keypair.P256 && curveCode != keypair.SECP256K1 {
+			return nil, nil, fmt.Errorf("HD account of ECDSA only support P-256 and secp256k1 curve")
+		}
+		alg = ec.ECDSA
+	case keypair.PK_SM2:
+		if curveCode != keypair.SM2P256V1 {
+			return nil, nil, fmt.Errorf("HD account of SM2 only support SM2P256V1 curve")
+		}
+		alg = ec.SM2
+	default:
+		return nil, nil, fmt.Errorf("HD account doesn't support key type:%d", keyType)
+	}
+	indexes, err := hd.ParsePath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	curve, err := keypair.GetCurve(curveCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	master, err := hd.NewMasterKey(seed, curve, hdCurveSeeds[curveCode])
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := master.Derive(indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	pri := &ec.PrivateKey{
+		Algorithm:  alg,
+		PrivateKey: ec.ConstructPrivateKey(key.Key(), curve),
+	}
+	return pri, pri.Public(), nil
+}
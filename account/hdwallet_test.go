@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"os"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	s "github.com/OnyxPay/OnyxChain-crypto/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/account/hd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientNewHDAccount(t *testing.T) {
+	mnemonic, err := hd.NewMnemonic(hd.DEFAULT_ENTROPY_BITS)
+	assert.Nil(t, err)
+	seed, err := hd.NewSeed(mnemonic, "")
+	assert.Nil(t, err)
+
+	path := hd.DefaultPath(0)
+	acc, err := testWallet.NewHDAccount("hd0", seed, path, keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	accMeta := testWallet.GetAccountMetadataByAddress(acc.Address.ToBase58())
+	assert.Equal(t, path, accMeta.HDPath)
+
+	_, err = testWallet.NewHDAccount("hd0_dup", seed, path, keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.NotNil(t, err)
+	_, err = testWallet.NewHDAccount("hd_eddsa", seed, path, keypair.PK_EDDSA, keypair.ED25519, s.SHA512withEDDSA, testPasswd)
+	assert.NotNil(t, err)
+
+	//restore from mnemonic in another wallet
+	walletPath := "./wallet_hd_test.dat"
+	defer os.Remove(walletPath)
+	wallet, err := Open(walletPath)
+	assert.Nil(t, err)
+	seed, err = hd.NewSeed(mnemonic, "")
+	assert.Nil(t, err)
+	restored, err := wallet.NewHDAccount("", seed, path, keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, acc.Address, restored.Address)
+
+	next, err := wallet.NewHDAccount("", seed, hd.DefaultPath(1), keypair.PK_ECDSA, keypair.P256, s.SHA256withECDSA, testPasswd)
+	assert.Nil(t, err)
+	assert.NotEqual(t, acc.Address, next.Address)
+
+	//reload wallet file
+	wallet, err = Open(walletPath)
+	assert.Nil(t, err)
+	accTmp, err := wallet.GetAccountByIndex(2, testPasswd)
+	assert.Nil(t, err)
+	assert.Equal(t, next.Address, accTmp.Address)
+	assert.Equal(t, hd.DefaultPath(1), wallet.GetAccountMetadataByIndex(2).HDPath)
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
 	"io/ioutil"
 	"strings"
 )
@@ -34,9 +35,11 @@ type AbiMgr struct {
 }
 
 func NewAbiMgr() *AbiMgr {
-	return &AbiMgr{
+	mgr := &AbiMgr{
 		nativeAbis: make(map[string]*NativeContractAbi),
 	}
+	mgr.loadDefaultNativeAbi()
+	return mgr
 }
 
 func (this *AbiMgr) GetNativeAbi(address string) *NativeContractAbi {
@@ -52,6 +55,17 @@ func (this *AbiMgr) Init(path string) {
 	this.loadNativeAbi()
 }
 
+//loadDefaultNativeAbi loads native abi compiled in native framework, which may be overridden by abi files in Path
+func (this *AbiMgr) loadDefaultNativeAbi() {
+	for _, data := range framework.NativeAbiData() {
+		nativeAbi := &NativeContractAbi{}
+		if err := json.Unmarshal([]byte(data), nativeAbi); err != nil {
+			continue
+		}
+		this.nativeAbis[nativeAbi.Address] = nativeAbi
+	}
+}
+
 func (this *AbiMgr) loadNativeAbi() {
 	nativeAbiFiles, err := ioutil.ReadDir(this.Path)
 	if err != nil {
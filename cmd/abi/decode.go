@@ -0,0 +1,328 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+const (
+	NEOVM_PARAM_TYPE_HASH160 = "hash160"
+	NEOVM_PARAM_TYPE_ADDRESS = "address"
+)
+
+//DecodedValue is value decoded by abi, named and typed by abi param
+type DecodedValue struct {
+	Name  string `json:",omitempty"`
+	Type  string `json:",omitempty"`
+	Value interface{}
+}
+
+//DecodedEvent is contract notify decoded by event abi
+type DecodedEvent struct {
+	Name   string
+	Params []*DecodedValue
+}
+
+//DecodedInvoke is contract invocation decoded by function abi
+type DecodedInvoke struct {
+	Contract string
+	Method   string
+	Params   []*DecodedValue
+}
+
+//Struct is struct item of invoke code, to distinguish from array item ([]interface{}).
+//Other items of invoke code are []byte, *big.Int and bool.
+type Struct []interface{}
+
+//DecodeEvent decodes states of native contract notify. The first state is event name, others are event params.
+//States of native contract are readable values already, so they are only named by event abi.
+func (this *NativeContractAbi) DecodeEvent(states interface{}) *DecodedEvent {
+	values, ok := sliceOf(states)
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	name, ok := values[0].(string)
+	if !ok {
+		return nil
+	}
+	evtAbi := this.GetEvent(name)
+	if evtAbi == nil {
+		return nil
+	}
+	evt := &DecodedEvent{Name: evtAbi.Name}
+	for i, v := range values[1:] {
+		param := &DecodedValue{Value: v}
+		if i < len(evtAbi.Parameters) {
+			param.Name, param.Type = evtAbi.Parameters[i].Name, evtAbi.Parameters[i].Type
+		}
+		evt.Params = append(evt.Params, param)
+	}
+	return evt
+}
+
+//DecodeInvoke decodes args of native contract invocation, which is the param item of invoke code.
+//Fields of struct item are params of function, unless function has only one struct param.
+func (this *NativeContractAbi) DecodeInvoke(method string, args interface{}) (*DecodedInvoke, error) {
+	funcAbi := this.GetFunc(method)
+	if funcAbi == nil {
+		return nil, fmt.Errorf("method:%s not found", method)
+	}
+	var items []interface{}
+	st, isStruct := args.(*Struct)
+	switch {
+	case args == nil:
+	case isStruct && !(len(funcAbi.Parameters) == 1 && strings.ToLower(funcAbi.Parameters[0].Type) == NATIVE_PARAM_TYPE_STRUCT):
+		items = *st
+	default:
+		items = []interface{}{args}
+	}
+	if len(items) != len(funcAbi.Parameters) {
+		return nil, fmt.Errorf("method:%s params count:%d not match abi:%d", method, len(items), len(funcAbi.Parameters))
+	}
+	res := &DecodedInvoke{Contract: this.Address, Method: funcAbi.Name}
+	for i, paramAbi := range funcAbi.Parameters {
+		value, err := decodeNativeValue(items[i], paramAbi)
+		if err != nil {
+			return nil, fmt.Errorf("decode param:%s error:%s", paramAbi.Name, err)
+		}
+		res.Params = append(res.Params, &DecodedValue{Name: paramAbi.Name, Type: paramAbi.Type, Value: value})
+	}
+	return res, nil
+}
+
+func decodeNativeValue(item interface{}, paramAbi *NativeContractParamAbi) (interface{}, error) {
+	switch strings.ToLower(paramAbi.Type) {
+	case NATIVE_PARAM_TYPE_ADDRESS:
+		return decodeAddress(item)
+	case NATIVE_PARAM_TYPE_INTEGER, NATIVE_PARAM_TYPE_BYTE:
+		return toBigInt(item), nil
+	case NATIVE_PARAM_TYPE_BOOL:
+		return toBool(item), nil
+	case NATIVE_PARAM_TYPE_STRING:
+		return string(toBytes(item)), nil
+	case NATIVE_PARAM_TYPE_BYTEARRAY:
+		return hex.EncodeToString(toBytes(item)), nil
+	case NATIVE_PARAM_TYPE_UINT256:
+		hash, err := common.Uint256ParseFromBytes(toBytes(item))
+		if err != nil {
+			return nil, err
+		}
+		return hash.ToHexString(), nil
+	case NATIVE_PARAM_TYPE_ARRAY:
+		arr, ok := item.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item is not array")
+		}
+		if len(paramAbi.SubType) == 0 {
+			return rawValue(item), nil
+		}
+		values := make([]interface{}, 0, len(arr))
+		for _, v := range arr {
+			value, err := decodeNativeValue(v, paramAbi.SubType[0])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case NATIVE_PARAM_TYPE_STRUCT:
+		st, ok := item.(*Struct)
+		if !ok {
+			return nil, fmt.Errorf("item is not struct")
+		}
+		if len(*st) != len(paramAbi.SubType) {
+			return nil, fmt.Errorf("struct fields count:%d not match abi:%d", len(*st), len(paramAbi.SubType))
+		}
+		fields := make([]*DecodedValue, 0, len(*st))
+		for i, fieldAbi := range paramAbi.SubType {
+			value, err := decodeNativeValue((*st)[i], fieldAbi)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, &DecodedValue{Name: fieldAbi.Name, Type: fieldAbi.Type, Value: value})
+		}
+		return fields, nil
+	}
+	return rawValue(item), nil
+}
+
+//DecodeEvent decodes states of NeoVM contract notify, which are stack items converted to hex string.
+//The first state is event name, others are event params.
+func (this *NeovmContractAbi) DecodeEvent(states interface{}) *DecodedEvent {
+	values, ok := sliceOf(states)
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	items := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		item, err := fromHexItem(v)
+		if err != nil {
+			return nil
+		}
+		items = append(items, item)
+	}
+	evtAbi := this.GetEvent(string(toBytes(items[0])))
+	if evtAbi == nil {
+		return nil
+	}
+	evt := &DecodedEvent{Name: evtAbi.Name}
+	for i, item := range items[1:] {
+		param := &DecodedValue{}
+		if i < len(evtAbi.Parameters) {
+			param.Name, param.Type = evtAbi.Parameters[i].Name, evtAbi.Parameters[i].Type
+		}
+		param.Value = decodeNeovmValue(item, param.Type)
+		evt.Params = append(evt.Params, param)
+	}
+	return evt
+}
+
+//DecodeInvoke decodes args of NeoVM contract invocation, which is the args array item of invoke code
+func (this *NeovmContractAbi) DecodeInvoke(method string, args interface{}) (*DecodedInvoke, error) {
+	funcAbi := this.GetFunc(method)
+	if funcAbi == nil {
+		return nil, fmt.Errorf("method:%s not found", method)
+	}
+	items, ok := args.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("args is not array")
+	}
+	if len(items) != len(funcAbi.Parameters) {
+		return nil, fmt.Errorf("method:%s params count:%d not match abi:%d", method, len(items), len(funcAbi.Parameters))
+	}
+	res := &DecodedInvoke{Contract: this.Address, Method: funcAbi.Name}
+	for i, paramAbi := range funcAbi.Parameters {
+		res.Params = append(res.Params, &DecodedValue{
+			Name:  paramAbi.Name,
+			Type:  paramAbi.Type,
+			Value: decodeNeovmValue(items[i], paramAbi.Type),
+		})
+	}
+	return res, nil
+}
+
+func decodeNeovmValue(item interface{}, paramType string) interface{} {
+	switch strings.ToLower(paramType) {
+	case NEOVM_PARAM_TYPE_INTEGER:
+		return toBigInt(item)
+	case NEOVM_PARAM_TYPE_BOOL:
+		return toBool(item)
+	case NEOVM_PARAM_TYPE_STRING:
+		return string(toBytes(item))
+	case NEOVM_PARAM_TYPE_BYTE_ARRAY:
+		return hex.EncodeToString(toBytes(item))
+	case NEOVM_PARAM_TYPE_HASH160, NEOVM_PARAM_TYPE_ADDRESS:
+		if addr, err := decodeAddress(item); err == nil {
+			return addr
+		}
+	}
+	return rawValue(item)
+}
+
+func decodeAddress(item interface{}) (string, error) {
+	addr, err := common.AddressParseFromBytes(toBytes(item))
+	if err != nil {
+		return "", err
+	}
+	return addr.ToBase58(), nil
+}
+
+//rawValue converts item without abi, byte array is encoded to hex string
+func rawValue(item interface{}) interface{} {
+	switch v := item.(type) {
+	case []byte:
+		return hex.EncodeToString(v)
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			values = append(values, rawValue(e))
+		}
+		return values
+	case *Struct:
+		return rawValue([]interface{}(*v))
+	}
+	return item
+}
+
+func toBytes(item interface{}) []byte {
+	switch v := item.(type) {
+	case []byte:
+		return v
+	case *big.Int:
+		return common.BigIntToNeoBytes(v)
+	case bool:
+		if v {
+			return []byte{1}
+		}
+		return []byte{0}
+	}
+	return nil
+}
+
+func toBigInt(item interface{}) *big.Int {
+	if v, ok := item.(*big.Int); ok {
+		return v
+	}
+	return common.BigIntFromNeoBytes(toBytes(item))
+}
+
+func toBool(item interface{}) bool {
+	if v, ok := item.(bool); ok {
+		return v
+	}
+	return toBigInt(item).Sign() != 0
+}
+
+//fromHexItem converts hex string states of NeoVM notify back to items
+func fromHexItem(state interface{}) (interface{}, error) {
+	switch v := state.(type) {
+	case string:
+		return hex.DecodeString(v)
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			item, err := fromHexItem(e)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("invalid state type:%T", state)
+}
+
+func sliceOf(states interface{}) ([]interface{}, bool) {
+	value := reflect.ValueOf(states)
+	if value.Kind() != reflect.Slice {
+		return nil, false
+	}
+	res := make([]interface{}, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		res = append(res, value.Index(i).Interface())
+	}
+	return res, true
+}
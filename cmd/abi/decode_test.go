@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+const ONX_CONTRACT_ADDRESS = "0100000000000000000000000000000000000000"
+
+func TestDefaultNativeAbi(t *testing.T) {
+	assert.NotNil(t, NewAbiMgr().GetNativeAbi(ONX_CONTRACT_ADDRESS))
+}
+
+func TestNativeDecodeEvent(t *testing.T) {
+	onxAbi := DefAbiMgr.GetNativeAbi(ONX_CONTRACT_ADDRESS)
+	evt := onxAbi.DecodeEvent([]interface{}{"transfer", "from", "to", uint64(10)})
+	assert.NotNil(t, evt)
+	assert.Equal(t, "transfer", evt.Name)
+	assert.Equal(t, 3, len(evt.Params))
+	assert.Equal(t, "value", evt.Params[2].Name)
+	assert.Equal(t, uint64(10), evt.Params[2].Value)
+
+	assert.Nil(t, onxAbi.DecodeEvent([]interface{}{"unknown"}))
+	assert.Nil(t, onxAbi.DecodeEvent("transfer"))
+}
+
+func TestNativeDecodeInvoke(t *testing.T) {
+	onxAbi := DefAbiMgr.GetNativeAbi(ONX_CONTRACT_ADDRESS)
+	from, to := common.Address{1}, common.Address{2}
+
+	//approve(from, to, value) is invoked with struct of params
+	args := &Struct{from[:], to[:], big.NewInt(100)}
+	invoke, err := onxAbi.DecodeInvoke("approve", args)
+	assert.Nil(t, err)
+	assert.Equal(t, "approve", invoke.Method)
+	assert.Equal(t, from.ToBase58(), invoke.Params[0].Value)
+	assert.Equal(t, to.ToBase58(), invoke.Params[1].Value)
+	assert.Equal(t, big.NewInt(100), invoke.Params[2].Value)
+
+	//transfer(states) is invoked with array of struct
+	invoke, err = onxAbi.DecodeInvoke("transfer", []interface{}{args})
+	assert.Nil(t, err)
+	states := invoke.Params[0].Value.([]interface{})
+	assert.Equal(t, 1, len(states))
+	fields := states[0].([]*DecodedValue)
+	assert.Equal(t, "from", fields[0].Name)
+	assert.Equal(t, from.ToBase58(), fields[0].Value)
+
+	_, err = onxAbi.DecodeInvoke("approve", &Struct{from[:]})
+	assert.NotNil(t, err)
+	_, err = onxAbi.DecodeInvoke("unknown", args)
+	assert.NotNil(t, err)
+}
+
+func TestNeovmDecode(t *testing.T) {
+	contractAbi := &NeovmContractAbi{}
+	err := json.Unmarshal([]byte(`{
+		"hash": "0203",
+		"functions": [{"name": "Put", "parameters": [{"name": "key", "type": "String"}, {"name": "value", "type": "Integer"}]}],
+		"events": [{"name": "put", "parameters": [{"name": "owner", "type": "Hash160"}, {"name": "ok", "type": "Boolean"}]}]
+	}`), contractAbi)
+	assert.Nil(t, err)
+
+	owner := common.Address{3}
+	states := []interface{}{hex.EncodeToString([]byte("put")), hex.EncodeToString(owner[:]), "01", "ff"}
+	evt := contractAbi.DecodeEvent(states)
+	assert.NotNil(t, evt)
+	assert.Equal(t, "put", evt.Name)
+	assert.Equal(t, owner.ToBase58(), evt.Params[0].Value)
+	assert.Equal(t, true, evt.Params[1].Value)
+	assert.Equal(t, "", evt.Params[2].Name)
+	assert.Equal(t, "ff", evt.Params[2].Value)
+
+	invoke, err := contractAbi.DecodeInvoke("put", []interface{}{[]byte("foo"), common.BigIntToNeoBytes(big.NewInt(1000))})
+	assert.Nil(t, err)
+	assert.Equal(t, "Put", invoke.Method)
+	assert.Equal(t, "foo", invoke.Params[0].Value)
+	assert.Equal(t, big.NewInt(1000), invoke.Params[1].Value)
+}
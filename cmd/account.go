@@ -69,6 +69,7 @@ var curveMap = map[string]curveInfo{
 	"384": {"P-384", keypair.P384},
 	"521": {"P-521", keypair.P521},
 
+	"SECP256K1": {"SECP256K1", keypair.SECP256K1},
 	"SM2P256V1": {"SM2P256V1", keypair.SM2P256V1},
 	"ED25519":   {"ED25519", keypair.ED25519},
 }
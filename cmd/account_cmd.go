@@ -25,6 +25,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
 	"github.com/OnyxPay/OnyxChain-crypto/signature"
 	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/account/hd"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/common"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/common/password"
@@ -55,9 +56,13 @@ You can use ./OnyxChain account --help command to view help information of walle
 					utils.AccountDefaultFlag,
 					utils.AccountLabelFlag,
 					utils.IdentityFlag,
+					utils.AccountMnemonicFlag,
+					utils.AccountHDPathFlag,
 					utils.WalletFileFlag,
 				},
 				Description: ` Add a new account to wallet.
+   With --mnemonic flag, accounts are derived from BIP39 mnemonic by BIP44 path m/44'/1024'/0'/0/<index>, and a new mnemonic is generated if input is empty.
+   HD account support ecdsa with P-256 or SECP256K1 curve, and sm2.
    OnyxChain support three type of key: ecdsa, sm2 and ed25519, and support 224、256、384、521 bits length of key in ecdsa, but only support 256 bits length of key in sm2 and ed25519.
    OnyxChain support multiple signature scheme.
    For ECDSA support SHA224withECDSA、SHA256withECDSA、SHA384withECDSA、SHA512withEdDSA、SHA3-224withECDSA、SHA3-256withECDSA、SHA3-384withECDSA、SHA3-512withECDSA、RIPEMD160withECDSA;
@@ -132,8 +137,16 @@ You can use ./OnyxChain account --help command to view help information of walle
 					utils.WalletFileFlag,
 					utils.AccountSourceFileFlag,
 					utils.AccountWIFFlag,
+					utils.AccountMnemonicFlag,
+					utils.AccountHDPathFlag,
+					utils.AccountQuantityFlag,
+					utils.AccountTypeFlag,
+					utils.AccountKeylenFlag,
+					utils.AccountSigSchemeFlag,
+					utils.AccountDefaultFlag,
+					utils.AccountLabelFlag,
 				},
-				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import",
+				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import. With --mnemonic flag, restore accounts from BIP39 mnemonic",
 			},
 			{
 				Action:    accountExport,
@@ -194,6 +207,9 @@ func accountCreate(ctx *cli.Context) error {
 		PrintInfoMsg("Bind public key:%s", id.Control[0].Public)
 		return nil
 	}
+	if ctx.Bool(utils.GetFlagName(utils.AccountMnemonicFlag)) {
+		return addHDAccounts(ctx, wallet, keyType, curve, scheme, optionNumber, optionLabel, pass)
+	}
 	for i := 0; i < optionNumber; i++ {
 		label := optionLabel
 		if label != "" && optionNumber > 1 {
@@ -214,6 +230,99 @@ func accountCreate(ctx *cli.Context) error {
 	return nil
 }
 
+//addHDAccounts derives accounts from mnemonic, accounts already in wallet are skipped
+func addHDAccounts(ctx *cli.Context, wallet account.Client, keyType keypair.KeyType, curve byte, scheme signature.SignatureScheme, number int, label string, pass []byte) error {
+	restore := ctx.Command.Name == "import"
+	mnemonic, err := getMnemonic(!restore)
+	if err != nil {
+		return err
+	}
+	seed, err := hd.NewSeed(mnemonic, "")
+	if err != nil {
+		return err
+	}
+	path := []uint32{hd.PURPOSE_BIP44 + hd.HARDENED_KEY_START, hd.COIN_TYPE + hd.HARDENED_KEY_START, hd.HARDENED_KEY_START, 0, 0}
+	if ctx.IsSet(utils.GetFlagName(utils.AccountHDPathFlag)) {
+		path, err = hd.ParsePath(ctx.String(utils.GetFlagName(utils.AccountHDPathFlag)))
+		if err != nil {
+			return err
+		}
+		if len(path) == 0 {
+			return fmt.Errorf("derivation path cannot be master key")
+		}
+	}
+	last := len(path) - 1
+	maxIndex := hd.HARDENED_KEY_START - 1
+	if path[last] >= hd.HARDENED_KEY_START {
+		maxIndex = ^uint32(0)
+	}
+	for i := 0; i < number; path[last]++ {
+		hdPath := hd.FormatPath(path)
+		_, pub, err := account.DeriveKeyPair(seed, hdPath, keyType, curve)
+		if err != nil {
+			return err
+		}
+		address := types.AddressFromPubKey(pub)
+		if wallet.GetAccountMetadataByAddress(address.ToBase58()) != nil {
+			PrintWarnMsg("Account:%s of path:%s already exists, skip.", address.ToBase58(), hdPath)
+			//restore the first number accounts, but add number new accounts
+			if restore {
+				i++
+			}
+		} else {
+			accLabel := label
+			if accLabel != "" && number > 1 {
+				accLabel = fmt.Sprintf("%s%d", label, i+1)
+			}
+			acc, err := wallet.NewHDAccount(accLabel, seed, hdPath, keyType, curve, scheme, pass)
+			if err != nil {
+				return fmt.Errorf("new account error:%s", err)
+			}
+			i++
+			PrintInfoMsg("Index:%d", wallet.GetAccountNum())
+			PrintInfoMsg("Label:%s", accLabel)
+			PrintInfoMsg("Address:%s", acc.Address.ToBase58())
+			PrintInfoMsg("Path:%s", hdPath)
+			PrintInfoMsg("Public key:%s", hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
+			PrintInfoMsg("Signature scheme:%s", acc.SigScheme.Name())
+		}
+		if i < number && path[last] == maxIndex {
+			return fmt.Errorf("derivation index of path:%s overflow", hdPath)
+		}
+	}
+	PrintInfoMsg("Create account successfully.")
+	return nil
+}
+
+//getMnemonic reads mnemonic from user input, generates a new one if input is empty and generate is true
+func getMnemonic(generate bool) (string, error) {
+	if generate {
+		PrintInfoMsg("Please input mnemonic to restore accounts, or leave it empty to generate a new one.")
+	}
+	input, err := password.GetSecret("Mnemonic")
+	if err != nil {
+		return "", fmt.Errorf("input mnemonic error:%s", err)
+	}
+	mnemonic := hd.NormalizeMnemonic(string(input))
+	common.ClearPasswd(input)
+	if mnemonic != "" {
+		if !hd.IsMnemonicValid(mnemonic) {
+			return "", fmt.Errorf("invalid mnemonic")
+		}
+		return mnemonic, nil
+	}
+	if !generate {
+		return "", fmt.Errorf("mnemonic cannot empty")
+	}
+	mnemonic, err = hd.NewMnemonic(hd.DEFAULT_ENTROPY_BITS)
+	if err != nil {
+		return "", err
+	}
+	PrintWarnMsg("Please write down the mnemonic and keep it safe, it's the only way to restore accounts:")
+	PrintInfoMsg("%s", mnemonic)
+	return mnemonic, nil
+}
+
 func accountList(ctx *cli.Context) error {
 	optionFile := checkFileName(ctx)
 	wallet, err := account.Open(optionFile)
@@ -264,6 +373,9 @@ func accountList(ctx *cli.Context) error {
 		PrintInfoMsg("	Curve: %v", accMeta.Curve)
 		PrintInfoMsg("	Key length: %v bits", len(accMeta.Key)*8)
 		PrintInfoMsg("	Public key: %v", accMeta.PubKey)
+		if accMeta.HDPath != "" {
+			PrintInfoMsg("	HD path: %v", accMeta.HDPath)
+		}
 		PrintInfoMsg("	Signature scheme: %v\n", accMeta.SigSch)
 	}
 	return nil
@@ -386,6 +498,10 @@ func accountDelete(ctx *cli.Context) error {
 }
 
 func accountImport(ctx *cli.Context) error {
+	if ctx.Bool(utils.GetFlagName(utils.AccountMnemonicFlag)) {
+		//restore accounts from mnemonic
+		return accountCreate(ctx)
+	}
 	source := ctx.String(utils.GetFlagName(utils.AccountSourceFileFlag))
 	if source == "" {
 		PrintErrorMsg("Missing source wallet path argument to import.")
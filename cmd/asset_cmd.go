@@ -23,9 +23,12 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/account"
 	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
 	nutils "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	"github.com/urfave/cli"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -40,7 +43,7 @@ var AssetCommand = cli.Command{
 			Name:        "transfer",
 			Usage:       "Transfer onx or oxg to another account",
 			ArgsUsage:   " ",
-			Description: "Transfer onx or oxg to another account. If from address does not specified, using default account",
+			Description: "Transfer onx or oxg to another account. If from address does not specified, using default account. With --batch, transfers in csv file are sent with memo by transferMulti, at most 500 transfers in one transaction.",
 			Flags: []cli.Flag{
 				utils.RPCPortFlag,
 				utils.TransactionGasPriceFlag,
@@ -49,8 +52,10 @@ var AssetCommand = cli.Command{
 				utils.TransactionFromFlag,
 				utils.TransactionToFlag,
 				utils.TransactionAmountFlag,
+				utils.TransactionBatchFlag,
 				utils.ForceSendTxFlag,
 				utils.WalletFileFlag,
+				utils.SignerFlag,
 			},
 		},
 		{
@@ -67,6 +72,7 @@ var AssetCommand = cli.Command{
 				utils.ApproveAssetToFlag,
 				utils.ApproveAmountFlag,
 				utils.WalletFileFlag,
+				utils.SignerFlag,
 			},
 		},
 		{
@@ -85,13 +91,14 @@ var AssetCommand = cli.Command{
 				utils.TransferFromAmountFlag,
 				utils.ForceSendTxFlag,
 				utils.WalletFileFlag,
+				utils.SignerFlag,
 			},
 		},
 		{
 			Action:    getBalance,
 			Name:      "balance",
 			Usage:     "Show balance of onx and oxg of specified account",
-			ArgsUsage: "<address|label|index>",
+			ArgsUsage: "<address|label|index|name>",
 			Flags: []cli.Flag{
 				utils.RPCPortFlag,
 				utils.WalletFileFlag,
@@ -129,6 +136,7 @@ var AssetCommand = cli.Command{
 				utils.TransactionGasPriceFlag,
 				utils.TransactionGasLimitFlag,
 				utils.WalletFileFlag,
+				utils.SignerFlag,
 			},
 		},
 	},
@@ -136,6 +144,9 @@ var AssetCommand = cli.Command{
 
 func transfer(ctx *cli.Context) error {
 	SetRpcPort(ctx)
+	if ctx.IsSet(utils.GetFlagName(utils.TransactionBatchFlag)) {
+		return transferBatch(ctx)
+	}
 	if !ctx.IsSet(utils.GetFlagName(utils.TransactionToFlag)) ||
 		!ctx.IsSet(utils.GetFlagName(utils.TransactionFromFlag)) ||
 		!ctx.IsSet(utils.GetFlagName(utils.TransactionAmountFlag)) {
@@ -154,7 +165,7 @@ func transfer(ctx *cli.Context) error {
 		return err
 	}
 	to := ctx.String(utils.TransactionToFlag.Name)
-	toAddr, err := cmdcom.ParseAddress(to, ctx)
+	toAddr, err := cmdcom.ParseAddressOrName(to, ctx)
 	if err != nil {
 		return err
 	}
@@ -213,7 +224,11 @@ func transfer(ctx *cli.Context) error {
 	}
 	PrintInfoMsg("Transfer %s", strings.ToUpper(asset))
 	PrintInfoMsg("  From:%s", fromAddr)
-	PrintInfoMsg("  To:%s", toAddr)
+	if nameservice.IsName(to) {
+		PrintInfoMsg("  To:%s (%s)", toAddr, to)
+	} else {
+		PrintInfoMsg("  To:%s", toAddr)
+	}
 	PrintInfoMsg("  Amount:%s", amountStr)
 	PrintInfoMsg("  TxHash:%s", txHash)
 	PrintInfoMsg("\nTip:")
@@ -221,6 +236,87 @@ func transfer(ctx *cli.Context) error {
 	return nil
 }
 
+func transferBatch(ctx *cli.Context) error {
+	if !ctx.IsSet(utils.GetFlagName(utils.TransactionFromFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.TransactionFromFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	asset := ctx.String(utils.GetFlagName(utils.TransactionAssetFlag))
+	if asset == "" {
+		asset = utils.ASSET_ONX
+	}
+	asset = strings.ToLower(asset)
+	fromAddr, err := cmdcom.ParseAddress(ctx.String(utils.TransactionFromFlag.Name), ctx)
+	if err != nil {
+		return err
+	}
+	from, err := common.AddressFromBase58(fromAddr)
+	if err != nil {
+		return fmt.Errorf("from address:%s invalid:%s", fromAddr, err)
+	}
+	file, err := os.Open(ctx.String(utils.GetFlagName(utils.TransactionBatchFlag)))
+	if err != nil {
+		return fmt.Errorf("open batch file error:%s", err)
+	}
+	defer file.Close()
+	states, err := utils.ParseTransferBatch(file, asset, from)
+	if err != nil {
+		return fmt.Errorf("parse batch file error:%s", err)
+	}
+	total, overflow := uint64(0), false
+	for _, state := range states {
+		if total, overflow = common.SafeAdd(total, state.Value); overflow {
+			return fmt.Errorf("total amount of batch overflow")
+		}
+	}
+	if err := utils.CheckAssetAmount(asset, total); err != nil {
+		return err
+	}
+
+	force := ctx.Bool(utils.GetFlagName(utils.ForceSendTxFlag))
+	if !force {
+		balance, err := utils.GetAccountBalance(fromAddr, asset)
+		if err != nil {
+			return err
+		}
+		if balance < total {
+			PrintErrorMsg("Account:%s balance not enough.", fromAddr)
+			PrintInfoMsg("\nTip:")
+			PrintInfoMsg("  If you want to send transaction compulsively, please using %s flag.", utils.GetFlagName(utils.ForceSendTxFlag))
+			return nil
+		}
+	}
+
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	signer, err := cmdcom.GetAccount(ctx, fromAddr)
+	if err != nil {
+		return err
+	}
+
+	PrintInfoMsg("Transfer %s", strings.ToUpper(asset))
+	PrintInfoMsg("  From:%s", fromAddr)
+	PrintInfoMsg("  Transfers:%d", len(states))
+	for i, batch := range utils.SplitTransferBatch(states) {
+		txHash, err := utils.TransferMulti(gasPrice, gasLimit, signer, asset, batch)
+		if err != nil {
+			return fmt.Errorf("transfer batch %d error:%s", i+1, err)
+		}
+		PrintInfoMsg("  TxHash:%s", txHash)
+	}
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status <TxHash>' to query transaction status.")
+	return nil
+}
+
 func getBalance(ctx *cli.Context) error {
 	SetRpcPort(ctx)
 	if ctx.NArg() < 1 {
@@ -230,7 +326,7 @@ func getBalance(ctx *cli.Context) error {
 	}
 
 	addrArg := ctx.Args().First()
-	accAddr, err := cmdcom.ParseAddress(addrArg, ctx)
+	accAddr, err := cmdcom.ParseAddressOrName(addrArg, ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/urfave/cli"
+)
+
+var authTxFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.TransactionGasPriceFlag,
+	utils.TransactionGasLimitFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.SignerFlag,
+	utils.IDFlag,
+	utils.IDKeyNoFlag,
+}
+
+var AuthCommand = cli.Command{
+	Name:        "auth",
+	Usage:       "Manage role based access control of contracts",
+	Description: "Auth commands can show admin, roles and role holders of contract, and let admin assign functions and ONX IDs to roles, and role holders delegate or withdraw roles. ONX ID of operator is set by --id, whose key --keyno is owned by signer. Admin of contract is set by initContractAdmin of the contract itself.",
+	Subcommands: []cli.Command{
+		{
+			Action:    authRoles,
+			Name:      "roles",
+			Usage:     "Show admin, functions and holders of roles of contract",
+			ArgsUsage: "<contract>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:    authIDRoles,
+			Name:      "idroles",
+			Usage:     "Show roles of ONX ID in contract with delegation expiry",
+			ArgsUsage: "<contract>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.IDFlag,
+			},
+		},
+		{
+			Action:    authAssignFuncs,
+			Name:      "assignfuncs",
+			Usage:     "Assign functions of contract to role by admin",
+			ArgsUsage: "<contract> <role> <function>...",
+			Flags:     authTxFlags,
+		},
+		{
+			Action:    authAssignIDs,
+			Name:      "assignids",
+			Usage:     "Assign role to ONX IDs by admin",
+			ArgsUsage: "<contract> <role> <ONX ID>...",
+			Flags:     authTxFlags,
+		},
+		{
+			Action:      authDelegate,
+			Name:        "delegate",
+			Usage:       "Delegate role to ONX ID for a period",
+			ArgsUsage:   "<contract> <role> <ONX ID>",
+			Description: "Delegate role held by ONX ID of --id to another ONX ID, which holds role until period passes or delegation is withdrawn",
+			Flags:       append([]cli.Flag{utils.AuthPeriodFlag, utils.AuthLevelFlag}, authTxFlags...),
+		},
+		{
+			Action:    authWithdraw,
+			Name:      "withdraw",
+			Usage:     "Withdraw role delegated to ONX ID",
+			ArgsUsage: "<contract> <role> <ONX ID>",
+			Flags:     authTxFlags,
+		},
+		{
+			Action:    authTransfer,
+			Name:      "transfer",
+			Usage:     "Transfer admin of contract to new ONX ID by admin",
+			ArgsUsage: "<contract> <ONX ID>",
+			Flags:     authTxFlags,
+		},
+	},
+}
+
+//parseAuthArgs parses contract address, which is hex string or base58, and checks count of arguments
+func parseAuthArgs(ctx *cli.Context, count int) (common.Address, error) {
+	if ctx.NArg() < count {
+		return common.ADDRESS_EMPTY, fmt.Errorf("missing arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	str := ctx.Args().First()
+	contract, err := common.AddressFromHexString(str)
+	if err != nil {
+		contract, err = common.AddressFromBase58(str)
+		if err != nil {
+			return common.ADDRESS_EMPTY, fmt.Errorf("invalid contract address:%s", str)
+		}
+	}
+	return contract, nil
+}
+
+func getAuthIDs(args []string) ([][]byte, error) {
+	ids := make([][]byte, 0, len(args))
+	for _, id := range args {
+		if !account.VerifyID(id) {
+			return nil, fmt.Errorf("invalid ONX ID:%s", id)
+		}
+		ids = append(ids, []byte(id))
+	}
+	return ids, nil
+}
+
+//invokeAuth sends transaction of auth contract signed by account
+func invokeAuth(ctx *cli.Context, method string, param interface{}) error {
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	txHash, err := utils.InvokeAuthContract(gasPrice, gasLimit, signer, method, param)
+	if err != nil {
+		return fmt.Errorf("invoke %s error:%s", method, err)
+	}
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+	PrintInfoMsg("  Auth contract returns false without error if permission is denied, check result in events.")
+	return nil
+}
+
+func authKeyNo(ctx *cli.Context) uint64 {
+	return uint64(ctx.Uint(utils.GetFlagName(utils.IDKeyNoFlag)))
+}
+
+func authRoles(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 1)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetContractRoles(contract.ToHexString())
+	if err != nil {
+		return fmt.Errorf("GetContractRoles error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func authIDRoles(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 1)
+	if err != nil {
+		return err
+	}
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetOnxIDRoles(contract.ToHexString(), id)
+	if err != nil {
+		return fmt.Errorf("GetOnxIDRoles error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func authAssignFuncs(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 3)
+	if err != nil {
+		return err
+	}
+	admin, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	param := &auth.FuncsToRoleParam{
+		ContractAddr: contract,
+		AdminOnxID:   []byte(admin),
+		Role:         []byte(ctx.Args().Get(1)),
+		FuncNames:    ctx.Args()[2:],
+		KeyNo:        authKeyNo(ctx),
+	}
+	PrintInfoMsg("Assign functions to role %s of contract %s:", param.Role, contract.ToHexString())
+	return invokeAuth(ctx, "assignFuncsToRole", param)
+}
+
+func authAssignIDs(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 3)
+	if err != nil {
+		return err
+	}
+	admin, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	persons, err := getAuthIDs(ctx.Args()[2:])
+	if err != nil {
+		return err
+	}
+	param := &auth.OnxIDsToRoleParam{
+		ContractAddr: contract,
+		AdminOnxID:   []byte(admin),
+		Role:         []byte(ctx.Args().Get(1)),
+		Persons:      persons,
+		KeyNo:        authKeyNo(ctx),
+	}
+	PrintInfoMsg("Assign role %s of contract %s to ONX IDs:", param.Role, contract.ToHexString())
+	return invokeAuth(ctx, "assignOnxIDsToRole", param)
+}
+
+func authDelegate(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 3)
+	if err != nil {
+		return err
+	}
+	from, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	to, err := getAuthIDs(ctx.Args()[2:3])
+	if err != nil {
+		return err
+	}
+	param := &auth.DelegateParam{
+		ContractAddr: contract,
+		From:         []byte(from),
+		To:           to[0],
+		Role:         []byte(ctx.Args().Get(1)),
+		Period:       uint64(ctx.Uint(utils.GetFlagName(utils.AuthPeriodFlag))),
+		Level:        uint64(ctx.Uint(utils.GetFlagName(utils.AuthLevelFlag))),
+		KeyNo:        authKeyNo(ctx),
+	}
+	PrintInfoMsg("Delegate role %s of contract %s to %s for %ds:", param.Role, contract.ToHexString(), param.To, param.Period)
+	return invokeAuth(ctx, "delegate", param)
+}
+
+func authWithdraw(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 3)
+	if err != nil {
+		return err
+	}
+	initiator, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	delegate, err := getAuthIDs(ctx.Args()[2:3])
+	if err != nil {
+		return err
+	}
+	param := &auth.WithdrawParam{
+		ContractAddr: contract,
+		Initiator:    []byte(initiator),
+		Delegate:     delegate[0],
+		Role:         []byte(ctx.Args().Get(1)),
+		KeyNo:        authKeyNo(ctx),
+	}
+	PrintInfoMsg("Withdraw role %s of contract %s from %s:", param.Role, contract.ToHexString(), param.Delegate)
+	return invokeAuth(ctx, "withdraw", param)
+}
+
+func authTransfer(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	contract, err := parseAuthArgs(ctx, 2)
+	if err != nil {
+		return err
+	}
+	newAdmin, err := getAuthIDs(ctx.Args()[1:2])
+	if err != nil {
+		return err
+	}
+	param := &auth.TransferParam{
+		ContractAddr:  contract,
+		NewAdminOnxID: newAdmin[0],
+		KeyNo:         authKeyNo(ctx),
+	}
+	PrintInfoMsg("Transfer admin of contract %s to %s:", contract.ToHexString(), param.NewAdminOnxID)
+	return invokeAuth(ctx, "transfer", param)
+}
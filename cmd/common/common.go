@@ -21,10 +21,12 @@ package common
 import (
 	"fmt"
 	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/account/extsigner"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/password"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
 	"github.com/urfave/cli"
 	"strconv"
 )
@@ -117,6 +119,16 @@ func GetAccountMetadataMulti(wallet account.Client, accAddr string) *account.Acc
 }
 
 func GetAccount(ctx *cli.Context, address ...string) (*account.Account, error) {
+	accAddr := ""
+	if len(address) > 0 {
+		accAddr = address[0]
+	} else {
+		accAddr = ctx.String(utils.GetFlagName(utils.AccountAddressFlag))
+	}
+	signerUri := ctx.String(utils.GetFlagName(utils.SignerFlag))
+	if signerUri != "" {
+		return GetExternalAccount(signerUri, accAddr)
+	}
 	wallet, err := OpenWallet(ctx)
 	if err != nil {
 		return nil, err
@@ -126,15 +138,21 @@ func GetAccount(ctx *cli.Context, address ...string) (*account.Account, error) {
 		return nil, err
 	}
 	defer ClearPasswd(passwd)
-	accAddr := ""
-	if len(address) > 0 {
-		accAddr = address[0]
-	} else {
-		accAddr = ctx.String(utils.GetFlagName(utils.AccountAddressFlag))
-	}
 	return GetAccountMulti(wallet, passwd, accAddr)
 }
 
+//GetExternalAccount returns account of external signer. If accAddr is base58 address, it must be the address of signer
+func GetExternalAccount(signerUri, accAddr string) (*account.Account, error) {
+	acc, err := extsigner.OpenAccount(signerUri)
+	if err != nil {
+		return nil, fmt.Errorf("open external signer error:%s", err)
+	}
+	if IsBase58Address(accAddr) && accAddr != acc.Address.ToBase58() {
+		return nil, fmt.Errorf("address of external signer:%s doesn't match:%s", acc.Address.ToBase58(), accAddr)
+	}
+	return acc, nil
+}
+
 func IsBase58Address(address string) bool {
 	if address == "" {
 		return false
@@ -167,6 +185,14 @@ func ParseAddress(address string, ctx *cli.Context) (string, error) {
 	return "", fmt.Errorf("cannot get account by:%s", address)
 }
 
+//ParseAddressOrName return base58 address which name of name service resolves to, or from base58, label or index
+func ParseAddressOrName(address string, ctx *cli.Context) (string, error) {
+	if nameservice.IsName(address) {
+		return utils.ResolveNameAddress(address)
+	}
+	return ParseAddress(address, ctx)
+}
+
 func ClearPasswd(passwd []byte) {
 	size := len(passwd)
 	for i := 0; i < size; i++ {
@@ -40,6 +40,7 @@ func SetOnyxChainConfig(ctx *cli.Context) (*config.OnyxChainConfig, error) {
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setMetricsConfig(ctx, cfg.Metrics)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -75,6 +76,7 @@ func setGenesis(ctx *cli.Context, cfg *config.OnyxChainConfig) error {
 
 	if ctx.Bool(utils.GetFlagName(utils.EnableTestModeFlag)) {
 		cfg.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+		cfg.Genesis.Features = &config.FeatureHeights{}
 		cfg.Genesis.SOLO.GenBlockTime = ctx.Uint(utils.GetFlagName(utils.TestModeGenBlockTimeFlag))
 		if cfg.Genesis.SOLO.GenBlockTime <= 1 {
 			cfg.Genesis.SOLO.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
@@ -125,6 +127,7 @@ func setGenesis(ctx *cli.Context, cfg *config.OnyxChainConfig) error {
 func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 	cfg.LogLevel = ctx.Uint(utils.GetFlagName(utils.LogLevelFlag))
 	cfg.EnableEventLog = !ctx.Bool(utils.GetFlagName(utils.DisableEventLogFlag))
+	cfg.EnableTxTrace = ctx.Bool(utils.GetFlagName(utils.EnableTxTraceFlag))
 	cfg.GasLimit = ctx.Uint64(utils.GetFlagName(utils.GasLimitFlag))
 	cfg.GasPrice = ctx.Uint64(utils.GetFlagName(utils.GasPriceFlag))
 	cfg.DataDir = ctx.String(utils.GetFlagName(utils.DataDirFlag))
@@ -184,6 +187,11 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnableFlag))
+	cfg.HttpMetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
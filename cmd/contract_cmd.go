@@ -19,15 +19,22 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	httpcom "github.com/OnyxPay/OnyxChain-legacy/http/base/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/debugger"
 	"github.com/urfave/cli"
 	"io/ioutil"
+	"os"
 	"strings"
 )
 
@@ -56,7 +63,9 @@ var (
 					utils.ContractEmailFlag,
 					utils.ContractDescFlag,
 					utils.ContractPrepareDeployFlag,
+					utils.ContractAbiFileFlag,
 					utils.WalletFileFlag,
+					utils.SignerFlag,
 					utils.AccountAddressFlag,
 				},
 			},
@@ -80,6 +89,12 @@ var (
      Return type support bytearray(encoded to hex string), string, integer, boolean. 
      If return type is object array, enclose array with '[]'. 
      For example: [string,int,bool,string]
+
+  Method
+     If contract abi has been registered on chain, use --method flag to specify the method to invoke.
+     Parameters are plain values without type prefix, and are converted by the abi of method.
+     For example: --method=transfer --params=ARVVxBPGySL56CvSSWfjRVVyZYpNZ7zp48,AaCe8nVkMRABnp5YgEjYZ9E5KYCxks2uce,10
+     Return type is taken from abi if --return flag is not set.
 `,
 				Flags: []cli.Flag{
 					utils.RPCPortFlag,
@@ -90,7 +105,9 @@ var (
 					utils.ContractVersionFlag,
 					utils.ContractPrepareInvokeFlag,
 					utils.ContractReturnTypeFlag,
+					utils.ContractMethodFlag,
 					utils.WalletFileFlag,
+					utils.SignerFlag,
 					utils.AccountAddressFlag,
 				},
 			},
@@ -105,10 +122,92 @@ var (
 					utils.TransactionGasPriceFlag,
 					utils.TransactionGasLimitFlag,
 					utils.WalletFileFlag,
+					utils.SignerFlag,
 					utils.ContractPrepareInvokeFlag,
 					utils.AccountAddressFlag,
 				},
 			},
+			{
+				Action:    registerContractAbi,
+				Name:      "registerabi",
+				Usage:     "Register contract abi to chain",
+				ArgsUsage: " ",
+				Description: `Register abi json file of NeoVM contract to the abi registry on chain. Only the deployer of contract can register its abi.
+Registered abi is returned by getcontractabi rpc, and used by 'contract invoke --method'.`,
+				Flags: []cli.Flag{
+					utils.RPCPortFlag,
+					utils.TransactionGasPriceFlag,
+					utils.TransactionGasLimitFlag,
+					utils.ContractAddrFlag,
+					utils.ContractAbiFileFlag,
+					utils.WalletFileFlag,
+					utils.SignerFlag,
+					utils.AccountAddressFlag,
+				},
+			},
+			{
+				Action:    getContractAbi,
+				Name:      "getabi",
+				Usage:     "Show contract abi registered on chain",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					utils.RPCPortFlag,
+					utils.ContractAddrFlag,
+				},
+			},
+			{
+				Action:    upgradeContract,
+				Name:      "upgrade",
+				Usage:     "Upgrade code of smart contract in place",
+				ArgsUsage: " ",
+				Description: `Upgrade code of NeoVM contract in place, keeping contract address and storage. Signer should own the key --keyno of admin ONX ID of contract in auth contract.
+Method onUpgrade of new code is invoked once in the upgrade transaction, with previous version of contract as parameter.`,
+				Flags: []cli.Flag{
+					utils.RPCPortFlag,
+					utils.TransactionGasPriceFlag,
+					utils.TransactionGasLimitFlag,
+					utils.ContractAddrFlag,
+					utils.ContractStorageFlag,
+					utils.ContractCodeFileFlag,
+					utils.ContractNameFlag,
+					utils.ContractVersionFlag,
+					utils.ContractAuthorFlag,
+					utils.ContractEmailFlag,
+					utils.ContractDescFlag,
+					utils.ContractAdminKeyFlag,
+					utils.WalletFileFlag,
+					utils.SignerFlag,
+					utils.AccountAddressFlag,
+				},
+			},
+			{
+				Action:    getContractHistory,
+				Name:      "history",
+				Usage:     "Show version history of contract",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					utils.RPCPortFlag,
+					utils.ContractAddrFlag,
+				},
+			},
+			{
+				Action:    debugContract,
+				Name:      "debug",
+				Usage:     "Debug transaction step by step against local ledger",
+				ArgsUsage: " ",
+				Description: `Debug transaction step by step against local ledger. Transaction is executed on the current state of ledger in data dir without commit, so please stop node or use a copy of ledger.
+Transaction can be specified by --raw-tx, --hash of transaction in ledger, or --code file of invoke code.
+Without --breakpoint, debugger pauses at the first instruction. Type h in debugger for commands.`,
+				Flags: []cli.Flag{
+					utils.RawTransactionFlag,
+					utils.TransactionHashFlag,
+					utils.ContractCodeFileFlag,
+					utils.ContractBreakpointFlag,
+					utils.DataDirFlag,
+					utils.ConfigFlag,
+					utils.NetworkIdFlag,
+				},
+			},
 		},
 	}
 )
@@ -150,6 +249,14 @@ func deployContract(ctx *cli.Context) error {
 
 	cversion := fmt.Sprintf("%s", version)
 
+	var abiData []byte
+	if abiFile := ctx.String(utils.GetFlagName(utils.ContractAbiFileFlag)); abiFile != "" {
+		abiData, err = readContractAbiFile(abiFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	if ctx.IsSet(utils.GetFlagName(utils.ContractPrepareDeployFlag)) {
 		preResult, err := utils.PrepareDeployContract(store, code, name, cversion, author, email, desc)
 		if err != nil {
@@ -177,11 +284,177 @@ func deployContract(ctx *cli.Context) error {
 	PrintInfoMsg("Deploy contract:")
 	PrintInfoMsg("  Contract Address:%s", address.ToHexString())
 	PrintInfoMsg("  TxHash:%s", txHash)
+	if abiData != nil {
+		PrintInfoMsg("\nWaiting for contract deployed ...")
+		_, err = utils.WaitForTransaction(txHash, utils.DEFAULT_WAIT_TX_TIMEOUT)
+		if err != nil {
+			return fmt.Errorf("contract abi not registered, error:%s", err)
+		}
+		abiTxHash, err := utils.RegisterContractAbi(gasPrice, gasLimit, signer, address, abiData)
+		if err != nil {
+			return fmt.Errorf("RegisterContractAbi error:%s", err)
+		}
+		PrintInfoMsg("Register contract abi:")
+		PrintInfoMsg("  TxHash:%s", abiTxHash)
+		txHash = abiTxHash
+	}
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+	return nil
+}
+
+func registerContractAbi(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if !ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) ||
+		!ctx.IsSet(utils.GetFlagName(utils.ContractAbiFileFlag)) {
+		PrintErrorMsg("Missing %s or %s argument.", utils.ContractAddrFlag.Name, utils.ContractAbiFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	contractAddrStr := ctx.String(utils.GetFlagName(utils.ContractAddrFlag))
+	contractAddr, err := common.AddressFromHexString(contractAddrStr)
+	if err != nil {
+		return fmt.Errorf("invalid contract address error:%s", err)
+	}
+	abiData, err := readContractAbiFile(ctx.String(utils.GetFlagName(utils.ContractAbiFileFlag)))
+	if err != nil {
+		return err
+	}
+	gasPrice := ctx.Uint64(utils.GetFlagName(utils.TransactionGasPriceFlag))
+	gasLimit := ctx.Uint64(utils.GetFlagName(utils.TransactionGasLimitFlag))
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("get signer account error:%s", err)
+	}
+	txHash, err := utils.RegisterContractAbi(gasPrice, gasLimit, signer, contractAddr, abiData)
+	if err != nil {
+		return fmt.Errorf("RegisterContractAbi error:%s", err)
+	}
+	PrintInfoMsg("Register contract abi:")
+	PrintInfoMsg("  Contract Address:%s", contractAddr.ToHexString())
+	PrintInfoMsg("  TxHash:%s", txHash)
 	PrintInfoMsg("\nTip:")
 	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
 	return nil
 }
 
+func getContractAbi(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if !ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.ContractAddrFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	contractAddrStr := ctx.String(utils.GetFlagName(utils.ContractAddrFlag))
+	contractAddr, err := common.AddressFromHexString(contractAddrStr)
+	if err != nil {
+		return fmt.Errorf("invalid contract address error:%s", err)
+	}
+	abiData, err := utils.GetContractAbiData(contractAddr)
+	if err != nil {
+		return fmt.Errorf("GetContractAbi error:%s", err)
+	}
+	if abiData == nil {
+		PrintInfoMsg("Contract:%s has no abi registered.", contractAddrStr)
+		return nil
+	}
+	PrintJsonData(abiData)
+	return nil
+}
+
+func upgradeContract(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if !ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) ||
+		!ctx.IsSet(utils.GetFlagName(utils.ContractCodeFileFlag)) ||
+		!ctx.IsSet(utils.GetFlagName(utils.ContractNameFlag)) {
+		PrintErrorMsg("Missing %s, %s or %s argument.", utils.ContractAddrFlag.Name, utils.ContractCodeFileFlag.Name,
+			utils.ContractNameFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	contractAddrStr := ctx.String(utils.GetFlagName(utils.ContractAddrFlag))
+	contractAddr, err := common.AddressFromHexString(contractAddrStr)
+	if err != nil {
+		return fmt.Errorf("invalid contract address error:%s", err)
+	}
+	codeFile := ctx.String(utils.GetFlagName(utils.ContractCodeFileFlag))
+	codeStr, err := ioutil.ReadFile(codeFile)
+	if err != nil {
+		return fmt.Errorf("read code:%s error:%s", codeFile, err)
+	}
+	code, err := common.HexToBytes(strings.TrimSpace(string(codeStr)))
+	if err != nil {
+		return fmt.Errorf("code hex decode error:%s", err)
+	}
+	param := &upgrade.UpgradeParam{
+		Contract:    contractAddr,
+		Code:        code,
+		NeedStorage: ctx.Bool(utils.GetFlagName(utils.ContractStorageFlag)),
+		Name:        ctx.String(utils.GetFlagName(utils.ContractNameFlag)),
+		Version:     ctx.String(utils.GetFlagName(utils.ContractVersionFlag)),
+		Author:      ctx.String(utils.GetFlagName(utils.ContractAuthorFlag)),
+		Email:       ctx.String(utils.GetFlagName(utils.ContractEmailFlag)),
+		Description: ctx.String(utils.GetFlagName(utils.ContractDescFlag)),
+		KeyNo:       ctx.Uint64(utils.GetFlagName(utils.ContractAdminKeyFlag)),
+	}
+	gasPrice := ctx.Uint64(utils.GetFlagName(utils.TransactionGasPriceFlag))
+	gasLimit := ctx.Uint64(utils.GetFlagName(utils.TransactionGasLimitFlag))
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("get signer account error:%s", err)
+	}
+	txHash, err := utils.UpgradeContract(gasPrice, gasLimit, signer, param)
+	if err != nil {
+		return fmt.Errorf("UpgradeContract error:%s", err)
+	}
+	PrintInfoMsg("Upgrade contract:")
+	PrintInfoMsg("  Contract Address:%s", contractAddr.ToHexString())
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+	return nil
+}
+
+func getContractHistory(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if !ctx.IsSet(utils.GetFlagName(utils.ContractAddrFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.ContractAddrFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	data, err := utils.GetContractHistory(ctx.String(utils.GetFlagName(utils.ContractAddrFlag)))
+	if err != nil {
+		return fmt.Errorf("GetContractHistory error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func readContractAbiFile(abiFile string) ([]byte, error) {
+	abiData, err := ioutil.ReadFile(abiFile)
+	if err != nil {
+		return nil, fmt.Errorf("read abi:%s error:%s", abiFile, err)
+	}
+	if _, err = utils.NewNeovmContractAbi(abiData); err != nil {
+		return nil, fmt.Errorf("invalid abi:%s error:%s", abiFile, err)
+	}
+	return abiData, nil
+}
+
 func invokeCodeContract(ctx *cli.Context) error {
 	SetRpcPort(ctx)
 	if !ctx.IsSet(utils.GetFlagName(utils.ContractCodeFileFlag)) {
@@ -288,9 +561,36 @@ func invokeContract(ctx *cli.Context) error {
 	}
 
 	paramsStr := ctx.String(utils.GetFlagName(utils.ContractParamsFlag))
-	params, err := utils.ParseParams(paramsStr)
-	if err != nil {
-		return fmt.Errorf("parseParams error:%s", err)
+	rawReturnTypes := ctx.String(utils.GetFlagName(utils.ContractReturnTypeFlag))
+	var params []interface{}
+	if method := ctx.String(utils.GetFlagName(utils.ContractMethodFlag)); method != "" {
+		contractAbi, err := utils.GetContractAbi(contractAddr)
+		if err != nil {
+			return fmt.Errorf("GetContractAbi error:%s", err)
+		}
+		if contractAbi == nil {
+			return fmt.Errorf("contract:%s has no abi registered, please use typed --params", contractAddrStr)
+		}
+		funcAbi := contractAbi.GetFunc(method)
+		if funcAbi == nil {
+			return fmt.Errorf("method:%s not found in contract abi", method)
+		}
+		var rawParams []string
+		if paramsStr != "" {
+			rawParams = strings.Split(paramsStr, ",")
+		}
+		params, err = utils.ParseNeovmFunc(rawParams, funcAbi)
+		if err != nil {
+			return fmt.Errorf("parse params of method:%s error:%s", method, err)
+		}
+		if rawReturnTypes == "" {
+			rawReturnTypes = utils.ReturnTypeOfNeovmFunc(funcAbi)
+		}
+	} else {
+		params, err = utils.ParseParams(paramsStr)
+		if err != nil {
+			return fmt.Errorf("parseParams error:%s", err)
+		}
 	}
 
 	paramData, _ := json.Marshal(params)
@@ -307,7 +607,6 @@ func invokeContract(ctx *cli.Context) error {
 		PrintInfoMsg("Contract invoke successfully")
 		PrintInfoMsg("  Gas limit:%d", preResult.Gas)
 
-		rawReturnTypes := ctx.String(utils.GetFlagName(utils.ContractReturnTypeFlag))
 		if rawReturnTypes == "" {
 			PrintInfoMsg("  Return:%s (raw value)", preResult.Result)
 			return nil
@@ -350,3 +649,88 @@ func invokeContract(ctx *cli.Context) error {
 	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
 	return nil
 }
+
+func debugContract(ctx *cli.Context) error {
+	log.InitLog(log.ErrorLog)
+	_, err := SetOnyxChainConfig(ctx)
+	if err != nil {
+		PrintErrorMsg("SetOnyxChainConfig error:%s", err)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	dbg := debugger.NewDebugger(os.Stdin, os.Stdout)
+	bpStr := ctx.String(utils.GetFlagName(utils.ContractBreakpointFlag))
+	if bpStr != "" {
+		for _, str := range strings.Split(bpStr, ",") {
+			bp, err := debugger.ParseBreakpoint(strings.TrimSpace(str))
+			if err != nil {
+				return err
+			}
+			dbg.AddBreakpoint(bp)
+		}
+		dbg.SetMode(debugger.MODE_CONTINUE)
+	}
+
+	err = initLocalLedger()
+	if err != nil {
+		return err
+	}
+	defer ledger.DefLedger.Close()
+
+	tx, err := getDebugTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		PrintErrorMsg("Missing %s, %s or %s argument.", utils.RawTransactionFlag.Name, utils.TransactionHashFlag.Name, utils.ContractCodeFileFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	txHash := tx.Hash()
+	PrintInfoMsg("Debug transaction:%s at height:%d", txHash.ToHexString(), ledger.DefLedger.GetCurrentBlockHeight())
+	result, err := ledger.DefLedger.DebugExecuteContract(tx, dbg)
+	if err != nil {
+		return fmt.Errorf("execute error:%s", err)
+	}
+	PrintInfoMsg("Execute successfully")
+	PrintInfoMsg("  Gas consumed:%d", result.Gas)
+	PrintInfoMsg("  Return:%v (raw value)", result.Result)
+	return nil
+}
+
+func getDebugTransaction(ctx *cli.Context) (*types.Transaction, error) {
+	if rawTx := ctx.String(utils.GetFlagName(utils.RawTransactionFlag)); rawTx != "" {
+		data, err := hex.DecodeString(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("decode raw tx error:%s", err)
+		}
+		return types.TransactionFromRawBytes(data)
+	}
+	if hashStr := ctx.String(utils.GetFlagName(utils.TransactionHashFlag)); hashStr != "" {
+		txHash, err := common.Uint256FromHexString(hashStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx hash:%s", hashStr)
+		}
+		tx, err := ledger.DefLedger.GetTransaction(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("get transaction:%s error:%s", hashStr, err)
+		}
+		return tx, nil
+	}
+	if codeFile := ctx.String(utils.GetFlagName(utils.ContractCodeFileFlag)); codeFile != "" {
+		codeStr, err := ioutil.ReadFile(codeFile)
+		if err != nil {
+			return nil, fmt.Errorf("read code:%s error:%s", codeFile, err)
+		}
+		code, err := common.HexToBytes(strings.TrimSpace(string(codeStr)))
+		if err != nil {
+			return nil, fmt.Errorf("contract code convert hex to bytes error:%s", err)
+		}
+		mutable, err := httpcom.NewSmartContractTransaction(0, 0, code)
+		if err != nil {
+			return nil, err
+		}
+		return mutable.IntoImmutable()
+	}
+	return nil, nil
+}
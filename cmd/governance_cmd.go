@@ -0,0 +1,259 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/urfave/cli"
+)
+
+var governanceTxFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.TransactionGasPriceFlag,
+	utils.TransactionGasLimitFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.SignerFlag,
+}
+
+var GovernanceCommand = cli.Command{
+	Name:        "governance",
+	Usage:       "Manage governance proposals",
+	Description: "Governance commands can list, create and vote on proposals. Stakers vote with their stake, and passed proposals are executed automatically at execute height.",
+	Subcommands: []cli.Command{
+		{
+			Action:    governanceList,
+			Name:      "list",
+			Usage:     "List all proposals",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:    governanceShow,
+			Name:      "show",
+			Usage:     "Show proposal",
+			ArgsUsage: "<id>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:      governanceCreate,
+			Name:        "create",
+			Usage:       "Create proposal",
+			ArgsUsage:   "[<key>=<value>...]",
+			Description: "Create proposal with stake of account. Params of global params contract are set by <key>=<value> arguments if kind is param, otherwise serialized params are set by --payload",
+			Flags: append([]cli.Flag{
+				utils.GovProposalKindFlag,
+				utils.GovProposalPayloadFlag,
+				utils.GovProposalDescFlag,
+				utils.GovExecuteHeightFlag,
+			}, governanceTxFlags...),
+		},
+		{
+			Action:      governanceVote,
+			Name:        "vote",
+			Usage:       "Vote for proposal",
+			ArgsUsage:   "<id> <yes|no>",
+			Description: "Vote for proposal with stake of account in voting period. Vote again to change the vote",
+			Flags:       governanceTxFlags,
+		},
+	},
+}
+
+func getProposalIdArg(ctx *cli.Context) (uint64, error) {
+	if ctx.NArg() < 1 {
+		return 0, fmt.Errorf("missing proposal id argument")
+	}
+	id, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid proposal id:%s", ctx.Args().First())
+	}
+	return id, nil
+}
+
+//invokeGovernance sends transaction of governance contract signed by account
+func invokeGovernance(ctx *cli.Context, signer *account.Account, method string, param interface{}) (string, error) {
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return "", err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	txHash, err := utils.InvokeGovernanceContract(gasPrice, gasLimit, signer, method, []interface{}{param})
+	if err != nil {
+		return "", fmt.Errorf("invoke %s error:%s", method, err)
+	}
+	return txHash, nil
+}
+
+func governanceList(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	data, err := utils.GetProposals()
+	if err != nil {
+		return fmt.Errorf("GetProposals error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func governanceShow(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getProposalIdArg(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetProposal(id)
+	if err != nil {
+		return fmt.Errorf("GetProposal error:%s", err)
+	}
+	if string(data) == "null" {
+		return fmt.Errorf("proposal:%d does not exist", id)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func governanceCreate(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	kindName := ctx.String(utils.GetFlagName(utils.GovProposalKindFlag))
+	kind, ok := utils.ParseProposalKind(kindName)
+	if !ok {
+		return fmt.Errorf("invalid proposal kind:%s", kindName)
+	}
+	if !ctx.IsSet(utils.GetFlagName(utils.GovExecuteHeightFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.GovExecuteHeightFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	var payload []byte
+	var err error
+	if kind == governance.PROPOSAL_GLOBAL_PARAM {
+		payload, err = buildParamsPayload(ctx.Args())
+	} else {
+		payload, err = hex.DecodeString(ctx.String(utils.GetFlagName(utils.GovProposalPayloadFlag)))
+		if err == nil && len(payload) == 0 {
+			err = fmt.Errorf("missing %s argument", utils.GovProposalPayloadFlag.Name)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid payload:%s", err)
+	}
+
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &governance.CreateProposalParam{
+		Proposer:      signer.Address,
+		Kind:          kind,
+		Payload:       payload,
+		Description:   ctx.String(utils.GetFlagName(utils.GovProposalDescFlag)),
+		ExecuteHeight: uint32(ctx.Uint(utils.GetFlagName(utils.GovExecuteHeightFlag))),
+	}
+	txHash, err := invokeGovernance(ctx, signer, governance.CREATE_PROPOSAL, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Create proposal:")
+	PrintInfoMsg("  Proposer:%s", signer.Address.ToBase58())
+	PrintInfoMsg("  Kind:%s", governance.ProposalKindNames[kind])
+	PrintInfoMsg("  ExecuteHeight:%d", param.ExecuteHeight)
+	printGovernanceTxHash(txHash)
+	return nil
+}
+
+//buildParamsPayload serializes <key>=<value> arguments as params of global params contract
+func buildParamsPayload(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing <key>=<value> arguments")
+	}
+	params := global_params.Params{}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid param:%s", arg)
+		}
+		params.SetParam(global_params.Param{Key: kv[0], Value: kv[1]})
+	}
+	bf := new(bytes.Buffer)
+	if err := params.Serialize(bf); err != nil {
+		return nil, err
+	}
+	return bf.Bytes(), nil
+}
+
+func governanceVote(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getProposalIdArg(ctx)
+	if err != nil {
+		return err
+	}
+	var approve bool
+	switch strings.ToLower(ctx.Args().Get(1)) {
+	case "yes":
+		approve = true
+	case "no":
+		approve = false
+	default:
+		PrintErrorMsg("Missing yes or no argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &governance.VoteProposalParam{
+		Voter:   signer.Address,
+		Id:      id,
+		Approve: approve,
+	}
+	txHash, err := invokeGovernance(ctx, signer, governance.VOTE_PROPOSAL, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Vote proposal:%d", id)
+	PrintInfoMsg("  Voter:%s", signer.Address.ToBase58())
+	PrintInfoMsg("  Approve:%t", approve)
+	printGovernanceTxHash(txHash)
+	return nil
+}
+
+func printGovernanceTxHash(txHash string) {
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+}
@@ -0,0 +1,679 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/claimrecord"
+	"github.com/urfave/cli"
+)
+
+var idTxFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.TransactionGasPriceFlag,
+	utils.TransactionGasLimitFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.SignerFlag,
+	utils.IDFlag,
+}
+
+var IdCommand = cli.Command{
+	Name:        "id",
+	Usage:       "Manage ONX ID",
+	Description: "ONX ID management commands can register ID, add or remove public keys and attributes, recover ID by recovery group, and commit or revoke claims about ID.",
+	Subcommands: []cli.Command{
+		{
+			Action:      idRegister,
+			Name:        "register",
+			Usage:       "Register ONX ID with public key of account",
+			ArgsUsage:   " ",
+			Description: "Register ONX ID with public key of account. If ID does not specified, a new ID will be generated",
+			Flags:       idTxFlags,
+		},
+		{
+			Action:    idAddKey,
+			Name:      "addkey",
+			Usage:     "Add public key to ONX ID",
+			ArgsUsage: " ",
+			Flags:     append([]cli.Flag{utils.IDPubKeyFlag}, idTxFlags...),
+		},
+		{
+			Action:    idRemoveKey,
+			Name:      "removekey",
+			Usage:     "Remove public key from ONX ID",
+			ArgsUsage: " ",
+			Flags:     append([]cli.Flag{utils.IDPubKeyFlag}, idTxFlags...),
+		},
+		{
+			Action:    idAddAttribute,
+			Name:      "addattr",
+			Usage:     "Add or update attribute of ONX ID",
+			ArgsUsage: "<key> <value>",
+			Flags:     append([]cli.Flag{utils.IDAttrTypeFlag}, idTxFlags...),
+		},
+		{
+			Action:    idRemoveAttribute,
+			Name:      "removeattr",
+			Usage:     "Remove attribute of ONX ID",
+			ArgsUsage: "<key>",
+			Flags:     idTxFlags,
+		},
+		{
+			Action:      idSetRecovery,
+			Name:        "setrecovery",
+			Usage:       "Set M-of-N recovery group of ONX ID",
+			ArgsUsage:   " ",
+			Description: "Set M-of-N recovery group of ONX ID. Recovery takes effect delay seconds after M members approved, in which time owner can cancel it",
+			Flags: append([]cli.Flag{
+				utils.IDRecoveryThresholdFlag,
+				utils.IDRecoveryMembersFlag,
+				utils.IDRecoveryDelayFlag,
+			}, idTxFlags...),
+		},
+		{
+			Action:      idRequestRecovery,
+			Name:        "requestrecovery",
+			Usage:       "Request to replace public keys of ONX ID with new public key",
+			ArgsUsage:   " ",
+			Description: "Request to replace public keys of ONX ID with new public key. Account should be member of recovery group, and the request counts as its approval",
+			Flags:       append([]cli.Flag{utils.IDPubKeyFlag}, idTxFlags...),
+		},
+		{
+			Action:    idApproveRecovery,
+			Name:      "approverecovery",
+			Usage:     "Approve pending recovery of ONX ID",
+			ArgsUsage: " ",
+			Flags:     idTxFlags,
+		},
+		{
+			Action:      idExecuteRecovery,
+			Name:        "executerecovery",
+			Usage:       "Execute approved recovery of ONX ID after delay",
+			ArgsUsage:   " ",
+			Description: "Execute approved recovery of ONX ID after delay. The new public key will be added and all other public keys will be revoked",
+			Flags:       idTxFlags,
+		},
+		{
+			Action:    idCancelRecovery,
+			Name:      "cancelrecovery",
+			Usage:     "Cancel pending recovery of ONX ID by owner",
+			ArgsUsage: " ",
+			Flags:     idTxFlags,
+		},
+		{
+			Action:    idShow,
+			Name:      "show",
+			Usage:     "Show public keys, attributes and recovery of ONX ID",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.IDFlag,
+			},
+		},
+		{
+			Action:    idDDO,
+			Name:      "ddo",
+			Usage:     "Show DDO of ONX ID as W3C DID document",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.IDFlag,
+			},
+		},
+		{
+			Action:      idCommitClaim,
+			Name:        "commitclaim",
+			Usage:       "Commit claim of ONX ID about subject",
+			ArgsUsage:   "<claimId>",
+			Description: "Commit claim of issuer ONX ID about subject ONX ID to claim record. Claim id is hash of credential in hex, which is kept off chain by subject.",
+			Flags: append([]cli.Flag{
+				utils.IDClaimSubjectFlag,
+				utils.IDClaimExpirationFlag,
+				utils.IDKeyNoFlag,
+			}, idTxFlags...),
+		},
+		{
+			Action:    idRevokeClaim,
+			Name:      "revokeclaim",
+			Usage:     "Revoke claim by its issuer",
+			ArgsUsage: "<claimId>",
+			Flags:     append([]cli.Flag{utils.IDKeyNoFlag}, idTxFlags...),
+		},
+		{
+			Action:    idShowClaim,
+			Name:      "claim",
+			Usage:     "Show claim of issuer and its status",
+			ArgsUsage: "<claimId>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.IDFlag,
+			},
+		},
+	},
+}
+
+func getIDArg(ctx *cli.Context) (string, error) {
+	id := ctx.String(utils.GetFlagName(utils.IDFlag))
+	if id == "" {
+		return "", fmt.Errorf("missing %s argument", utils.IDFlag.Name)
+	}
+	if !account.VerifyID(id) {
+		return "", fmt.Errorf("invalid ONX ID:%s", id)
+	}
+	return id, nil
+}
+
+func getIDPubKeyArg(ctx *cli.Context) ([]byte, error) {
+	pkStr := ctx.String(utils.GetFlagName(utils.IDPubKeyFlag))
+	if pkStr == "" {
+		return nil, fmt.Errorf("missing %s argument", utils.IDPubKeyFlag.Name)
+	}
+	data, err := hex.DecodeString(pkStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key:%s", err)
+	}
+	_, err = keypair.DeserializePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key:%s", err)
+	}
+	return data, nil
+}
+
+//invokeIDContract sends transaction of ONX ID contract signed by account
+func invokeIDContract(ctx *cli.Context, signer *account.Account, method string, param interface{}) (string, error) {
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return "", err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	txHash, err := utils.InvokeOnxIDContract(gasPrice, gasLimit, signer, method, []interface{}{param})
+	if err != nil {
+		return "", fmt.Errorf("invoke %s error:%s", method, err)
+	}
+	return txHash, nil
+}
+
+func printIDTxHash(txHash string) {
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+}
+
+func idRegister(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	var err error
+	id := ctx.String(utils.GetFlagName(utils.IDFlag))
+	if id == "" {
+		id, err = account.GenerateID()
+		if err != nil {
+			return err
+		}
+	} else if !account.VerifyID(id) {
+		return fmt.Errorf("invalid ONX ID:%s", id)
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID     []byte
+		PubKey []byte
+	}{[]byte(id), keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, "regIDWithPublicKey", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Register ONX ID:")
+	PrintInfoMsg("  ID:%s", id)
+	PrintInfoMsg("  Owner:%s", signer.Address.ToBase58())
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idChangeKey(ctx *cli.Context, method string) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	pk, err := getIDPubKeyArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID       []byte
+		PubKey   []byte
+		Operator []byte
+	}{[]byte(id), pk, keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, method, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("ONX ID:%s", id)
+	PrintInfoMsg("  %s:%s", method, hex.EncodeToString(pk))
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idAddKey(ctx *cli.Context) error {
+	return idChangeKey(ctx, "addKey")
+}
+
+func idRemoveKey(ctx *cli.Context) error {
+	return idChangeKey(ctx, "removeKey")
+}
+
+func idAddAttribute(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 2 {
+		PrintErrorMsg("Missing key or value argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	attr := &utils.IDAttributeParam{
+		Key:   []byte(ctx.Args().Get(0)),
+		Type:  []byte(ctx.String(utils.GetFlagName(utils.IDAttrTypeFlag))),
+		Value: []byte(ctx.Args().Get(1)),
+	}
+	param := struct {
+		ID       []byte
+		Attrs    []*utils.IDAttributeParam
+		Operator []byte
+	}{[]byte(id), []*utils.IDAttributeParam{attr}, keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, "addAttributes", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("ONX ID:%s", id)
+	PrintInfoMsg("  Add attribute:%s", attr.Key)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idRemoveAttribute(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing key argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	key := ctx.Args().First()
+	param := struct {
+		ID       []byte
+		Key      []byte
+		Operator []byte
+	}{[]byte(id), []byte(key), keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, "removeAttribute", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("ONX ID:%s", id)
+	PrintInfoMsg("  Remove attribute:%s", key)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idSetRecovery(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	membersStr := ctx.String(utils.GetFlagName(utils.IDRecoveryMembersFlag))
+	if membersStr == "" {
+		PrintErrorMsg("Missing %s argument.", utils.IDRecoveryMembersFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	group := &utils.IDRecoveryGroupParam{
+		Threshold: uint32(ctx.Uint(utils.GetFlagName(utils.IDRecoveryThresholdFlag))),
+		Delay:     uint32(ctx.Uint(utils.GetFlagName(utils.IDRecoveryDelayFlag))),
+	}
+	for _, member := range strings.Split(membersStr, ",") {
+		addr, err := common.AddressFromBase58(strings.TrimSpace(member))
+		if err != nil {
+			return fmt.Errorf("invalid member address:%s", member)
+		}
+		group.Members = append(group.Members, addr)
+	}
+	if group.Threshold == 0 || int(group.Threshold) > len(group.Members) {
+		return fmt.Errorf("invalid %s:%d, should be in [1, %d]", utils.IDRecoveryThresholdFlag.Name, group.Threshold, len(group.Members))
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID       []byte
+		Group    *utils.IDRecoveryGroupParam
+		Operator []byte
+	}{[]byte(id), group, keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, "setRecoveryGroup", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Set recovery group of ONX ID:%s", id)
+	PrintInfoMsg("  Threshold:%d/%d", group.Threshold, len(group.Members))
+	PrintInfoMsg("  Delay:%ds", group.Delay)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idRequestRecovery(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	pk, err := getIDPubKeyArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID       []byte
+		PubKey   []byte
+		Operator common.Address
+	}{[]byte(id), pk, signer.Address}
+	txHash, err := invokeIDContract(ctx, signer, "requestRecovery", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Request recovery of ONX ID:%s", id)
+	PrintInfoMsg("  New public key:%s", hex.EncodeToString(pk))
+	printIDTxHash(txHash)
+	return nil
+}
+
+//idMemberRecovery invokes recovery method which only takes ID and member address
+func idMemberRecovery(ctx *cli.Context, method string) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID       []byte
+		Operator common.Address
+	}{[]byte(id), signer.Address}
+	txHash, err := invokeIDContract(ctx, signer, method, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("ONX ID:%s", id)
+	PrintInfoMsg("  %s by:%s", method, signer.Address.ToBase58())
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idApproveRecovery(ctx *cli.Context) error {
+	return idMemberRecovery(ctx, "approveRecovery")
+}
+
+func idExecuteRecovery(ctx *cli.Context) error {
+	return idMemberRecovery(ctx, "executeRecovery")
+}
+
+func idCancelRecovery(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := struct {
+		ID       []byte
+		Operator []byte
+	}{[]byte(id), keypair.SerializePublicKey(signer.PublicKey)}
+	txHash, err := invokeIDContract(ctx, signer, "cancelRecovery", param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Cancel recovery of ONX ID:%s", id)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idShow(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	pks, err := utils.GetIDPublicKeys(id)
+	if err != nil {
+		return err
+	}
+	if len(pks) == 0 {
+		return fmt.Errorf("ONX ID:%s not registered", id)
+	}
+	attrs, err := utils.GetIDAttributes(id)
+	if err != nil {
+		return err
+	}
+	group, err := utils.GetIDRecoveryGroup(id)
+	if err != nil {
+		return err
+	}
+	pending, err := utils.GetIDPendingRecovery(id)
+	if err != nil {
+		return err
+	}
+
+	PrintInfoMsg("ONX ID:%s", id)
+	PrintInfoMsg("  Public keys:")
+	for _, pk := range pks {
+		PrintInfoMsg("    %s#keys-%d:%s", id, pk.Index, pk.PubKey)
+	}
+	if len(attrs) > 0 {
+		PrintInfoMsg("  Attributes:")
+		for _, attr := range attrs {
+			PrintInfoMsg("    %s(%s):%s", attr.Key, attr.Type, attr.Value)
+		}
+	}
+	if group != nil {
+		PrintInfoMsg("  Recovery group:")
+		PrintInfoMsg("    Threshold:%d/%d", group.Threshold, len(group.Members))
+		PrintInfoMsg("    Members:%s", strings.Join(group.Members, ","))
+		PrintInfoMsg("    Delay:%ds", group.Delay)
+	}
+	if pending != nil {
+		PrintInfoMsg("  Pending recovery:")
+		PrintInfoMsg("    New public key:%s", pending.NewPubKey)
+		PrintInfoMsg("    Approvals:%s", strings.Join(pending.Approvals, ","))
+		PrintInfoMsg("    Request time:%s", time.Unix(int64(pending.RequestTime), 0))
+		if pending.EffectiveTime == 0 {
+			PrintInfoMsg("    Effective time:waiting for approvals")
+		} else {
+			PrintInfoMsg("    Effective time:%s", time.Unix(int64(pending.EffectiveTime), 0))
+		}
+	}
+	return nil
+}
+
+func idDDO(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	id, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetDDO(id)
+	if err != nil {
+		return err
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func getClaimIdArg(ctx *cli.Context) ([]byte, error) {
+	if ctx.NArg() < 1 {
+		return nil, fmt.Errorf("missing claim id argument")
+	}
+	claimId, err := hex.DecodeString(ctx.Args().First())
+	if err != nil || len(claimId) == 0 {
+		return nil, fmt.Errorf("invalid claim id:%s", ctx.Args().First())
+	}
+	return claimId, nil
+}
+
+//invokeClaimContract sends transaction of claim record signed by account
+func invokeClaimContract(ctx *cli.Context, signer *account.Account, method string, param interface{}) (string, error) {
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return "", err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	txHash, err := utils.InvokeClaimContract(gasPrice, gasLimit, signer, method, param)
+	if err != nil {
+		return "", fmt.Errorf("invoke %s error:%s", method, err)
+	}
+	return txHash, nil
+}
+
+func idCommitClaim(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	claimId, err := getClaimIdArg(ctx)
+	if err != nil {
+		return err
+	}
+	issuer, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	subject := ctx.String(utils.GetFlagName(utils.IDClaimSubjectFlag))
+	if !account.VerifyID(subject) {
+		return fmt.Errorf("invalid subject ONX ID:%s", subject)
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &claimrecord.CommitParam{
+		ClaimId:     claimId,
+		Issuer:      []byte(issuer),
+		IssuerKeyNo: uint32(ctx.Uint(utils.GetFlagName(utils.IDKeyNoFlag))),
+		Subject:     []byte(subject),
+		Expiration:  uint32(ctx.Uint(utils.GetFlagName(utils.IDClaimExpirationFlag))),
+	}
+	txHash, err := invokeClaimContract(ctx, signer, claimrecord.COMMIT_NAME, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Commit claim:%x", claimId)
+	PrintInfoMsg("  Issuer:%s", issuer)
+	PrintInfoMsg("  Subject:%s", subject)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idRevokeClaim(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	claimId, err := getClaimIdArg(ctx)
+	if err != nil {
+		return err
+	}
+	issuer, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &claimrecord.RevokeParam{
+		ClaimId: claimId,
+		Issuer:  []byte(issuer),
+		KeyNo:   uint32(ctx.Uint(utils.GetFlagName(utils.IDKeyNoFlag))),
+	}
+	txHash, err := invokeClaimContract(ctx, signer, claimrecord.REVOKE_NAME, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Revoke claim:%x", claimId)
+	PrintInfoMsg("  Issuer:%s", issuer)
+	printIDTxHash(txHash)
+	return nil
+}
+
+func idShowClaim(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	claimId, err := getClaimIdArg(ctx)
+	if err != nil {
+		return err
+	}
+	issuer, err := getIDArg(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetClaim(issuer, hex.EncodeToString(claimId))
+	if err != nil {
+		return err
+	}
+	PrintJsonData(data)
+	return nil
+}
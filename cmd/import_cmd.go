@@ -59,24 +59,9 @@ func importBlocks(ctx *cli.Context) error {
 		cli.ShowSubcommandHelp(ctx)
 		return nil
 	}
-	dbDir := utils.GetStoreDirPath(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
-
-	ledger.DefLedger, err = ledger.NewLedger(dbDir)
-	if err != nil {
-		return fmt.Errorf("NewLedger error:%s", err)
-	}
-	bookKeepers, err := config.DefConfig.GetBookkeepers()
+	err = initLocalLedger()
 	if err != nil {
-		return fmt.Errorf("GetBookkeepers error:%s", err)
-	}
-	genesisConfig := config.DefConfig.Genesis
-	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, genesisConfig)
-	if err != nil {
-		return fmt.Errorf("BuildGenesisBlock error %s", err)
-	}
-	err = ledger.DefLedger.Init(bookKeepers, genesisBlock)
-	if err != nil {
-		return fmt.Errorf("init ledger error:%s", err)
+		return err
 	}
 
 	dataDir := ctx.String(utils.GetFlagName(utils.DataDirFlag))
@@ -167,3 +152,27 @@ func importBlocks(ctx *cli.Context) error {
 	PrintInfoMsg("Import block completed, current block height:%d.", ledger.DefLedger.GetCurrentBlockHeight())
 	return nil
 }
+
+//initLocalLedger opens ledger in data dir of config as ledger.DefLedger
+func initLocalLedger() error {
+	dbDir := utils.GetStoreDirPath(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+	var err error
+	ledger.DefLedger, err = ledger.NewLedger(dbDir)
+	if err != nil {
+		return fmt.Errorf("NewLedger error:%s", err)
+	}
+	bookKeepers, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		return fmt.Errorf("GetBookkeepers error:%s", err)
+	}
+	genesisConfig := config.DefConfig.Genesis
+	genesisBlock, err := genesis.BuildGenesisBlock(bookKeepers, genesisConfig)
+	if err != nil {
+		return fmt.Errorf("BuildGenesisBlock error %s", err)
+	}
+	err = ledger.DefLedger.Init(bookKeepers, genesisBlock)
+	if err != nil {
+		return fmt.Errorf("init ledger error:%s", err)
+	}
+	return nil
+}
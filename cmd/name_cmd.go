@@ -0,0 +1,330 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
+	"github.com/urfave/cli"
+)
+
+var nameTxFlags = []cli.Flag{
+	utils.RPCPortFlag,
+	utils.TransactionGasPriceFlag,
+	utils.TransactionGasLimitFlag,
+	utils.WalletFileFlag,
+	utils.AccountAddressFlag,
+	utils.SignerFlag,
+}
+
+var NameCommand = cli.Command{
+	Name:        "name",
+	Usage:       "Manage names of name service",
+	Description: "Name commands can resolve names such as alice.onx to address, ONX ID and contract, and let account register names with oxg fee, set their subnames and records, and set reverse name of account. Names can be used in place of address by '--to' of 'asset transfer'.",
+	Subcommands: []cli.Command{
+		{
+			Action:    nameResolve,
+			Name:      "resolve",
+			Usage:     "Show owner, expiration and records of name",
+			ArgsUsage: "<name>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:    nameReverse,
+			Name:      "reverse",
+			Usage:     "Show reverse name of account",
+			ArgsUsage: "<address|label|index>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.WalletFileFlag,
+			},
+		},
+		{
+			Action:      nameRegister,
+			Name:        "register",
+			Usage:       "Register name to account for years",
+			ArgsUsage:   "<name>",
+			Description: "Register name directly under top level domain, such as alice.onx, to account, which pays oxg fee for each year. Name resolves to address of account until its records are set.",
+			Flags:       append([]cli.Flag{utils.NameYearsFlag}, nameTxFlags...),
+		},
+		{
+			Action:    nameRenew,
+			Name:      "renew",
+			Usage:     "Renew name for years, paid by account",
+			ArgsUsage: "<name>",
+			Flags:     append([]cli.Flag{utils.NameYearsFlag}, nameTxFlags...),
+		},
+		{
+			Action:      nameSubname,
+			Name:        "subname",
+			Usage:       "Set subname to owner by owner of parent name",
+			ArgsUsage:   "<name> <owner>",
+			Description: "Set subname, such as pay.alice.onx, to owner. Account should own parent name, and subname set before is replaced.",
+			Flags:       nameTxFlags,
+		},
+		{
+			Action:    nameTransfer,
+			Name:      "transfer",
+			Usage:     "Transfer name to new owner",
+			ArgsUsage: "<name> <address|label|index>",
+			Flags:     nameTxFlags,
+		},
+		{
+			Action:      nameRecords,
+			Name:        "records",
+			Usage:       "Set address, ONX ID and contract which name resolves to",
+			ArgsUsage:   "<name>",
+			Description: "Set records of name owned by account. Records not set by flags are kept, and empty value clears record.",
+			Flags:       append([]cli.Flag{utils.NameAddressFlag, utils.IDFlag, utils.NameContractFlag}, nameTxFlags...),
+		},
+		{
+			Action:      nameSetReverse,
+			Name:        "setreverse",
+			Usage:       "Set reverse name of account",
+			ArgsUsage:   "[<name>]",
+			Description: "Set name resolved to address of account as its reverse name, or clear reverse name if name is omitted",
+			Flags:       nameTxFlags,
+		},
+	},
+}
+
+func getNameArg(ctx *cli.Context, count int) (string, error) {
+	if ctx.NArg() < count {
+		return "", fmt.Errorf("missing arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	name := strings.ToLower(ctx.Args().First())
+	if !nameservice.IsName(name) {
+		return "", fmt.Errorf("invalid name:%s", name)
+	}
+	return name, nil
+}
+
+func nameYears(ctx *cli.Context) uint32 {
+	return uint32(ctx.Uint(utils.GetFlagName(utils.NameYearsFlag)))
+}
+
+func parseNameAddress(address string, ctx *cli.Context) (common.Address, error) {
+	addr, err := cmdcom.ParseAddress(address, ctx)
+	if err != nil {
+		return common.ADDRESS_EMPTY, err
+	}
+	return common.AddressFromBase58(addr)
+}
+
+//invokeName sends transaction of name service signed by account
+func invokeName(ctx *cli.Context, signer *account.Account, method string, param interface{}) error {
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+	txHash, err := utils.InvokeNameContract(gasPrice, gasLimit, signer, method, param)
+	if err != nil {
+		return fmt.Errorf("invoke %s error:%s", method, err)
+	}
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './onyxchain info status %s' to query transaction status.", txHash)
+	return nil
+}
+
+func nameResolve(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 1)
+	if err != nil {
+		return err
+	}
+	info, err := utils.ResolveName(name)
+	if err != nil {
+		return fmt.Errorf("ResolveName error:%s", err)
+	}
+	PrintJsonObject(info)
+	return nil
+}
+
+func nameReverse(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("missing arguments, usage: %s", ctx.Command.ArgsUsage)
+	}
+	address, err := cmdcom.ParseAddress(ctx.Args().First(), ctx)
+	if err != nil {
+		return err
+	}
+	info, err := utils.ReverseName(address)
+	if err != nil {
+		return fmt.Errorf("ReverseName error:%s", err)
+	}
+	PrintJsonObject(info)
+	return nil
+}
+
+func nameRegister(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 1)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &nameservice.RegisterParam{Name: name, Owner: signer.Address, Years: nameYears(ctx)}
+	PrintInfoMsg("Register name %s to %s for %d years:", name, signer.Address.ToBase58(), param.Years)
+	PrintInfoMsg("  Fee:%s OXG", utils.FormatOxg(nameservice.REGISTER_FEE*uint64(param.Years)))
+	return invokeName(ctx, signer, nameservice.REGISTER_NAME, param)
+}
+
+func nameRenew(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 1)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &nameservice.RenewParam{Name: name, Payer: signer.Address, Years: nameYears(ctx)}
+	PrintInfoMsg("Renew name %s for %d years:", name, param.Years)
+	PrintInfoMsg("  Fee:%s OXG", utils.FormatOxg(nameservice.REGISTER_FEE*uint64(param.Years)))
+	return invokeName(ctx, signer, nameservice.RENEW_NAME, param)
+}
+
+func nameSubname(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 2)
+	if err != nil {
+		return err
+	}
+	owner, err := parseNameAddress(ctx.Args().Get(1), ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	parts := strings.SplitN(name, ".", 2)
+	param := &nameservice.SetSubnameParam{Parent: parts[1], Label: parts[0], Owner: owner}
+	PrintInfoMsg("Set subname %s to %s:", name, owner.ToBase58())
+	return invokeName(ctx, signer, nameservice.SET_SUBNAME_NAME, param)
+}
+
+func nameTransfer(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 2)
+	if err != nil {
+		return err
+	}
+	to, err := parseNameAddress(ctx.Args().Get(1), ctx)
+	if err != nil {
+		return err
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Transfer name %s to %s:", name, to.ToBase58())
+	return invokeName(ctx, signer, nameservice.TRANSFER_NAME, &nameservice.TransferParam{Name: name, To: to})
+}
+
+func nameRecords(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name, err := getNameArg(ctx, 1)
+	if err != nil {
+		return err
+	}
+	info, err := utils.ResolveName(name)
+	if err != nil {
+		return fmt.Errorf("ResolveName error:%s", err)
+	}
+	if ctx.IsSet(utils.GetFlagName(utils.NameAddressFlag)) {
+		info.Address = ctx.String(utils.GetFlagName(utils.NameAddressFlag))
+		if info.Address != "" {
+			if info.Address, err = cmdcom.ParseAddress(info.Address, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	if ctx.IsSet(utils.GetFlagName(utils.IDFlag)) {
+		info.OnxID = ctx.String(utils.GetFlagName(utils.IDFlag))
+		if info.OnxID != "" && !account.VerifyID(info.OnxID) {
+			return fmt.Errorf("invalid ONX ID:%s", info.OnxID)
+		}
+	}
+	if ctx.IsSet(utils.GetFlagName(utils.NameContractFlag)) {
+		info.Contract = ctx.String(utils.GetFlagName(utils.NameContractFlag))
+	}
+	param := &nameservice.SetRecordsParam{Name: name, OnxID: []byte(info.OnxID)}
+	if info.Address != "" {
+		if param.Address, err = common.AddressFromBase58(info.Address); err != nil {
+			return fmt.Errorf("invalid address:%s", info.Address)
+		}
+	}
+	if info.Contract != "" {
+		if param.Contract, err = common.AddressFromHexString(info.Contract); err != nil {
+			return fmt.Errorf("invalid contract address:%s", info.Contract)
+		}
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Set records of name %s:", name)
+	PrintInfoMsg("  Address:%s", info.Address)
+	PrintInfoMsg("  OnxID:%s", info.OnxID)
+	PrintInfoMsg("  Contract:%s", info.Contract)
+	return invokeName(ctx, signer, nameservice.SET_RECORDS_NAME, param)
+}
+
+func nameSetReverse(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	name := ""
+	if ctx.NArg() > 0 {
+		var err error
+		if name, err = getNameArg(ctx, 1); err != nil {
+			return err
+		}
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &nameservice.SetReverseParam{Address: signer.Address, Name: name}
+	if name == "" {
+		PrintInfoMsg("Clear reverse name of %s:", signer.Address.ToBase58())
+	} else {
+		PrintInfoMsg("Set reverse name of %s to %s:", signer.Address.ToBase58(), name)
+	}
+	return invokeName(ctx, signer, nameservice.SET_REVERSE_NAME, param)
+}
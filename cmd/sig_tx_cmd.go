@@ -50,6 +50,7 @@ var MultiSigTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 		utils.WalletFileFlag,
+		utils.SignerFlag,
 		utils.AccountMultiMFlag,
 		utils.AccountMultiPubKeyFlag,
 		utils.AccountAddressFlag,
@@ -67,6 +68,7 @@ var SigTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 		utils.WalletFileFlag,
+		utils.SignerFlag,
 		utils.AccountAddressFlag,
 		utils.SendTxFlag,
 		utils.PrepareExecTransactionFlag,
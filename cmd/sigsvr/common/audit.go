@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+//AuditRecord is one line of audit log, written for every request of sig server
+type AuditRecord struct {
+	Time       string `json:"time"`
+	RemoteAddr string `json:"remote_addr"`
+	Caller     string `json:"caller"`
+	Qid        string `json:"qid"`
+	Method     string `json:"method"`
+	Account    string `json:"account"`
+	TxHash     string `json:"tx_hash,omitempty"`
+	ErrorCode  int    `json:"error_code"`
+}
+
+//AuditLog appends audit records to file in json lines format
+type AuditLog struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func NewAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log:%s error:%s", path, err)
+	}
+	return &AuditLog{file: file}, nil
+}
+
+func (this *AuditLog) Write(record *AuditRecord) error {
+	if record.Time == "" {
+		record.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	_, err = this.file.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+	return this.file.Sync()
+}
+
+func (this *AuditLog) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.file.Close()
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const AUTH_HEADER = "Authorization"
+const AUTH_SCHEME = "Bearer "
+
+//CliCaller is a client allowed to call sig server
+type CliCaller struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+//CallerAuth authenticates callers of sig server by bearer token
+type CallerAuth struct {
+	callers []*CliCaller
+}
+
+//NewCallerAuth loads callers from json file, like {"callers":[{"name":"treasury","token":"..."}]}
+func NewCallerAuth(file string) (*CallerAuth, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read file:%s error:%s", file, err)
+	}
+	cfg := &struct {
+		Callers []*CliCaller `json:"callers"`
+	}{}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error:%s", err)
+	}
+	names := make(map[string]bool)
+	for _, caller := range cfg.Callers {
+		if caller.Name == "" || caller.Token == "" {
+			return nil, fmt.Errorf("caller name and token cannot empty")
+		}
+		if names[caller.Name] {
+			return nil, fmt.Errorf("duplicate caller:%s", caller.Name)
+		}
+		names[caller.Name] = true
+	}
+	return &CallerAuth{callers: cfg.Callers}, nil
+}
+
+//Authenticate returns the caller name of the token
+func (this *CallerAuth) Authenticate(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	for _, caller := range this.callers {
+		if subtle.ConstantTimeCompare([]byte(caller.Token), []byte(token)) == 1 {
+			return caller.Name, true
+		}
+	}
+	return "", false
+}
+
+//AuthenticateRequest authenticates http request by bearer token in Authorization header
+func (this *CallerAuth) AuthenticateRequest(r *http.Request) (string, bool) {
+	header := r.Header.Get(AUTH_HEADER)
+	if !strings.HasPrefix(header, AUTH_SCHEME) {
+		return "", false
+	}
+	return this.Authenticate(strings.TrimSpace(header[len(AUTH_SCHEME):]))
+}
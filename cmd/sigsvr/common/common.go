@@ -21,31 +21,56 @@ package common
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
+
 	"github.com/OnyxPay/OnyxChain-legacy/account"
 	"github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/store"
 )
 
 var DefWalletStore *store.WalletStore
 
+//external signer accounts, keyed by base58 address. Password is not required for these accounts
+var extAccounts = make(map[string]*account.Account)
+var extAccountsLock sync.RWMutex
+
+//AddExternalAccount registers account of external signer, should be called before server start
+func AddExternalAccount(acc *account.Account) {
+	extAccountsLock.Lock()
+	defer extAccountsLock.Unlock()
+	extAccounts[acc.Address.ToBase58()] = acc
+}
+
+func getExternalAccount(address string) *account.Account {
+	extAccountsLock.RLock()
+	defer extAccountsLock.RUnlock()
+	return extAccounts[address]
+}
+
 type CliRpcRequest struct {
 	Qid     string          `json:"qid"`
 	Params  json.RawMessage `json:"params"`
 	Account string          `json:"account"`
 	Pwd     string          `json:"pwd"`
 	Method  string          `json:"method"`
+	Caller  string          `json:"-"` //name of authenticated caller
+	TxHash  string          `json:"-"` //hash of handled transaction, for audit log
 }
 
 func (this *CliRpcRequest) GetAccount() (*account.Account, error) {
 	var acc *account.Account
 	var err error
 
+	if this.Account == "" {
+		return nil, fmt.Errorf("account cannot empty")
+	}
+	acc = getExternalAccount(this.Account)
+	if acc != nil {
+		return acc, nil
+	}
 	pwd := []byte(this.Pwd)
 	if this.Pwd == "" {
 		return nil, fmt.Errorf("pwd cannot empty")
 	}
-	if this.Account == "" {
-		return nil, fmt.Errorf("account cannot empty")
-	}
 	acc, err = DefWalletStore.GetAccountByAddress(this.Account, pwd)
 	if err != nil {
 		return nil, err
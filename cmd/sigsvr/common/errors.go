@@ -29,6 +29,10 @@ const (
 	CLIERR_ABI_NOT_FOUND       = 1007
 	CLIERR_ABI_UNMATCH         = 1008
 	CLIERR_DUPLICATE_SIG       = 1009
+	CLIERR_UNAUTHORIZED        = 1010
+	CLIERR_TX_NOT_FOUND        = 1011
+	CLIERR_TX_EXIST            = 1012
+	CLIERR_INVALID_SIG         = 1013
 	CLIERR_INTERNAL_ERR        = 900
 )
 
@@ -42,6 +46,10 @@ var RPCErrorDesc = map[int]string{
 	CLIERR_ABI_NOT_FOUND:       "abi not found",
 	CLIERR_ABI_UNMATCH:         "abi unmatch",
 	CLIERR_DUPLICATE_SIG:       "Duplicate sig",
+	CLIERR_UNAUTHORIZED:        "unauthorized",
+	CLIERR_TX_NOT_FOUND:        "tx not found",
+	CLIERR_TX_EXIST:            "tx already exist",
+	CLIERR_INVALID_SIG:         "invalid sig",
 	CLIERR_INTERNAL_ERR:        "internal error",
 }
 
@@ -0,0 +1,351 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/constants"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+)
+
+const DEFAULT_MULTISIG_TX_EXPIRE = 24 * time.Hour
+
+var DefMultiSigTxPool = NewMultiSigTxPool(DEFAULT_MULTISIG_TX_EXPIRE)
+
+//MultiSigSigner records a co-signature of pending multi-sig transaction
+type MultiSigSigner struct {
+	PubKey string `json:"pub_key"`
+	Caller string `json:"caller"`
+	Time   int64  `json:"time"`
+}
+
+//PendingMultiSigTx is a transaction waiting for M-of-N signatures
+type PendingMultiSigTx struct {
+	TxHash     common.Uint256
+	M          uint16
+	PubKeys    []keypair.PublicKey
+	Tx         *types.MutableTransaction
+	Creator    string
+	CreateTime int64
+	Signers    []*MultiSigSigner
+}
+
+//clone returns a snapshot of pending transaction which can be read without lock of pool
+func (this *PendingMultiSigTx) clone() *PendingMultiSigTx {
+	tx := *this.Tx
+	tx.Sigs = make([]types.Sig, 0, len(this.Tx.Sigs))
+	for _, sig := range this.Tx.Sigs {
+		sigData := make([][]byte, len(sig.SigData))
+		copy(sigData, sig.SigData)
+		tx.Sigs = append(tx.Sigs, types.Sig{PubKeys: sig.PubKeys, M: sig.M, SigData: sigData})
+	}
+	signers := make([]*MultiSigSigner, len(this.Signers))
+	copy(signers, this.Signers)
+	pending := *this
+	pending.Tx = &tx
+	pending.Signers = signers
+	return &pending
+}
+
+//sigIndex returns index of the multi-sig entry in transaction sigs, or -1
+func (this *PendingMultiSigTx) sigIndex() int {
+	for i, sig := range this.Tx.Sigs {
+		if sig.M == this.M && pubKeysEqual(sig.PubKeys, this.PubKeys) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *PendingMultiSigTx) hasSigned(pk keypair.PublicKey) bool {
+	pkStr := pubKeyToHex(pk)
+	for _, signer := range this.Signers {
+		if signer.PubKey == pkStr {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *PendingMultiSigTx) isMember(pk keypair.PublicKey) bool {
+	for _, key := range this.PubKeys {
+		if keypair.ComparePublicKey(key, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+//MissingPubKeys returns the public keys which haven't signed yet
+func (this *PendingMultiSigTx) MissingPubKeys() []string {
+	missing := make([]string, 0)
+	for _, pk := range this.PubKeys {
+		if !this.hasSigned(pk) {
+			missing = append(missing, pubKeyToHex(pk))
+		}
+	}
+	return missing
+}
+
+//IsComplete returns whether M signatures are collected
+func (this *PendingMultiSigTx) IsComplete() bool {
+	return len(this.Signers) >= int(this.M)
+}
+
+//SignedTx returns the hex string of transaction with collected signatures
+func (this *PendingMultiSigTx) SignedTx() (string, error) {
+	tx, err := this.Tx.IntoImmutable()
+	if err != nil {
+		return "", err
+	}
+	sink := common.NewZeroCopySink(nil)
+	err = tx.Serialization(sink)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sink.Bytes()), nil
+}
+
+func (this *PendingMultiSigTx) addSig(pk keypair.PublicKey, sigData []byte, caller string) error {
+	if !this.isMember(pk) {
+		return fmt.Errorf("public key is not in multi-sig public keys")
+	}
+	if this.hasSigned(pk) {
+		return fmt.Errorf("public key has already signed")
+	}
+	if this.IsComplete() {
+		return fmt.Errorf("transaction already has %d signatures", this.M)
+	}
+	err := signature.Verify(pk, this.TxHash.ToArray(), sigData)
+	if err != nil {
+		return fmt.Errorf("verify signature error:%s", err)
+	}
+	index := this.sigIndex()
+	if index < 0 {
+		this.Tx.Sigs = append(this.Tx.Sigs, types.Sig{
+			PubKeys: this.PubKeys,
+			M:       this.M,
+			SigData: [][]byte{sigData},
+		})
+	} else {
+		this.Tx.Sigs[index].SigData = append(this.Tx.Sigs[index].SigData, sigData)
+	}
+	this.Signers = append(this.Signers, &MultiSigSigner{
+		PubKey: pubKeyToHex(pk),
+		Caller: caller,
+		Time:   time.Now().Unix(),
+	})
+	return nil
+}
+
+//MultiSigTxPool holds pending multi-sig transactions keyed by transaction hash
+type MultiSigTxPool struct {
+	lock   sync.RWMutex
+	expire time.Duration
+	txs    map[common.Uint256]*PendingMultiSigTx
+}
+
+func NewMultiSigTxPool(expire time.Duration) *MultiSigTxPool {
+	return &MultiSigTxPool{
+		expire: expire,
+		txs:    make(map[common.Uint256]*PendingMultiSigTx),
+	}
+}
+
+//Add puts transaction into pool. If payer of transaction is empty, multi-sig address will be used as payer.
+//Signatures of the multi-sig public keys already in the transaction are kept.
+func (this *MultiSigTxPool) Add(mutTx *types.MutableTransaction, m uint16, pubKeys []keypair.PublicKey, creator string) (*PendingMultiSigTx, error) {
+	pkSize := len(pubKeys)
+	if m == 0 || int(m) > pkSize || pkSize <= 1 || pkSize > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return nil, fmt.Errorf("invalid m:%d and public key size:%d", m, pkSize)
+	}
+	if mutTx.Payer == common.ADDRESS_EMPTY {
+		payer, err := types.AddressFromMultiPubKeys(pubKeys, int(m))
+		if err != nil {
+			return nil, fmt.Errorf("AddressFromMultiPubKeys error:%s", err)
+		}
+		mutTx.Payer = payer
+	}
+	pending := &PendingMultiSigTx{
+		TxHash:     mutTx.Hash(),
+		M:          m,
+		PubKeys:    pubKeys,
+		Tx:         mutTx,
+		Creator:    creator,
+		CreateTime: time.Now().Unix(),
+		Signers:    make([]*MultiSigSigner, 0),
+	}
+	index := pending.sigIndex()
+	if index >= 0 {
+		sigData := mutTx.Sigs[index].SigData
+		mutTx.Sigs = append(mutTx.Sigs[:index], mutTx.Sigs[index+1:]...)
+		for _, data := range sigData {
+			signer, err := findSigner(pubKeys, pending.TxHash.ToArray(), data)
+			if err != nil {
+				return nil, err
+			}
+			err = pending.addSig(signer, data, creator)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.clean()
+	if _, ok := this.txs[pending.TxHash]; ok {
+		return nil, fmt.Errorf("transaction %s already exist", pending.TxHash.ToHexString())
+	}
+	this.txs[pending.TxHash] = pending
+	return pending.clone(), nil
+}
+
+//AddSig adds the co-signature of public key to the pending transaction
+func (this *MultiSigTxPool) AddSig(txHash common.Uint256, pk keypair.PublicKey, sigData []byte, caller string) (*PendingMultiSigTx, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	pending, ok := this.txs[txHash]
+	if !ok {
+		return nil, fmt.Errorf("cannot find transaction %s", txHash.ToHexString())
+	}
+	err := pending.addSig(pk, sigData, caller)
+	if err != nil {
+		return nil, err
+	}
+	return pending.clone(), nil
+}
+
+//Sign signs the pending transaction by signer
+func (this *MultiSigTxPool) Sign(txHash common.Uint256, signer signature.Signer, caller string) (*PendingMultiSigTx, error) {
+	sigData, err := signature.Sign(signer, txHash.ToArray())
+	if err != nil {
+		return nil, fmt.Errorf("sign error:%s", err)
+	}
+	return this.AddSig(txHash, signer.PubKey(), sigData, caller)
+}
+
+func (this *MultiSigTxPool) Get(txHash common.Uint256) *PendingMultiSigTx {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	pending, ok := this.txs[txHash]
+	if !ok {
+		return nil
+	}
+	return pending.clone()
+}
+
+func (this *MultiSigTxPool) Remove(txHash common.Uint256) bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	_, ok := this.txs[txHash]
+	delete(this.txs, txHash)
+	return ok
+}
+
+//RemoveByCreator removes pending transaction if it is created by creator. It returns false if transaction
+//is not found, and error if transaction is created by another caller.
+func (this *MultiSigTxPool) RemoveByCreator(txHash common.Uint256, creator string) (bool, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	pending, ok := this.txs[txHash]
+	if !ok {
+		return false, nil
+	}
+	if pending.Creator != creator {
+		return true, fmt.Errorf("transaction %s is created by another caller", txHash.ToHexString())
+	}
+	delete(this.txs, txHash)
+	return true, nil
+}
+
+//List returns all pending transactions ordered by create time
+func (this *MultiSigTxPool) List() []*PendingMultiSigTx {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.clean()
+	txs := make([]*PendingMultiSigTx, 0, len(this.txs))
+	for _, tx := range this.txs {
+		txs = append(txs, tx.clone())
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].CreateTime < txs[j].CreateTime
+	})
+	return txs
+}
+
+//clean removes expired transactions, must be called with lock held
+func (this *MultiSigTxPool) clean() {
+	if this.expire == 0 {
+		return
+	}
+	deadline := time.Now().Add(-this.expire).Unix()
+	for hash, tx := range this.txs {
+		if tx.CreateTime < deadline {
+			delete(this.txs, hash)
+		}
+	}
+}
+
+func findSigner(pubKeys []keypair.PublicKey, data, sigData []byte) (keypair.PublicKey, error) {
+	for _, pk := range pubKeys {
+		if signature.Verify(pk, data, sigData) == nil {
+			return pk, nil
+		}
+	}
+	return nil, fmt.Errorf("signature doesn't match any multi-sig public key")
+}
+
+func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
+	if len(pks1) != len(pks2) {
+		return false
+	}
+	size := len(pks1)
+	if size == 0 {
+		return true
+	}
+	pkstr1 := make([]string, 0, size)
+	for _, pk := range pks1 {
+		pkstr1 = append(pkstr1, pubKeyToHex(pk))
+	}
+	pkstr2 := make([]string, 0, size)
+	for _, pk := range pks2 {
+		pkstr2 = append(pkstr2, pubKeyToHex(pk))
+	}
+	sort.Strings(pkstr1)
+	sort.Strings(pkstr2)
+	for i := 0; i < size; i++ {
+		if pkstr1[i] != pkstr2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func pubKeyToHex(pk keypair.PublicKey) string {
+	return hex.EncodeToString(keypair.SerializePublicKey(pk))
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sigsvr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account/extsigner"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/urfave/cli"
+)
+
+//SetCliRpcSecurity enables caller authentication and audit log of DefCliRpcSvr by cli flags
+func SetCliRpcSecurity(ctx *cli.Context) error {
+	authFile := ctx.String(utils.GetFlagName(utils.CliAuthFileFlag))
+	if authFile != "" {
+		auth, err := common.NewCallerAuth(authFile)
+		if err != nil {
+			return fmt.Errorf("NewCallerAuth error:%s", err)
+		}
+		DefCliRpcSvr.SetCallerAuth(auth)
+		log.Infof("Sig server caller auth enabled, config:%s", authFile)
+	}
+	auditFile := ctx.String(utils.GetFlagName(utils.CliAuditLogFlag))
+	if auditFile != "" {
+		auditLog, err := common.NewAuditLog(auditFile)
+		if err != nil {
+			return fmt.Errorf("NewAuditLog error:%s", err)
+		}
+		DefCliRpcSvr.SetAuditLog(auditLog)
+		log.Infof("Sig server audit log:%s", auditFile)
+	}
+	return nil
+}
+
+//SetExternalSigners registers accounts of external signers by cli flag, multiple uri are separated by ','
+func SetExternalSigners(ctx *cli.Context) error {
+	signers := ctx.String(utils.GetFlagName(utils.SignerFlag))
+	if signers == "" {
+		return nil
+	}
+	for _, uri := range strings.Split(signers, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+		acc, err := extsigner.OpenAccount(uri)
+		if err != nil {
+			return fmt.Errorf("open external signer:%s error:%s", uri, err)
+		}
+		common.AddExternalAccount(acc)
+		log.Infof("Sig server external signer account:%s", acc.Address.ToBase58())
+	}
+	return nil
+}
@@ -27,7 +27,14 @@ func init() {
 	DefCliRpcSvr.RegHandler("sigrawtx", handlers.SigRawTransaction)
 	DefCliRpcSvr.RegHandler("sigmutilrawtx", handlers.SigMutilRawTransaction)
 	DefCliRpcSvr.RegHandler("sigtransfertx", handlers.SigTransferTransaction)
+	DefCliRpcSvr.RegHandler("sigtransfermultitx", handlers.SigTransferMultiTransaction)
 	DefCliRpcSvr.RegHandler("signeovminvoketx", handlers.SigNeoVMInvokeTx)
 	DefCliRpcSvr.RegHandler("signeovminvokeabitx", handlers.SigNeoVMInvokeAbiTx)
 	DefCliRpcSvr.RegHandler("signativeinvoketx", handlers.SigNativeInvokeTx)
+	DefCliRpcSvr.RegHandler("createmultisigtx", handlers.CreateMultiSigTx)
+	DefCliRpcSvr.RegHandler("cosignmultisigtx", handlers.CoSignMultiSigTx)
+	DefCliRpcSvr.RegHandler("addmultisigsig", handlers.AddMultiSigSig)
+	DefCliRpcSvr.RegHandler("getmultisigtx", handlers.GetMultiSigTx)
+	DefCliRpcSvr.RegHandler("listmultisigtx", handlers.ListMultiSigTx)
+	DefCliRpcSvr.RegHandler("removemultisigtx", handlers.RemoveMultiSigTx)
 }
@@ -0,0 +1,313 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	clisvrcom "github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+)
+
+type CreateMultiSigTxReq struct {
+	RawTx   string   `json:"raw_tx"`
+	M       int      `json:"m"`
+	PubKeys []string `json:"pub_keys"`
+	Sign    bool     `json:"sign"` //sign by request account after creating
+}
+
+type MultiSigTxHashReq struct {
+	TxHash string `json:"tx_hash"`
+}
+
+type AddMultiSigSigReq struct {
+	TxHash  string `json:"tx_hash"`
+	PubKey  string `json:"pub_key"`
+	SigData string `json:"sig_data"`
+}
+
+type MultiSigTxRsp struct {
+	TxHash         string                      `json:"tx_hash"`
+	M              int                         `json:"m"`
+	PubKeys        []string                    `json:"pub_keys"`
+	Signers        []*clisvrcom.MultiSigSigner `json:"signers"`
+	MissingPubKeys []string                    `json:"missing_pub_keys"`
+	Complete       bool                        `json:"complete"`
+	Creator        string                      `json:"creator"`
+	CreateTime     int64                       `json:"create_time"`
+	SignedTx       string                      `json:"signed_tx,omitempty"` //only set when complete
+}
+
+func newMultiSigTxRsp(pending *clisvrcom.PendingMultiSigTx) (*MultiSigTxRsp, error) {
+	pubKeys := make([]string, 0, len(pending.PubKeys))
+	for _, pk := range pending.PubKeys {
+		pubKeys = append(pubKeys, hex.EncodeToString(keypair.SerializePublicKey(pk)))
+	}
+	rsp := &MultiSigTxRsp{
+		TxHash:         pending.TxHash.ToHexString(),
+		M:              int(pending.M),
+		PubKeys:        pubKeys,
+		Signers:        pending.Signers,
+		MissingPubKeys: pending.MissingPubKeys(),
+		Complete:       pending.IsComplete(),
+		Creator:        pending.Creator,
+		CreateTime:     pending.CreateTime,
+	}
+	if rsp.Complete {
+		signedTx, err := pending.SignedTx()
+		if err != nil {
+			return nil, err
+		}
+		rsp.SignedTx = signedTx
+	}
+	return rsp, nil
+}
+
+func parseMultiSigTxHash(req *clisvrcom.CliRpcRequest) (common.Uint256, error) {
+	hashReq := &MultiSigTxHashReq{}
+	err := json.Unmarshal(req.Params, hashReq)
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	txHash, err := common.Uint256FromHexString(hashReq.TxHash)
+	if err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	req.TxHash = hashReq.TxHash
+	return txHash, nil
+}
+
+func parsePubKeys(pubKeys []string) ([]keypair.PublicKey, error) {
+	pks := make([]keypair.PublicKey, 0, len(pubKeys))
+	for _, pkStr := range pubKeys {
+		pkData, err := hex.DecodeString(pkStr)
+		if err != nil {
+			return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+		}
+		pk, err := keypair.DeserializePublicKey(pkData)
+		if err != nil {
+			return nil, fmt.Errorf("keypair.DeserializePublicKey error:%s", err)
+		}
+		pks = append(pks, pk)
+	}
+	return pks, nil
+}
+
+//CreateMultiSigTx puts a transaction into the pending multi-sig pool, waiting for co-signatures
+func CreateMultiSigTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &CreateMultiSigTxReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	pubKeys, err := parsePubKeys(rawReq.PubKeys)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigTx parsePubKeys error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	rawTxData, err := hex.DecodeString(rawReq.RawTx)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigTx hex.DecodeString error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	tmpTx, err := types.TransactionFromRawBytes(rawTxData)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigTx TransactionFromRawBytes error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	mutTx, err := tmpTx.IntoMutable()
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigTx IntoMutable error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	if rawReq.M <= 0 || rawReq.M > len(pubKeys) {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	pending, err := clisvrcom.DefMultiSigTxPool.Add(mutTx, uint16(rawReq.M), pubKeys, req.Caller)
+	if err != nil {
+		log.Infof("Cli Qid:%s CreateMultiSigTx Add error:%s", req.Qid, err)
+		if clisvrcom.DefMultiSigTxPool.Get(mutTx.Hash()) != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_TX_EXIST
+		} else {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		}
+		return
+	}
+	txHash := pending.TxHash
+	req.TxHash = txHash.ToHexString()
+	if rawReq.Sign {
+		signer, err := req.GetAccount()
+		if err != nil {
+			log.Infof("Cli Qid:%s CreateMultiSigTx GetAccount:%s", req.Qid, err)
+			clisvrcom.DefMultiSigTxPool.Remove(txHash)
+			resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+			return
+		}
+		pending, err = clisvrcom.DefMultiSigTxPool.Sign(txHash, signer, req.Caller)
+		if err != nil {
+			log.Infof("Cli Qid:%s CreateMultiSigTx Sign error:%s", req.Qid, err)
+			clisvrcom.DefMultiSigTxPool.Remove(txHash)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_SIG
+			resp.ErrorInfo = err.Error()
+			return
+		}
+	}
+	writeMultiSigTxRsp(req, resp, pending)
+}
+
+//CoSignMultiSigTx signs a pending multi-sig transaction by request account
+func CoSignMultiSigTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	txHash, err := parseMultiSigTxHash(req)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if clisvrcom.DefMultiSigTxPool.Get(txHash) == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_TX_NOT_FOUND
+		return
+	}
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s CoSignMultiSigTx GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	pending, err := clisvrcom.DefMultiSigTxPool.Sign(txHash, signer, req.Caller)
+	if err != nil {
+		log.Infof("Cli Qid:%s CoSignMultiSigTx Sign error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_SIG
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	writeMultiSigTxRsp(req, resp, pending)
+}
+
+//AddMultiSigSig adds a signature made outside of sig server to a pending multi-sig transaction
+func AddMultiSigSig(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &AddMultiSigSigReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	txHash, err := common.Uint256FromHexString(rawReq.TxHash)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	req.TxHash = rawReq.TxHash
+	pks, err := parsePubKeys([]string{rawReq.PubKey})
+	if err != nil {
+		log.Infof("Cli Qid:%s AddMultiSigSig parsePubKeys error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	sigData, err := hex.DecodeString(rawReq.SigData)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if clisvrcom.DefMultiSigTxPool.Get(txHash) == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_TX_NOT_FOUND
+		return
+	}
+	pending, err := clisvrcom.DefMultiSigTxPool.AddSig(txHash, pks[0], sigData, req.Caller)
+	if err != nil {
+		log.Infof("Cli Qid:%s AddMultiSigSig AddSig error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_SIG
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	writeMultiSigTxRsp(req, resp, pending)
+}
+
+//GetMultiSigTx returns the signing status of a pending multi-sig transaction
+func GetMultiSigTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	txHash, err := parseMultiSigTxHash(req)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	pending := clisvrcom.DefMultiSigTxPool.Get(txHash)
+	if pending == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_TX_NOT_FOUND
+		return
+	}
+	writeMultiSigTxRsp(req, resp, pending)
+}
+
+//ListMultiSigTx returns all pending multi-sig transactions
+func ListMultiSigTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	txs := clisvrcom.DefMultiSigTxPool.List()
+	rsp := make([]*MultiSigTxRsp, 0, len(txs))
+	for _, pending := range txs {
+		txRsp, err := newMultiSigTxRsp(pending)
+		if err != nil {
+			log.Infof("Cli Qid:%s ListMultiSigTx tx:%s error:%s", req.Qid, pending.TxHash.ToHexString(), err)
+			resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+			return
+		}
+		rsp = append(rsp, txRsp)
+	}
+	resp.Result = rsp
+}
+
+//RemoveMultiSigTx drops a pending multi-sig transaction, only by the caller who created it
+func RemoveMultiSigTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	txHash, err := parseMultiSigTxHash(req)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	found, err := clisvrcom.DefMultiSigTxPool.RemoveByCreator(txHash, req.Caller)
+	if !found {
+		resp.ErrorCode = clisvrcom.CLIERR_TX_NOT_FOUND
+		return
+	}
+	if err != nil {
+		log.Infof("Cli Qid:%s RemoveMultiSigTx error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_UNAUTHORIZED
+		resp.ErrorInfo = err.Error()
+		return
+	}
+}
+
+func writeMultiSigTxRsp(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse, pending *clisvrcom.PendingMultiSigTx) {
+	rsp, err := newMultiSigTxRsp(pending)
+	if err != nil {
+		log.Infof("Cli Qid:%s %s tx:%s error:%s", req.Qid, req.Method, pending.TxHash.ToHexString(), err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	if rsp.Complete {
+		log.Infof("Cli Qid:%s multi-sig tx:%s complete", req.Qid, rsp.TxHash)
+	}
+	resp.Result = rsp
+}
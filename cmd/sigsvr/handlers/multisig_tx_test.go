@@ -0,0 +1,227 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-crypto/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	clisvrcom "github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiSigTxWorkflow(t *testing.T) {
+	accs := make([]*account.AccountData, 0, 3)
+	pubKeys := make([]keypair.PublicKey, 0, 3)
+	pkStrs := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		acc, err := clisvrcom.DefWalletStore.NewAccountData(keypair.PK_ECDSA, keypair.P256, signature.SHA256withECDSA, pwd)
+		assert.Nil(t, err)
+		clisvrcom.DefWalletStore.AddAccountData(acc)
+		accs = append(accs, acc)
+		pkData, _ := hex.DecodeString(acc.PubKey)
+		pk, _ := keypair.DeserializePublicKey(pkData)
+		pubKeys = append(pubKeys, pk)
+		pkStrs = append(pkStrs, acc.PubKey)
+	}
+	m := 2
+	fromAddr, err := types.AddressFromMultiPubKeys(pubKeys, m)
+	assert.Nil(t, err)
+	tx, err := utils.TransferTx(0, 0, "onyx", fromAddr.ToBase58(), accs[0].Address, 10)
+	assert.Nil(t, err)
+	immut, err := tx.IntoImmutable()
+	assert.Nil(t, err)
+	sink := common.ZeroCopySink{}
+	err = immut.Serialization(&sink)
+	assert.Nil(t, err)
+
+	createReq := &CreateMultiSigTxReq{
+		RawTx:   hex.EncodeToString(sink.Bytes()),
+		M:       m,
+		PubKeys: pkStrs,
+		Sign:    true,
+	}
+	data, _ := json.Marshal(createReq)
+	req := &clisvrcom.CliRpcRequest{
+		Qid:     "t",
+		Method:  "createmultisigtx",
+		Params:  data,
+		Account: accs[0].Address,
+		Pwd:     string(pwd),
+		Caller:  "treasury-a",
+	}
+	resp := &clisvrcom.CliRpcResponse{}
+	CreateMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	rsp := resp.Result.(*MultiSigTxRsp)
+	assert.False(t, rsp.Complete)
+	assert.Equal(t, 2, len(rsp.MissingPubKeys))
+	assert.Equal(t, "", rsp.SignedTx)
+
+	//create twice
+	resp = &clisvrcom.CliRpcResponse{}
+	createReq.Sign = false
+	req.Params, _ = json.Marshal(createReq)
+	CreateMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_TX_EXIST, resp.ErrorCode)
+
+	hashData, _ := json.Marshal(&MultiSigTxHashReq{TxHash: rsp.TxHash})
+	//first signer cannot sign again
+	resp = &clisvrcom.CliRpcResponse{}
+	req.Params = hashData
+	CoSignMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_SIG, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	req.Account = accs[2].Address
+	req.Caller = "treasury-c"
+	CoSignMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	rsp = resp.Result.(*MultiSigTxRsp)
+	assert.True(t, rsp.Complete)
+	assert.Equal(t, []string{accs[1].PubKey}, rsp.MissingPubKeys)
+	assert.Equal(t, "treasury-c", rsp.Signers[1].Caller)
+
+	txData, err := hex.DecodeString(rsp.SignedTx)
+	assert.Nil(t, err)
+	signedTx, err := types.TransactionFromRawBytes(txData)
+	assert.Nil(t, err)
+	txHash := signedTx.Hash()
+	assert.Equal(t, rsp.TxHash, txHash.ToHexString())
+	mutTx, err := signedTx.IntoMutable()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mutTx.Sigs))
+	assert.Equal(t, 2, len(mutTx.Sigs[0].SigData))
+
+	//more signatures than m are rejected
+	resp = &clisvrcom.CliRpcResponse{}
+	req.Account = accs[1].Address
+	CoSignMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_SIG, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	GetMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+
+	//only creator can remove
+	resp = &clisvrcom.CliRpcResponse{}
+	RemoveMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_UNAUTHORIZED, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	req.Caller = "treasury-a"
+	RemoveMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+
+	resp = &clisvrcom.CliRpcResponse{}
+	GetMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_TX_NOT_FOUND, resp.ErrorCode)
+}
+
+func TestAddMultiSigSig(t *testing.T) {
+	acc1 := account.NewAccount("")
+	acc2 := account.NewAccount("")
+	pubKeys := []keypair.PublicKey{acc1.PublicKey, acc2.PublicKey}
+	fromAddr, err := types.AddressFromMultiPubKeys(pubKeys, 2)
+	assert.Nil(t, err)
+	tx, err := utils.TransferTx(0, 0, "onyx", fromAddr.ToBase58(), acc1.Address.ToBase58(), 20)
+	assert.Nil(t, err)
+
+	pending, err := clisvrcom.DefMultiSigTxPool.Add(tx, 2, pubKeys, "")
+	assert.Nil(t, err)
+	defer clisvrcom.DefMultiSigTxPool.Remove(pending.TxHash)
+
+	sigData, err := utils.Sign(pending.TxHash.ToArray(), acc2)
+	assert.Nil(t, err)
+	addReq := &AddMultiSigSigReq{
+		TxHash:  pending.TxHash.ToHexString(),
+		PubKey:  hex.EncodeToString(keypair.SerializePublicKey(acc2.PublicKey)),
+		SigData: hex.EncodeToString(sigData),
+	}
+	data, _ := json.Marshal(addReq)
+	req := &clisvrcom.CliRpcRequest{
+		Qid:    "t",
+		Method: "addmultisigsig",
+		Params: data,
+	}
+	resp := &clisvrcom.CliRpcResponse{}
+	AddMultiSigSig(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_OK, resp.ErrorCode)
+	rsp := resp.Result.(*MultiSigTxRsp)
+	assert.False(t, rsp.Complete)
+	assert.Equal(t, []string{hex.EncodeToString(keypair.SerializePublicKey(acc1.PublicKey))}, rsp.MissingPubKeys)
+
+	//signature of acc1 cannot be added as signature of acc2
+	sigData, err = utils.Sign(pending.TxHash.ToArray(), acc1)
+	assert.Nil(t, err)
+	addReq.SigData = hex.EncodeToString(sigData)
+	req.Params, _ = json.Marshal(addReq)
+	resp = &clisvrcom.CliRpcResponse{}
+	AddMultiSigSig(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_SIG, resp.ErrorCode)
+}
+
+func TestCreateMultiSigTxSignByNonMember(t *testing.T) {
+	acc, err := clisvrcom.DefWalletStore.NewAccountData(keypair.PK_ECDSA, keypair.P256, signature.SHA256withECDSA, pwd)
+	assert.Nil(t, err)
+	clisvrcom.DefWalletStore.AddAccountData(acc)
+	acc1 := account.NewAccount("")
+	acc2 := account.NewAccount("")
+	pubKeys := []keypair.PublicKey{acc1.PublicKey, acc2.PublicKey}
+	fromAddr, err := types.AddressFromMultiPubKeys(pubKeys, 2)
+	assert.Nil(t, err)
+	tx, err := utils.TransferTx(0, 0, "onyx", fromAddr.ToBase58(), acc1.Address.ToBase58(), 30)
+	assert.Nil(t, err)
+	immut, err := tx.IntoImmutable()
+	assert.Nil(t, err)
+	sink := common.ZeroCopySink{}
+	err = immut.Serialization(&sink)
+	assert.Nil(t, err)
+
+	createReq := &CreateMultiSigTxReq{
+		RawTx: hex.EncodeToString(sink.Bytes()),
+		M:     2,
+		PubKeys: []string{
+			hex.EncodeToString(keypair.SerializePublicKey(acc1.PublicKey)),
+			hex.EncodeToString(keypair.SerializePublicKey(acc2.PublicKey)),
+		},
+		Sign: true,
+	}
+	data, _ := json.Marshal(createReq)
+	req := &clisvrcom.CliRpcRequest{
+		Qid:     "t",
+		Method:  "createmultisigtx",
+		Params:  data,
+		Account: acc.Address,
+		Pwd:     string(pwd),
+	}
+	resp := &clisvrcom.CliRpcResponse{}
+	CreateMultiSigTx(req, resp)
+	assert.Equal(t, clisvrcom.CLIERR_INVALID_SIG, resp.ErrorCode)
+	txHash, err := common.Uint256FromHexString(req.TxHash)
+	assert.Nil(t, err)
+	assert.Nil(t, clisvrcom.DefMultiSigTxPool.Get(txHash))
+}
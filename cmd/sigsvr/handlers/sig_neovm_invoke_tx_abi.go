@@ -50,7 +50,29 @@ func SigNeoVMInvokeAbiTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcRes
 		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
 		return
 	}
-	contractAbi, err := cliutil.NewNeovmContractAbi(rawReq.ContractAbi)
+	abiData := []byte(rawReq.ContractAbi)
+	if len(abiData) == 0 || string(abiData) == "null" {
+		//Contract abi not in request, use abi registered on chain
+		contAddr, err := common.AddressFromHexString(rawReq.Address)
+		if err != nil {
+			log.Infof("Cli Qid:%s SigNeoVMInvokeAbiTx AddressParseFromBytes:%s error:%s", req.Qid, rawReq.Address, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		abiData, err = cliutil.GetContractAbiData(contAddr)
+		if err != nil {
+			log.Infof("Cli Qid:%s SigNeoVMInvokeAbiTx GetContractAbiData:%s error:%s", req.Qid, rawReq.Address, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+			resp.ErrorInfo = err.Error()
+			return
+		}
+		if abiData == nil {
+			resp.ErrorCode = clisvrcom.CLIERR_ABI_NOT_FOUND
+			resp.ErrorInfo = "contract abi not registered"
+			return
+		}
+	}
+	contractAbi, err := cliutil.NewNeovmContractAbi(abiData)
 	if err != nil {
 		resp.ErrorCode = clisvrcom.CLIERR_ABI_UNMATCH
 		resp.ErrorInfo = err.Error()
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	clisvrcom "github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+	cliutil "github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+)
+
+type TransferMultiItem struct {
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	Memo   string `json:"memo"`
+}
+
+type SigTransferMultiTransactionReq struct {
+	GasPrice  uint64               `json:"gas_price"`
+	GasLimit  uint64               `json:"gas_limit"`
+	Asset     string               `json:"asset"`
+	From      string               `json:"from"`
+	Transfers []*TransferMultiItem `json:"transfers"`
+	Payer     string               `json:"payer"`
+}
+
+//SigTransferMultiTransaction signs transferMulti transaction of transfers from one account, with memo of each transfer
+func SigTransferMultiTransaction(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &SigTransferMultiTransactionReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if len(rawReq.Transfers) == 0 || len(rawReq.Transfers) > cliutil.BATCH_TRANSFER_SIZE {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = fmt.Sprintf("count of transfers should be 1 to %d", cliutil.BATCH_TRANSFER_SIZE)
+		return
+	}
+	fromAddr, err := common.AddressFromBase58(rawReq.From)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		resp.ErrorInfo = "invalid from address"
+		return
+	}
+	states := make([]*onx.MemoState, 0, len(rawReq.Transfers))
+	for i, item := range rawReq.Transfers {
+		toAddr, err := common.AddressFromBase58(item.To)
+		if err != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			resp.ErrorInfo = fmt.Sprintf("invalid to address of transfer %d", i)
+			return
+		}
+		amount, err := strconv.ParseUint(item.Amount, 10, 64)
+		if err != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			resp.ErrorInfo = fmt.Sprintf("amount of transfer %d should be string type", i)
+			return
+		}
+		if err := onx.CheckMemo(item.Memo); err != nil {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			resp.ErrorInfo = fmt.Sprintf("memo of transfer %d error:%s", i, err)
+			return
+		}
+		states = append(states, &onx.MemoState{From: fromAddr, To: toAddr, Value: amount, Memo: item.Memo})
+	}
+	mutable, err := cliutil.TransferMultiTx(rawReq.GasPrice, rawReq.GasLimit, rawReq.Asset, states)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigTransferMultiTransaction TransferMultiTx error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	if rawReq.Payer != "" {
+		payerAddress, err := common.AddressFromBase58(rawReq.Payer)
+		if err != nil {
+			log.Infof("Cli Qid:%s SigTransferMultiTransaction AddressFromBase58 error:%s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		mutable.Payer = payerAddress
+	}
+
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s SigTransferMultiTransaction GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	if signer == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	err = cliutil.SignTransaction(signer, mutable)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigTransferMultiTransaction SignTransaction error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		log.Infof("Cli Qid:%s SigTransferMultiTransaction tx IntoInmmutable error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	sink := common.ZeroCopySink{}
+	err = tx.Serialization(&sink)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigTransferMultiTransaction tx Serialize error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &SinTransferTransactionRsp{
+		SignedTx: hex.EncodeToString(sink.Bytes()),
+	}
+}
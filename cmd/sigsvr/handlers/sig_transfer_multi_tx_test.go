@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	clisvrcom "github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+)
+
+func TestSigTransferMultiTransaction(t *testing.T) {
+	acc1 := account.NewAccount("")
+	acc2 := account.NewAccount("")
+	defAcc, err := testWallet.GetDefaultAccount(pwd)
+	if err != nil {
+		t.Errorf("GetDefaultAccount error:%s", err)
+		return
+	}
+	sigReq := &SigTransferMultiTransactionReq{
+		Asset: "oxg",
+		From:  defAcc.Address.ToBase58(),
+		Transfers: []*TransferMultiItem{
+			{To: acc1.Address.ToBase58(), Amount: "10", Memo: "INV-001"},
+			{To: acc2.Address.ToBase58(), Amount: "20", Memo: "INV-002"},
+		},
+	}
+	data, err := json.Marshal(sigReq)
+	if err != nil {
+		t.Errorf("json.Marshal SigTransferMultiTransactionReq error:%s", err)
+	}
+	req := &clisvrcom.CliRpcRequest{
+		Qid:     "t",
+		Method:  "sigtransfermultitx",
+		Params:  data,
+		Account: defAcc.Address.ToBase58(),
+		Pwd:     string(pwd),
+	}
+	rsp := &clisvrcom.CliRpcResponse{}
+	SigTransferMultiTransaction(req, rsp)
+	if rsp.ErrorCode != 0 {
+		t.Errorf("SigTransferMultiTransaction failed. ErrorCode:%d", rsp.ErrorCode)
+		return
+	}
+
+	sigReq.Transfers[1].Amount = "-1"
+	data, _ = json.Marshal(sigReq)
+	req.Params = data
+	rsp = &clisvrcom.CliRpcResponse{}
+	SigTransferMultiTransaction(req, rsp)
+	if rsp.ErrorCode != clisvrcom.CLIERR_INVALID_PARAMS {
+		t.Errorf("SigTransferMultiTransaction with invalid amount should fail")
+	}
+}
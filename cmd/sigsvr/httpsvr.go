@@ -35,6 +35,8 @@ type CliRpcServer struct {
 	handlers   map[string]func(req *common.CliRpcRequest, resp *common.CliRpcResponse)
 	httpSvr    *http.Server
 	httpSvtMux *http.ServeMux
+	auth       *common.CallerAuth
+	auditLog   *common.AuditLog
 }
 
 func NewCliRpcServer() *CliRpcServer {
@@ -65,6 +67,16 @@ func (this *CliRpcServer) RegHandler(method string, handler func(req *common.Cli
 	this.handlers[method] = handler
 }
 
+//SetCallerAuth enables authentication of callers. All requests are accepted if auth is nil
+func (this *CliRpcServer) SetCallerAuth(auth *common.CallerAuth) {
+	this.auth = auth
+}
+
+//SetAuditLog enables audit log of requests
+func (this *CliRpcServer) SetAuditLog(auditLog *common.AuditLog) {
+	this.auditLog = auditLog
+}
+
 func (this *CliRpcServer) GetHandler(method string) func(req *common.CliRpcRequest, resp *common.CliRpcResponse) {
 	handler, ok := this.handlers[method]
 	if !ok {
@@ -75,7 +87,9 @@ func (this *CliRpcServer) GetHandler(method string) func(req *common.CliRpcReque
 
 func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 	resp := &common.CliRpcResponse{}
+	req := &common.CliRpcRequest{}
 	defer func() {
+		this.audit(r, req, resp)
 		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
 		w.Header().Set("content-type", "application/json;charset=utf-8")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -101,6 +115,15 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 		resp.ErrorCode = common.CLIERR_HTTP_METHOD_INVALID
 		return
 	}
+	if this.auth != nil {
+		caller, ok := this.auth.AuthenticateRequest(r)
+		if !ok {
+			log.Warnf("CliRpcServer unauthorized request from:%s", r.RemoteAddr)
+			resp.ErrorCode = common.CLIERR_UNAUTHORIZED
+			return
+		}
+		req.Caller = caller
+	}
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Error("CliRpcServer read body error:%s", err)
@@ -110,7 +133,6 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	req := &common.CliRpcRequest{}
 	err = json.Unmarshal(data, req)
 	if err != nil {
 		log.Errorf("CliRpcServer json.Unmarshal JsonRpcRequest error:%s", err)
@@ -136,9 +158,33 @@ func (this *CliRpcServer) Handler(w http.ResponseWriter, r *http.Request) {
 	handler(req, resp)
 }
 
+func (this *CliRpcServer) audit(r *http.Request, req *common.CliRpcRequest, resp *common.CliRpcResponse) {
+	if this.auditLog == nil {
+		return
+	}
+	err := this.auditLog.Write(&common.AuditRecord{
+		RemoteAddr: r.RemoteAddr,
+		Caller:     req.Caller,
+		Qid:        req.Qid,
+		Method:     req.Method,
+		Account:    req.Account,
+		TxHash:     req.TxHash,
+		ErrorCode:  resp.ErrorCode,
+	})
+	if err != nil {
+		log.Errorf("CliRpcServer write audit log error:%s", err)
+	}
+}
+
 func (this *CliRpcServer) Close() {
 	err := this.httpSvr.Close()
 	if err != nil {
 		log.Error("httpSvr close error:%s", err)
 	}
+	if this.auditLog != nil {
+		err = this.auditLog.Close()
+		if err != nil {
+			log.Error("audit log close error:%s", err)
+		}
+	}
 }
@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	cmdcom "github.com/OnyxPay/OnyxChain-legacy/cmd/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/urfave/cli"
+)
+
+var StakeCommand = cli.Command{
+	Name:        "stake",
+	Usage:       "Manage stake authorized to peers",
+	Description: "Stake commands can show peer pool and stake positions, and authorize, unauthorize or withdraw pos of peers. Fee split to stake is withdrawn by withdrawfee.",
+	Subcommands: []cli.Command{
+		{
+			Action:    stakePeers,
+			Name:      "peers",
+			Usage:     "Show peer pool with stakes and projected fee split of next epoch",
+			ArgsUsage: " ",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:    stakeShow,
+			Name:      "show",
+			Usage:     "Show stake positions of address",
+			ArgsUsage: "<address>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+		},
+		{
+			Action:      stakeAuthorize,
+			Name:        "authorize",
+			Usage:       "Authorize pos to peers",
+			ArgsUsage:   "<peerPubkey> <pos> [<peerPubkey> <pos>...]",
+			Description: "Authorize pos to peers with onx of account. New pos takes effect in next epoch",
+			Flags:       governanceTxFlags,
+		},
+		{
+			Action:      stakeUnauthorize,
+			Name:        "unauthorize",
+			Usage:       "Unauthorize pos from peers",
+			ArgsUsage:   "<peerPubkey> <pos> [<peerPubkey> <pos>...]",
+			Description: "Unauthorize pos from peers. Pending pos is withdrawable at once, pos of consensus and candidate peers is frozen until it unfreezes in later epochs",
+			Flags:       governanceTxFlags,
+		},
+		{
+			Action:      stakeWithdraw,
+			Name:        "withdraw",
+			Usage:       "Withdraw unfrozen pos from peers",
+			ArgsUsage:   "<peerPubkey> <pos> [<peerPubkey> <pos>...]",
+			Description: "Withdraw unfrozen pos from peers, onx of pos is transferred back to account",
+			Flags:       governanceTxFlags,
+		},
+		{
+			Action:      stakeWithdrawFee,
+			Name:        "withdrawfee",
+			Usage:       "Withdraw fee split to account",
+			ArgsUsage:   " ",
+			Description: "Withdraw oxg fee split to account by peers it owns and authorized",
+			Flags:       governanceTxFlags,
+		},
+	},
+}
+
+//parsePeerPosArgs parses <peerPubkey> <pos> pairs of arguments
+func parsePeerPosArgs(ctx *cli.Context) ([]string, []uint32, error) {
+	args := ctx.Args()
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, nil, fmt.Errorf("arguments should be <peerPubkey> <pos> pairs")
+	}
+	peerPubkeyList := make([]string, 0, len(args)/2)
+	posList := make([]uint32, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		pos, err := strconv.ParseUint(args[i+1], 10, 32)
+		if err != nil || pos == 0 {
+			return nil, nil, fmt.Errorf("invalid pos:%s", args[i+1])
+		}
+		peerPubkeyList = append(peerPubkeyList, args[i])
+		posList = append(posList, uint32(pos))
+	}
+	return peerPubkeyList, posList, nil
+}
+
+func stakePeers(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	data, err := utils.GetPeerPool()
+	if err != nil {
+		return fmt.Errorf("GetPeerPool error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func stakeShow(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing address argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	data, err := utils.GetStakeInfo(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("GetStakeInfo error:%s", err)
+	}
+	PrintJsonData(data)
+	return nil
+}
+
+func stakeAuthorize(ctx *cli.Context) error {
+	return invokePeerPos(ctx, governance.AUTHORIZE_FOR_PEER, "Authorize")
+}
+
+func stakeUnauthorize(ctx *cli.Context) error {
+	return invokePeerPos(ctx, governance.UNAUTHORIZE_FOR_PEER, "Unauthorize")
+}
+
+func stakeWithdraw(ctx *cli.Context) error {
+	return invokePeerPos(ctx, governance.WITHDRAW, "Withdraw")
+}
+
+//invokePeerPos sends authorizeForPeer, unAuthorizeForPeer or withdraw transaction with pos of peers in arguments
+func invokePeerPos(ctx *cli.Context, method string, action string) error {
+	SetRpcPort(ctx)
+	peerPubkeyList, posList, err := parsePeerPosArgs(ctx)
+	if err != nil {
+		PrintErrorMsg("%s.", err)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	var param interface{}
+	if method == governance.WITHDRAW {
+		param = &governance.WithdrawParam{
+			Address:        signer.Address,
+			PeerPubkeyList: peerPubkeyList,
+			WithdrawList:   posList,
+		}
+	} else {
+		param = &governance.AuthorizeForPeerParam{
+			Address:        signer.Address,
+			PeerPubkeyList: peerPubkeyList,
+			PosList:        posList,
+		}
+	}
+	txHash, err := invokeGovernance(ctx, signer, method, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("%s pos:", action)
+	PrintInfoMsg("  Address:%s", signer.Address.ToBase58())
+	for i, peerPubkey := range peerPubkeyList {
+		PrintInfoMsg("  Peer:%s Pos:%d", peerPubkey, posList[i])
+	}
+	printGovernanceTxHash(txHash)
+	return nil
+}
+
+func stakeWithdrawFee(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	signer, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	param := &governance.WithdrawFeeParam{
+		Address: signer.Address,
+	}
+	txHash, err := invokeGovernance(ctx, signer, governance.WITHDRAW_FEE, param)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Withdraw fee:")
+	PrintInfoMsg("  Address:%s", signer.Address.ToBase58())
+	printGovernanceTxHash(txHash)
+	return nil
+}
@@ -100,6 +100,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ConfigFlag,
 			utils.LogLevelFlag,
 			utils.DisableEventLogFlag,
+			utils.EnableTxTraceFlag,
 			utils.DataDirFlag,
 		},
 	},
@@ -107,6 +108,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "ACCOUNT",
 		Flags: []cli.Flag{
 			utils.WalletFileFlag,
+			utils.SignerFlag,
 			utils.AccountAddressFlag,
 			utils.AccountPassFlag,
 			utils.AccountDefaultFlag,
@@ -123,6 +125,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.AccountLowSecurityFlag,
 			utils.AccountMultiMFlag,
 			utils.AccountMultiPubKeyFlag,
+			utils.AccountMnemonicFlag,
+			utils.AccountHDPathFlag,
 			utils.IdentityFlag,
 		},
 	},
@@ -180,6 +184,13 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "METRICS",
+		Flags: []cli.Flag{
+			utils.MetricsEnableFlag,
+			utils.MetricsPortFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
@@ -202,6 +213,10 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ContractPrepareInvokeFlag,
 			utils.ContractParamsFlag,
 			utils.ContractReturnTypeFlag,
+			utils.ContractMethodFlag,
+			utils.ContractAbiFileFlag,
+			utils.ContractBreakpointFlag,
+			utils.ContractAdminKeyFlag,
 		},
 	},
 	{
@@ -213,6 +228,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TransactionFromFlag,
 			utils.TransactionToFlag,
 			utils.TransactionAmountFlag,
+			utils.TransactionBatchFlag,
 			utils.TransactionHashFlag,
 			utils.TransferFromSenderFlag,
 			utils.ApproveAssetFlag,
@@ -235,6 +251,44 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ApproveAssetToFlag,
 		},
 	},
+	{
+		Name: "ONX ID",
+		Flags: []cli.Flag{
+			utils.IDFlag,
+			utils.IDPubKeyFlag,
+			utils.IDAttrTypeFlag,
+			utils.IDRecoveryThresholdFlag,
+			utils.IDRecoveryMembersFlag,
+			utils.IDRecoveryDelayFlag,
+			utils.IDKeyNoFlag,
+			utils.IDClaimSubjectFlag,
+			utils.IDClaimExpirationFlag,
+		},
+	},
+	{
+		Name: "AUTH",
+		Flags: []cli.Flag{
+			utils.AuthPeriodFlag,
+			utils.AuthLevelFlag,
+		},
+	},
+	{
+		Name: "NAME SERVICE",
+		Flags: []cli.Flag{
+			utils.NameYearsFlag,
+			utils.NameAddressFlag,
+			utils.NameContractFlag,
+		},
+	},
+	{
+		Name: "GOVERNANCE",
+		Flags: []cli.Flag{
+			utils.GovProposalKindFlag,
+			utils.GovProposalPayloadFlag,
+			utils.GovProposalDescFlag,
+			utils.GovExecuteHeightFlag,
+		},
+	},
 	{
 		Name: "EXPORT",
 		Flags: []cli.Flag{
@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const VERSION_CONTRACT_AUTH = byte(0)
+
+//InvokeAuthContract sends transaction of auth native contract
+func InvokeAuthContract(gasPrice, gasLimit uint64, signer *account.Account, method string, param interface{}) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.AuthContractAddress, VERSION_CONTRACT_AUTH,
+		method, []interface{}{param})
+}
+
+//GetContractRoles return admin and roles of contract in json
+func GetContractRoles(contract string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getcontractroles", []interface{}{contract})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//GetOnxIDRoles return roles of ONX ID in contract in json
+func GetOnxIDRoles(contract, id string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getonxidroles", []interface{}{contract, id})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/abi"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	rpccommon "github.com/OnyxPay/OnyxChain-legacy/http/base/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/contractabi"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	VERSION_CONTRACT_ABI    = byte(0)
+	DEFAULT_WAIT_TX_TIMEOUT = 60 * time.Second
+)
+
+//GetContractAbiData return abi json of contract registered on chain, nil if not registered
+func GetContractAbiData(address common.Address) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getcontractabi", []interface{}{address.ToHexString()})
+	if onxErr != nil {
+		switch onxErr.ErrorCode {
+		case ERROR_INVALID_PARAMS:
+			return nil, fmt.Errorf("invalid contract address:%s", address.ToHexString())
+		}
+		return nil, onxErr.Error
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+//GetContractAbi return NeoVM abi of contract registered on chain, nil if not registered
+func GetContractAbi(address common.Address) (*abi.NeovmContractAbi, error) {
+	data, err := GetContractAbiData(address)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return NewNeovmContractAbi(data)
+}
+
+//RegisterContractAbi register abi json of contract to abi registry, signer should be deployer of contract
+func RegisterContractAbi(gasPrice, gasLimit uint64, signer *account.Account, contract common.Address, abiData []byte) (string, error) {
+	if !json.Valid(abiData) {
+		return "", fmt.Errorf("abi is not valid json")
+	}
+	param := &contractabi.RegisterAbiParam{
+		Contract: contract,
+		Abi:      abiData,
+	}
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.AbiContractAddress, VERSION_CONTRACT_ABI,
+		contractabi.REGISTER_ABI, []interface{}{param})
+}
+
+//WaitForTransaction waits until transaction is executed in block, and return its event notify
+func WaitForTransaction(txHash string, timeout time.Duration) (*rpccommon.ExecuteNotify, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		notify, err := GetSmartContractEvent(txHash)
+		if err == nil && notify != nil {
+			return notify, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("wait for transaction:%s timeout", txHash)
+		}
+		time.Sleep(time.Second)
+	}
+}
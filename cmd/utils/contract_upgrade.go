@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const VERSION_CONTRACT_UPGRADE = byte(0)
+
+//UpgradeContract replaces code of contract in place, signer should own key of contract admin ONX ID
+func UpgradeContract(gasPrice, gasLimit uint64, signer *account.Account, param *upgrade.UpgradeParam) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.UpgradeContractAddress, VERSION_CONTRACT_UPGRADE,
+		upgrade.UPGRADE_CONTRACT, []interface{}{param})
+}
+
+//GetContractHistory return version history of contract in json
+func GetContractHistory(address string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getcontracthistory", []interface{}{address})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
@@ -48,11 +48,19 @@ var (
 		Name:  "disable-event-log",
 		Usage: "Discard event log output by smart contract execution",
 	}
+	EnableTxTraceFlag = cli.BoolFlag{
+		Name:  "enable-tx-trace",
+		Usage: "Record execution trace of invoke transactions in blocks, for tracetransaction rpc",
+	}
 	WalletFileFlag = cli.StringFlag{
 		Name:  "wallet,w",
 		Value: config.DEFAULT_WALLET_FILE_NAME,
 		Usage: "Wallet `<file>`",
 	}
+	SignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer `<uri>` used instead of wallet account, e.g. unix:///path/to/signer.sock?key=<key id>",
+	}
 	ImportFileFlag = cli.StringFlag{
 		Name:  "import-file",
 		Usage: "Path of import `<file>`",
@@ -187,6 +195,17 @@ var (
 		Value: config.DEFAULT_REST_PORT,
 	}
 
+	//Metrics setting
+	MetricsEnableFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
 	//Account setting
 	AccountPassFlag = cli.StringFlag{
 		Name:   "password,p",
@@ -263,6 +282,14 @@ var (
 		Name:  "onxid",
 		Usage: "create an ONX ID instead of account",
 	}
+	AccountMnemonicFlag = cli.BoolFlag{
+		Name:  "mnemonic",
+		Usage: "Derive accounts from BIP39 mnemonic",
+	}
+	AccountHDPathFlag = cli.StringFlag{
+		Name:  "hdpath",
+		Usage: "BIP44 derivation `<path>` of the first account, e.g. m/44'/1024'/0'/0/0. Following accounts increase the last index",
+	}
 
 	//SmartContract setting
 	ContractAddrFlag = cli.StringFlag{
@@ -316,6 +343,23 @@ var (
 		Name:  "return",
 		Usage: "Return `<type>` of contract. bytearray(hexstring), string, integer, boolean",
 	}
+	ContractMethodFlag = cli.StringFlag{
+		Name:  "method",
+		Usage: "Invoke `<method>` of contract by contract abi registered on chain. With this flag, --params are plain values separate with comma ','",
+	}
+	ContractAbiFileFlag = cli.StringFlag{
+		Name:  "abi",
+		Usage: "Register contract abi in json `<file>` to chain",
+	}
+	ContractBreakpointFlag = cli.StringFlag{
+		Name:  "breakpoint",
+		Usage: "Breakpoints `<[address:]offset>` of debugger, separate breakpoints with comma ','",
+	}
+	ContractAdminKeyFlag = cli.Uint64Flag{
+		Name:  "keyno",
+		Usage: "Key `<number>` of contract admin ONX ID, owned by signer",
+		Value: 1,
+	}
 
 	//information cmd settings
 	BlockHashInfoFlag = cli.StringFlag{
@@ -339,12 +383,16 @@ var (
 	}
 	TransactionToFlag = cli.StringFlag{
 		Name:  "to",
-		Usage: "Transfer-in account `<address>`",
+		Usage: "Transfer-in account `<address>`, or name of name service such as alice.onx",
 	}
 	TransactionAmountFlag = cli.StringFlag{
 		Name:  "amount",
 		Usage: "Transfer `<amount>`. Float number",
 	}
+	TransactionBatchFlag = cli.StringFlag{
+		Name:  "batch",
+		Usage: "Batch transfer csv `<file>`, every line of which is to,amount[,memo]",
+	}
 	TransactionHashFlag = cli.StringFlag{
 		Name:  "hash",
 		Usage: "Transaction `<hash>`",
@@ -430,6 +478,14 @@ var (
 		Usage: "Rpc bind port `<number>`",
 		Value: config.DEFAULT_CLI_RPC_PORT,
 	}
+	CliAuthFileFlag = cli.StringFlag{
+		Name:  "cliauth",
+		Usage: "Callers and tokens json `<file>`. If set, requests must carry \"Authorization: Bearer <token>\" header",
+	}
+	CliAuditLogFlag = cli.StringFlag{
+		Name:  "cliauditlog",
+		Usage: "Audit log `<file>` of requests",
+	}
 	CliABIPathFlag = cli.StringFlag{
 		Name:  "abi",
 		Usage: "Abi `<file>` path",
@@ -441,6 +497,94 @@ var (
 		Value: DEFAULT_WALLET_PATH,
 	}
 
+	//ONX ID setting
+	IDFlag = cli.StringFlag{
+		Name:  "id",
+		Usage: "ONX `<ID>`, e.g. did:onx:AXXXXX",
+	}
+	IDPubKeyFlag = cli.StringFlag{
+		Name:  "pubkey",
+		Usage: "Public `<key>` encode with hex string",
+	}
+	IDAttrTypeFlag = cli.StringFlag{
+		Name:  "type",
+		Usage: "Value `<type>` of attribute",
+		Value: "string",
+	}
+	IDRecoveryThresholdFlag = cli.UintFlag{
+		Name:  "m",
+		Usage: "Number of recovery members `<number>` required to recover ID",
+		Value: 1,
+	}
+	IDRecoveryMembersFlag = cli.StringFlag{
+		Name:  "members",
+		Usage: "Recovery member `<addresses>`, separate addresses with comma ','",
+	}
+	IDRecoveryDelayFlag = cli.UintFlag{
+		Name:  "delay",
+		Usage: "Delay `<seconds>` between threshold approvals and recovery execution, in which time owner can cancel recovery",
+		Value: 24 * 3600,
+	}
+	IDKeyNoFlag = cli.UintFlag{
+		Name:  "keyno",
+		Usage: "Key `<number>` of ONX ID, owned by signer",
+		Value: 1,
+	}
+	IDClaimSubjectFlag = cli.StringFlag{
+		Name:  "subject",
+		Usage: "ONX `<ID>` of subject of claim",
+	}
+	IDClaimExpirationFlag = cli.UintFlag{
+		Name:  "expiration",
+		Usage: "Expiration `<timestamp>` of claim in seconds, 0 means claim never expires",
+	}
+
+	//Auth setting
+	AuthPeriodFlag = cli.UintFlag{
+		Name:  "period",
+		Usage: "Delegation `<seconds>`, delegated role expires after period",
+		Value: 24 * 3600,
+	}
+	AuthLevelFlag = cli.UintFlag{
+		Name:  "level",
+		Usage: "Delegation `<level>`, lower than level of delegator",
+		Value: 1,
+	}
+
+	//Name service setting
+	NameYearsFlag = cli.UintFlag{
+		Name:  "years",
+		Usage: "Registration `<years>` of name, paid by oxg fee of each year",
+		Value: 1,
+	}
+	NameAddressFlag = cli.StringFlag{
+		Name:  "addr",
+		Usage: "Account `<address>` which name resolves to, base58, label or index of wallet account",
+	}
+	NameContractFlag = cli.StringFlag{
+		Name:  "contract",
+		Usage: "Contract `<address>` in hex which name resolves to",
+	}
+
+	//Governance setting
+	GovProposalKindFlag = cli.StringFlag{
+		Name:  "kind",
+		Usage: "Proposal `<kind>`, one of param, config, globalparam, globalparam2, proposalconfig and gasschedule",
+		Value: "param",
+	}
+	GovProposalPayloadFlag = cli.StringFlag{
+		Name:  "payload",
+		Usage: "Serialized params of proposal `<payload>` in hex, required if kind is not param",
+	}
+	GovProposalDescFlag = cli.StringFlag{
+		Name:  "desc",
+		Usage: "Set `<text>` as the description of the proposal",
+	}
+	GovExecuteHeightFlag = cli.UintFlag{
+		Name:  "executeheight",
+		Usage: "Block `<height>` to execute proposal, at least voting period and min execute delay after current height",
+	}
+
 	//Export setting
 	ExportFileFlag = cli.StringFlag{
 		Name:  "export-file",
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"strconv"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const VERSION_CONTRACT_GOVERNANCE = byte(0)
+
+//InvokeGovernanceContract sends transaction of governance native contract
+func InvokeGovernanceContract(gasPrice, gasLimit uint64, signer *account.Account, method string, params []interface{}) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.GovernanceContractAddress, VERSION_CONTRACT_GOVERNANCE,
+		method, params)
+}
+
+//GetProposal return governance proposal in json
+func GetProposal(id uint64) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getproposal", []interface{}{id})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//GetProposals return all governance proposals in json
+func GetProposals() ([]byte, error) {
+	data, onxErr := sendRpcRequest("getproposals", []interface{}{})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//GetPeerPool return peer pool of governance contract in json
+func GetPeerPool() ([]byte, error) {
+	data, onxErr := sendRpcRequest("getpeerpool", []interface{}{})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//GetStakeInfo return stake positions of address in json
+func GetStakeInfo(address string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getstakeinfo", []interface{}{address})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//ParseProposalKind returns proposal kind by name
+func ParseProposalKind(name string) (uint8, bool) {
+	for kind, kindName := range governance.ProposalKindNames {
+		if kindName == name {
+			return kind, true
+		}
+	}
+	kind, err := strconv.ParseUint(name, 10, 8)
+	if err != nil {
+		return 0, false
+	}
+	_, ok := governance.ProposalKindNames[uint8(kind)]
+	return uint8(kind), ok
+}
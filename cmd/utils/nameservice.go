@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	httpcom "github.com/OnyxPay/OnyxChain-legacy/http/base/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const VERSION_CONTRACT_NAME = byte(0)
+
+//InvokeNameContract sends transaction of name service native contract
+func InvokeNameContract(gasPrice, gasLimit uint64, signer *account.Account, method string, param interface{}) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.NameContractAddress, VERSION_CONTRACT_NAME,
+		method, []interface{}{param})
+}
+
+//ResolveName return record of name in name service
+func ResolveName(name string) (*httpcom.NameInfo, error) {
+	return getNameInfo("resolvename", name)
+}
+
+//ReverseName return record of reverse name of address in name service
+func ReverseName(address string) (*httpcom.NameInfo, error) {
+	return getNameInfo("reversename", address)
+}
+
+//ResolveNameAddress return base58 address which name resolves to
+func ResolveNameAddress(name string) (string, error) {
+	info, err := ResolveName(name)
+	if err != nil {
+		return "", fmt.Errorf("resolve name %s error:%s", name, err)
+	}
+	if info.Address == "" {
+		return "", fmt.Errorf("name %s has no address record", name)
+	}
+	return info.Address, nil
+}
+
+func getNameInfo(method, arg string) (*httpcom.NameInfo, error) {
+	data, onxErr := sendRpcRequest(method, []interface{}{arg})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	info := &httpcom.NameInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error:%s", err)
+	}
+	return info, nil
+}
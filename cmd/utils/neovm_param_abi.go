@@ -122,3 +122,19 @@ func ParseNeovmParamByteArray(param string) (interface{}, error) {
 	}
 	return res, nil
 }
+
+//ReturnTypeOfNeovmFunc return return type string of function abi, which can be used by ParseReturnValue.
+//Return empty string if return type is unknown, which means raw value.
+func ReturnTypeOfNeovmFunc(funcAbi *abi.NeovmContractFunctionAbi) string {
+	switch strings.ToLower(funcAbi.ReturnType) {
+	case abi.NEOVM_PARAM_TYPE_INTEGER:
+		return PARAM_TYPE_INTEGER
+	case abi.NEOVM_PARAM_TYPE_BOOL:
+		return PARAM_TYPE_BOOLEAN
+	case abi.NEOVM_PARAM_TYPE_STRING:
+		return PARAM_TYPE_STRING
+	case abi.NEOVM_PARAM_TYPE_BYTE_ARRAY:
+		return PARAM_TYPE_BYTE_ARRAY
+	}
+	return ""
+}
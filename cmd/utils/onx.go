@@ -48,12 +48,13 @@ import (
 )
 
 const (
-	VERSION_TRANSACTION    = byte(0)
-	VERSION_CONTRACT_ONX   = byte(0)
-	VERSION_CONTRACT_OXG   = byte(0)
-	CONTRACT_TRANSFER      = "transfer"
-	CONTRACT_TRANSFER_FROM = "transferFrom"
-	CONTRACT_APPROVE       = "approve"
+	VERSION_TRANSACTION     = byte(0)
+	VERSION_CONTRACT_ONX    = byte(0)
+	VERSION_CONTRACT_OXG    = byte(0)
+	CONTRACT_TRANSFER       = "transfer"
+	CONTRACT_TRANSFER_FROM  = "transferFrom"
+	CONTRACT_APPROVE        = "approve"
+	CONTRACT_TRANSFER_MULTI = "transferMulti"
 
 	ASSET_ONX = "onyx"
 	ASSET_OXG = "oxg"
@@ -135,6 +136,27 @@ func Transfer(gasPrice, gasLimit uint64, signer *account.Account, asset, from, t
 	return txHash, nil
 }
 
+//TransferMulti sends transferMulti of onx|oxg, memo of each transfer is emitted in its transfer event
+func TransferMulti(gasPrice, gasLimit uint64, signer *account.Account, asset string, states []*onx.MemoState) (string, error) {
+	mutable, err := TransferMultiTx(gasPrice, gasLimit, asset, states)
+	if err != nil {
+		return "", err
+	}
+	err = SignTransaction(signer, mutable)
+	if err != nil {
+		return "", fmt.Errorf("SignTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return "", fmt.Errorf("convert immutable transaction error:%s", err)
+	}
+	txHash, err := SendRawTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("SendTransaction error:%s", err)
+	}
+	return txHash, nil
+}
+
 func TransferFrom(gasPrice, gasLimit uint64, signer *account.Account, asset, sender, from, to string, amount uint64) (string, error) {
 	mutable, err := TransferFromTx(gasPrice, gasLimit, asset, sender, from, to, amount)
 	if err != nil {
@@ -245,6 +267,30 @@ func TransferTx(gasPrice, gasLimit uint64, asset, from, to string, amount uint64
 	return mutableTx, nil
 }
 
+func TransferMultiTx(gasPrice, gasLimit uint64, asset string, states []*onx.MemoState) (*types.MutableTransaction, error) {
+	if len(states) == 0 {
+		return nil, fmt.Errorf("no transfer")
+	}
+	var version byte
+	var contractAddr common.Address
+	switch strings.ToLower(asset) {
+	case ASSET_ONX:
+		version = VERSION_CONTRACT_ONX
+		contractAddr = utils.OnxContractAddress
+	case ASSET_OXG:
+		version = VERSION_CONTRACT_OXG
+		contractAddr = utils.OxgContractAddress
+	default:
+		return nil, fmt.Errorf("unsupport asset:%s", asset)
+	}
+	invokeCode, err := httpcom.BuildNativeInvokeCode(contractAddr, version, CONTRACT_TRANSFER_MULTI, []interface{}{states})
+	if err != nil {
+		return nil, fmt.Errorf("build invoke code error:%s", err)
+	}
+	mutableTx := NewInvokeTransaction(gasPrice, gasLimit, invokeCode)
+	return mutableTx, nil
+}
+
 func TransferFromTx(gasPrice, gasLimit uint64, asset, sender, from, to string, amount uint64) (*types.MutableTransaction, error) {
 	senderAddr, err := common.AddressFromBase58(sender)
 	if err != nil {
@@ -425,7 +471,7 @@ func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
 
 //Sign sign return the signature to the data of private key
 func Sign(data []byte, signer *account.Account) ([]byte, error) {
-	s, err := sig.Sign(signer.SigScheme, signer.PrivateKey, data, nil)
+	s, err := signer.SignData(data)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,238 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	VERSION_CONTRACT_ONXID = byte(0)
+	VERSION_CONTRACT_CLAIM = byte(0)
+)
+
+//IDPublicKey is a public key in use of ONX ID
+type IDPublicKey struct {
+	Index  uint32
+	PubKey string
+}
+
+//IDAttribute is an attribute of ONX ID
+type IDAttribute struct {
+	Key   string
+	Type  string
+	Value string
+}
+
+//IDRecoveryGroup is the M-of-N recovery setting of ONX ID
+type IDRecoveryGroup struct {
+	Threshold uint64
+	Members   []string
+	Delay     uint64
+}
+
+//IDPendingRecovery is the recovery waiting for approvals or delay
+type IDPendingRecovery struct {
+	NewPubKey     string
+	Approvals     []string
+	RequestTime   uint32
+	EffectiveTime uint32
+}
+
+//IDRecoveryGroupParam is the param of recovery group in ONX ID contract
+type IDRecoveryGroupParam struct {
+	Threshold uint32
+	Members   []common.Address
+	Delay     uint32
+}
+
+//IDAttributeParam is the param of attribute in ONX ID contract
+type IDAttributeParam struct {
+	Key   []byte
+	Type  []byte
+	Value []byte
+}
+
+//InvokeOnxIDContract sends transaction of ONX ID native contract
+func InvokeOnxIDContract(gasPrice, gasLimit uint64, signer *account.Account, method string, params []interface{}) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.OnxIDContractAddress, VERSION_CONTRACT_ONXID, method, params)
+}
+
+//InvokeClaimContract sends transaction of claim record native contract
+func InvokeClaimContract(gasPrice, gasLimit uint64, signer *account.Account, method string, param interface{}) (string, error) {
+	return InvokeNativeContract(gasPrice, gasLimit, signer, utils.ClaimContractAddress, VERSION_CONTRACT_CLAIM, method, []interface{}{param})
+}
+
+//GetDDO return DDO of ONX ID as DID document in json
+func GetDDO(id string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getddo", []interface{}{id})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+//GetClaim return claim of issuer in claim record in json
+func GetClaim(issuer, claimId string) ([]byte, error) {
+	data, onxErr := sendRpcRequest("getclaim", []interface{}{issuer, claimId})
+	if onxErr != nil {
+		return nil, onxErr.Error
+	}
+	return data, nil
+}
+
+func queryOnxID(method, id string) ([]byte, error) {
+	preResult, err := PrepareInvokeNativeContract(utils.OnxIDContractAddress, VERSION_CONTRACT_ONXID, method, []interface{}{[]byte(id)})
+	if err != nil {
+		return nil, err
+	}
+	if preResult.State == 0 {
+		return nil, fmt.Errorf("%s failed", method)
+	}
+	res, ok := preResult.Result.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid result type of %s", method)
+	}
+	return hex.DecodeString(res)
+}
+
+//GetIDPublicKeys returns public keys in use of ONX ID
+func GetIDPublicKeys(id string) ([]*IDPublicKey, error) {
+	data, err := queryOnxID("getPublicKeys", id)
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]*IDPublicKey, 0)
+	buf := bytes.NewBuffer(data)
+	for buf.Len() > 0 {
+		index, err := serialization.ReadUint32(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read public key index error:%s", err)
+		}
+		pk, err := serialization.ReadVarBytes(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read public key error:%s", err)
+		}
+		pks = append(pks, &IDPublicKey{Index: index, PubKey: hex.EncodeToString(pk)})
+	}
+	return pks, nil
+}
+
+//GetIDAttributes returns attributes of ONX ID
+func GetIDAttributes(id string) ([]*IDAttribute, error) {
+	data, err := queryOnxID("getAttributes", id)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]*IDAttribute, 0)
+	buf := bytes.NewBuffer(data)
+	for buf.Len() > 0 {
+		fields := make([][]byte, 0, 3)
+		for i := 0; i < 3; i++ {
+			field, err := serialization.ReadVarBytes(buf)
+			if err != nil {
+				return nil, fmt.Errorf("read attribute error:%s", err)
+			}
+			fields = append(fields, field)
+		}
+		attrs = append(attrs, &IDAttribute{Key: string(fields[0]), Type: string(fields[1]), Value: string(fields[2])})
+	}
+	return attrs, nil
+}
+
+//GetIDRecoveryGroup returns recovery group of ONX ID, nil if not set
+func GetIDRecoveryGroup(id string) (*IDRecoveryGroup, error) {
+	data, err := queryOnxID("getRecoveryGroup", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	buf := bytes.NewBuffer(data)
+	group := &IDRecoveryGroup{}
+	group.Threshold, err = utils.ReadVarUint(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read threshold error:%s", err)
+	}
+	group.Members, err = readAddresses(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read members error:%s", err)
+	}
+	group.Delay, err = utils.ReadVarUint(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read delay error:%s", err)
+	}
+	return group, nil
+}
+
+//GetIDPendingRecovery returns pending recovery of ONX ID, nil if there is not
+func GetIDPendingRecovery(id string) (*IDPendingRecovery, error) {
+	data, err := queryOnxID("getPendingRecovery", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	buf := bytes.NewBuffer(data)
+	pending := &IDPendingRecovery{}
+	pk, err := serialization.ReadVarBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read new public key error:%s", err)
+	}
+	pending.NewPubKey = hex.EncodeToString(pk)
+	pending.Approvals, err = readAddresses(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read approvals error:%s", err)
+	}
+	pending.RequestTime, err = serialization.ReadUint32(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read request time error:%s", err)
+	}
+	pending.EffectiveTime, err = serialization.ReadUint32(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read effective time error:%s", err)
+	}
+	return pending, nil
+}
+
+func readAddresses(r io.Reader) ([]string, error) {
+	num, err := utils.ReadVarUint(r)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0)
+	for i := uint64(0); i < num; i++ {
+		addr, err := utils.ReadAddress(r)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr.ToBase58())
+	}
+	return addrs, nil
+}
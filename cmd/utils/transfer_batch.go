@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+)
+
+//max transfers in one transferMulti transaction of batch transfer
+const BATCH_TRANSFER_SIZE = onx.MAX_MULTI_TRANSFERS
+
+//ParseTransferBatch parses batch transfer file of csv, every line of which is "to,amount[,memo]".
+//Amount is float number of asset, empty lines and lines starting with # are ignored.
+func ParseTransferBatch(r io.Reader, asset string, from common.Address) ([]*onx.MemoState, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	states := make([]*onx.MemoState, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		index := len(states) + 1
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("transfer %d: should be to,amount[,memo]", index)
+		}
+		to, err := common.AddressFromBase58(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: invalid to address:%s", index, record[0])
+		}
+		var amount uint64
+		switch strings.ToLower(asset) {
+		case ASSET_ONX:
+			amount = ParseOnx(strings.TrimSpace(record[1]))
+		case ASSET_OXG:
+			amount = ParseOxg(strings.TrimSpace(record[1]))
+		default:
+			return nil, fmt.Errorf("unsupport asset:%s", asset)
+		}
+		if amount == 0 {
+			return nil, fmt.Errorf("transfer %d: invalid amount:%s", index, record[1])
+		}
+		if err := CheckAssetAmount(asset, amount); err != nil {
+			return nil, fmt.Errorf("transfer %d: %s", index, err)
+		}
+		state := &onx.MemoState{From: from, To: to, Value: amount}
+		if len(record) == 3 {
+			state.Memo = record[2]
+		}
+		if err := onx.CheckMemo(state.Memo); err != nil {
+			return nil, fmt.Errorf("transfer %d: %s", index, err)
+		}
+		states = append(states, state)
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("no transfer in batch")
+	}
+	return states, nil
+}
+
+//SplitTransferBatch splits transfers into batches of BATCH_TRANSFER_SIZE, one transaction for each batch
+func SplitTransferBatch(states []*onx.MemoState) [][]*onx.MemoState {
+	batches := make([][]*onx.MemoState, 0, (len(states)+BATCH_TRANSFER_SIZE-1)/BATCH_TRANSFER_SIZE)
+	for len(states) > BATCH_TRANSFER_SIZE {
+		batches = append(batches, states[:BATCH_TRANSFER_SIZE])
+		states = states[BATCH_TRANSFER_SIZE:]
+	}
+	if len(states) > 0 {
+		batches = append(batches, states)
+	}
+	return batches
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransferBatch(t *testing.T) {
+	from, to1, to2 := common.Address{1}, common.Address{2}, common.Address{3}
+	data := fmt.Sprintf("# payroll\n%s,1.5,salary 2019-06\n\n%s, 2\n", to1.ToBase58(), to2.ToBase58())
+	states, err := ParseTransferBatch(strings.NewReader(data), ASSET_OXG, from)
+	assert.Nil(t, err)
+	assert.Equal(t, []*onx.MemoState{
+		{From: from, To: to1, Value: 1500000000, Memo: "salary 2019-06"},
+		{From: from, To: to2, Value: 2000000000},
+	}, states)
+
+	_, err = ParseTransferBatch(strings.NewReader(to1.ToBase58()+",0\n"), ASSET_ONX, from)
+	assert.NotNil(t, err)
+	_, err = ParseTransferBatch(strings.NewReader("abc,1\n"), ASSET_ONX, from)
+	assert.NotNil(t, err)
+	_, err = ParseTransferBatch(strings.NewReader("# empty\n"), ASSET_ONX, from)
+	assert.NotNil(t, err)
+}
+
+func TestSplitTransferBatch(t *testing.T) {
+	states := make([]*onx.MemoState, BATCH_TRANSFER_SIZE*2+1)
+	batches := SplitTransferBatch(states)
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, BATCH_TRANSFER_SIZE, len(batches[0]))
+	assert.Equal(t, 1, len(batches[2]))
+	assert.Equal(t, 0, len(SplitTransferBatch(nil)))
+}
@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
@@ -61,6 +62,7 @@ const (
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP = uint(16)
 	DEFAULT_HTTP_INFO_PORT                  = uint(0)
+	DEFAULT_METRICS_PORT                    = uint(20340)
 	DEFAULT_MAX_TX_IN_BLOCK                 = 60000
 	DEFAULT_MAX_SYNC_HEADER                 = 500
 	DEFAULT_ENABLE_CONSENSUS                = true
@@ -178,6 +180,11 @@ var PolarisConfig = &GenesisConfig{
 	},
 	DBFT: &DBFTConfig{},
 	SOLO: &SOLOConfig{},
+	Features: &FeatureHeights{
+		ContractAbi:     FEATURE_NOT_SCHEDULED,
+		ContractUpgrade: FEATURE_NOT_SCHEDULED,
+		StorageUsage:    FEATURE_NOT_SCHEDULED,
+	},
 }
 
 var MainNetConfig = &GenesisConfig{
@@ -241,6 +248,11 @@ var MainNetConfig = &GenesisConfig{
 	},
 	DBFT: &DBFTConfig{},
 	SOLO: &SOLOConfig{},
+	Features: &FeatureHeights{
+		ContractAbi:     FEATURE_NOT_SCHEDULED,
+		ContractUpgrade: FEATURE_NOT_SCHEDULED,
+		StorageUsage:    FEATURE_NOT_SCHEDULED,
+	},
 }
 
 var DefConfig = NewOnyxChainConfig()
@@ -251,6 +263,7 @@ type GenesisConfig struct {
 	VBFT          *VBFTConfig
 	DBFT          *DBFTConfig
 	SOLO          *SOLOConfig
+	Features      *FeatureHeights //active from genesis if nil
 }
 
 func NewGenesisConfig() *GenesisConfig {
@@ -263,6 +276,31 @@ func NewGenesisConfig() *GenesisConfig {
 	}
 }
 
+//height of feature not activated on network
+const FEATURE_NOT_SCHEDULED = math.MaxUint32
+
+//
+// heights of blocks from which features changing state of executed blocks take effect, so that blocks
+// before are executed again with the rules they were executed with
+//
+type FeatureHeights struct {
+	ContractAbi     uint32 `json:"contract_abi"`     //deployer of contract recorded for contract abi contract
+	ContractUpgrade uint32 `json:"contract_upgrade"` //upgrade contract, version history of contracts and onUpgrade
+	StorageUsage    uint32 `json:"storage_usage"`    //storage usage of contracts accounted and charged per byte
+}
+
+func (this *FeatureHeights) ContractAbiActive(height uint32) bool {
+	return this == nil || height >= this.ContractAbi
+}
+
+func (this *FeatureHeights) ContractUpgradeActive(height uint32) bool {
+	return this == nil || height >= this.ContractUpgrade
+}
+
+func (this *FeatureHeights) StorageUsageActive(height uint32) bool {
+	return this == nil || height >= this.StorageUsage
+}
+
 //
 // VBFT genesis config, from local config file
 //
@@ -474,6 +512,7 @@ type CommonConfig struct {
 	LogLevel       uint
 	NodeType       string
 	EnableEventLog bool
+	EnableTxTrace  bool
 	SystemFee      map[string]int64
 	GasLimit       uint64
 	GasPrice       uint64
@@ -530,6 +569,11 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+type MetricsConfig struct {
+	EnableMetrics   bool
+	HttpMetricsPort uint
+}
+
 type OnyxChainConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -538,6 +582,7 @@ type OnyxChainConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	Metrics   *MetricsConfig
 }
 
 func NewOnyxChainConfig() *OnyxChainConfig {
@@ -586,6 +631,10 @@ func NewOnyxChainConfig() *OnyxChainConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Metrics: &MetricsConfig{
+			EnableMetrics:   false,
+			HttpMetricsPort: DEFAULT_METRICS_PORT,
+		},
 	}
 }
 
@@ -28,3 +28,15 @@ func TestConfigGeneration(t *testing.T) {
 	defaultConfig := newDefaultConfig()
 	assert.NotEqual(t, defaultConfig, polarisConfig)
 }
+
+func TestFeatureHeights(t *testing.T) {
+	var features *FeatureHeights
+	assert.True(t, features.ContractAbiActive(0))
+	assert.False(t, MainNetConfig.Features.ContractAbiActive(1000000))
+	assert.False(t, MainNetConfig.Features.ContractUpgradeActive(1000000))
+	assert.False(t, MainNetConfig.Features.StorageUsageActive(1000000))
+
+	features = &FeatureHeights{ContractAbi: 10}
+	assert.False(t, features.ContractAbiActive(9))
+	assert.True(t, features.ContractAbiActive(10))
+}
@@ -0,0 +1,446 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics provides counters, gauges and histograms of node internals
+// which can be exported in the prometheus text exposition format
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TYPE_COUNTER   = "counter"
+	TYPE_GAUGE     = "gauge"
+	TYPE_HISTOGRAM = "histogram"
+)
+
+//DefBuckets is the default histogram buckets in seconds, used for latencies
+var DefBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+//DefRegistry is the registry used by the package level constructors
+var DefRegistry = NewRegistry()
+
+//Metric is the interface implemented by every collector of the registry
+type Metric interface {
+	Name() string
+	//Write outputs the metric in prometheus text format
+	Write(w io.Writer) error
+}
+
+//Registry keeps metrics by name
+type Registry struct {
+	lock    sync.RWMutex
+	metrics map[string]Metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]Metric),
+	}
+}
+
+//Register adds the metric to the registry. Metric names must be unique
+func (this *Registry) Register(m Metric) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if _, ok := this.metrics[m.Name()]; ok {
+		return fmt.Errorf("metric %s already registered", m.Name())
+	}
+	this.metrics[m.Name()] = m
+	return nil
+}
+
+//Unregister removes the metric with the name from the registry
+func (this *Registry) Unregister(name string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	delete(this.metrics, name)
+}
+
+//Get returns the metric registered with the name
+func (this *Registry) Get(name string) Metric {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.metrics[name]
+}
+
+//WriteMetrics outputs all metrics sorted by name
+func (this *Registry) WriteMetrics(w io.Writer) error {
+	this.lock.RLock()
+	names := make([]string, 0, len(this.metrics))
+	for name := range this.metrics {
+		names = append(names, name)
+	}
+	metrics := make([]Metric, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		metrics = append(metrics, this.metrics[name])
+	}
+	this.lock.RUnlock()
+
+	for _, m := range metrics {
+		if err := m.Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mustRegister(m Metric) {
+	if err := DefRegistry.Register(m); err != nil {
+		panic(err)
+	}
+}
+
+//desc holds the fields shared by all metric types
+type desc struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+}
+
+func (this *desc) Name() string {
+	return this.name
+}
+
+func (this *desc) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", this.name, escapeHelp(this.help), this.name, this.typ)
+	return err
+}
+
+func (this *desc) labelKey(labelValues []string) string {
+	if len(labelValues) != len(this.labelNames) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", this.name, len(this.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+//labelString formats the label pairs of a sample, extra is appended as is
+func (this *desc) labelString(key string, extra string) string {
+	if len(this.labelNames) == 0 && extra == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(this.labelNames)+1)
+	if len(this.labelNames) > 0 {
+		values := strings.Split(key, "\xff")
+		for i, name := range this.labelNames {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escapeLabel(values[i])))
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+//Counter is a monotonically increasing value, partitioned by label values
+type Counter struct {
+	desc
+	lock   sync.RWMutex
+	values map[string]float64
+}
+
+//NewCounter creates a counter and registers it in DefRegistry
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	c := newCounter(name, help, labelNames...)
+	mustRegister(c)
+	return c
+}
+
+func newCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{
+		desc:   desc{name: name, help: help, typ: TYPE_COUNTER, labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+}
+
+//Inc increases the counter by 1
+func (this *Counter) Inc(labelValues ...string) {
+	this.Add(1, labelValues...)
+}
+
+//Add increases the counter by delta, negative delta is ignored
+func (this *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := this.labelKey(labelValues)
+	this.lock.Lock()
+	this.values[key] += delta
+	this.lock.Unlock()
+}
+
+//Value returns the current value of the counter
+func (this *Counter) Value(labelValues ...string) float64 {
+	key := this.labelKey(labelValues)
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.values[key]
+}
+
+func (this *Counter) Write(w io.Writer) error {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return writeSamples(w, &this.desc, this.values)
+}
+
+//Gauge is a value which can go up and down, partitioned by label values
+type Gauge struct {
+	desc
+	lock   sync.RWMutex
+	values map[string]float64
+}
+
+//NewGauge creates a gauge and registers it in DefRegistry
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	g := newGauge(name, help, labelNames...)
+	mustRegister(g)
+	return g
+}
+
+func newGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{
+		desc:   desc{name: name, help: help, typ: TYPE_GAUGE, labelNames: labelNames},
+		values: make(map[string]float64),
+	}
+}
+
+//Set sets the gauge to v
+func (this *Gauge) Set(v float64, labelValues ...string) {
+	key := this.labelKey(labelValues)
+	this.lock.Lock()
+	this.values[key] = v
+	this.lock.Unlock()
+}
+
+//Add adds delta to the gauge
+func (this *Gauge) Add(delta float64, labelValues ...string) {
+	key := this.labelKey(labelValues)
+	this.lock.Lock()
+	this.values[key] += delta
+	this.lock.Unlock()
+}
+
+func (this *Gauge) Inc(labelValues ...string) {
+	this.Add(1, labelValues...)
+}
+
+func (this *Gauge) Dec(labelValues ...string) {
+	this.Add(-1, labelValues...)
+}
+
+//Value returns the current value of the gauge
+func (this *Gauge) Value(labelValues ...string) float64 {
+	key := this.labelKey(labelValues)
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.values[key]
+}
+
+func (this *Gauge) Write(w io.Writer) error {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return writeSamples(w, &this.desc, this.values)
+}
+
+//GaugeFunc is a gauge whose value is read from a function when exported
+type GaugeFunc struct {
+	desc
+	lock sync.RWMutex
+	fn   func() float64
+}
+
+//NewGaugeFunc creates a gauge func and registers it in DefRegistry.
+//The function may be replaced later by SetFunc, nil function outputs nothing
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{
+		desc: desc{name: name, help: help, typ: TYPE_GAUGE},
+		fn:   fn,
+	}
+	mustRegister(g)
+	return g
+}
+
+func (this *GaugeFunc) SetFunc(fn func() float64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.fn = fn
+}
+
+func (this *GaugeFunc) Write(w io.Writer) error {
+	this.lock.RLock()
+	fn := this.fn
+	this.lock.RUnlock()
+	if fn == nil {
+		return nil
+	}
+	return writeSamples(w, &this.desc, map[string]float64{"": fn()})
+}
+
+type histogramValue struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+//Histogram samples observations into configurable buckets, partitioned by label values
+type Histogram struct {
+	desc
+	buckets []float64
+	lock    sync.RWMutex
+	values  map[string]*histogramValue
+}
+
+//NewHistogram creates a histogram and registers it in DefRegistry.
+//DefBuckets is used if buckets is empty
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	h := newHistogram(name, help, buckets, labelNames...)
+	mustRegister(h)
+	return h
+}
+
+func newHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	return &Histogram{
+		desc:    desc{name: name, help: help, typ: TYPE_HISTOGRAM, labelNames: labelNames},
+		buckets: sorted,
+		values:  make(map[string]*histogramValue),
+	}
+}
+
+//Observe adds a single observation to the histogram
+func (this *Histogram) Observe(v float64, labelValues ...string) {
+	key := this.labelKey(labelValues)
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	hv, ok := this.values[key]
+	if !ok {
+		hv = &histogramValue{counts: make([]uint64, len(this.buckets))}
+		this.values[key] = hv
+	}
+	for i, upper := range this.buckets {
+		if v <= upper {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+//ObserveSince observes the seconds elapsed since start
+func (this *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	this.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+//Count returns the number of observations
+func (this *Histogram) Count(labelValues ...string) uint64 {
+	key := this.labelKey(labelValues)
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	if hv, ok := this.values[key]; ok {
+		return hv.count
+	}
+	return 0
+}
+
+func (this *Histogram) Write(w io.Writer) error {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	if err := this.writeHeader(w); err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	for _, key := range sortedKeys(this.values) {
+		hv := this.values[key]
+		for i, upper := range this.buckets {
+			le := fmt.Sprintf("le=\"%s\"", formatFloat(upper))
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", this.name, this.labelString(key, le), hv.counts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", this.name, this.labelString(key, "le=\"+Inf\""), hv.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", this.name, this.labelString(key, ""), formatFloat(hv.sum))
+		fmt.Fprintf(buf, "%s_count%s %d\n", this.name, this.labelString(key, ""), hv.count)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeSamples(w io.Writer, d *desc, values map[string]float64) error {
+	if err := d.writeHeader(w); err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(buf, "%s%s %s\n", d.name, d.labelString(key, ""), formatFloat(values[key]))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]float64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*histogramValue:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	return strings.Replace(s, "\n", "\\n", -1)
+}
+
+func escapeLabel(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	return strings.Replace(s, "\n", "\\n", -1)
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	c := newCounter("test_counter", "test counter", "type")
+	c.Inc("a")
+	c.Add(2, "a")
+	c.Add(-1, "a")
+	c.Inc("b")
+	assert.Equal(t, float64(3), c.Value("a"))
+	assert.Equal(t, float64(1), c.Value("b"))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, c.Write(buf))
+	assert.Equal(t, "# HELP test_counter test counter\n# TYPE test_counter counter\n"+
+		"test_counter{type=\"a\"} 3\ntest_counter{type=\"b\"} 1\n", buf.String())
+}
+
+func TestGauge(t *testing.T) {
+	g := newGauge("test_gauge", "test gauge")
+	g.Set(10)
+	g.Dec()
+	assert.Equal(t, float64(9), g.Value())
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, g.Write(buf))
+	assert.True(t, strings.HasSuffix(buf.String(), "test_gauge 9\n"))
+}
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram("test_histogram", "test histogram", []float64{1, 0.5}, "method")
+	h.Observe(0.2, "get")
+	h.Observe(0.7, "get")
+	h.Observe(3, "get")
+	assert.Equal(t, uint64(3), h.Count("get"))
+	assert.Equal(t, uint64(0), h.Count("put"))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, h.Write(buf))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "test_histogram_bucket{method=\"get\",le=\"0.5\"} 1\n"))
+	assert.True(t, strings.Contains(out, "test_histogram_bucket{method=\"get\",le=\"1\"} 2\n"))
+	assert.True(t, strings.Contains(out, "test_histogram_bucket{method=\"get\",le=\"+Inf\"} 3\n"))
+	assert.True(t, strings.Contains(out, "test_histogram_sum{method=\"get\"} 3.9\n"))
+	assert.True(t, strings.Contains(out, "test_histogram_count{method=\"get\"} 3\n"))
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	assert.Nil(t, r.Register(newCounter("b_total", "b")))
+	assert.Nil(t, r.Register(newGauge("a_value", "a")))
+	assert.NotNil(t, r.Register(newGauge("a_value", "a")))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, r.WriteMetrics(buf))
+	out := buf.String()
+	assert.True(t, strings.Index(out, "a_value") < strings.Index(out, "b_total"))
+
+	r.Unregister("a_value")
+	assert.Nil(t, r.Get("a_value"))
+}
+
+func TestLabelEscape(t *testing.T) {
+	c := newCounter("test_escape", "escape", "method")
+	c.Inc("a\"b")
+	buf := new(bytes.Buffer)
+	assert.Nil(t, c.Write(buf))
+	assert.True(t, strings.Contains(buf.String(), "test_escape{method=\"a\\\"b\"} 1\n"))
+}
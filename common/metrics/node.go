@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+const NAMESPACE = "onyxchain"
+
+//ledger metrics
+var (
+	BlockHeight = NewGauge(NAMESPACE+"_block_height",
+		"Height of the latest block saved to the ledger")
+	BlockInterval = NewGauge(NAMESPACE+"_block_interval_seconds",
+		"Timestamp difference between the latest block and its parent")
+	BlockTxCount = NewGauge(NAMESPACE+"_block_transactions",
+		"Transaction count of the latest block")
+	BlockProcessTime = NewHistogram(NAMESPACE+"_block_process_seconds",
+		"Time spent executing and committing a block to the stores", nil)
+	LedgerAddBlockTime = NewHistogram(NAMESPACE+"_ledger_add_block_seconds",
+		"Latency of Ledger.AddBlock including header verification", nil)
+)
+
+//txnpool metrics
+var (
+	TxPoolSize = NewGauge(NAMESPACE+"_txnpool_size",
+		"Number of verified transactions in the tx pool")
+	TxPoolPending = NewGauge(NAMESPACE+"_txnpool_pending",
+		"Number of transactions waiting for verification")
+	TxPoolVerify = NewCounter(NAMESPACE+"_txnpool_transactions_total",
+		"Transactions handled by the tx pool by outcome", "stats")
+)
+
+//p2p metrics
+var (
+	P2PPeers = NewGauge(NAMESPACE+"_p2p_peers",
+		"Number of p2p connections by state", "state")
+	P2PRecvBytes = NewCounter(NAMESPACE+"_p2p_received_bytes_total",
+		"Payload bytes received from peers by message type", "type")
+	P2PSendBytes = NewCounter(NAMESPACE+"_p2p_sent_bytes_total",
+		"Bytes sent to peers by message type", "type")
+	P2PRecvMsgs = NewCounter(NAMESPACE+"_p2p_received_messages_total",
+		"Messages received from peers by message type", "type")
+	P2PSendMsgs = NewCounter(NAMESPACE+"_p2p_sent_messages_total",
+		"Messages sent to peers by message type", "type")
+)
+
+//consensus metrics
+var (
+	ConsensusRounds = NewCounter(NAMESPACE+"_consensus_rounds_total",
+		"Consensus rounds started", "consensus")
+	ConsensusViewChanges = NewCounter(NAMESPACE+"_consensus_view_changes_total",
+		"Consensus view changes", "consensus")
+	ConsensusView = NewGauge(NAMESPACE+"_consensus_view",
+		"Current consensus view number", "consensus")
+)
+
+//rpc metrics
+var (
+	RpcRequestTime = NewHistogram(NAMESPACE+"_rpc_request_seconds",
+		"Json rpc request latency by method", nil, "method")
+)
@@ -36,6 +36,16 @@ func GetPassword() ([]byte, error) {
 	return passwd, nil
 }
 
+// GetSecret gets secret like mnemonic from user input without echo
+func GetSecret(prompt string) ([]byte, error) {
+	fmt.Printf("%s:", prompt)
+	secret, err := gopass.GetPasswd()
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
 // GetConfirmedPassword gets double confirmed password from user input
 func GetConfirmedPassword() ([]byte, error) {
 	fmt.Printf("Password:")
@@ -26,6 +26,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	actorTypes "github.com/OnyxPay/OnyxChain-legacy/consensus/actor"
 	"github.com/OnyxPay/OnyxChain-legacy/core/genesis"
 	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
@@ -270,7 +271,10 @@ func (ds *DbftService) InitializeConsensus(viewNum byte) error {
 			return nil
 		}
 		ds.context.ChangeView(viewNum)
+		metrics.ConsensusViewChanges.Inc("dbft")
 	}
+	metrics.ConsensusRounds.Inc("dbft")
+	metrics.ConsensusView.Set(float64(viewNum), "dbft")
 
 	if ds.context.BookkeeperIndex < 0 {
 		log.Info("You aren't bookkeeper")
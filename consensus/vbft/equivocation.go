@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	vconfig "github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/OnyxPay/OnyxChain-legacy/core/utils"
+	gover "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	nutils "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	txpool "github.com/OnyxPay/OnyxChain-legacy/txnpool/common"
+)
+
+type equivocationKey struct {
+	blockNum uint32
+	peer     uint32
+}
+
+//
+// collect blocks signed by peer in its endorsements and commits in msg pool, and report them to governance
+// contract if they prove conflicting endorsements or commits. the peer will be put into black list by governance
+// contract once the evidence is verified.
+//
+func (self *Server) reportEquivocation(blkNum uint32, peer uint32) {
+	key := equivocationKey{blockNum: blkNum, peer: peer}
+	if self.reportedEquivocations[key] {
+		return
+	}
+	headers, sigs := getEquivocationEvidence(self.msgPool.GetProposalMsgs(blkNum),
+		self.msgPool.GetEndorsementsMsgs(blkNum), self.msgPool.GetCommitMsgs(blkNum), peer)
+	if len(headers) < gover.MIN_EQUIVOCATION_BLOCKS {
+		return
+	}
+	for k := range self.reportedEquivocations {
+		if k.blockNum+self.msgHistoryDuration < blkNum {
+			delete(self.reportedEquivocations, k)
+		}
+	}
+
+	peerPk := self.peerPool.GetPeerPubKey(peer)
+	if peerPk == nil {
+		log.Errorf("server %d failed to get peer %d pk of block %d", self.Index, peer, blkNum)
+		return
+	}
+	tx, err := self.createEquivocationTransaction(peerPk, headers, sigs)
+	if err != nil {
+		log.Errorf("server %d failed to construct equivocation transaction of block %d: %s", self.Index, blkNum, err)
+		return
+	}
+	self.poolActor.Pool.Tell(&txpool.TxReq{Tx: tx, Sender: txpool.HttpSender})
+	self.reportedEquivocations[key] = true
+	txHash := tx.Hash()
+	log.Warnf("server %d reported equivocation of peer %d in block %d, tx %s",
+		self.Index, peer, blkNum, txHash.ToHexString())
+}
+
+//
+// get headers of different blocks proposed by others and signed by peer in endorsements and commits,
+// with signatures of peer on them
+//
+func getEquivocationEvidence(proposals, endorses, commits []ConsensusMsg, peer uint32) ([][]byte, [][]byte) {
+	blocks := make(map[common.Uint256]*types.Block)
+	for _, msg := range proposals {
+		p, ok := msg.(*blockProposalMsg)
+		if !ok || p.Block.getProposer() == peer {
+			continue
+		}
+		for _, blk := range []*types.Block{p.Block.Block, p.Block.EmptyBlock} {
+			if blk != nil {
+				blocks[blk.Hash()] = blk
+			}
+		}
+	}
+
+	headers := make([][]byte, 0)
+	sigs := make([][]byte, 0)
+	signed := make(map[common.Uint256]bool)
+	addEvidence := func(hash common.Uint256, sig []byte) {
+		blk, present := blocks[hash]
+		if !present || signed[hash] || len(headers) >= gover.MAX_EQUIVOCATION_BLOCKS {
+			return
+		}
+		signed[hash] = true
+		headers = append(headers, blk.Header.ToArray())
+		sigs = append(sigs, sig)
+	}
+	for _, msg := range endorses {
+		if e, ok := msg.(*blockEndorseMsg); ok && e.Endorser == peer {
+			addEvidence(e.EndorsedBlockHash, e.EndorserSig)
+		}
+	}
+	for _, msg := range commits {
+		if c, ok := msg.(*blockCommitMsg); ok && c.Committer == peer {
+			addEvidence(c.CommitBlockHash, c.CommitterSig)
+		}
+	}
+	return headers, sigs
+}
+
+func (self *Server) createEquivocationTransaction(peerPk keypair.PublicKey, headers, sigs [][]byte) (*types.Transaction, error) {
+	param := &gover.ReportEquivocationParam{
+		PeerPubkey: vconfig.PubkeyID(peerPk),
+		Headers:    headers,
+		Sigs:       sigs,
+	}
+	bf := new(bytes.Buffer)
+	if err := param.Serialize(bf); err != nil {
+		return nil, fmt.Errorf("serialize equivocation param: %s", err)
+	}
+	mutable := utils.BuildNativeTransaction(nutils.GovernanceContractAddress, gover.REPORT_EQUIVOCATION, bf.Bytes())
+	mutable.GasPrice = config.DefConfig.Common.GasPrice
+	mutable.GasLimit = config.DefConfig.Common.GasLimit
+	mutable.Nonce = uint32(time.Now().Unix())
+	mutable.Payer = self.account.Address
+	txHash := mutable.Hash()
+	sig, err := signature.Sign(self.account, txHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("sign equivocation transaction: %s", err)
+	}
+	mutable.Sigs = []types.Sig{{
+		PubKeys: []keypair.PublicKey{self.account.PublicKey},
+		M:       1,
+		SigData: [][]byte{sig},
+	}}
+	return mutable.IntoImmutable()
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/OnyxPay/OnyxChain-eventbus/actor"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	actorTypes "github.com/OnyxPay/OnyxChain-legacy/consensus/actor"
+	vconfig "github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	gover "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	txpool "github.com/OnyxPay/OnyxChain-legacy/txnpool/common"
+)
+
+func constructEquivocationProposal(t *testing.T, proposer uint32, blkNum uint32) *blockProposalMsg {
+	info := &vconfig.VbftBlockInfo{Proposer: proposer}
+	payload, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("marshal block info: %s", err)
+	}
+	newBlock := func(nonce uint64) *types.Block {
+		return &types.Block{Header: &types.Header{
+			Height:           blkNum,
+			ConsensusData:    nonce,
+			ConsensusPayload: payload,
+		}}
+	}
+	return &blockProposalMsg{Block: &Block{Block: newBlock(1), EmptyBlock: newBlock(0), Info: info}}
+}
+
+func constructEquivocationEndorse(t *testing.T, acc *account.Account, endorser uint32, blk *types.Block) *blockEndorseMsg {
+	hash := blk.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	if err != nil {
+		t.Fatalf("sign block: %s", err)
+	}
+	return &blockEndorseMsg{Endorser: endorser, BlockNum: blk.Header.Height, EndorsedBlockHash: hash, EndorserSig: sig}
+}
+
+func constructEquivocationCommit(t *testing.T, acc *account.Account, committer uint32, blk *types.Block) *blockCommitMsg {
+	hash := blk.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	if err != nil {
+		t.Fatalf("sign block: %s", err)
+	}
+	return &blockCommitMsg{Committer: committer, BlockNum: blk.Header.Height, CommitBlockHash: hash, CommitterSig: sig}
+}
+
+//peer 1 endorses its own block, blocks of proposer 2 and 3, empty block of proposer 4, and commits block of
+//proposer 5
+func constructEquivocationMsgs(t *testing.T, acc *account.Account, blkNum uint32) ([]ConsensusMsg, []ConsensusMsg, []ConsensusMsg) {
+	var proposals []ConsensusMsg
+	var blocks []*Block
+	for proposer := uint32(1); proposer <= 5; proposer++ {
+		p := constructEquivocationProposal(t, proposer, blkNum)
+		proposals = append(proposals, p)
+		blocks = append(blocks, p.Block)
+	}
+	other := account.NewAccount("SHA256withECDSA")
+	endorses := []ConsensusMsg{
+		constructEquivocationEndorse(t, acc, 1, blocks[0].Block),
+		constructEquivocationEndorse(t, acc, 1, blocks[1].Block),
+		constructEquivocationEndorse(t, acc, 1, blocks[1].Block),
+		constructEquivocationEndorse(t, acc, 1, blocks[2].Block),
+		constructEquivocationEndorse(t, other, 2, blocks[3].Block),
+		constructEquivocationEndorse(t, acc, 1, blocks[3].EmptyBlock),
+	}
+	commits := []ConsensusMsg{
+		constructEquivocationCommit(t, acc, 1, blocks[4].Block),
+		constructEquivocationCommit(t, other, 2, blocks[0].Block),
+	}
+	return proposals, endorses, commits
+}
+
+func TestGetEquivocationEvidence(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	proposals, endorses, commits := constructEquivocationMsgs(t, acc, 10)
+
+	headers, sigs := getEquivocationEvidence(proposals, endorses, commits, 1)
+	if len(headers) != gover.MIN_EQUIVOCATION_BLOCKS || len(sigs) != len(headers) {
+		t.Fatalf("evidence of %d blocks, %d sigs", len(headers), len(sigs))
+	}
+	for i, raw := range headers {
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			t.Fatalf("deserialize header: %s", err)
+		}
+		hash := header.Hash()
+		if err := signature.Verify(acc.PublicKey, hash[:], sigs[i]); err != nil {
+			t.Errorf("block %d is not signed by peer: %s", i, err)
+		}
+	}
+
+	//an honest peer endorses a block and an empty block, and commits a block
+	headers, _ = getEquivocationEvidence(proposals, endorses[:5], commits, 1)
+	if len(headers) != gover.MIN_EQUIVOCATION_BLOCKS-1 {
+		t.Errorf("evidence of %d blocks without empty endorsement", len(headers))
+	}
+	headers, _ = getEquivocationEvidence(proposals[:4], endorses, commits, 1)
+	if len(headers) != 3 {
+		t.Errorf("evidence of %d blocks without proposal", len(headers))
+	}
+	headers, _ = getEquivocationEvidence(proposals, endorses, commits, 2)
+	if len(headers) != 2 {
+		t.Errorf("evidence of %d blocks of peer 2", len(headers))
+	}
+}
+
+func TestReportEquivocation(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	txs := make(chan *types.Transaction, 4)
+	pid := actor.Spawn(actor.FromFunc(func(ctx actor.Context) {
+		if req, ok := ctx.Message().(*txpool.TxReq); ok {
+			txs <- req.Tx
+		}
+	}))
+	defer pid.Stop()
+
+	server := constructServer()
+	server.account = acc
+	server.poolActor = &actorTypes.TxPoolActor{Pool: pid}
+	server.msgHistoryDuration = 64
+	server.currentBlockNum = 10
+	server.reportedEquivocations = make(map[equivocationKey]bool)
+	server.msgPool = newMsgPool(server, 64)
+	server.peerPool = NewPeerPool(7, server)
+	if err := server.peerPool.addPeer(&vconfig.PeerConfig{Index: 1, ID: vconfig.PubkeyID(acc.PublicKey)}); err != nil {
+		t.Fatalf("add peer: %s", err)
+	}
+
+	proposals, endorses, commits := constructEquivocationMsgs(t, acc, 10)
+	for _, msgs := range [][]ConsensusMsg{proposals, endorses[:5], commits} {
+		for _, msg := range msgs {
+			h, _ := HashMsg(msg)
+			server.msgPool.AddMsg(msg, h)
+		}
+	}
+	server.reportEquivocation(10, 1)
+	select {
+	case <-txs:
+		t.Fatalf("reported equivocation of honest peer")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	h, _ := HashMsg(endorses[5])
+	server.msgPool.AddMsg(endorses[5], h)
+	server.reportEquivocation(10, 1)
+	select {
+	case tx := <-txs:
+		if tx.Payer != acc.Address {
+			t.Errorf("payer of equivocation transaction is %s", tx.Payer.ToBase58())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("equivocation is not reported")
+	}
+
+	//reported only once
+	server.reportEquivocation(10, 1)
+	select {
+	case <-txs:
+		t.Fatalf("equivocation reported again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -31,7 +32,9 @@ import (
 	"github.com/OnyxPay/OnyxChain-eventbus/actor"
 	"github.com/OnyxPay/OnyxChain-legacy/account"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	actorTypes "github.com/OnyxPay/OnyxChain-legacy/consensus/actor"
 	"github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
 	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
@@ -114,11 +117,15 @@ type Server struct {
 	chainStore *ChainStore // block store
 	msgPool    *MsgPool    // consensus msg pool
 	blockPool  *BlockPool  // received block proposals
+	signGuard  *signGuard  // blocks signed in endorsements and commits
 	peerPool   *PeerPool   // consensus peers
 	syncer     *Syncer
 	stateMgr   *StateMgr
 	timer      *EventTimer
 
+	// equivocations reported to governance contract, only accessed in msg processing routine
+	reportedEquivocations map[equivocationKey]bool
+
 	msgRecvC   map[uint32]chan *p2pMsgPayload
 	msgC       chan ConsensusMsg
 	bftActionC chan *BftAction
@@ -137,6 +144,8 @@ func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, e
 		p2p:                &actorTypes.P2PActor{P2P: p2p},
 		ledger:             ledger.DefLedger,
 		incrValidator:      increment.NewIncrementValidator(10),
+
+		reportedEquivocations: make(map[equivocationKey]bool),
 	}
 	server.stateMgr = newStateMgr(server)
 
@@ -286,6 +295,7 @@ func (self *Server) LoadChainConfig(chainStore *ChainStore) error {
 	if self.config.View == 0 || self.config.MaxBlockChangeView == 0 {
 		panic("invalid view or maxblockchangeview ")
 	}
+	metrics.ConsensusView.Set(float64(self.config.View), "vbft")
 	// update msg delays
 	makeProposalTimeout = time.Duration(self.config.BlockMsgDelay * 2)
 	make2ndProposalTimeout = time.Duration(self.config.BlockMsgDelay)
@@ -332,6 +342,8 @@ func (self *Server) updateChainConfig() error {
 	self.config = block.Info.NewChainConfig
 	self.LastConfigBlockNum = block.getLastConfigBlockNum()
 	self.metaLock.Unlock()
+	metrics.ConsensusViewChanges.Inc("vbft")
+	metrics.ConsensusView.Set(float64(block.Info.NewChainConfig.View), "vbft")
 
 	self.metaLock.RLock()
 	defer self.metaLock.RUnlock()
@@ -413,6 +425,13 @@ func (self *Server) initialize() error {
 	self.chainStore = store
 	log.Info("block store opened")
 
+	self.signGuard, err = openSignGuard(filepath.Join(config.DefConfig.Common.DataDir,
+		config.DefConfig.P2PNode.NetworkName, SIGN_GUARD_FILE))
+	if err != nil {
+		log.Errorf("failed to open sign guard: %s", err)
+		return fmt.Errorf("failed to open sign guard: %s", err)
+	}
+
 	self.blockPool, err = newBlockPool(self, self.msgHistoryDuration, store)
 	if err != nil {
 		log.Errorf("init blockpool: %s", err)
@@ -492,7 +511,7 @@ func (self *Server) initialize() error {
 
 func (self *Server) start() error {
 	// check if server pubkey support VRF
-	if !vrf.ValidatePrivateKey(self.account.PrivateKey) || !vrf.ValidatePublicKey(self.account.PublicKey) {
+	if !self.account.SupportVrf() {
 		return fmt.Errorf("server %d consensus start failed: invalid account key for VRF", self.Index)
 	}
 
@@ -648,6 +667,7 @@ func (self *Server) updateParticipantConfig() error {
 
 func (self *Server) startNewRound() error {
 	blkNum := self.GetCurrentBlockNo()
+	metrics.ConsensusRounds.Inc("vbft")
 
 	if err := self.updateParticipantConfig(); err != nil {
 		log.Errorf("startNewRound error:%s", err)
@@ -1206,6 +1226,7 @@ func (self *Server) processMsgEvent() error {
 		case BlockEndorseMessage:
 			pMsg := msg.(*blockEndorseMsg)
 			msgBlkNum := pMsg.GetBlockNum()
+			self.reportEquivocation(msgBlkNum, pMsg.Endorser)
 
 			// if had committed for current round, ignore the endorsement
 			if self.blockPool.committedForBlock(msgBlkNum) {
@@ -1271,6 +1292,7 @@ func (self *Server) processMsgEvent() error {
 		case BlockCommitMessage:
 			pMsg := msg.(*blockCommitMsg)
 			msgBlkNum := pMsg.GetBlockNum()
+			self.reportEquivocation(msgBlkNum, pMsg.Committer)
 
 			if msgBlkNum == self.GetCurrentBlockNo() {
 				//              if countOfCommitment(msg.proposal) >= 2C + 1:
@@ -1841,6 +1863,15 @@ func (self *Server) endorseBlock(proposal *blockProposalMsg, forEmpty bool) erro
 		return fmt.Errorf("failed to construct endorse msg: %s", err)
 	}
 
+	// persist the endorsed block before sending, so no other block is endorsed at the height after restart
+	kind := SIGN_ENDORSE
+	if forEmpty {
+		kind = SIGN_ENDORSE_EMPTY
+	}
+	if err := self.signGuard.sign(blkNum, kind, endorseMsg.EndorsedBlockHash, self.minSignBlockNum()); err != nil {
+		return fmt.Errorf("failed to endorse block %d: %s", blkNum, err)
+	}
+
 	// set the block as self-endorsed-block
 	if err := self.blockPool.setProposalEndorsed(proposal, forEmpty); err != nil {
 		return fmt.Errorf("failed to set proposal as endorsed: %s", err)
@@ -1874,6 +1905,15 @@ func (self *Server) endorseBlock(proposal *blockProposalMsg, forEmpty bool) erro
 	return nil
 }
 
+//minSignBlockNum returns lowest block num whose signed blocks are kept in sign guard
+func (self *Server) minSignBlockNum() uint32 {
+	chained := self.chainStore.GetChainedBlockNum()
+	if chained < self.msgHistoryDuration {
+		return 0
+	}
+	return chained - self.msgHistoryDuration
+}
+
 func (self *Server) commitBlock(proposal *blockProposalMsg, forEmpty bool) error {
 	// for each round, we can only commit one block
 
@@ -1907,6 +1947,11 @@ func (self *Server) commitBlock(proposal *blockProposalMsg, forEmpty bool) error
 		return fmt.Errorf("failed to construct commit msg: %s", err)
 	}
 
+	// persist the committed block before sending, so no other block is committed at the height after restart
+	if err := self.signGuard.sign(blkNum, SIGN_COMMIT, blkHash, self.minSignBlockNum()); err != nil {
+		return fmt.Errorf("failed to commit block %d: %s", blkNum, err)
+	}
+
 	// set the block as committed-block
 	if err := self.blockPool.setProposalCommitted(proposal, forEmpty); err != nil {
 		return fmt.Errorf("failed to set proposal as committed: %s", err)
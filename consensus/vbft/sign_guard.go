@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+const SIGN_GUARD_FILE = "vbft_signed.json"
+
+type signKind int
+
+const (
+	SIGN_ENDORSE signKind = iota
+	SIGN_ENDORSE_EMPTY
+	SIGN_COMMIT
+	SIGN_KIND_COUNT
+)
+
+//
+// signGuard persists hashes of blocks signed by server in endorsements and commits, before the msgs are sent.
+// An honest peer signs at most one block of each kind per height, and the guard keeps it so after restart,
+// otherwise blocks signed before and after restart are evidence of equivocation.
+//
+type signGuard struct {
+	lock   sync.Mutex
+	path   string                                     // file of signed blocks, nothing is persisted if empty
+	Blocks map[uint32][SIGN_KIND_COUNT]common.Uint256 `json:"blocks"` // indexed by blockNum
+}
+
+func openSignGuard(path string) (*signGuard, error) {
+	guard := &signGuard{
+		path:   path,
+		Blocks: make(map[uint32][SIGN_KIND_COUNT]common.Uint256),
+	}
+	if path == "" {
+		return guard, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return guard, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read %s: %s", path, err)
+	}
+	if err := json.Unmarshal(data, guard); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %s", path, err)
+	}
+	return guard, nil
+}
+
+//
+// sign records block of kind signed at blkNum and persists it, it fails if another block of the kind is
+// signed at blkNum. Blocks signed before minBlkNum are dropped.
+//
+func (self *signGuard) sign(blkNum uint32, kind signKind, hash common.Uint256, minBlkNum uint32) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	signed := self.Blocks[blkNum]
+	if signed[kind] == hash {
+		return nil
+	}
+	if signed[kind] != common.UINT256_EMPTY {
+		return fmt.Errorf("signed block %s at %d already", signed[kind].ToHexString(), blkNum)
+	}
+	signed[kind] = hash
+	self.Blocks[blkNum] = signed
+	for n := range self.Blocks {
+		if n < minBlkNum {
+			delete(self.Blocks, n)
+		}
+	}
+	return self.save()
+}
+
+func (self *signGuard) save() error {
+	if self.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(self)
+	if err != nil {
+		return err
+	}
+	tmp := self.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, self.path)
+}
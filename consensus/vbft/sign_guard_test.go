@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	gover "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+)
+
+func TestSignGuardRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vbft-sign-guard")
+	if err != nil {
+		t.Fatalf("create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, SIGN_GUARD_FILE)
+
+	blocks := make([]common.Uint256, 6)
+	for i := range blocks {
+		blocks[i][0] = byte(i + 1)
+	}
+	guard, err := openSignGuard(path)
+	if err != nil {
+		t.Fatalf("open sign guard: %s", err)
+	}
+	if err := guard.sign(10, SIGN_ENDORSE, blocks[0], 0); err != nil {
+		t.Fatalf("endorse block: %s", err)
+	}
+	if err := guard.sign(10, SIGN_ENDORSE_EMPTY, blocks[1], 0); err != nil {
+		t.Fatalf("endorse empty block: %s", err)
+	}
+	if err := guard.sign(10, SIGN_COMMIT, blocks[2], 0); err != nil {
+		t.Fatalf("commit block: %s", err)
+	}
+
+	//restart
+	guard, err = openSignGuard(path)
+	if err != nil {
+		t.Fatalf("reopen sign guard: %s", err)
+	}
+	if err := guard.sign(10, SIGN_ENDORSE, blocks[0], 0); err != nil {
+		t.Errorf("endorse same block after restart: %s", err)
+	}
+	if err := guard.sign(10, SIGN_ENDORSE, blocks[3], 0); err == nil {
+		t.Errorf("endorsed another block after restart")
+	}
+	if err := guard.sign(10, SIGN_ENDORSE_EMPTY, blocks[4], 0); err == nil {
+		t.Errorf("endorsed another empty block after restart")
+	}
+	if err := guard.sign(10, SIGN_COMMIT, blocks[5], 0); err == nil {
+		t.Errorf("committed another block after restart")
+	}
+	signed := make(map[common.Uint256]bool)
+	for _, hash := range guard.Blocks[10] {
+		signed[hash] = true
+	}
+	if len(signed) >= gover.MIN_EQUIVOCATION_BLOCKS {
+		t.Errorf("signed %d blocks at height 10", len(signed))
+	}
+
+	if err := guard.sign(11, SIGN_ENDORSE, blocks[3], 0); err != nil {
+		t.Errorf("endorse block at next height: %s", err)
+	}
+	if err := guard.sign(80, SIGN_ENDORSE, blocks[4], 11); err != nil {
+		t.Fatalf("endorse block at height 80: %s", err)
+	}
+	guard, err = openSignGuard(path)
+	if err != nil {
+		t.Fatalf("reopen sign guard: %s", err)
+	}
+	if _, present := guard.Blocks[10]; present {
+		t.Errorf("blocks signed at height 10 are not dropped")
+	}
+	if _, present := guard.Blocks[11]; !present {
+		t.Errorf("blocks signed at height 11 are dropped")
+	}
+}
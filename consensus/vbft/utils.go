@@ -96,7 +96,7 @@ type vrfData struct {
 	PrevVrf  []byte `json:"prev_vrf"`
 }
 
-func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
+func computeVrf(acc *account.Account, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
 	data, err := json.Marshal(&vrfData{
 		BlockNum: blkNum,
 		PrevVrf:  prevVrf,
@@ -105,7 +105,7 @@ func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, [
 		return nil, nil, fmt.Errorf("computeVrf failed to marshal vrfData: %s", err)
 	}
 
-	return vrf.Vrf(sk, data)
+	return acc.Vrf(data)
 }
 
 func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byte) error {
@@ -20,9 +20,12 @@ package ledger
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
 	"github.com/OnyxPay/OnyxChain-legacy/core/states"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store"
@@ -30,6 +33,8 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
 	cstate "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
 var DefLedger *Ledger
@@ -65,7 +70,9 @@ func (self *Ledger) AddHeaders(headers []*types.Header) error {
 }
 
 func (self *Ledger) AddBlock(block *types.Block) error {
+	start := time.Now()
 	err := self.ldgStore.AddBlock(block)
+	metrics.LedgerAddBlockTime.ObserveSince(start)
 	if err != nil {
 		log.Errorf("Ledger AddBlock BlockHeight:%d BlockHash:%x error:%s", block.Header.Height, block.Hash(), err)
 	}
@@ -152,6 +159,10 @@ func (self *Ledger) GetStorageItem(codeHash common.Address, key []byte) ([]byte,
 	return storageItem.Value, nil
 }
 
+func (self *Ledger) GetStorageUsage(contractHash common.Address) (uint64, error) {
+	return self.ldgStore.GetStorageUsage(contractHash)
+}
+
 func (self *Ledger) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	return self.ldgStore.GetContractState(contractHash)
 }
@@ -164,10 +175,22 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) DebugExecuteContract(tx *types.Transaction, hook vm.DebugHook) (*cstate.PreExecResult, error) {
+	return self.ldgStore.DebugExecuteContract(tx, hook)
+}
+
+func (self *Ledger) SimulateTransactions(txs []*types.Transaction, cfg *cstate.SimulateConfig) ([]*cstate.SimulateResult, error) {
+	return self.ldgStore.SimulateTransactions(txs, cfg)
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
 
+func (self *Ledger) GetTxTrace(tx common.Uint256) (*trace.TxTrace, error) {
+	return self.ldgStore.GetTxTrace(tx)
+}
+
 func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
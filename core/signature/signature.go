@@ -26,7 +26,13 @@ import (
 
 // Sign returns the signature of data using privKey
 func Sign(signer Signer, data []byte) ([]byte, error) {
-	signature, err := s.Sign(signer.Scheme(), signer.PrivKey(), data, nil)
+	var signature *s.Signature
+	var err error
+	if ds, ok := signer.(DataSigner); ok {
+		signature, err = ds.SignData(data)
+	} else {
+		signature, err = s.Sign(signer.Scheme(), signer.PrivKey(), data, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
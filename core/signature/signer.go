@@ -33,3 +33,11 @@ type Signer interface {
 
 	Scheme() signature.SignatureScheme
 }
+
+// DataSigner is a Signer which signs data by itself, e.g. the key is kept in an external device
+// and PrivKey returns nil.
+type DataSigner interface {
+	Signer
+
+	SignData(data []byte) (*signature.Signature, error)
+}
@@ -34,6 +34,8 @@ const (
 	ST_VALIDATOR  DataEntryPrefix = 0x07 //no use
 	ST_VOTE       DataEntryPrefix = 0x08 //Vote state key prefix
 
+	ST_STORAGE_USAGE DataEntryPrefix = 0x06 //Smart contract storage usage in bytes key prefix
+
 	IX_HEADER_HASH_LIST DataEntryPrefix = 0x09 //Block height => block hash key prefix
 
 	//SYSTEM
@@ -43,4 +45,5 @@ const (
 	SYS_BLOCK_MERKLE_TREE  DataEntryPrefix = 0x13 // Block merkle tree root key prefix
 
 	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
+	EVENT_TRACE  DataEntryPrefix = 0x15 //Transaction execution trace key prefix
 )
@@ -23,6 +23,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/states"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
 )
 
 var ErrNotFound = errors.New("not found")
@@ -90,6 +91,8 @@ type EventStore interface {
 	SaveEventNotifyByBlock(height uint32, txHashs []common.Uint256) error
 	//GetEventNotifyByTx return event notify by transaction hash
 	GetEventNotifyByTx(txHash common.Uint256) (*event.ExecuteNotify, error)
+	//SaveTxTrace save execution trace of transaction
+	SaveTxTrace(txTrace *trace.TxTrace) error
 	//Commit event notify to store
 	CommitTo() error
 }
@@ -29,6 +29,7 @@ import (
 	scom "github.com/OnyxPay/OnyxChain-legacy/core/store/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/leveldbstore"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
 )
 
 //Saving event notifies gen by smart contract execution
@@ -88,6 +89,33 @@ func (this *EventStore) SaveEventNotifyByBlock(height uint32, txHashs []common.U
 	return nil
 }
 
+//SaveTxTrace persist execution trace of transaction
+func (this *EventStore) SaveTxTrace(txTrace *trace.TxTrace) error {
+	result, err := json.Marshal(txTrace)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error %s", err)
+	}
+	txHash, err := common.Uint256FromHexString(txTrace.TxHash)
+	if err != nil {
+		return err
+	}
+	this.store.BatchPut(this.getTxTraceKey(txHash), result)
+	return nil
+}
+
+//GetTxTrace return execution trace by transaction hash
+func (this *EventStore) GetTxTrace(txHash common.Uint256) (*trace.TxTrace, error) {
+	data, err := this.store.Get(this.getTxTraceKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	var txTrace trace.TxTrace
+	if err = json.Unmarshal(data, &txTrace); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error %s", err)
+	}
+	return &txTrace, nil
+}
+
 //GetEventNotifyByTx return event notify by trasanction hash
 func (this *EventStore) GetEventNotifyByTx(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	key := this.getEventNotifyByTxKey(txHash)
@@ -207,3 +235,11 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func (this *EventStore) getTxTraceKey(txHash common.Uint256) []byte {
+	data := txHash.ToArray()
+	key := make([]byte, 1+len(data))
+	key[0] = byte(scom.EVENT_TRACE)
+	copy(key[1:], data)
+	return key
+}
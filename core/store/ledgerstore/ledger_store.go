@@ -19,12 +19,10 @@
 package ledgerstore
 
 import (
-	"bytes"
 	"fmt"
 	"math"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -33,7 +31,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
-	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	"github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
 	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
 	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
@@ -47,11 +45,13 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract"
 	scommon "github.com/OnyxPay/OnyxChain-legacy/smartcontract/common"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
-	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
 	sstate "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
 const (
@@ -287,11 +287,11 @@ func (this *LedgerStoreImp) initStore() error {
 		}
 		this.eventStore.NewBatch()
 		this.stateStore.NewBatch()
-		err = this.saveBlockToStateStore(block)
+		blockNotify, err := this.saveBlockToStateStore(block)
 		if err != nil {
 			return fmt.Errorf("save to state store height:%d error:%s", i, err)
 		}
-		err = this.saveBlockToEventStore(block)
+		err = this.saveBlockToEventStore(block, blockNotify)
 		if err != nil {
 			return fmt.Errorf("save to event store height:%d error:%s", i, err)
 		}
@@ -549,52 +549,80 @@ func (this *LedgerStoreImp) saveBlockToBlockStore(block *types.Block) error {
 	return nil
 }
 
-func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block) error {
+//saveBlockToStateStore executes block on state store, and returns notify of proposals executed at beginning of
+//block, which is nil if proposals have no notification
+func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block) (*event.ExecuteNotify, error) {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
 
 	overlay := this.stateStore.NewOverlayDB()
-
-	if block.Header.Height != 0 {
-		config := &smartcontract.Config{
-			Time:   block.Header.Timestamp,
-			Height: block.Header.Height,
-			Tx:     &types.Transaction{},
-		}
-
-		if err := refreshGlobalParam(config, storage.NewCacheDB(this.stateStore.NewOverlayDB()), this); err != nil {
-			return err
-		}
+	gasTable, notifies, err := this.beginBlock(overlay, block)
+	if err != nil {
+		return nil, err
 	}
 
 	for _, tx := range block.Transactions {
-		err := this.handleTransaction(overlay, block, tx)
+		err := this.handleTransaction(overlay, gasTable, block, tx)
 		if err != nil {
-			return fmt.Errorf("handleTransaction error %s", err)
+			return nil, fmt.Errorf("handleTransaction error %s", err)
 		}
 	}
 
-	err := this.stateStore.AddMerkleTreeRoot(block.Header.TransactionsRoot)
+	err = this.stateStore.AddMerkleTreeRoot(block.Header.TransactionsRoot)
 	if err != nil {
-		return fmt.Errorf("AddMerkleTreeRoot error %s", err)
+		return nil, fmt.Errorf("AddMerkleTreeRoot error %s", err)
 	}
 
 	err = this.stateStore.SaveCurrentBlock(blockHeight, blockHash)
 	if err != nil {
-		return fmt.Errorf("SaveCurrentBlock error %s", err)
+		return nil, fmt.Errorf("SaveCurrentBlock error %s", err)
 	}
 
 	stateHash := overlay.ChangeHash()
 	log.Debugf("the state transition hash of block %d is:%s", blockHeight, stateHash.ToHexString())
 	overlay.CommitTo()
 
-	return nil
+	if len(notifies) == 0 {
+		return nil, nil
+	}
+	return &event.ExecuteNotify{TxHash: blockHash, State: event.CONTRACT_STATE_SUCCESS, Notify: notifies}, nil
+}
+
+//beginBlock executes proposals which take effect at block on overlay, and returns gas prices of block with
+//notifications of proposals
+func (this *LedgerStoreImp) beginBlock(overlay *overlaydb.OverlayDB,
+	block *types.Block) (neovm.GasTable, []*event.NotifyEventInfo, error) {
+	config := &smartcontract.Config{
+		Time:   block.Header.Timestamp,
+		Height: block.Header.Height,
+		Tx:     &types.Transaction{},
+	}
+	gasTable, err := getGasTable(config, storage.NewCacheDB(overlay), this)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache := storage.NewCacheDB(overlay)
+	notifies, err := executeProposals(config, cache, this)
+	if err != nil {
+		return nil, nil, fmt.Errorf("executeProposals error %s", err)
+	}
+	cache.Commit()
+	return gasTable, notifies, nil
 }
 
-func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block) error {
+//saveBlockToEventStore saves transaction list of block. Notify of proposals executed at beginning of block is
+//saved by block hash, which is the first one in the list.
+func (this *LedgerStoreImp) saveBlockToEventStore(block *types.Block, blockNotify *event.ExecuteNotify) error {
 	blockHash := block.Hash()
 	blockHeight := block.Header.Height
 	txs := make([]common.Uint256, 0)
+	if blockNotify != nil {
+		if err := SaveNotify(this.eventStore, blockHash, blockNotify); err != nil {
+			return err
+		}
+		txs = append(txs, blockHash)
+	}
 	for _, tx := range block.Transactions {
 		txHash := tx.Hash()
 		txs = append(txs, txHash)
@@ -641,6 +669,7 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	if blockHeight > 0 && blockHeight != (this.GetCurrentBlockHeight()+1) {
 		return nil
 	}
+	start := time.Now()
 
 	this.blockStore.NewBatch()
 	this.stateStore.NewBatch()
@@ -649,11 +678,11 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	if err != nil {
 		return fmt.Errorf("save to block store height:%d error:%s", blockHeight, err)
 	}
-	err = this.saveBlockToStateStore(block)
+	blockNotify, err := this.saveBlockToStateStore(block)
 	if err != nil {
 		return fmt.Errorf("save to state store height:%d error:%s", blockHeight, err)
 	}
-	err = this.saveBlockToEventStore(block)
+	err = this.saveBlockToEventStore(block, blockNotify)
 	if err != nil {
 		return fmt.Errorf("save to event store height:%d error:%s", blockHeight, err)
 	}
@@ -671,6 +700,7 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
+	this.updateBlockMetrics(block, start)
 
 	if events.DefActorPublisher != nil {
 		events.DefActorPublisher.Publish(
@@ -682,12 +712,27 @@ func (this *LedgerStoreImp) saveBlock(block *types.Block) error {
 	return nil
 }
 
-func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, block *types.Block, tx *types.Transaction) error {
+func (this *LedgerStoreImp) updateBlockMetrics(block *types.Block, start time.Time) {
+	metrics.BlockProcessTime.ObserveSince(start)
+	metrics.BlockHeight.Set(float64(block.Header.Height))
+	metrics.BlockTxCount.Set(float64(len(block.Transactions)))
+	if block.Header.Height == 0 {
+		return
+	}
+	prevHeader, err := this.GetHeaderByHash(block.Header.PrevBlockHash)
+	if err != nil || prevHeader == nil {
+		return
+	}
+	metrics.BlockInterval.Set(float64(block.Header.Timestamp) - float64(prevHeader.Timestamp))
+}
+
+func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, gasTable neovm.GasTable, block *types.Block,
+	tx *types.Transaction) error {
 	txHash := tx.Hash()
 	notify := &event.ExecuteNotify{TxHash: txHash, State: event.CONTRACT_STATE_FAIL}
 	switch tx.TxType {
 	case types.Deploy:
-		err := this.stateStore.HandleDeployTransaction(this, overlay, tx, block, notify)
+		err := this.stateStore.HandleDeployTransaction(this, overlay, gasTable, tx, block, notify)
 		if overlay.Error() != nil {
 			return fmt.Errorf("HandleDeployTransaction tx %s error %s", txHash.ToHexString(), overlay.Error())
 		}
@@ -696,7 +741,11 @@ func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, bloc
 		}
 		SaveNotify(this.eventStore, txHash, notify)
 	case types.Invoke:
-		err := this.stateStore.HandleInvokeTransaction(this, overlay, tx, block, notify)
+		var tracer *trace.Tracer
+		if config.DefConfig.Common.EnableTxTrace {
+			tracer = trace.NewTracer()
+		}
+		err := this.stateStore.HandleInvokeTransaction(this, overlay, gasTable, tx, block, notify, tracer)
 		if overlay.Error() != nil {
 			return fmt.Errorf("HandleInvokeTransaction tx %s error %s", txHash.ToHexString(), overlay.Error())
 		}
@@ -704,6 +753,12 @@ func (this *LedgerStoreImp) handleTransaction(overlay *overlaydb.OverlayDB, bloc
 			log.Debugf("HandleInvokeTransaction tx %s error %s", txHash.ToHexString(), err)
 		}
 		SaveNotify(this.eventStore, txHash, notify)
+		if tracer != nil {
+			tracer.Finish(err)
+			if err := SaveTxTrace(this.eventStore, tracer.TxTrace(txHash, block.Header.Height, notify)); err != nil {
+				log.Errorf("handleTransaction tx %s error %s", txHash.ToHexString(), err)
+			}
+		}
 	}
 	return nil
 }
@@ -809,6 +864,11 @@ func (this *LedgerStoreImp) GetMerkleProof(proofHeight, rootHeight uint32) ([]co
 	return this.stateStore.GetMerkleProof(proofHeight, rootHeight)
 }
 
+//GetStorageUsage return bytes of storage tracked for contract. Wrap function of StateStore.GetStorageUsage
+func (this *LedgerStoreImp) GetStorageUsage(contractHash common.Address) (uint64, error) {
+	return this.stateStore.GetStorageUsage(contractHash)
+}
+
 //GetContractState return contract by contract address. Wrap function of StateStore.GetContractState
 func (this *LedgerStoreImp) GetContractState(contractHash common.Address) (*payload.DeployCode, error) {
 	return this.stateStore.GetContractState(contractHash)
@@ -819,6 +879,11 @@ func (this *LedgerStoreImp) GetStorageItem(key *states.StorageKey) (*states.Stor
 	return this.stateStore.GetStorageState(key)
 }
 
+//GetTxTrace return execution trace of transaction recorded when tx trace enabled. Wrap function of EventStore.GetTxTrace
+func (this *LedgerStoreImp) GetTxTrace(tx common.Uint256) (*trace.TxTrace, error) {
+	return this.eventStore.GetTxTrace(tx)
+}
+
 //GetEventNotifyByTx return the events notify gen by executing of smart contract.  Wrap function of EventStore.GetEventNotifyByTx
 func (this *LedgerStoreImp) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return this.eventStore.GetEventNotifyByTx(tx)
@@ -831,6 +896,15 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 
 //PreExecuteContract return the result of smart contract execution without commit to store
 func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.PreExecResult, error) {
+	return this.preExecuteContract(tx, nil)
+}
+
+//DebugExecuteContract pre-executes transaction with hook attached to every neovm engine
+func (this *LedgerStoreImp) DebugExecuteContract(tx *types.Transaction, hook vm.DebugHook) (*sstate.PreExecResult, error) {
+	return this.preExecuteContract(tx, hook)
+}
+
+func (this *LedgerStoreImp) preExecuteContract(tx *types.Transaction, hook vm.DebugHook) (*sstate.PreExecResult, error) {
 	height := this.GetCurrentBlockHeight()
 	stf := &sstate.PreExecResult{State: event.CONTRACT_STATE_FAIL, Gas: neovm.MIN_TRANSACTION_GAS, Result: nil}
 
@@ -843,20 +917,22 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.P
 
 	overlay := this.stateStore.NewOverlayDB()
 	cache := storage.NewCacheDB(overlay)
-	preGas, err := this.getPreGas(config, cache)
+	gasTable, err := getGasTable(config, cache, this)
 	if err != nil {
 		return stf, err
 	}
+	config.GasTable = gasTable
 
 	if tx.TxType == types.Invoke {
 		invoke := tx.Payload.(*payload.InvokeCode)
 
 		sc := smartcontract.SmartContract{
-			Config:  config,
-			Store:   this,
-			CacheDB: cache,
-			Gas:     math.MaxUint64 - calcGasByCodeLen(len(invoke.Code), preGas[neovm.UINT_INVOKE_CODE_LEN_NAME]),
-			PreExec: true,
+			Config:    config,
+			Store:     this,
+			CacheDB:   cache,
+			Gas:       math.MaxUint64 - calcGasByCodeLen(len(invoke.Code), gasTable[neovm.UINT_INVOKE_CODE_LEN_NAME]),
+			PreExec:   true,
+			DebugHook: hook,
 		}
 
 		//start the smart contract executive function
@@ -877,53 +953,140 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*sstate.P
 		return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Gas: gasCost, Result: cv}, nil
 	} else if tx.TxType == types.Deploy {
 		deploy := tx.Payload.(*payload.DeployCode)
-		return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Gas: preGas[neovm.CONTRACT_CREATE_NAME] + calcGasByCodeLen(len(deploy.Code), preGas[neovm.UINT_DEPLOY_CODE_LEN_NAME]), Result: nil}, nil
+		return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Gas: gasTable[neovm.CONTRACT_CREATE_NAME] + calcGasByCodeLen(len(deploy.Code), gasTable[neovm.UINT_DEPLOY_CODE_LEN_NAME]), Result: nil}, nil
 	} else {
 		return stf, errors.NewErr("transaction type error")
 	}
 }
 
-func (this *LedgerStoreImp) getPreGas(config *smartcontract.Config, cache *storage.CacheDB) (map[string]uint64, error) {
-	bf := new(bytes.Buffer)
-	names := []string{neovm.CONTRACT_CREATE_NAME, neovm.UINT_INVOKE_CODE_LEN_NAME, neovm.UINT_DEPLOY_CODE_LEN_NAME}
-	if err := utils.WriteVarUint(bf, uint64(len(names))); err != nil {
-		return nil, fmt.Errorf("write gas_table_keys length error:%s", err)
+//SimulateTransactions executes transactions in sequence on one overlay of current state with cfg overrides applied,
+//and discards the state after. Transaction without signature takes payer as witness, so it can be previewed before signing.
+func (this *LedgerStoreImp) SimulateTransactions(txs []*types.Transaction, cfg *sstate.SimulateConfig) ([]*sstate.SimulateResult, error) {
+	if cfg == nil {
+		cfg = &sstate.SimulateConfig{}
 	}
-
-	for _, v := range names {
-		if err := serialization.WriteString(bf, v); err != nil {
-			return nil, fmt.Errorf("serialize param name error:%s", err)
-		}
+	height := this.GetCurrentBlockHeight()
+	config := &smartcontract.Config{
+		Time:      uint32(time.Now().Unix()),
+		Height:    height + 1,
+		BlockHash: this.GetBlockHash(height),
 	}
-
-	sc := smartcontract.SmartContract{
-		Config:  config,
-		CacheDB: cache,
-		Store:   this,
-		Gas:     math.MaxUint64,
+	if cfg.Height != 0 {
+		config.Height = cfg.Height
+	}
+	if cfg.Time != 0 {
+		config.Time = cfg.Time
 	}
 
-	service, _ := sc.NewNativeService()
-	result, err := service.NativeCall(utils.ParamContractAddress, "getGlobalParam", bf.Bytes())
+	overlay := this.stateStore.NewOverlayDB()
+	cache := storage.NewCacheDB(overlay)
+	if err := applyStateOverrides(cache, cfg); err != nil {
+		return nil, err
+	}
+	cache.Commit()
+	gasTable, err := getGasTable(config, storage.NewCacheDB(overlay), this)
 	if err != nil {
 		return nil, err
 	}
-	params := new(global_params.Params)
-	if err := params.Deserialize(bytes.NewBuffer(result.([]byte))); err != nil {
-		return nil, fmt.Errorf("deserialize global params error:%s", err)
+	config.GasTable = gasTable
+
+	results := make([]*sstate.SimulateResult, 0, len(txs))
+	for _, tx := range txs {
+		if len(tx.Sigs) == 0 {
+			//unsigned tx is simulated as signed by payer, on a copy which leaves tx of caller unchanged
+			signed := *tx
+			signed.SignedAddr = []common.Address{tx.Payer}
+			tx = &signed
+		}
+		txConfig := *config
+		txConfig.Tx = tx
+		results = append(results, this.simulateTransaction(overlay, &txConfig, tx))
 	}
-	m := make(map[string]uint64, 0)
-	for _, v := range names {
-		n, ps := params.GetParam(v)
-		if n != -1 && ps.Value != "" {
-			pu, err := strconv.ParseUint(ps.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse uint %v", err)
-			}
-			m[v] = pu
+	return results, nil
+}
+
+func (this *LedgerStoreImp) simulateTransaction(overlay *overlaydb.OverlayDB, config *smartcontract.Config,
+	tx *types.Transaction) *sstate.SimulateResult {
+	result := &sstate.SimulateResult{TxHash: tx.Hash(), State: event.CONTRACT_STATE_FAIL, Gas: neovm.MIN_TRANSACTION_GAS}
+	gasTable := config.GasTable
+
+	cache := storage.NewCacheDB(overlay)
+	switch tx.TxType {
+	case types.Invoke:
+		invoke := tx.Payload.(*payload.InvokeCode)
+		sc := smartcontract.SmartContract{
+			Config:  config,
+			Store:   this,
+			CacheDB: cache,
+			Gas:     math.MaxUint64 - calcGasByCodeLen(len(invoke.Code), gasTable[neovm.UINT_INVOKE_CODE_LEN_NAME]),
+			PreExec: true,
+		}
+		engine, _ := sc.NewExecuteEngine(invoke.Code)
+		ret, err := engine.Invoke()
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		cv, err := scommon.ConvertNeoVmTypeHexString(ret)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if gasCost := math.MaxUint64 - sc.Gas; gasCost > result.Gas {
+			result.Gas = gasCost
+		}
+		result.Result = cv
+		result.Notify = sc.Notifications
+	case types.Deploy:
+		deploy := tx.Payload.(*payload.DeployCode)
+		dep, err := cache.GetContract(deploy.Address())
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if dep == nil {
+			cache.PutContract(deploy)
+		}
+		result.Gas = gasTable[neovm.CONTRACT_CREATE_NAME] + calcGasByCodeLen(len(deploy.Code), gasTable[neovm.UINT_DEPLOY_CODE_LEN_NAME])
+	default:
+		result.Error = "transaction type error"
+		return result
+	}
+	cache.Commit()
+	result.State = event.CONTRACT_STATE_SUCCESS
+	return result
+}
+
+func applyStateOverrides(cache *storage.CacheDB, cfg *sstate.SimulateConfig) error {
+	for _, balance := range cfg.Balances {
+		if balance.Asset != utils.OnxContractAddress && balance.Asset != utils.OxgContractAddress {
+			return fmt.Errorf("balance override of asset %s is not supported, override its storage instead",
+				balance.Asset.ToHexString())
+		}
+		cache.Put(onx.GenBalanceKey(balance.Asset, balance.Account), utils.GenUInt64StorageItem(balance.Value).ToArray())
+	}
+	for _, item := range cfg.Storage {
+		key := append(item.Contract[:], item.Key...)
+		if len(item.Value) == 0 {
+			cache.Delete(key)
+		} else {
+			cache.Put(key, states.GenRawStorageItem(item.Value))
+		}
+	}
+	for _, override := range cfg.Contracts {
+		contract, err := cache.GetContract(override.Contract)
+		if err != nil {
+			return fmt.Errorf("get contract %s error %s", override.Contract.ToHexString(), err)
+		}
+		if contract == nil {
+			contract = &payload.DeployCode{NeedStorage: true}
+		}
+		contract.Code = override.Code
+		if err := cache.PutContractAt(override.Contract, contract); err != nil {
+			return err
 		}
 	}
-	return m, nil
+	return nil
 }
 
 //Close ledger store.
@@ -27,8 +27,17 @@ import (
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
 	"github.com/OnyxPay/OnyxChain-legacy/account"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/core/genesis"
+	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	sstate "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
 var testBlockStore *BlockStore
@@ -134,3 +143,114 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestApplyStateOverrides(t *testing.T) {
+	addr := common.AddressFromVmCode([]byte("account"))
+	contract := common.AddressFromVmCode([]byte("contract"))
+	cfg := &sstate.SimulateConfig{
+		Balances:  []*sstate.BalanceOverride{{Asset: utils.OxgContractAddress, Account: addr, Value: 100}},
+		Storage:   []*sstate.StorageOverride{{Contract: contract, Key: []byte("key"), Value: []byte("value")}},
+		Contracts: []*sstate.ContractOverride{{Contract: contract, Code: []byte("code")}},
+	}
+	cache := storage.NewCacheDB(testStateStore.NewOverlayDB())
+	if err := applyStateOverrides(cache, cfg); err != nil {
+		t.Errorf("applyStateOverrides error %s", err)
+		return
+	}
+
+	balance, err := cache.Get(onx.GenBalanceKey(utils.OxgContractAddress, addr))
+	if err != nil {
+		t.Errorf("get balance error %s", err)
+		return
+	}
+	if item, err := states.GetValueFromRawStorageItem(balance); err != nil || len(item) != 8 || item[0] != 100 {
+		t.Errorf("balance override failed %x", balance)
+	}
+	value, err := cache.Get(append(contract[:], "key"...))
+	if err != nil {
+		t.Errorf("get storage error %s", err)
+		return
+	}
+	if item, err := states.GetValueFromRawStorageItem(value); err != nil || string(item) != "value" {
+		t.Errorf("storage override failed %x", value)
+	}
+	dep, err := cache.GetContract(contract)
+	if err != nil || dep == nil || string(dep.Code) != "code" {
+		t.Errorf("contract override failed %v", err)
+	}
+
+	cfg = &sstate.SimulateConfig{
+		Balances: []*sstate.BalanceOverride{{Asset: contract, Account: addr, Value: 100}},
+	}
+	if err := applyStateOverrides(cache, cfg); err == nil {
+		t.Errorf("balance override of non native asset should fail")
+	}
+}
+
+func TestGetTxTrace(t *testing.T) {
+	config.DefConfig.Common.EnableTxTrace = true
+	defer func() { config.DefConfig.Common.EnableTxTrace = false }()
+	ledgerStore, err := NewLedgerStore("test/trace")
+	if err != nil {
+		t.Fatalf("NewLedgerStore error %s", err)
+	}
+	defer ledgerStore.Close()
+	bookkeepers, err := config.DefConfig.GetBookkeepers()
+	if err != nil {
+		t.Fatalf("GetBookkeepers error %s", err)
+	}
+	genesisBlock, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	if err != nil {
+		t.Fatalf("BuildGenesisBlock error %s", err)
+	}
+	if err := ledgerStore.InitLedgerStoreWithGenesisBlock(genesisBlock, bookkeepers); err != nil {
+		t.Fatalf("InitLedgerStoreWithGenesisBlock error %s", err)
+	}
+
+	var txs []*types.Transaction
+	for i, code := range [][]byte{{byte(vm.PUSH1), byte(vm.RET)}, {byte(vm.PUSH2), byte(vm.PUSH3), byte(vm.ADD), byte(vm.RET)}} {
+		mutable := &types.MutableTransaction{
+			TxType:   types.Invoke,
+			Nonce:    uint32(i),
+			GasLimit: 20000,
+			Payload:  &payload.InvokeCode{Code: code},
+		}
+		tx, err := mutable.IntoImmutable()
+		if err != nil {
+			t.Fatalf("IntoImmutable error %s", err)
+		}
+		txs = append(txs, tx)
+	}
+	block := &types.Block{
+		Header: &types.Header{
+			PrevBlockHash: genesisBlock.Hash(),
+			Timestamp:     genesisBlock.Header.Timestamp + 1,
+			Height:        1,
+		},
+		Transactions: txs,
+	}
+	if err := ledgerStore.saveBlock(block); err != nil {
+		t.Fatalf("saveBlock error %s", err)
+	}
+
+	txTrace, err := ledgerStore.GetTxTrace(txs[1].Hash())
+	if err != nil {
+		t.Fatalf("GetTxTrace error %s", err)
+	}
+	if txTrace.Height != 1 || txTrace.Error != "" || len(txTrace.Calls) != 1 {
+		t.Fatalf("GetTxTrace unexpected trace %+v", txTrace)
+	}
+	ops := []string{"PUSH2", "PUSH3", "ADD", "RET"}
+	steps := txTrace.Calls[0].Steps
+	if len(steps) != len(ops) {
+		t.Fatalf("GetTxTrace steps %d != %d", len(steps), len(ops))
+	}
+	for i, step := range steps {
+		if step.Op != ops[i] {
+			t.Errorf("GetTxTrace step %d op %s != %s", i, step.Op, ops[i])
+		}
+	}
+	if _, err := ledgerStore.GetTxTrace(common.Uint256{1}); err == nil {
+		t.Errorf("GetTxTrace of unknown transaction should fail")
+	}
+}
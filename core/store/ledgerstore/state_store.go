@@ -180,6 +180,25 @@ func (self *StateStore) GetContractState(contractHash common.Address) (*payload.
 	return contractState, nil
 }
 
+//GetStorageUsage return bytes of storage tracked for contract
+func (self *StateStore) GetStorageUsage(contractHash common.Address) (uint64, error) {
+	key := make([]byte, 1+common.ADDR_LEN)
+	key[0] = byte(scom.ST_STORAGE_USAGE)
+	copy(key[1:], contractHash[:])
+	value, err := self.store.Get(key)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	usage, eof := common.NewZeroCopySource(value).NextUint64()
+	if eof {
+		return 0, fmt.Errorf("invalid storage usage of contract %s", contractHash.ToHexString())
+	}
+	return usage, nil
+}
+
 //GetBookkeeperState return current book keeper states
 func (self *StateStore) GetBookkeeperState() (*states.BookkeeperState, error) {
 	key, err := self.getBookkeeperKey()
@@ -29,24 +29,30 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
 	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
 	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store"
 	scommon "github.com/OnyxPay/OnyxChain-legacy/core/store/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/overlaydb"
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/context"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/contractabi"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
 	ninit "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/init"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
 	ntypes "github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
 )
 
 //HandleDeployTransaction deal with smart contract deploy transaction
-func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay *overlaydb.OverlayDB,
+func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay *overlaydb.OverlayDB, gasTable neovm.GasTable,
 	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify) error {
 	deploy := tx.Payload.(*payload.DeployCode)
 	var (
@@ -63,20 +69,21 @@ func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay
 			Height:    block.Header.Height,
 			Tx:        tx,
 			BlockHash: block.Hash(),
+			GasTable:  gasTable,
 		}
-		createGasPrice, ok := neovm.GAS_TABLE.Load(neovm.CONTRACT_CREATE_NAME)
+		createGasPrice, ok := gasTable.Price(neovm.CONTRACT_CREATE_NAME)
 		if !ok {
 			overlay.SetError(errors.NewErr("[HandleDeployTransaction] get CONTRACT_CREATE_NAME gas failed"))
 			return nil
 		}
 
-		uintCodePrice, ok := neovm.GAS_TABLE.Load(neovm.UINT_DEPLOY_CODE_LEN_NAME)
+		uintCodePrice, ok := gasTable.Price(neovm.UINT_DEPLOY_CODE_LEN_NAME)
 		if !ok {
 			overlay.SetError(errors.NewErr("[HandleDeployTransaction] get UINT_DEPLOY_CODE_LEN_NAME gas failed"))
 			return nil
 		}
 
-		gasLimit := createGasPrice.(uint64) + calcGasByCodeLen(len(deploy.Code), uintCodePrice.(uint64))
+		gasLimit := createGasPrice + calcGasByCodeLen(len(deploy.Code), uintCodePrice)
 		balance, err := isBalanceSufficient(tx.Payer, cache, config, store, gasLimit*tx.GasPrice)
 		if err != nil {
 			if err := costInvalidGas(tx.Payer, balance, config, overlay, store, notify); err != nil {
@@ -108,6 +115,23 @@ func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay
 	}
 	if dep == nil {
 		cache.PutContract(deploy)
+		if config.DefConfig.Genesis.Features.ContractAbiActive(block.Header.Height) {
+			contractabi.PutDeployer(cache, address, tx.Payer)
+		}
+		if config.DefConfig.Genesis.Features.ContractUpgradeActive(block.Header.Height) {
+			versionNotify, err := upgrade.AddVersion(cache, address, &upgrade.ContractVersion{
+				Action:   upgrade.ACTION_DEPLOY,
+				Address:  address,
+				CodeHash: address,
+				Version:  deploy.Version,
+				Height:   block.Header.Height,
+				TxHash:   tx.Hash(),
+			})
+			if err != nil {
+				return err
+			}
+			notifies = append(notifies, versionNotify)
+		}
 	}
 	cache.Commit()
 
@@ -117,9 +141,9 @@ func (self *StateStore) HandleDeployTransaction(store store.LedgerStore, overlay
 	return nil
 }
 
-//HandleInvokeTransaction deal with smart contract invoke transaction
-func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay *overlaydb.OverlayDB,
-	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify) error {
+//HandleInvokeTransaction deal with smart contract invoke transaction, tracer records execution if not nil
+func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay *overlaydb.OverlayDB, gasTable neovm.GasTable,
+	tx *types.Transaction, block *types.Block, notify *event.ExecuteNotify, tracer *trace.Tracer) error {
 	invoke := tx.Payload.(*payload.InvokeCode)
 	code := invoke.Code
 	sysTransFlag := bytes.Compare(code, ninit.COMMIT_DPOS_BYTES) == 0 || block.Header.Height == 0
@@ -132,6 +156,7 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 		Height:    block.Header.Height,
 		Tx:        tx,
 		BlockHash: block.Hash(),
+		GasTable:  gasTable,
 	}
 
 	var (
@@ -147,7 +172,7 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 	cache := storage.NewCacheDB(overlay)
 	availableGasLimit = tx.GasLimit
 	if isCharge {
-		uintCodeGasPrice, ok := neovm.GAS_TABLE.Load(neovm.UINT_INVOKE_CODE_LEN_NAME)
+		uintCodeGasPrice, ok := gasTable.Price(neovm.UINT_INVOKE_CODE_LEN_NAME)
 		if !ok {
 			overlay.SetError(errors.NewErr("[HandleInvokeTransaction] get UINT_INVOKE_CODE_LEN_NAME gas failed"))
 			return nil
@@ -167,7 +192,7 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 			return fmt.Errorf("balance gas: %d less than min gas: %d", oldBalance, minGas)
 		}
 
-		codeLenGasLimit = calcGasByCodeLen(len(invoke.Code), uintCodeGasPrice.(uint64))
+		codeLenGasLimit = calcGasByCodeLen(len(invoke.Code), uintCodeGasPrice)
 
 		if oldBalance < codeLenGasLimit*tx.GasPrice {
 			if err := costInvalidGas(tx.Payer, oldBalance, config, overlay, store, notify); err != nil {
@@ -196,6 +221,10 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 		Store:   store,
 		Gas:     availableGasLimit - codeLenGasLimit,
 	}
+	if tracer != nil {
+		tracer.GasLeft = func() uint64 { return sc.Gas }
+		sc.DebugHook = tracer
+	}
 
 	//start the smart contract executive function
 	engine, _ := sc.NewExecuteEngine(invoke.Code)
@@ -203,6 +232,9 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 	_, err = engine.Invoke()
 
 	costGasLimit = availableGasLimit - sc.Gas
+	if err == nil {
+		costGasLimit -= calcGasRefund(costGasLimit, sc.GasRefund)
+	}
 	if costGasLimit < neovm.MIN_TRANSACTION_GAS {
 		costGasLimit = neovm.MIN_TRANSACTION_GAS
 	}
@@ -255,6 +287,13 @@ func SaveNotify(eventStore scommon.EventStore, txHash common.Uint256, notify *ev
 	return nil
 }
 
+func SaveTxTrace(eventStore scommon.EventStore, txTrace *trace.TxTrace) error {
+	if err := eventStore.SaveTxTrace(txTrace); err != nil {
+		return fmt.Errorf("SaveTxTrace error %s", err)
+	}
+	return nil
+}
+
 func genNativeTransferCode(from, to common.Address, value uint64) []byte {
 	transfer := onx.Transfers{States: []onx.State{{From: from, To: to, Value: value}}}
 	tr := new(bytes.Buffer)
@@ -294,14 +333,53 @@ func chargeCostGas(payer common.Address, gas uint64, config *smartcontract.Confi
 	return sc.Notifications, nil
 }
 
-func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore) error {
+//executeProposals executes governance proposals scheduled at height of config, in context of governance contract,
+//and returns notifications of them
+func executeProposals(config *smartcontract.Config, cache *storage.CacheDB,
+	store store.LedgerStore) ([]*event.NotifyEventInfo, error) {
+	sc := smartcontract.SmartContract{
+		Config:  config,
+		CacheDB: cache,
+		Store:   store,
+		Gas:     math.MaxUint64,
+	}
+	service, err := sc.NewNativeService()
+	if err != nil {
+		return nil, err
+	}
+	sc.PushContext(&context.Context{ContractAddress: utils.GovernanceContractAddress})
+	defer sc.PopContext()
+	if err := governance.ExecuteProposals(service); err != nil {
+		return nil, err
+	}
+	return append(sc.Notifications, service.Notifications...), nil
+}
+
+//getGasTable resolves gas prices of block at config.Height. Gas schedule active at the height takes precedence,
+//otherwise gas params of global params contract are used.
+func getGasTable(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore) (neovm.GasTable, error) {
+	table := neovm.DefaultGasTable()
+	if config.Height == 0 {
+		return table, nil
+	}
+	schedule, err := global_params.GetGasScheduleAt(cacheStorageGetter(cache, utils.ParamContractAddress), config.Height)
+	if err != nil {
+		return nil, fmt.Errorf("get gas schedule error:%s", err)
+	}
+	if schedule != nil {
+		for name, price := range schedule.Prices {
+			table[name] = price
+		}
+		return table, nil
+	}
+
 	bf := new(bytes.Buffer)
 	if err := utils.WriteVarUint(bf, uint64(len(neovm.GAS_TABLE_KEYS))); err != nil {
-		return fmt.Errorf("write gas_table_keys length error:%s", err)
+		return nil, fmt.Errorf("write gas_table_keys length error:%s", err)
 	}
 	for _, value := range neovm.GAS_TABLE_KEYS {
 		if err := serialization.WriteString(bf, value); err != nil {
-			return fmt.Errorf("serialize param name error:%s", value)
+			return nil, fmt.Errorf("serialize param name error:%s", value)
 		}
 	}
 
@@ -315,26 +393,34 @@ func refreshGlobalParam(config *smartcontract.Config, cache *storage.CacheDB, st
 	service, _ := sc.NewNativeService()
 	result, err := service.NativeCall(utils.ParamContractAddress, "getGlobalParam", bf.Bytes())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	params := new(global_params.Params)
 	if err := params.Deserialize(bytes.NewBuffer(result.([]byte))); err != nil {
-		return fmt.Errorf("deserialize global params error:%s", err)
+		return nil, fmt.Errorf("deserialize global params error:%s", err)
 	}
-	neovm.GAS_TABLE.Range(func(key, value interface{}) bool {
-		n, ps := params.GetParam(key.(string))
+	for _, key := range neovm.GAS_TABLE_KEYS {
+		n, ps := params.GetParam(key)
 		if n != -1 && ps.Value != "" {
 			pu, err := strconv.ParseUint(ps.Value, 10, 64)
 			if err != nil {
-				log.Errorf("[refreshGlobalParam] failed to parse uint %v\n", ps.Value)
+				log.Errorf("[getGasTable] failed to parse uint %v\n", ps.Value)
 			} else {
-				neovm.GAS_TABLE.Store(key, pu)
-
+				table[key] = pu
 			}
 		}
-		return true
-	})
-	return nil
+	}
+	return table, nil
+}
+
+func cacheStorageGetter(cache *storage.CacheDB, contract common.Address) global_params.StorageGetter {
+	return func(key []byte) ([]byte, error) {
+		raw, err := cache.Get(utils.ConcatKey(contract, key))
+		if err != nil || raw == nil {
+			return nil, err
+		}
+		return states.GetValueFromRawStorageItem(raw)
+	}
 }
 
 func getBalanceFromNative(config *smartcontract.Config, cache *storage.CacheDB, store store.LedgerStore, address common.Address) (uint64, error) {
@@ -370,6 +456,14 @@ func costInvalidGas(address common.Address, gas uint64, config *smartcontract.Co
 	return nil
 }
 
+//calcGasRefund caps refund of freed storage to half of gas used by transaction
+func calcGasRefund(gasUsed, refund uint64) uint64 {
+	if max := gasUsed / 2; refund > max {
+		return max
+	}
+	return refund
+}
+
 func calcGasByCodeLen(codeLen int, codeGas uint64) uint64 {
 	return uint64(codeLen/neovm.PER_UNIT_CODE_LEN) * codeGas
 }
@@ -26,6 +26,8 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
 	cstates "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
 // LedgerStore provides func with store package.
@@ -49,9 +51,13 @@ type LedgerStore interface {
 	GetBlockRootWithNewTxRoot(txRoot common.Uint256) common.Uint256
 	GetMerkleProof(m, n uint32) ([]common.Uint256, error)
 	GetContractState(contractHash common.Address) (*payload.DeployCode, error)
+	GetStorageUsage(contractHash common.Address) (uint64, error)
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	DebugExecuteContract(tx *types.Transaction, hook vm.DebugHook) (*cstates.PreExecResult, error)
+	SimulateTransactions(txs []*types.Transaction, cfg *cstates.SimulateConfig) ([]*cstates.SimulateResult, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetTxTrace(tx common.Uint256) (*trace.TxTrace, error)
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
 	cstate "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
 )
 
 const (
@@ -72,6 +73,11 @@ func GetStorageItem(address common.Address, key []byte) ([]byte, error) {
 	return ledger.DefLedger.GetStorageItem(address, key)
 }
 
+//GetStorageUsage of contract from ledger
+func GetStorageUsage(address common.Address) (uint64, error) {
+	return ledger.DefLedger.GetStorageUsage(address)
+}
+
 //GetContractStateFromStore from ledger
 func GetContractStateFromStore(hash common.Address) (*payload.DeployCode, error) {
 	hash = updateNativeSCAddr(hash)
@@ -89,11 +95,21 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//SimulateTransactions from ledger
+func SimulateTransactions(txs []*types.Transaction, cfg *cstate.SimulateConfig) ([]*cstate.SimulateResult, error) {
+	return ledger.DefLedger.SimulateTransactions(txs, cfg)
+}
+
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByTx(txHash)
 }
 
+//GetTxTrace from ledger
+func GetTxTrace(txHash common.Uint256) (*trace.TxTrace, error) {
+	return ledger.DefLedger.GetTxTrace(txHash)
+}
+
 //GetEventNotifyByHeight from ledger
 func GetEventNotifyByHeight(height uint32) ([]*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByBlock(height)
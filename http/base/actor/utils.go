@@ -37,6 +37,20 @@ func updateNativeSCAddr(hash common.Address) common.Address {
 		hash = common.AddressFromVmCode(utils.AuthContractAddress[:])
 	} else if hash == utils.GovernanceContractAddress {
 		hash = common.AddressFromVmCode(utils.GovernanceContractAddress[:])
+	} else if hash == utils.AbiContractAddress {
+		hash = common.AddressFromVmCode(utils.AbiContractAddress[:])
+	} else if hash == utils.UpgradeContractAddress {
+		hash = common.AddressFromVmCode(utils.UpgradeContractAddress[:])
+	} else if hash == utils.AssetContractAddress {
+		hash = common.AddressFromVmCode(utils.AssetContractAddress[:])
+	} else if hash == utils.EscrowContractAddress {
+		hash = common.AddressFromVmCode(utils.EscrowContractAddress[:])
+	} else if hash == utils.HtlcContractAddress {
+		hash = common.AddressFromVmCode(utils.HtlcContractAddress[:])
+	} else if hash == utils.ClaimContractAddress {
+		hash = common.AddressFromVmCode(utils.ClaimContractAddress[:])
+	} else if hash == utils.NameContractAddress {
+		hash = common.AddressFromVmCode(utils.NameContractAddress[:])
 	}
 	return hash
 }
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type RoleGrantInfo struct {
+	OnxID      string
+	Role       string
+	Level      uint8
+	ExpireTime uint32
+	Delegator  string
+	Expired    bool
+}
+
+type RoleInfo struct {
+	Role   string
+	Funcs  []string
+	Grants []*RoleGrantInfo
+}
+
+type ContractRolesInfo struct {
+	Contract string
+	Admin    string
+	Roles    []*RoleInfo
+}
+
+//GetContractRoles returns admin of contract, and functions and holders of its roles in auth contract
+func GetContractRoles(contract common.Address) (*ContractRolesInfo, error) {
+	roles := new(auth.ContractRoles)
+	param := &auth.ContractRolesParam{ContractAddr: contract}
+	if err := preExecuteNative(utils.AuthContractAddress, "getContractRoles", param, roles); err != nil {
+		return nil, err
+	}
+	info := &ContractRolesInfo{
+		Contract: contract.ToHexString(),
+		Admin:    string(roles.Admin),
+		Roles:    make([]*RoleInfo, 0, len(roles.Roles)),
+	}
+	for _, role := range roles.Roles {
+		info.Roles = append(info.Roles, &RoleInfo{
+			Role:   string(role.Role),
+			Funcs:  role.Funcs,
+			Grants: roleGrantInfos(role.Grants),
+		})
+	}
+	return info, nil
+}
+
+//GetOnxIDRoles returns roles held by ONX ID in contract, including expired delegates
+func GetOnxIDRoles(contract common.Address, onxID string) ([]*RoleGrantInfo, error) {
+	roles := new(auth.OnxIDRoles)
+	param := &auth.OnxIDRolesParam{ContractAddr: contract, OnxID: []byte(onxID)}
+	if err := preExecuteNative(utils.AuthContractAddress, "getOnxIDRoles", param, roles); err != nil {
+		return nil, err
+	}
+	return roleGrantInfos(roles.Grants), nil
+}
+
+func roleGrantInfos(grants []*auth.RoleGrant) []*RoleGrantInfo {
+	infos := make([]*RoleGrantInfo, 0, len(grants))
+	for _, grant := range grants {
+		infos = append(infos, &RoleGrantInfo{
+			OnxID:      string(grant.OnxID),
+			Role:       string(grant.Role),
+			Level:      grant.Level,
+			ExpireTime: grant.ExpireTime,
+			Delegator:  string(grant.Delegator),
+			Expired:    grant.Expired,
+		})
+	}
+	return infos
+}
@@ -22,21 +22,28 @@ package common
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/abi"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/constants"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
 	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
 	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
 	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	scom "github.com/OnyxPay/OnyxChain-legacy/core/store/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	onxErrors "github.com/OnyxPay/OnyxChain-legacy/errors"
 	bactor "github.com/OnyxPay/OnyxChain-legacy/http/base/actor"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/contractabi"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	svrneovm "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
 	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 	"math/big"
 	"reflect"
@@ -51,6 +58,11 @@ type BalanceOfRsp struct {
 	Oxg string `json:"oxg"`
 }
 
+type AssetBalanceRsp struct {
+	Asset   string `json:"asset"`
+	Balance string `json:"balance"`
+}
+
 type MerkleProof struct {
 	Type             string
 	TransactionsRoot string
@@ -76,6 +88,7 @@ type ExecuteNotify struct {
 type NotifyEventInfo struct {
 	ContractAddress string
 	States          interface{}
+	Decoded         *abi.DecodedEvent `json:",omitempty"`
 }
 
 type TxAttributeInfo struct {
@@ -110,6 +123,7 @@ type Transactions struct {
 	Sigs       []Sig
 	Hash       string
 	Height     uint32
+	Decoded    *abi.DecodedInvoke `json:",omitempty"`
 }
 
 type BlockHead struct {
@@ -163,6 +177,40 @@ type TXNEntryInfo struct {
 	State []TXNAttrInfo // the result from each validator
 }
 
+type SimulateConfig struct {
+	Height    uint32
+	Time      uint32
+	Balances  []BalanceOverride
+	Storage   []StorageOverride
+	Contracts []ContractOverride
+}
+
+type BalanceOverride struct {
+	Asset   string // onyx or oxg
+	Account string
+	Value   uint64
+}
+
+type StorageOverride struct {
+	Contract string
+	Key      string
+	Value    string
+}
+
+type ContractOverride struct {
+	Contract string
+	Code     string
+}
+
+type SimulateResult struct {
+	TxHash string
+	State  byte
+	Gas    uint64
+	Result interface{}
+	Notify []NotifyEventInfo
+	Error  string `json:",omitempty"`
+}
+
 func GetLogEvent(obj *event.LogEventArgs) (map[string]bool, LogEventArgs) {
 	hash := obj.TxHash
 	addr := obj.ContractAddress.ToHexString()
@@ -174,7 +222,7 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 	evts := []NotifyEventInfo{}
 	var contractAddrs = make(map[string]bool)
 	for _, v := range obj.Notify {
-		evts = append(evts, NotifyEventInfo{v.ContractAddress.ToHexString(), v.States})
+		evts = append(evts, NotifyEventInfo{ContractAddress: v.ContractAddress.ToHexString(), States: v.States})
 		contractAddrs[v.ContractAddress.ToHexString()] = true
 	}
 	txhash := obj.TxHash.ToHexString()
@@ -276,6 +324,104 @@ func GetBalance(address common.Address) (*BalanceOfRsp, error) {
 	}, nil
 }
 
+//GetAssetBalance returns balance of asset created in asset registry
+func GetAssetBalance(asset string, address common.Address) (*AssetBalanceRsp, error) {
+	type balanceOfStruct struct {
+		Id      string
+		Account common.Address
+	}
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.AssetContractAddress, 0, "balanceOf",
+		[]interface{}{&balanceOfStruct{Id: asset, Account: address}})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(result.Result.(string))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return &AssetBalanceRsp{
+		Asset:   asset,
+		Balance: common.BigIntFromNeoBytes(data).String(),
+	}, nil
+}
+
+//GetContractAbi returns abi json of contract registered in abi registry, nil if not registered
+func GetContractAbi(address common.Address) (json.RawMessage, error) {
+	value, err := bactor.GetStorageItem(utils.AbiContractAddress, contractabi.AbiKey(address))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return json.RawMessage(value), nil
+}
+
+type StorageUsageInfo struct {
+	Contract string
+	Bytes    uint64
+}
+
+//GetStorageUsage returns bytes of storage tracked for NeoVM contract
+func GetStorageUsage(address common.Address) (*StorageUsageInfo, error) {
+	usage, err := bactor.GetStorageUsage(address)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageUsageInfo{
+		Contract: address.ToHexString(),
+		Bytes:    usage,
+	}, nil
+}
+
+type ContractVersionInfo struct {
+	Index    uint32
+	Action   string
+	Address  string
+	CodeHash string
+	Version  string
+	Height   uint32
+	TxHash   string
+}
+
+//GetContractHistory returns version history of contract recorded by upgrade contract, oldest first
+func GetContractHistory(address common.Address) ([]*ContractVersionInfo, error) {
+	value, err := bactor.GetStorageItem(utils.UpgradeContractAddress, upgrade.VersionsKey(address))
+	if err != nil {
+		return nil, err
+	}
+	var versions upgrade.ContractVersions
+	if len(value) > 0 {
+		if err := versions.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, err
+		}
+	}
+	infos := make([]*ContractVersionInfo, 0, len(versions))
+	for _, v := range versions {
+		infos = append(infos, &ContractVersionInfo{
+			Index:    v.Index,
+			Action:   upgrade.ActionNames[v.Action],
+			Address:  v.Address.ToHexString(),
+			CodeHash: v.CodeHash.ToHexString(),
+			Version:  v.Version,
+			Height:   v.Height,
+			TxHash:   v.TxHash.ToHexString(),
+		})
+	}
+	return infos, nil
+}
+
 func GetGrantOxg(addr common.Address) (string, error) {
 	key := append([]byte(onx.UNBOUND_TIME_OFFSET), addr[:]...)
 	value, err := ledger.DefLedger.GetStorageItem(utils.OnxContractAddress, key)
@@ -397,6 +543,24 @@ func GetGasPrice() (map[string]interface{}, error) {
 	return result, nil
 }
 
+//GetGasSchedule returns gas schedule active at block height, nil if no schedule is active
+func GetGasSchedule(height uint32) (*global_params.GasSchedule, error) {
+	return global_params.GetGasScheduleAt(paramStorageGetter, height)
+}
+
+//GetGasSchedules returns all gas schedules, including the one not activated yet
+func GetGasSchedules() ([]*global_params.GasSchedule, error) {
+	return global_params.GetGasSchedules(paramStorageGetter)
+}
+
+func paramStorageGetter(key []byte) ([]byte, error) {
+	value, err := bactor.GetStorageItem(utils.ParamContractAddress, key)
+	if err == scom.ErrNotFound {
+		return nil, nil
+	}
+	return value, err
+}
+
 func GetBlockTransactions(block *types.Block) interface{} {
 	trans := make([]string, len(block.Transactions))
 	for i := 0; i < len(block.Transactions); i++ {
@@ -566,3 +730,66 @@ func GetAddress(str string) (common.Address, error) {
 	}
 	return address, err
 }
+
+//ParseSimulateConfig converts config of simulatetransactions request
+func ParseSimulateConfig(cfg *SimulateConfig) (*cstates.SimulateConfig, error) {
+	res := &cstates.SimulateConfig{Height: cfg.Height, Time: cfg.Time}
+	for _, v := range cfg.Balances {
+		var asset common.Address
+		switch strings.ToLower(v.Asset) {
+		case "onyx":
+			asset = utils.OnxContractAddress
+		case "oxg":
+			asset = utils.OxgContractAddress
+		default:
+			return nil, fmt.Errorf("invalid asset %s, only onyx and oxg balances can be overridden", v.Asset)
+		}
+		account, err := GetAddress(v.Account)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account %s", v.Account)
+		}
+		res.Balances = append(res.Balances, &cstates.BalanceOverride{Asset: asset, Account: account, Value: v.Value})
+	}
+	for _, v := range cfg.Storage {
+		contract, err := GetAddress(v.Contract)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract %s", v.Contract)
+		}
+		key, err := common.HexToBytes(v.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage key %s", v.Key)
+		}
+		value, err := common.HexToBytes(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage value %s", v.Value)
+		}
+		res.Storage = append(res.Storage, &cstates.StorageOverride{Contract: contract, Key: key, Value: value})
+	}
+	for _, v := range cfg.Contracts {
+		contract, err := GetAddress(v.Contract)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract %s", v.Contract)
+		}
+		code, err := common.HexToBytes(v.Code)
+		if err != nil || len(code) == 0 {
+			return nil, fmt.Errorf("invalid contract code of %s", v.Contract)
+		}
+		res.Contracts = append(res.Contracts, &cstates.ContractOverride{Contract: contract, Code: code})
+	}
+	return res, nil
+}
+
+func GetSimulateResult(obj *cstates.SimulateResult) SimulateResult {
+	evts := []NotifyEventInfo{}
+	for _, v := range obj.Notify {
+		evts = append(evts, NotifyEventInfo{ContractAddress: v.ContractAddress.ToHexString(), States: v.States})
+	}
+	return SimulateResult{
+		TxHash: obj.TxHash.ToHexString(),
+		State:  obj.State,
+		Gas:    obj.Gas,
+		Result: obj.Result,
+		Notify: evts,
+		Error:  obj.Error,
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/abi"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	svrneovm "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+//InvokeCode is contract invocation parsed from invoke code built by BuildNativeInvokeCode or BuildNeoVMInvokeCode
+type InvokeCode struct {
+	Contract common.Address
+	Native   bool
+	Method   string
+	//Param item of native invocation, or args array item of NeoVM invocation
+	Args interface{}
+}
+
+//ParseInvokeCode parses invoke code which only pushes params and calls a contract.
+//Items are []byte, *big.Int, bool, []interface{} for array and *abi.Struct for struct.
+func ParseInvokeCode(code []byte) (*InvokeCode, error) {
+	var stack, altStack []interface{}
+	pop := func() (interface{}, error) {
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("stack is empty")
+		}
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return item, nil
+	}
+	popInt := func() (int, error) {
+		item, err := pop()
+		if err != nil {
+			return 0, err
+		}
+		switch v := item.(type) {
+		case *big.Int:
+			return int(v.Int64()), nil
+		case []byte:
+			return int(common.BigIntFromNeoBytes(v).Int64()), nil
+		}
+		return 0, fmt.Errorf("item is not integer")
+	}
+	popBytes := func() ([]byte, error) {
+		item, err := pop()
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := item.([]byte); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("item is not byte array")
+	}
+
+	r := bytes.NewReader(code)
+	for r.Len() > 0 {
+		b, _ := r.ReadByte()
+		op := neovm.OpCode(b)
+		switch {
+		case op == neovm.PUSH0:
+			stack = append(stack, []byte{})
+		case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75:
+			data, err := serialization.ReadBytes(r, uint64(op))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, data)
+		case op == neovm.PUSHDATA1 || op == neovm.PUSHDATA2 || op == neovm.PUSHDATA4:
+			var n uint64
+			switch op {
+			case neovm.PUSHDATA1:
+				v, err := serialization.ReadUint8(r)
+				if err != nil {
+					return nil, err
+				}
+				n = uint64(v)
+			case neovm.PUSHDATA2:
+				v, err := serialization.ReadUint16(r)
+				if err != nil {
+					return nil, err
+				}
+				n = uint64(v)
+			default:
+				v, err := serialization.ReadUint32(r)
+				if err != nil {
+					return nil, err
+				}
+				n = uint64(v)
+			}
+			if n > uint64(r.Len()) {
+				return nil, fmt.Errorf("push data out of code")
+			}
+			data, err := serialization.ReadBytes(r, n)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, data)
+		case op == neovm.PUSHM1:
+			stack = append(stack, big.NewInt(-1))
+		case op >= neovm.PUSH1 && op <= neovm.PUSH16:
+			stack = append(stack, big.NewInt(int64(op-neovm.PUSH1+1)))
+		case op == neovm.NEWSTRUCT:
+			n, err := popInt()
+			if err != nil {
+				return nil, err
+			}
+			st := make(abi.Struct, n)
+			stack = append(stack, &st)
+		case op == neovm.PACK:
+			n, err := popInt()
+			if err != nil {
+				return nil, err
+			}
+			if n < 0 || n > len(stack) {
+				return nil, fmt.Errorf("invalid pack count:%d", n)
+			}
+			arr := make([]interface{}, 0, n)
+			for i := 0; i < n; i++ {
+				item, _ := pop()
+				arr = append(arr, item)
+			}
+			stack = append(stack, arr)
+		case op == neovm.TOALTSTACK:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			altStack = append(altStack, item)
+		case op == neovm.DUPFROMALTSTACK || op == neovm.FROMALTSTACK:
+			if len(altStack) == 0 {
+				return nil, fmt.Errorf("alt stack is empty")
+			}
+			stack = append(stack, altStack[len(altStack)-1])
+			if op == neovm.FROMALTSTACK {
+				altStack = altStack[:len(altStack)-1]
+			}
+		case op == neovm.APPEND:
+			item, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			target, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			st, ok := target.(*abi.Struct)
+			if !ok {
+				return nil, fmt.Errorf("append target is not struct")
+			}
+			*st = append(*st, item)
+		case op == neovm.SYSCALL:
+			name, err := serialization.ReadVarBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			if string(name) != svrneovm.NATIVE_INVOKE_NAME || r.Len() > 0 {
+				return nil, fmt.Errorf("unsupported syscall:%s", name)
+			}
+			if _, err = popInt(); err != nil {
+				return nil, err
+			}
+			addr, err := popBytes()
+			if err != nil {
+				return nil, err
+			}
+			method, err := popBytes()
+			if err != nil {
+				return nil, err
+			}
+			res := &InvokeCode{Native: true, Method: string(method)}
+			if res.Contract, err = common.AddressParseFromBytes(addr); err != nil {
+				return nil, err
+			}
+			if len(stack) > 0 {
+				res.Args, _ = pop()
+			}
+			return res, nil
+		case op == neovm.APPCALL:
+			addr, err := serialization.ReadBytes(r, common.ADDR_LEN)
+			if err != nil {
+				return nil, err
+			}
+			if r.Len() > 0 {
+				return nil, fmt.Errorf("code after appcall")
+			}
+			method, err := popBytes()
+			if err != nil {
+				return nil, err
+			}
+			res := &InvokeCode{Method: string(method)}
+			if res.Contract, err = common.AddressParseFromBytes(addr); err != nil {
+				return nil, err
+			}
+			res.Args, _ = pop()
+			return res, nil
+		default:
+			return nil, fmt.Errorf("unsupported opcode:%x", b)
+		}
+	}
+	return nil, fmt.Errorf("no contract call in code")
+}
+
+//abiCache caches abi of contracts looked up when decoding a response
+type abiCache struct {
+	neovmAbis map[common.Address]*abi.NeovmContractAbi
+}
+
+func newAbiCache() *abiCache {
+	return &abiCache{neovmAbis: make(map[common.Address]*abi.NeovmContractAbi)}
+}
+
+//getNeovmAbi returns abi of NeoVM contract registered in abi registry, nil if not registered
+func (this *abiCache) getNeovmAbi(address common.Address) *abi.NeovmContractAbi {
+	if contractAbi, ok := this.neovmAbis[address]; ok {
+		return contractAbi
+	}
+	var contractAbi *abi.NeovmContractAbi
+	data, err := GetContractAbi(address)
+	if err == nil && data != nil {
+		contractAbi = &abi.NeovmContractAbi{}
+		if err = json.Unmarshal(data, contractAbi); err != nil {
+			contractAbi = nil
+		}
+	}
+	this.neovmAbis[address] = contractAbi
+	return contractAbi
+}
+
+func (this *abiCache) decodeEvent(contract string, states interface{}) *abi.DecodedEvent {
+	if nativeAbi := abi.DefAbiMgr.GetNativeAbi(contract); nativeAbi != nil {
+		return nativeAbi.DecodeEvent(states)
+	}
+	address, err := common.AddressFromHexString(contract)
+	if err != nil {
+		return nil
+	}
+	if contractAbi := this.getNeovmAbi(address); contractAbi != nil {
+		return contractAbi.DecodeEvent(states)
+	}
+	return nil
+}
+
+//DecodeExecuteNotify decodes events of notify by abi of contracts, events without abi are not decoded
+func DecodeExecuteNotify(notifies ...*ExecuteNotify) {
+	cache := newAbiCache()
+	for _, notify := range notifies {
+		for i := range notify.Notify {
+			evt := &notify.Notify[i]
+			evt.Decoded = cache.decodeEvent(evt.ContractAddress, evt.States)
+		}
+	}
+}
+
+//DecodeInvokeTransaction decodes contract invocation of transaction by abi of invoked contract,
+//returns nil if transaction is not a contract invocation or abi of contract is not available
+func DecodeInvokeTransaction(tx *types.Transaction) *abi.DecodedInvoke {
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil
+	}
+	code, err := ParseInvokeCode(invoke.Code)
+	if err != nil {
+		return nil
+	}
+	var decoded *abi.DecodedInvoke
+	if code.Native {
+		nativeAbi := abi.DefAbiMgr.GetNativeAbi(code.Contract.ToHexString())
+		if nativeAbi == nil {
+			return nil
+		}
+		decoded, err = nativeAbi.DecodeInvoke(code.Method, code.Args)
+	} else {
+		contractAbi := newAbiCache().getNeovmAbi(code.Contract)
+		if contractAbi == nil {
+			return nil
+		}
+		decoded, err = contractAbi.DecodeInvoke(code.Method, code.Args)
+	}
+	if err != nil {
+		return nil
+	}
+	decoded.Contract = code.Contract.ToHexString()
+	return decoded
+}
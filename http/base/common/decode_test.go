@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/abi"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+func TestParseNativeInvokeCode(t *testing.T) {
+	from, to := common.Address{1}, common.Address{2}
+	states := []*onx.State{{From: from, To: to, Value: 100}}
+	code, err := BuildNativeInvokeCode(utils.OnxContractAddress, 0, onx.TRANSFER_NAME, []interface{}{states})
+	assert.Nil(t, err)
+
+	invoke, err := ParseInvokeCode(code)
+	assert.Nil(t, err)
+	assert.True(t, invoke.Native)
+	assert.Equal(t, utils.OnxContractAddress, invoke.Contract)
+	assert.Equal(t, onx.TRANSFER_NAME, invoke.Method)
+	arr, ok := invoke.Args.([]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(arr))
+	assert.Equal(t, &abi.Struct{from[:], to[:], common.BigIntToNeoBytes(big.NewInt(100))}, arr[0])
+
+	nativeAbi := abi.DefAbiMgr.GetNativeAbi(invoke.Contract.ToHexString())
+	decoded, err := nativeAbi.DecodeInvoke(invoke.Method, invoke.Args)
+	assert.Nil(t, err)
+	assert.Equal(t, onx.TRANSFER_NAME, decoded.Method)
+}
+
+func TestParseNeoVMInvokeCode(t *testing.T) {
+	contract := common.Address{3}
+	code, err := BuildNeoVMInvokeCode(contract, []interface{}{"put", []interface{}{"foo", 1, true}})
+	assert.Nil(t, err)
+
+	invoke, err := ParseInvokeCode(code)
+	assert.Nil(t, err)
+	assert.False(t, invoke.Native)
+	assert.Equal(t, contract, invoke.Contract)
+	assert.Equal(t, "put", invoke.Method)
+	assert.Equal(t, []interface{}{[]byte("foo"), big.NewInt(1), big.NewInt(1)}, invoke.Args)
+
+	_, err = ParseInvokeCode(append(code, byte(0)))
+	assert.NotNil(t, err)
+	_, err = ParseInvokeCode([]byte{0x4e, 0xff, 0xff, 0xff, 0xff})
+	assert.NotNil(t, err)
+}
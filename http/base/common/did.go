@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	bactor "github.com/OnyxPay/OnyxChain-legacy/http/base/actor"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/claimrecord"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const DID_CONTEXT = "https://www.w3.org/ns/did/v1"
+
+//DIDDocument is DDO of ONX ID rendered as W3C DID document. Keys in use of ID are its verification
+//methods, and attributes of ID are listed as they are, since onxid does not define their semantics.
+type DIDDocument struct {
+	Context        string          `json:"@context"`
+	Id             string          `json:"id"`
+	PublicKey      []*DIDPublicKey `json:"publicKey"`
+	Authentication []string        `json:"authentication"`
+	Attribute      []*DIDAttribute `json:"attribute"`
+	Recovery       string          `json:"recovery,omitempty"`
+}
+
+type DIDPublicKey struct {
+	Id           string `json:"id"`
+	Type         string `json:"type"`
+	Controller   string `json:"controller"`
+	PublicKeyHex string `json:"publicKeyHex"`
+}
+
+type DIDAttribute struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ClaimInfo struct {
+	ClaimId    string
+	Issuer     string
+	Subject    string
+	Committed  uint32
+	Expiration uint32
+	Status     string
+}
+
+//GetDDO returns DDO of ONX ID as DID document
+func GetDDO(id string) (*DIDDocument, error) {
+	data, err := preExecuteOnxID("getDDO", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ONX ID %s not registered", id)
+	}
+	buf := bytes.NewBuffer(data)
+	pks, err := serialization.ReadVarBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read public keys error:%s", err)
+	}
+	attrs, err := serialization.ReadVarBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read attributes error:%s", err)
+	}
+	recovery, err := serialization.ReadVarBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("read recovery error:%s", err)
+	}
+	doc := &DIDDocument{
+		Context:        DID_CONTEXT,
+		Id:             id,
+		PublicKey:      make([]*DIDPublicKey, 0),
+		Authentication: make([]string, 0),
+		Attribute:      make([]*DIDAttribute, 0),
+	}
+	buf = bytes.NewBuffer(pks)
+	for buf.Len() > 0 {
+		index, err := serialization.ReadUint32(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read public key index error:%s", err)
+		}
+		pk, err := serialization.ReadVarBytes(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read public key error:%s", err)
+		}
+		keyId := fmt.Sprintf("%s#keys-%d", id, index)
+		doc.PublicKey = append(doc.PublicKey, &DIDPublicKey{
+			Id:           keyId,
+			Type:         didKeyType(pk),
+			Controller:   id,
+			PublicKeyHex: hex.EncodeToString(pk),
+		})
+		doc.Authentication = append(doc.Authentication, keyId)
+	}
+	buf = bytes.NewBuffer(attrs)
+	for buf.Len() > 0 {
+		fields := make([]string, 0, 3)
+		for i := 0; i < 3; i++ {
+			field, err := serialization.ReadVarBytes(buf)
+			if err != nil {
+				return nil, fmt.Errorf("read attribute error:%s", err)
+			}
+			fields = append(fields, string(field))
+		}
+		doc.Attribute = append(doc.Attribute, &DIDAttribute{Key: fields[0], Type: fields[1], Value: fields[2]})
+	}
+	if len(recovery) != 0 {
+		addr, err := common.AddressParseFromBytes(recovery)
+		if err != nil {
+			return nil, fmt.Errorf("read recovery error:%s", err)
+		}
+		doc.Recovery = addr.ToBase58()
+	}
+	return doc, nil
+}
+
+//didKeyType names verification method of public key serialized by keypair, which keeps ECDSA key on P-256
+//as compressed point and prefixes others with key type
+func didKeyType(pk []byte) string {
+	if len(pk) == 0 {
+		return ""
+	}
+	switch pk[0] {
+	case 0x02, 0x03, 0x04:
+		return "EcdsaSecp256r1VerificationKey2019"
+	}
+	switch keypair.KeyType(pk[0]) {
+	case keypair.PK_ECDSA:
+		return "EcdsaVerificationKey"
+	case keypair.PK_SM2:
+		return "SM2VerificationKey"
+	case keypair.PK_EDDSA:
+		return "Ed25519VerificationKey2018"
+	}
+	return "UnknownVerificationKey"
+}
+
+//preExecuteOnxID pre-executes query method of onxid with ONX ID, and returns raw result
+func preExecuteOnxID(method, id string) ([]byte, error) {
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.OnxIDContractAddress, 0, method, []interface{}{[]byte(id)})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if rsp.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	return hex.DecodeString(rsp.Result.(string))
+}
+
+//GetClaim returns claim of issuer in claim record with its status at current block
+func GetClaim(issuer string, claimId []byte) (*ClaimInfo, error) {
+	claim := new(claimrecord.Claim)
+	param := &claimrecord.ClaimIdParam{ClaimId: claimId, Issuer: []byte(issuer)}
+	if err := preExecuteNative(utils.ClaimContractAddress, claimrecord.GET_CLAIM_NAME, param, claim); err != nil {
+		return nil, err
+	}
+	status := "valid"
+	switch claim.Status {
+	case claimrecord.CLAIM_REVOKED:
+		status = "revoked"
+	case claimrecord.CLAIM_EXPIRED:
+		status = "expired"
+	}
+	return &ClaimInfo{
+		ClaimId:    hex.EncodeToString(claim.ClaimId),
+		Issuer:     string(claim.Issuer),
+		Subject:    string(claim.Subject),
+		Committed:  claim.Committed,
+		Expiration: claim.Expiration,
+		Status:     status,
+	}, nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDKeyType(t *testing.T) {
+	_, pub, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	assert.Nil(t, err)
+	assert.Equal(t, "EcdsaSecp256r1VerificationKey2019", didKeyType(keypair.SerializePublicKey(pub)))
+
+	_, pub, err = keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P384)
+	assert.Nil(t, err)
+	assert.Equal(t, "EcdsaVerificationKey", didKeyType(keypair.SerializePublicKey(pub)))
+
+	_, pub, err = keypair.GenerateKeyPair(keypair.PK_SM2, keypair.SM2P256V1)
+	assert.Nil(t, err)
+	assert.Equal(t, "SM2VerificationKey", didKeyType(keypair.SerializePublicKey(pub)))
+
+	_, pub, err = keypair.GenerateKeyPair(keypair.PK_EDDSA, keypair.ED25519)
+	assert.Nil(t, err)
+	assert.Equal(t, "Ed25519VerificationKey2018", didKeyType(keypair.SerializePublicKey(pub)))
+
+	assert.Equal(t, "", didKeyType(nil))
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	bactor "github.com/OnyxPay/OnyxChain-legacy/http/base/actor"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/escrow"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type VestingInfo struct {
+	Id          uint64
+	Creator     string
+	Beneficiary string
+	Asset       string
+	Amount      uint64
+	Claimed     uint64
+	Claimable   uint64
+	ByHeight    bool
+	Start       uint32
+	Cliff       uint32
+	Duration    uint32
+}
+
+type EscrowInfo struct {
+	Id          uint64
+	Depositor   string
+	Beneficiary string
+	Asset       string
+	Amount      uint64
+	Arbiter     string
+	ByHeight    bool
+	Timeout     uint32
+	Status      string
+}
+
+type LockedBalanceInfo struct {
+	Account   string
+	Asset     string
+	Locked    uint64
+	Claimable uint64
+}
+
+//GetVesting returns vesting of escrow contract with amount claimable at current block
+func GetVesting(id uint64) (*VestingInfo, error) {
+	info := new(escrow.VestingInfo)
+	if err := preExecuteNative(utils.EscrowContractAddress, escrow.GET_VESTING_NAME, &escrow.IdParam{Id: id}, info); err != nil {
+		return nil, err
+	}
+	vesting := info.Vesting
+	return &VestingInfo{
+		Id:          vesting.Id,
+		Creator:     vesting.Creator.ToBase58(),
+		Beneficiary: vesting.Beneficiary.ToBase58(),
+		Asset:       assetName(vesting.Asset),
+		Amount:      vesting.Amount,
+		Claimed:     vesting.Claimed,
+		Claimable:   info.Claimable,
+		ByHeight:    vesting.ByHeight,
+		Start:       vesting.Start,
+		Cliff:       vesting.Cliff,
+		Duration:    vesting.Duration,
+	}, nil
+}
+
+//GetEscrow returns escrow of escrow contract
+func GetEscrow(id uint64) (*EscrowInfo, error) {
+	e := new(escrow.Escrow)
+	if err := preExecuteNative(utils.EscrowContractAddress, escrow.GET_ESCROW_NAME, &escrow.IdParam{Id: id}, e); err != nil {
+		return nil, err
+	}
+	status := "open"
+	switch e.Status {
+	case escrow.ESCROW_RELEASED:
+		status = "released"
+	case escrow.ESCROW_REFUNDED:
+		status = "refunded"
+	}
+	return &EscrowInfo{
+		Id:          e.Id,
+		Depositor:   e.Depositor.ToBase58(),
+		Beneficiary: e.Beneficiary.ToBase58(),
+		Asset:       assetName(e.Asset),
+		Amount:      e.Amount,
+		Arbiter:     string(e.Arbiter),
+		ByHeight:    e.ByHeight,
+		Timeout:     e.Timeout,
+		Status:      status,
+	}, nil
+}
+
+//GetLockedBalance returns amount of onx or oxg locked in vestings and open escrows of account, and the claimable part
+func GetLockedBalance(asset string, account common.Address) (*LockedBalanceInfo, error) {
+	var contractAddr common.Address
+	switch strings.ToLower(asset) {
+	case "onyx":
+		contractAddr = utils.OnxContractAddress
+	case "oxg":
+		contractAddr = utils.OxgContractAddress
+	default:
+		return nil, fmt.Errorf("unsupport asset")
+	}
+	balance := new(escrow.LockedBalance)
+	param := &escrow.LockedBalanceParam{Account: account, Asset: contractAddr}
+	if err := preExecuteNative(utils.EscrowContractAddress, escrow.LOCKED_BALANCE_NAME, param, balance); err != nil {
+		return nil, err
+	}
+	return &LockedBalanceInfo{
+		Account:   account.ToBase58(),
+		Asset:     strings.ToLower(asset),
+		Locked:    balance.Locked,
+		Claimable: balance.Claimable,
+	}, nil
+}
+
+func assetName(asset common.Address) string {
+	switch asset {
+	case utils.OnxContractAddress:
+		return "onyx"
+	case utils.OxgContractAddress:
+		return "oxg"
+	}
+	return asset.ToHexString()
+}
+
+//preExecuteNative pre-executes method of native contract declared by native framework, and decodes result
+func preExecuteNative(contractAddr common.Address, method string, param interface{}, result interface{}) error {
+	mutable, err := NewNativeInvokeTransaction(0, 0, contractAddr, 0, method, []interface{}{param})
+	if err != nil {
+		return fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return err
+	}
+	rsp, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if rsp.State == 0 {
+		return fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(rsp.Result.(string))
+	if err != nil {
+		return fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return framework.Decode(data, result)
+}
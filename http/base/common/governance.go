@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	scom "github.com/OnyxPay/OnyxChain-legacy/core/store/common"
+	bactor "github.com/OnyxPay/OnyxChain-legacy/http/base/actor"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type ProposalInfo struct {
+	Id            uint64
+	Proposer      string
+	Kind          string
+	Payload       string
+	Params        map[string]string `json:",omitempty"`
+	Description   string
+	StartHeight   uint32
+	EndHeight     uint32
+	ExecuteHeight uint32
+	Status        string
+	YesWeight     uint64
+	NoWeight      uint64
+	TotalWeight   uint64
+	Voters        []string
+}
+
+func getGovernanceStorage(key []byte) ([]byte, error) {
+	value, err := bactor.GetStorageItem(utils.GovernanceContractAddress, key)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, err
+	}
+	return value, nil
+}
+
+//GetProposal returns governance proposal by id, nil if not exist
+func GetProposal(id uint64) (*ProposalInfo, error) {
+	value, err := getGovernanceStorage(governance.ProposalKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	proposal := new(governance.Proposal)
+	if err := proposal.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, err
+	}
+	info := &ProposalInfo{
+		Id:            proposal.Id,
+		Proposer:      proposal.Proposer.ToBase58(),
+		Kind:          governance.ProposalKindNames[proposal.Kind],
+		Payload:       hex.EncodeToString(proposal.Payload),
+		Description:   proposal.Description,
+		StartHeight:   proposal.StartHeight,
+		EndHeight:     proposal.EndHeight,
+		ExecuteHeight: proposal.ExecuteHeight,
+		Status:        governance.ProposalStatusNames[proposal.Status],
+		YesWeight:     proposal.YesWeight,
+		NoWeight:      proposal.NoWeight,
+		TotalWeight:   proposal.TotalWeight,
+		Voters:        make([]string, 0, len(proposal.Voters)),
+	}
+	if proposal.Kind == governance.PROPOSAL_GLOBAL_PARAM {
+		params := global_params.Params{}
+		if err := params.Deserialize(bytes.NewBuffer(proposal.Payload)); err == nil {
+			info.Params = make(map[string]string)
+			for _, param := range params {
+				info.Params[param.Key] = param.Value
+			}
+		}
+	}
+	for _, voter := range proposal.Voters {
+		info.Voters = append(info.Voters, voter.ToBase58())
+	}
+	return info, nil
+}
+
+//GetProposals returns all governance proposals, oldest first
+func GetProposals() ([]*ProposalInfo, error) {
+	value, err := getGovernanceStorage(governance.ProposalCountKey())
+	if err != nil {
+		return nil, err
+	}
+	var count uint64
+	if len(value) > 0 {
+		count, err = governance.GetBytesUint64(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	infos := make([]*ProposalInfo, 0, count)
+	for id := uint64(1); id <= count; id++ {
+		info, err := GetProposal(id)
+		if err != nil {
+			return nil, err
+		}
+		if info != nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/htlc"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type SwapInfo struct {
+	Hashlock  string
+	HashType  string
+	Sender    string
+	Recipient string
+	Asset     string
+	Amount    uint64
+	Timelock  uint32
+	Preimage  string
+	Status    string
+}
+
+//GetSwap returns swap of htlc contract locked under hashlock
+func GetSwap(hashlock []byte) (*SwapInfo, error) {
+	swap := new(htlc.Swap)
+	if err := preExecuteNative(utils.HtlcContractAddress, htlc.GET_SWAP_NAME, &htlc.HashlockParam{Hashlock: hashlock}, swap); err != nil {
+		return nil, err
+	}
+	hashType := "sha256"
+	if swap.HashType == htlc.HASH_HASH160 {
+		hashType = "hash160"
+	}
+	status := "locked"
+	switch swap.Status {
+	case htlc.SWAP_CLAIMED:
+		status = "claimed"
+	case htlc.SWAP_REFUNDED:
+		status = "refunded"
+	}
+	return &SwapInfo{
+		Hashlock:  hex.EncodeToString(swap.Hashlock),
+		HashType:  hashType,
+		Sender:    swap.Sender.ToBase58(),
+		Recipient: swap.Recipient.ToBase58(),
+		Asset:     assetName(swap.Asset),
+		Amount:    swap.Amount,
+		Timelock:  swap.Timelock,
+		Preimage:  hex.EncodeToString(swap.Preimage),
+		Status:    status,
+	}, nil
+}
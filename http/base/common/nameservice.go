@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//NameInfo is record of name service, empty Address, OnxID or Contract means name has no such record
+type NameInfo struct {
+	Name       string
+	Owner      string
+	Registered uint32
+	Expiration uint32
+	Address    string
+	OnxID      string
+	Contract   string
+}
+
+//ResolveName returns record of name which is registered and not expired at current block
+func ResolveName(name string) (*NameInfo, error) {
+	record, err := resolveName(name)
+	if err != nil {
+		return nil, err
+	}
+	return newNameInfo(record), nil
+}
+
+//ReverseName returns record of reverse name of address, if the name resolves to the address
+func ReverseName(addr common.Address) (*NameInfo, error) {
+	record := new(nameservice.Name)
+	param := &nameservice.AddressParam{Address: addr}
+	if err := preExecuteNative(utils.NameContractAddress, nameservice.REVERSE_NAME, param, record); err != nil {
+		return nil, err
+	}
+	return newNameInfo(record), nil
+}
+
+//AddressFromBase58OrName returns address from base58 address, or address which name resolves to
+func AddressFromBase58OrName(str string) (common.Address, error) {
+	if !nameservice.IsName(str) {
+		return common.AddressFromBase58(str)
+	}
+	record, err := resolveName(str)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("resolve name %s error:%s", str, err)
+	}
+	if record.Address == common.ADDRESS_EMPTY {
+		return common.ADDRESS_EMPTY, fmt.Errorf("name %s has no address record", str)
+	}
+	return record.Address, nil
+}
+
+func resolveName(name string) (*nameservice.Name, error) {
+	record := new(nameservice.Name)
+	param := &nameservice.NameParam{Name: name}
+	if err := preExecuteNative(utils.NameContractAddress, nameservice.RESOLVE_NAME, param, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func newNameInfo(record *nameservice.Name) *NameInfo {
+	info := &NameInfo{
+		Name:       record.Name,
+		Owner:      record.Owner.ToBase58(),
+		Registered: record.Registered,
+		Expiration: record.Expiration,
+		OnxID:      string(record.OnxID),
+	}
+	if record.Address != common.ADDRESS_EMPTY {
+		info.Address = record.Address.ToBase58()
+	}
+	if record.Contract != common.ADDRESS_EMPTY {
+		info.Contract = record.Contract.ToHexString()
+	}
+	return info
+}
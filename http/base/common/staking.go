@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var peerStatusNames = map[uint8]string{
+	uint8(governance.RegisterCandidateStatus): "registered",
+	uint8(governance.CandidateStatus):         "candidate",
+	uint8(governance.ConsensusStatus):         "consensus",
+	uint8(governance.QuitConsensusStatus):     "quitconsensus",
+	uint8(governance.QuitingStatus):           "quiting",
+	uint8(governance.BlackStatus):             "black",
+}
+
+type PeerInfo struct {
+	Index        uint32
+	PeerPubkey   string
+	Address      string
+	Status       string
+	InitPos      uint64
+	TotalPos     uint64
+	MaxAuthorize uint64
+	TPeerCost    uint64
+	T1PeerCost   uint64
+	T2PeerCost   uint64
+	NextSplitFee uint64
+}
+
+type PeerPoolInfo struct {
+	View   uint32
+	Income uint64
+	Peers  []*PeerInfo
+}
+
+type StakePositionInfo struct {
+	PeerPubkey      string
+	InitPos         uint64
+	ConsensusPos    uint64
+	CandidatePos    uint64
+	PendingPos      uint64
+	FrozenPos       uint64
+	WithdrawablePos uint64
+	NextReward      uint64
+}
+
+type StakeInfo struct {
+	Address    string
+	TotalStake uint64
+	SplitFee   uint64
+	NextReward uint64
+	Positions  []*StakePositionInfo
+}
+
+//GetPeerPool returns peers of governance contract with stakes and projected fee split of next epoch
+func GetPeerPool() (*PeerPoolInfo, error) {
+	status := new(governance.PeerPoolStatus)
+	//getPeerPoolInfo takes no param
+	if err := preExecuteNative(utils.GovernanceContractAddress, governance.GET_PEER_POOL_INFO, "", status); err != nil {
+		return nil, err
+	}
+	info := &PeerPoolInfo{
+		View:   status.View,
+		Income: status.Income,
+		Peers:  make([]*PeerInfo, 0, len(status.Peers)),
+	}
+	for _, peer := range status.Peers {
+		info.Peers = append(info.Peers, &PeerInfo{
+			Index:        peer.Index,
+			PeerPubkey:   peer.PeerPubkey,
+			Address:      peer.Address.ToBase58(),
+			Status:       peerStatusNames[peer.Status],
+			InitPos:      peer.InitPos,
+			TotalPos:     peer.TotalPos,
+			MaxAuthorize: peer.MaxAuthorize,
+			TPeerCost:    peer.TPeerCost,
+			T1PeerCost:   peer.T1PeerCost,
+			T2PeerCost:   peer.T2PeerCost,
+			NextSplitFee: peer.NextSplitFee,
+		})
+	}
+	return info, nil
+}
+
+//GetStakeInfo returns stake positions of address in all peers, with pending, frozen and withdrawable pos
+func GetStakeInfo(address common.Address) (*StakeInfo, error) {
+	stake := new(governance.StakeInfo)
+	param := &governance.StakeInfoParam{Address: address}
+	if err := preExecuteNative(utils.GovernanceContractAddress, governance.GET_STAKE_INFO, param, stake); err != nil {
+		return nil, err
+	}
+	info := &StakeInfo{
+		Address:    address.ToBase58(),
+		TotalStake: stake.TotalStake,
+		SplitFee:   stake.SplitFee,
+		NextReward: stake.NextReward,
+		Positions:  make([]*StakePositionInfo, 0, len(stake.Positions)),
+	}
+	for _, position := range stake.Positions {
+		info.Positions = append(info.Positions, &StakePositionInfo{
+			PeerPubkey:      position.PeerPubkey,
+			InitPos:         position.InitPos,
+			ConsensusPos:    position.ConsensusPos,
+			CandidatePos:    position.CandidatePos,
+			PendingPos:      position.NewPos,
+			FrozenPos:       position.WithdrawConsensusPos + position.WithdrawCandidatePos,
+			WithdrawablePos: position.WithdrawUnfreezePos,
+			NextReward:      position.NextReward,
+		})
+	}
+	return info, nil
+}
@@ -242,6 +242,9 @@ func GetTransactionByHash(cmd map[string]interface{}) map[string]interface{} {
 	}
 	tran := bcomn.TransArryByteToHexString(tx)
 	tran.Height = height
+	if isDecode(cmd) {
+		tran.Decoded = bcomn.DecodeInvokeTransaction(tx)
+	}
 	resp["Result"] = tran
 	return resp
 }
@@ -318,6 +321,9 @@ func GetSmartCodeEventTxsByHeight(cmd map[string]interface{}) map[string]interfa
 		_, notify := bcomn.GetExecuteNotify(eventInfo)
 		eInfos = append(eInfos, &notify)
 	}
+	if isDecode(cmd) {
+		bcomn.DecodeExecuteNotify(eInfos...)
+	}
 	resp["Result"] = eInfos
 	return resp
 }
@@ -349,10 +355,19 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 		return ResponsePack(berr.INVALID_TRANSACTION)
 	}
 	_, notify := bcomn.GetExecuteNotify(eventInfo)
+	if isDecode(cmd) {
+		bcomn.DecodeExecuteNotify(&notify)
+	}
 	resp["Result"] = notify
 	return resp
 }
 
+//isDecode returns whether result should be decoded by contract abi, set by decode=true in request
+func isDecode(cmd map[string]interface{}) bool {
+	decode, ok := cmd["Decode"].(string)
+	return ok && (decode == "true" || decode == "1")
+}
+
 //get contract state
 func GetContractState(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -381,6 +396,68 @@ func GetContractState(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//get abi of contract registered in abi registry
+func GetContractAbi(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	abi, err := bcomn.GetContractAbi(address)
+	if err != nil && err != scom.ErrNotFound {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	if abi != nil {
+		resp["Result"] = abi
+	}
+	return resp
+}
+
+//get version history of contract, including deploy, migrate and in-place upgrade
+func GetContractHistory(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	history, err := bcomn.GetContractHistory(address)
+	if err != nil && err != scom.ErrNotFound {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	if history == nil {
+		history = []*bcomn.ContractVersionInfo{}
+	}
+	resp["Result"] = history
+	return resp
+}
+
+//get bytes of storage used by contract
+func GetStorageUsage(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	str, ok := cmd["Hash"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	usage, err := bcomn.GetStorageUsage(address)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = usage
+	return resp
+}
+
 //get storage from contract
 func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -415,10 +492,18 @@ func GetBalance(cmd map[string]interface{}) map[string]interface{} {
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	address, err := common.AddressFromBase58(addrBase58)
+	address, err := bcomn.AddressFromBase58OrName(addrBase58)
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
+	if asset, _ := cmd["Asset"].(string); asset != "" {
+		balance, err := bcomn.GetAssetBalance(asset, address)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		resp["Result"] = balance
+		return resp
+	}
 	balance, err := bcomn.GetBalance(address)
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
@@ -479,6 +564,25 @@ func GetGasPrice(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//get gas schedule active at block height, the next block if height is omitted
+func GetGasSchedule(cmd map[string]interface{}) map[string]interface{} {
+	height := bactor.GetCurrentBlockHeight() + 1
+	if param, ok := cmd["Height"].(string); ok && len(param) > 0 {
+		h, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		height = uint32(h)
+	}
+	schedule, err := bcomn.GetGasSchedule(height)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = schedule
+	return resp
+}
+
 //get allowance
 func GetAllowance(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
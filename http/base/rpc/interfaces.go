@@ -21,6 +21,8 @@ package rpc
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"github.com/OnyxPay/OnyxChain-legacy/account"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
@@ -175,6 +177,8 @@ func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 // get raw transaction in raw or json
 // A JSON example for getrawtransaction method as following:
 //   {"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex"], "id": 0}
+// Invocation of contract is decoded by contract abi with verbose and decode flag:
+//   {"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex", 1, true], "id": 0}
 func GetRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -203,8 +207,15 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 		case float64:
 			json := uint32(params[1].(float64))
 			if json == 1 {
+				decode, ok := decodeFlag(params, 2)
+				if !ok {
+					return responsePack(berr.INVALID_PARAMS, "")
+				}
 				txinfo := bcomn.TransArryByteToHexString(tx)
 				txinfo.Height = height
+				if decode {
+					txinfo.Decoded = bcomn.DecodeInvokeTransaction(tx)
+				}
 				return responseSuccess(txinfo)
 			}
 		default:
@@ -305,6 +316,60 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(hash.ToHexString())
 }
 
+//simulate transactions in sequence on current state, no transaction is sent
+// A JSON example for simulatetransactions method as following:
+//   {"jsonrpc": "2.0", "method": "simulatetransactions", "params": [["raw transaction in hex", ...],
+//     {"Height": 0, "Time": 0, "Balances": [{"Asset": "oxg", "Account": "address", "Value": 1000}],
+//      "Storage": [{"Contract": "address", "Key": "hex", "Value": "hex"}], "Contracts": [{"Contract": "address", "Code": "hex"}]}], "id": 0}
+func SimulateTransactions(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	raws, ok := params[0].([]interface{})
+	if !ok || len(raws) == 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txs := make([]*types.Transaction, 0, len(raws))
+	for _, v := range raws {
+		str, ok := v.(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		raw, err := common.HexToBytes(str)
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		txn, err := types.TransactionFromRawBytes(raw)
+		if err != nil {
+			return responsePack(berr.INVALID_TRANSACTION, "")
+		}
+		txs = append(txs, txn)
+	}
+	cfg := &bcomn.SimulateConfig{}
+	if len(params) > 1 && params[1] != nil {
+		data, err := json.Marshal(params[1])
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return responsePack(berr.INVALID_PARAMS, err.Error())
+		}
+	}
+	simCfg, err := bcomn.ParseSimulateConfig(cfg)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, err.Error())
+	}
+	results, err := bactor.SimulateTransactions(txs, simCfg)
+	if err != nil {
+		return responsePack(berr.SMARTCODE_ERROR, err.Error())
+	}
+	res := make([]bcomn.SimulateResult, 0, len(results))
+	for _, result := range results {
+		res = append(res, bcomn.GetSimulateResult(result))
+	}
+	return responseSuccess(res)
+}
+
 //get node version
 func GetNodeVersion(params []interface{}) map[string]interface{} {
 	return responseSuccess(config.Version)
@@ -352,7 +417,77 @@ func GetContractState(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(w.Bytes()))
 }
 
-//get smartconstract event
+//get abi of contract registered in abi registry
+func GetContractAbi(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	abi, err := bcomn.GetContractAbi(address)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return responseSuccess(nil)
+		}
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	if abi == nil {
+		return responseSuccess(nil)
+	}
+	return responseSuccess(abi)
+}
+
+//get version history of contract, including deploy, migrate and in-place upgrade
+func GetContractHistory(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	history, err := bcomn.GetContractHistory(address)
+	if err != nil && err != scom.ErrNotFound {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	if history == nil {
+		history = []*bcomn.ContractVersionInfo{}
+	}
+	return responseSuccess(history)
+}
+
+//get bytes of storage used by contract
+func GetStorageUsage(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	usage, err := bcomn.GetStorageUsage(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(usage)
+}
+
+//get smartconstract event, events are decoded by contract abi with decode flag:
+//   {"jsonrpc": "2.0", "method": "getsmartcodeevent", "params": ["transaction hash in hex", true], "id": 0}
 func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	if !config.DefConfig.Common.EnableEventLog {
 		return responsePack(berr.INVALID_METHOD, "")
@@ -360,6 +495,10 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
+	decode, ok := decodeFlag(params, 1)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
 
 	switch (params[0]).(type) {
 	// block height
@@ -377,6 +516,9 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 			_, notify := bcomn.GetExecuteNotify(eventInfo)
 			eInfos = append(eInfos, &notify)
 		}
+		if decode {
+			bcomn.DecodeExecuteNotify(eInfos...)
+		}
 		return responseSuccess(eInfos)
 		//txhash
 	case string:
@@ -393,6 +535,9 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 			return responsePack(berr.INTERNAL_ERROR, "")
 		}
 		_, notify := bcomn.GetExecuteNotify(eventInfo)
+		if decode {
+			bcomn.DecodeExecuteNotify(&notify)
+		}
 		return responseSuccess(notify)
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -400,6 +545,42 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	return responsePack(berr.INVALID_PARAMS, "")
 }
 
+//decodeFlag returns optional decode flag at index of params, which decodes result by contract abi.
+//The second return value is false if the flag is not a bool.
+func decodeFlag(params []interface{}, index int) (bool, bool) {
+	if len(params) <= index {
+		return false, true
+	}
+	decode, ok := params[index].(bool)
+	return decode, ok
+}
+
+//get execution trace of transaction, recorded when node executed it with tx trace enabled
+func TraceTransaction(params []interface{}) map[string]interface{} {
+	if !config.DefConfig.Common.EnableTxTrace {
+		return responsePack(berr.INVALID_METHOD, "tx trace disabled, restart node with --enable-tx-trace")
+	}
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	txTrace, err := bactor.GetTxTrace(hash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return responsePack(berr.UNKNOWN_TRANSACTION, "no trace of transaction, it was executed before tx trace enabled")
+		}
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(txTrace)
+}
+
 //get block height by transaction hash
 func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -425,7 +606,7 @@ func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 	return responsePack(berr.INVALID_PARAMS, "")
 }
 
-//get balance of address
+//get balance of address, or of address which name of name service, such as alice.onx, resolves to
 func GetBalance(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -434,10 +615,22 @@ func GetBalance(params []interface{}) map[string]interface{} {
 	if !ok {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	address, err := common.AddressFromBase58(addrBase58)
+	address, err := bcomn.AddressFromBase58OrName(addrBase58)
 	if err != nil {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	//optional asset id of asset registry
+	if len(params) >= 2 {
+		asset, ok := params[1].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		rsp, err := bcomn.GetAssetBalance(asset, address)
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		return responseSuccess(rsp)
+	}
 	rsp, err := bcomn.GetBalance(address)
 	if err != nil {
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -445,6 +638,270 @@ func GetBalance(params []interface{}) map[string]interface{} {
 	return responseSuccess(rsp)
 }
 
+//get vesting of escrow contract by id:
+//   {"jsonrpc": "2.0", "method": "getvesting", "params": [1], "id": 0}
+func GetVesting(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[0].(float64)
+	if !ok || id < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetVesting(uint64(id))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get escrow of escrow contract by id:
+//   {"jsonrpc": "2.0", "method": "getescrow", "params": [1], "id": 0}
+func GetEscrow(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[0].(float64)
+	if !ok || id < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetEscrow(uint64(id))
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get onyx or oxg locked in vestings of account, and the claimable part:
+//   {"jsonrpc": "2.0", "method": "getlockedbalance", "params": ["address in base58", "onyx"], "id": 0}
+func GetLockedBalance(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	asset, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetLockedBalance(asset, address)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get swap of htlc contract by hashlock:
+//   {"jsonrpc": "2.0", "method": "getswap", "params": ["hashlock in hex"], "id": 0}
+func GetSwap(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hashlock, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetSwap(hashlock)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get governance proposal by id:
+//   {"jsonrpc": "2.0", "method": "getproposal", "params": [1], "id": 0}
+func GetProposal(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[0].(float64)
+	if !ok || id < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetProposal(uint64(id))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get all governance proposals:
+//   {"jsonrpc": "2.0", "method": "getproposals", "params": [], "id": 0}
+func GetProposals(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetProposals()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get peer pool of governance contract with stakes and projected fee split of next epoch:
+//   {"jsonrpc": "2.0", "method": "getpeerpool", "params": [], "id": 0}
+func GetPeerPool(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetPeerPool()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get stake positions of address in all peers:
+//   {"jsonrpc": "2.0", "method": "getstakeinfo", "params": ["address"], "id": 0}
+func GetStakeInfo(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetStakeInfo(address)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get DDO of ONX ID as W3C DID document:
+//   {"jsonrpc": "2.0", "method": "getddo", "params": ["did:onx:..."], "id": 0}
+func GetDDO(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[0].(string)
+	if !ok || !account.VerifyID(id) {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetDDO(id)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get claim of claim record by issuer and claim id:
+//   {"jsonrpc": "2.0", "method": "getclaim", "params": ["did:onx:...", "claim id in hex"], "id": 0}
+func GetClaim(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	issuer, ok := params[0].(string)
+	if !ok || !account.VerifyID(issuer) {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	claimId, err := hex.DecodeString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetClaim(issuer, claimId)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get admin, functions and holders of roles of contract in auth contract:
+//   {"jsonrpc": "2.0", "method": "getcontractroles", "params": ["contract address"], "id": 0}
+func GetContractRoles(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetContractRoles(contract)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get roles of ONX ID in contract with delegation expiry:
+//   {"jsonrpc": "2.0", "method": "getonxidroles", "params": ["contract address", "did:onx:..."], "id": 0}
+func GetOnxIDRoles(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[1].(string)
+	if !ok || !account.VerifyID(id) {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetOnxIDRoles(contract, id)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//resolve name of name service to its records:
+//   {"jsonrpc": "2.0", "method": "resolvename", "params": ["alice.onx"], "id": 0}
+func ResolveName(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	name, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.ResolveName(name)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get reverse name of address, which resolves to the address:
+//   {"jsonrpc": "2.0", "method": "reversename", "params": ["base58 address"], "id": 0}
+func ReverseName(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addrBase58, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	address, err := common.AddressFromBase58(addrBase58)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.ReverseName(address)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
+
 //get allowance
 func GetAllowance(params []interface{}) map[string]interface{} {
 	if len(params) < 3 {
@@ -547,6 +1004,34 @@ func GetGasPrice(params []interface{}) map[string]interface{} {
 	return responseSuccess(result)
 }
 
+//get gas schedule active at block height, the next block if height is omitted:
+//   {"jsonrpc": "2.0", "method": "getgasschedule", "params": [height], "id": 0}
+func GetGasSchedule(params []interface{}) map[string]interface{} {
+	height := bactor.GetCurrentBlockHeight() + 1
+	if len(params) >= 1 {
+		switch params[0].(type) {
+		case float64:
+			height = uint32(params[0].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	schedule, err := bcomn.GetGasSchedule(height)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(schedule)
+}
+
+//get all gas schedules, including the one not activated yet
+func GetGasSchedules(params []interface{}) map[string]interface{} {
+	schedules, err := bcomn.GetGasSchedules()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(schedules)
+}
+
 // get unbound oxg of address
 func GetUnboundOxg(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
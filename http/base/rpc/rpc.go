@@ -23,12 +23,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	berr "github.com/OnyxPay/OnyxChain-legacy/http/base/error"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
 func init() {
@@ -116,7 +118,9 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 	//get the corresponding function
 	function, ok := mainMux.m[method]
 	if ok {
+		start := time.Now()
 		response := function(request["params"].([]interface{}))
+		metrics.RpcRequestTime.ObserveSince(start, method)
 		data, err := json.Marshal(map[string]interface{}{
 			"jsonrpc": "2.0",
 			"error":   response["error"],
@@ -42,21 +42,42 @@ func StartRPCServer() error {
 
 	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
 	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
+	rpc.HandleFunc("simulatetransactions", rpc.SimulateTransactions)
 	rpc.HandleFunc("getstorage", rpc.GetStorage)
 	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
 	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
 
 	rpc.HandleFunc("getcontractstate", rpc.GetContractState)
+	rpc.HandleFunc("getcontractabi", rpc.GetContractAbi)
+	rpc.HandleFunc("getcontracthistory", rpc.GetContractHistory)
+	rpc.HandleFunc("getstorageusage", rpc.GetStorageUsage)
 	rpc.HandleFunc("getmempooltxcount", rpc.GetMemPoolTxCount)
 	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
 	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
 	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
+	rpc.HandleFunc("tracetransaction", rpc.TraceTransaction)
 
 	rpc.HandleFunc("getbalance", rpc.GetBalance)
 	rpc.HandleFunc("getallowance", rpc.GetAllowance)
+	rpc.HandleFunc("getvesting", rpc.GetVesting)
+	rpc.HandleFunc("getescrow", rpc.GetEscrow)
+	rpc.HandleFunc("getlockedbalance", rpc.GetLockedBalance)
+	rpc.HandleFunc("getswap", rpc.GetSwap)
+	rpc.HandleFunc("getproposal", rpc.GetProposal)
+	rpc.HandleFunc("getproposals", rpc.GetProposals)
+	rpc.HandleFunc("getpeerpool", rpc.GetPeerPool)
+	rpc.HandleFunc("getstakeinfo", rpc.GetStakeInfo)
+	rpc.HandleFunc("getddo", rpc.GetDDO)
+	rpc.HandleFunc("getclaim", rpc.GetClaim)
+	rpc.HandleFunc("getcontractroles", rpc.GetContractRoles)
+	rpc.HandleFunc("getonxidroles", rpc.GetOnxIDRoles)
+	rpc.HandleFunc("resolvename", rpc.ResolveName)
+	rpc.HandleFunc("reversename", rpc.ReverseName)
 	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
 	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
 	rpc.HandleFunc("getgasprice", rpc.GetGasPrice)
+	rpc.HandleFunc("getgasschedule", rpc.GetGasSchedule)
+	rpc.HandleFunc("getgasschedules", rpc.GetGasSchedules)
 	rpc.HandleFunc("getunboundoxg", rpc.GetUnboundOxg)
 	rpc.HandleFunc("getgrantoxg", rpc.GetGrantOxg)
 
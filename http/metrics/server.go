@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics privides the http server exporting node metrics in prometheus text format
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
+	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
+	p2p "github.com/OnyxPay/OnyxChain-legacy/p2pserver/net/protocol"
+)
+
+const (
+	METRICS_PATH = "/metrics"
+	CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var node p2p.P2P
+
+//updateGauges refreshes the gauges which are read from other modules at scrape time
+func updateGauges() {
+	if ledger.DefLedger != nil {
+		metrics.BlockHeight.Set(float64(ledger.DefLedger.GetCurrentBlockHeight()))
+	}
+	if node != nil {
+		metrics.P2PPeers.Set(float64(node.GetConnectionCnt()), "connected")
+		metrics.P2PPeers.Set(float64(node.GetOutConnRecordLen()), "outbound")
+		metrics.P2PPeers.Set(float64(node.GetOutConnectingListLen()), "connecting")
+	}
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	updateGauges()
+	buf := new(bytes.Buffer)
+	if err := metrics.DefRegistry.WriteMetrics(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	w.Write(buf.Bytes())
+}
+
+//StartServer starts the metrics http server, n may be nil when p2p is not started
+func StartServer(n p2p.P2P) error {
+	node = n
+	port := int(config.DefConfig.Metrics.HttpMetricsPort)
+	mux := http.NewServeMux()
+	mux.HandleFunc(METRICS_PATH, metricsHandler)
+	log.Infof("Metrics server listening on port %d", port)
+	err := http.ListenAndServe(":"+strconv.Itoa(port), mux)
+	if err != nil {
+		return fmt.Errorf("ListenAndServe error:%s", err)
+	}
+	return nil
+}
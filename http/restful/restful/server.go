@@ -59,13 +59,17 @@ const (
 	GET_BLK_HASH          = "/api/v1/block/hash/:height"
 	GET_TX                = "/api/v1/transaction/:hash"
 	GET_STORAGE           = "/api/v1/storage/:hash/:key"
+	GET_STORAGE_USAGE     = "/api/v1/storageusage/:hash"
 	GET_BALANCE           = "/api/v1/balance/:addr"
 	GET_CONTRACT_STATE    = "/api/v1/contract/:hash"
+	GET_CONTRACT_ABI      = "/api/v1/contractabi/:hash"
+	GET_CONTRACT_HISTORY  = "/api/v1/contracthistory/:hash"
 	GET_SMTCOCE_EVT_TXS   = "/api/v1/smartcode/event/transactions/:height"
 	GET_SMTCOCE_EVTS      = "/api/v1/smartcode/event/txhash/:hash"
 	GET_BLK_HGT_BY_TXHASH = "/api/v1/block/height/txhash/:hash"
 	GET_MERKLE_PROOF      = "/api/v1/merkleproof/:hash"
 	GET_GAS_PRICE         = "/api/v1/gasprice"
+	GET_GAS_SCHEDULE      = "/api/v1/gasschedule"
 	GET_ALLOWANCE         = "/api/v1/allowance/:asset/:from/:to"
 	GET_UNBOUNDOXG        = "/api/v1/unboundoxg/:addr"
 	GET_GRANTOXG          = "/api/v1/grantong/:addr"
@@ -135,14 +139,18 @@ func (this *restServer) registryMethod() {
 		GET_BLK_HASH:          {name: "getblockhash", handler: rest.GetBlockHash},
 		GET_TX:                {name: "gettransaction", handler: rest.GetTransactionByHash},
 		GET_CONTRACT_STATE:    {name: "getcontract", handler: rest.GetContractState},
+		GET_CONTRACT_ABI:      {name: "getcontractabi", handler: rest.GetContractAbi},
+		GET_CONTRACT_HISTORY:  {name: "getcontracthistory", handler: rest.GetContractHistory},
 		GET_SMTCOCE_EVT_TXS:   {name: "getsmartcodeeventbyheight", handler: rest.GetSmartCodeEventTxsByHeight},
 		GET_SMTCOCE_EVTS:      {name: "getsmartcodeeventbyhash", handler: rest.GetSmartCodeEventByTxHash},
 		GET_BLK_HGT_BY_TXHASH: {name: "getblockheightbytxhash", handler: rest.GetBlockHeightByTxHash},
 		GET_STORAGE:           {name: "getstorage", handler: rest.GetStorage},
+		GET_STORAGE_USAGE:     {name: "getstorageusage", handler: rest.GetStorageUsage},
 		GET_BALANCE:           {name: "getbalance", handler: rest.GetBalance},
 		GET_ALLOWANCE:         {name: "getallowance", handler: rest.GetAllowance},
 		GET_MERKLE_PROOF:      {name: "getmerkleproof", handler: rest.GetMerkleProof},
 		GET_GAS_PRICE:         {name: "getgasprice", handler: rest.GetGasPrice},
+		GET_GAS_SCHEDULE:      {name: "getgasschedule", handler: rest.GetGasSchedule},
 		GET_UNBOUNDOXG:        {name: "getunboundoxg", handler: rest.GetUnboundOxg},
 		GET_GRANTOXG:          {name: "getgrantoxg", handler: rest.GetGrantOxg},
 		GET_MEMPOOL_TXCOUNT:   {name: "getmempooltxcount", handler: rest.GetMemPoolTxCount},
@@ -169,6 +177,10 @@ func (this *restServer) getPath(url string) string {
 		return GET_BLK_BY_HASH
 	} else if strings.Contains(url, strings.TrimRight(GET_TX, ":hash")) {
 		return GET_TX
+	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_ABI, ":hash")) {
+		return GET_CONTRACT_ABI
+	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_HISTORY, ":hash")) {
+		return GET_CONTRACT_HISTORY
 	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_STATE, ":hash")) {
 		return GET_CONTRACT_STATE
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVT_TXS, ":height")) {
@@ -177,6 +189,8 @@ func (this *restServer) getPath(url string) string {
 		return GET_SMTCOCE_EVTS
 	} else if strings.Contains(url, strings.TrimRight(GET_BLK_HGT_BY_TXHASH, ":hash")) {
 		return GET_BLK_HGT_BY_TXHASH
+	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE_USAGE, ":hash")) {
+		return GET_STORAGE_USAGE
 	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE, ":hash/:key")) {
 		return GET_STORAGE
 	} else if strings.Contains(url, strings.TrimRight(GET_BALANCE, ":addr")) {
@@ -210,20 +224,23 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Height"] = getParam(r, "height")
 	case GET_TX:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
+		req["Decode"] = r.FormValue("decode")
 	case GET_CONTRACT_STATE:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
+	case GET_CONTRACT_ABI, GET_CONTRACT_HISTORY, GET_STORAGE_USAGE:
+		req["Hash"] = getParam(r, "hash")
 	case POST_RAW_TX:
 		req["PreExec"] = r.FormValue("preExec")
 	case GET_STORAGE:
 		req["Hash"], req["Key"] = getParam(r, "hash"), getParam(r, "key")
 	case GET_SMTCOCE_EVT_TXS:
-		req["Height"] = getParam(r, "height")
+		req["Height"], req["Decode"] = getParam(r, "height"), r.FormValue("decode")
 	case GET_SMTCOCE_EVTS:
-		req["Hash"] = getParam(r, "hash")
+		req["Hash"], req["Decode"] = getParam(r, "hash"), r.FormValue("decode")
 	case GET_BLK_HGT_BY_TXHASH:
 		req["Hash"] = getParam(r, "hash")
 	case GET_BALANCE:
-		req["Addr"] = getParam(r, "addr")
+		req["Addr"], req["Asset"] = getParam(r, "addr"), r.FormValue("asset")
 	case GET_MERKLE_PROOF:
 		req["Hash"] = getParam(r, "hash")
 	case GET_ALLOWANCE:
@@ -235,6 +252,8 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Addr"] = getParam(r, "addr")
 	case GET_MEMPOOL_TXSTATE:
 		req["Hash"] = getParam(r, "hash")
+	case GET_GAS_SCHEDULE:
+		req["Height"] = r.FormValue("height")
 	default:
 	}
 	return req
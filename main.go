@@ -46,12 +46,14 @@ import (
 	hserver "github.com/OnyxPay/OnyxChain-legacy/http/base/actor"
 	"github.com/OnyxPay/OnyxChain-legacy/http/jsonrpc"
 	"github.com/OnyxPay/OnyxChain-legacy/http/localrpc"
+	"github.com/OnyxPay/OnyxChain-legacy/http/metrics"
 	"github.com/OnyxPay/OnyxChain-legacy/http/nodeinfo"
 	"github.com/OnyxPay/OnyxChain-legacy/http/restful"
 	"github.com/OnyxPay/OnyxChain-legacy/http/websocket"
 	"github.com/OnyxPay/OnyxChain-legacy/p2pserver"
 	netreqactor "github.com/OnyxPay/OnyxChain-legacy/p2pserver/actor/req"
 	p2pactor "github.com/OnyxPay/OnyxChain-legacy/p2pserver/actor/server"
+	p2pnet "github.com/OnyxPay/OnyxChain-legacy/p2pserver/net/protocol"
 	"github.com/OnyxPay/OnyxChain-legacy/txnpool"
 	tc "github.com/OnyxPay/OnyxChain-legacy/txnpool/common"
 	"github.com/OnyxPay/OnyxChain-legacy/txnpool/proc"
@@ -79,15 +81,22 @@ func setupAPP() *cli.App {
 		cmd.MultiSigTxCommand,
 		cmd.SendTxCommand,
 		cmd.ShowTxCommand,
+		cmd.IdCommand,
+		cmd.GovernanceCommand,
+		cmd.StakeCommand,
+		cmd.AuthCommand,
+		cmd.NameCommand,
 	}
 	app.Flags = []cli.Flag{
 		//common setting
 		utils.ConfigFlag,
 		utils.LogLevelFlag,
 		utils.DisableEventLogFlag,
+		utils.EnableTxTraceFlag,
 		utils.DataDirFlag,
 		//account setting
 		utils.WalletFileFlag,
+		utils.SignerFlag,
 		utils.AccountAddressFlag,
 		utils.AccountPassFlag,
 		//consensus setting
@@ -123,6 +132,9 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//metrics setting
+		utils.MetricsEnableFlag,
+		utils.MetricsPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -185,6 +197,7 @@ func startOnyxChain(ctx *cli.Context) {
 	initRestful(ctx)
 	initWs(ctx)
 	initNodeInfo(ctx, p2pSvr)
+	initMetrics(ctx, p2pSvr)
 
 	go logCurrBlockHeight()
 	waitToExit()
@@ -211,12 +224,14 @@ func initAccount(ctx *cli.Context) (*account.Account, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
-	walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
-	if walletFile == "" {
-		return nil, fmt.Errorf("Please config wallet file using --wallet flag")
-	}
-	if !common.FileExisted(walletFile) {
-		return nil, fmt.Errorf("Cannot find wallet file:%s. Please create wallet first", walletFile)
+	if ctx.GlobalString(utils.GetFlagName(utils.SignerFlag)) == "" {
+		walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
+		if walletFile == "" {
+			return nil, fmt.Errorf("Please config wallet file using --wallet flag")
+		}
+		if !common.FileExisted(walletFile) {
+			return nil, fmt.Errorf("Cannot find wallet file:%s. Please create wallet first", walletFile)
+		}
 	}
 
 	acc, err := cmdcom.GetAccount(ctx)
@@ -403,6 +418,23 @@ func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	log.Infof("Nodeinfo init success")
 }
 
+func initMetrics(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
+	if !config.DefConfig.Metrics.EnableMetrics {
+		return
+	}
+	var node p2pnet.P2P
+	if p2pSvr != nil {
+		node = p2pSvr.GetNetWork()
+	}
+	go func() {
+		if err := metrics.StartServer(node); err != nil {
+			log.Errorf("metrics.StartServer error:%s", err)
+		}
+	}()
+
+	log.Infof("Metrics init success")
+}
+
 func logCurrBlockHeight() {
 	ticker := time.NewTicker(config.DEFAULT_GEN_BLOCK_TIME * time.Second)
 	for {
@@ -27,6 +27,7 @@ import (
 
 	comm "github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	"github.com/OnyxPay/OnyxChain-legacy/p2pserver/common"
 	"github.com/OnyxPay/OnyxChain-legacy/p2pserver/message/types"
 )
@@ -126,6 +127,8 @@ func (this *Link) Rx() {
 
 		t := time.Now()
 		this.UpdateRXTime(t)
+		metrics.P2PRecvMsgs.Inc(msg.CmdType())
+		metrics.P2PRecvBytes.Add(float64(payloadSize), msg.CmdType())
 
 		if !this.needSendMsg(msg) {
 			log.Debugf("skip handle msgType:%s from:%d", msg.CmdType(), this.id)
@@ -194,6 +197,8 @@ func (this *Link) Tx(msg types.Message) error {
 		this.disconnectNotify()
 		return err
 	}
+	metrics.P2PSendMsgs.Inc(msg.CmdType())
+	metrics.P2PSendBytes.Add(float64(nByteCnt), msg.CmdType())
 
 	return nil
 }
@@ -0,0 +1,120 @@
+// +build ignore
+
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Sig server signs transactions by accounts in wallet data and external signers, for callers over http.
+//Build by: go build -o sigsvr sigsvr.go
+package main
+
+import (
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/OnyxPay/OnyxChain-legacy/cmd"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/abi"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr"
+	clisvrcom "github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/common"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/sigsvr/store"
+	"github.com/OnyxPay/OnyxChain-legacy/cmd/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/urfave/cli"
+)
+
+func setupSigSvr() *cli.App {
+	app := cli.NewApp()
+	app.Usage = "OnyxChain Sig server"
+	app.Action = startSigSvr
+	app.Version = config.Version
+	app.Copyright = "Copyright in 2019 The OnyxChain Authors"
+	app.Flags = []cli.Flag{
+		utils.LogLevelFlag,
+		utils.CliWalletDirFlag,
+		utils.CliAddressFlag,
+		utils.CliRpcPortFlag,
+		utils.CliABIPathFlag,
+		utils.CliAuthFileFlag,
+		utils.CliAuditLogFlag,
+		utils.SignerFlag,
+	}
+	app.Commands = []cli.Command{
+		sigsvr.ImportWalletCommand,
+	}
+	app.Before = func(context *cli.Context) error {
+		runtime.GOMAXPROCS(runtime.NumCPU())
+		return nil
+	}
+	return app
+}
+
+func startSigSvr(ctx *cli.Context) {
+	logLevel := ctx.GlobalInt(utils.GetFlagName(utils.LogLevelFlag))
+	log.InitLog(logLevel, log.PATH, log.Stdout)
+
+	walletDirPath := ctx.String(utils.GetFlagName(utils.CliWalletDirFlag))
+	if walletDirPath == "" {
+		log.Errorf("Please specify wallet data path using --%s flag", utils.CliWalletDirFlag.Name)
+		return
+	}
+	walletStore, err := store.NewWalletStore(walletDirPath)
+	if err != nil {
+		log.Errorf("NewWalletStore error:%s", err)
+		return
+	}
+	clisvrcom.DefWalletStore = walletStore
+	abi.DefAbiMgr.Init(ctx.String(utils.GetFlagName(utils.CliABIPathFlag)))
+
+	//caller auth, audit log and external signers should be set before server starts
+	if err := sigsvr.SetCliRpcSecurity(ctx); err != nil {
+		log.Errorf("SetCliRpcSecurity error:%s", err)
+		return
+	}
+	if err := sigsvr.SetExternalSigners(ctx); err != nil {
+		log.Errorf("SetExternalSigners error:%s", err)
+		return
+	}
+
+	rpcAddress := ctx.String(utils.GetFlagName(utils.CliAddressFlag))
+	rpcPort := ctx.Uint(utils.GetFlagName(utils.CliRpcPortFlag))
+	if rpcPort == 0 {
+		log.Errorf("Please specify sig server port using --%s flag", utils.CliRpcPortFlag.Name)
+		return
+	}
+	go sigsvr.DefCliRpcSvr.Start(rpcAddress, rpcPort)
+	log.Infof("Sig server listening on %s:%d", rpcAddress, rpcPort)
+
+	waitToExit()
+	sigsvr.DefCliRpcSvr.Close()
+}
+
+func waitToExit() {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	sig := <-sc
+	log.Infof("Sig server received exit signal:%s", sig.String())
+}
+
+func main() {
+	if err := setupSigSvr().Run(os.Args); err != nil {
+		cmd.PrintErrorMsg(err.Error())
+		os.Exit(1)
+	}
+}
@@ -21,6 +21,7 @@ package context
 import (
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
 )
 
 // ContextRef is a interface of smart context
@@ -38,7 +39,10 @@ type ContextRef interface {
 	CheckWitness(address common.Address) bool
 	PushNotifications(notifications []*event.NotifyEventInfo)
 	NewExecuteEngine(code []byte) (Engine, error)
+	AppCall(address common.Address, method string, args []interface{}) (interface{}, error)
 	CheckUseGas(gas uint64) bool
+	RefundGas(gas uint64)
+	Revertible(call func(cache *storage.CacheDB) error) error
 	CheckExecStep() bool
 }
 
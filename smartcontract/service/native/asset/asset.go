@@ -0,0 +1,329 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package asset
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type CreateAssetParam struct {
+	Id       string
+	Name     string
+	Decimals uint8
+	Issuer   []byte
+	KeyNo    uint64
+}
+
+//MintParam mints value of asset to account, caller is ONX ID holding role of mint
+type MintParam struct {
+	Id     string
+	Caller []byte
+	KeyNo  uint64
+	To     common.Address
+	Value  uint64
+}
+
+//BurnParam burns value of asset from account, caller is ONX ID holding role of burn
+type BurnParam struct {
+	Id     string
+	Caller []byte
+	KeyNo  uint64
+	From   common.Address `native:"from,witness"`
+	Value  uint64
+}
+
+type State struct {
+	From  common.Address `native:"from,witness"`
+	To    common.Address
+	Value uint64
+}
+
+type TransferParam struct {
+	Id     string
+	States []State
+}
+
+type ApproveParam struct {
+	Id    string
+	From  common.Address `native:"from,witness"`
+	To    common.Address
+	Value uint64
+}
+
+type TransferFromParam struct {
+	Id     string
+	Sender common.Address `native:"sender,witness"`
+	From   common.Address
+	To     common.Address
+	Value  uint64
+}
+
+type AssetParam struct {
+	Id string
+}
+
+type BalanceOfParam struct {
+	Id      string
+	Account common.Address
+}
+
+type AllowanceParam struct {
+	Id   string
+	From common.Address
+	To   common.Address
+}
+
+type CreateAssetEvent struct {
+	Id       string
+	Name     string
+	Decimals uint8
+	Issuer   string
+	Address  common.Address
+}
+
+//TransferEvent has the same states as transfer event of ONX, followed by asset id.
+//From is empty address for mint, and to is empty address for burn.
+type TransferEvent struct {
+	From  common.Address
+	To    common.Address
+	Value uint64
+	Id    string
+}
+
+//CreateAsset registers asset by issuer, who becomes admin of the asset in auth contract
+func CreateAsset(native *native.NativeService, param *CreateAssetParam) (bool, error) {
+	if err := checkAssetId(param.Id); err != nil {
+		return false, err
+	}
+	if len(param.Name) == 0 || len(param.Name) > MAX_ASSET_NAME_LEN {
+		return false, fmt.Errorf("length of asset name should be 1 to %d", MAX_ASSET_NAME_LEN)
+	}
+	if param.Decimals > MAX_DECIMALS {
+		return false, fmt.Errorf("decimals %d over %d", param.Decimals, MAX_DECIMALS)
+	}
+	if !account.VerifyID(string(param.Issuer)) {
+		return false, fmt.Errorf("invalid issuer ONX ID %s", param.Issuer)
+	}
+	info, err := getAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if info != nil {
+		return false, fmt.Errorf("asset %s already exists", param.Id)
+	}
+	ok, err := auth.VerifyOnxIDSig(native, param.Issuer, param.KeyNo)
+	if err != nil {
+		return false, fmt.Errorf("verify signature of issuer error:%s", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("verify signature of issuer failed")
+	}
+	address := AssetAddress(param.Id)
+	ok, err = auth.InitContractAdminOf(native, address, param.Issuer)
+	if err != nil {
+		return false, fmt.Errorf("init admin of asset error:%s", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("admin of asset %s is already set", param.Id)
+	}
+	info = &AssetInfo{
+		Id:       param.Id,
+		Name:     param.Name,
+		Decimals: param.Decimals,
+		Issuer:   param.Issuer,
+	}
+	if err := putAsset(native, info); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, CREATE_ASSET_NAME, &CreateAssetEvent{
+		Id:       info.Id,
+		Name:     info.Name,
+		Decimals: info.Decimals,
+		Issuer:   string(info.Issuer),
+		Address:  address,
+	})
+	return err == nil, err
+}
+
+func Mint(native *native.NativeService, param *MintParam) (bool, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if param.Value == 0 {
+		return false, nil
+	}
+	if err := checkRole(native, param.Id, param.Caller, MINT_NAME, param.KeyNo); err != nil {
+		return false, err
+	}
+	if info.TotalSupply+param.Value < info.TotalSupply {
+		return false, fmt.Errorf("mint amount:%d overflows totalSupply:%d", param.Value, info.TotalSupply)
+	}
+	info.TotalSupply += param.Value
+	if err := putAsset(native, info); err != nil {
+		return false, err
+	}
+	if err := toTransfer(native, genBalanceKey(param.Id, param.To), param.Value); err != nil {
+		return false, err
+	}
+	err = notifyTransfer(native, &TransferEvent{From: common.ADDRESS_EMPTY, To: param.To, Value: param.Value, Id: param.Id})
+	return err == nil, err
+}
+
+func Burn(native *native.NativeService, param *BurnParam) (bool, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if param.Value == 0 {
+		return false, nil
+	}
+	if err := checkRole(native, param.Id, param.Caller, BURN_NAME, param.KeyNo); err != nil {
+		return false, err
+	}
+	if err := fromTransfer(native, genBalanceKey(param.Id, param.From), param.Value); err != nil {
+		return false, fmt.Errorf("burn %s of %s error:%s", param.Id, param.From.ToBase58(), err)
+	}
+	info.TotalSupply -= param.Value
+	if err := putAsset(native, info); err != nil {
+		return false, err
+	}
+	err = notifyTransfer(native, &TransferEvent{From: param.From, To: common.ADDRESS_EMPTY, Value: param.Value, Id: param.Id})
+	return err == nil, err
+}
+
+func Transfer(native *native.NativeService, param *TransferParam) (bool, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	for _, state := range param.States {
+		if state.Value == 0 {
+			continue
+		}
+		if state.Value > info.TotalSupply {
+			return false, fmt.Errorf("transfer %s amount:%d over totalSupply:%d", param.Id, state.Value, info.TotalSupply)
+		}
+		if err := fromTransfer(native, genBalanceKey(param.Id, state.From), state.Value); err != nil {
+			return false, fmt.Errorf("transfer %s from %s error:%s", param.Id, state.From.ToBase58(), err)
+		}
+		if err := toTransfer(native, genBalanceKey(param.Id, state.To), state.Value); err != nil {
+			return false, err
+		}
+		err := notifyTransfer(native, &TransferEvent{From: state.From, To: state.To, Value: state.Value, Id: param.Id})
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func Approve(native *native.NativeService, param *ApproveParam) (bool, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if param.Value == 0 {
+		return false, nil
+	}
+	if param.Value > info.TotalSupply {
+		return false, fmt.Errorf("approve %s amount:%d over totalSupply:%d", param.Id, param.Value, info.TotalSupply)
+	}
+	native.CacheDB.Put(genApproveKey(param.Id, param.From, param.To), utils.GenUInt64StorageItem(param.Value).ToArray())
+	return true, nil
+}
+
+func TransferFrom(native *native.NativeService, param *TransferFromParam) (bool, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if param.Value == 0 {
+		return false, nil
+	}
+	if param.Value > info.TotalSupply {
+		return false, fmt.Errorf("transferFrom %s amount:%d over totalSupply:%d", param.Id, param.Value, info.TotalSupply)
+	}
+	approveKey := genApproveKey(param.Id, param.From, param.Sender)
+	approved, err := utils.GetStorageUInt64(native, approveKey)
+	if err != nil {
+		return false, err
+	}
+	if approved < param.Value {
+		return false, fmt.Errorf("approve balance insufficient! have %d, got %d", approved, param.Value)
+	} else if approved == param.Value {
+		native.CacheDB.Delete(approveKey)
+	} else {
+		native.CacheDB.Put(approveKey, utils.GenUInt64StorageItem(approved-param.Value).ToArray())
+	}
+	if err := fromTransfer(native, genBalanceKey(param.Id, param.From), param.Value); err != nil {
+		return false, fmt.Errorf("transferFrom %s from %s error:%s", param.Id, param.From.ToBase58(), err)
+	}
+	if err := toTransfer(native, genBalanceKey(param.Id, param.To), param.Value); err != nil {
+		return false, err
+	}
+	err = notifyTransfer(native, &TransferEvent{From: param.From, To: param.To, Value: param.Value, Id: param.Id})
+	return err == nil, err
+}
+
+func BalanceOf(native *native.NativeService, param *BalanceOfParam) (uint64, error) {
+	return utils.GetStorageUInt64(native, genBalanceKey(param.Id, param.Account))
+}
+
+func Allowance(native *native.NativeService, param *AllowanceParam) (uint64, error) {
+	return utils.GetStorageUInt64(native, genApproveKey(param.Id, param.From, param.To))
+}
+
+func TotalSupply(native *native.NativeService, param *AssetParam) (uint64, error) {
+	info, err := mustGetAsset(native, param.Id)
+	if err != nil {
+		return 0, err
+	}
+	return info.TotalSupply, nil
+}
+
+func GetAsset(native *native.NativeService, param *AssetParam) (*AssetInfo, error) {
+	return mustGetAsset(native, param.Id)
+}
+
+//checkRole verifies that caller signs and holds auth token of fn assigned by issuer of asset
+func checkRole(native *native.NativeService, id string, caller []byte, fn string, keyNo uint64) error {
+	ok, err := auth.VerifyContractToken(native, AssetAddress(id), caller, fn, keyNo)
+	if err != nil {
+		return fmt.Errorf("verify %s token of asset %s error:%s", fn, id, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s has no authorization to %s asset %s", caller, fn, id)
+	}
+	return nil
+}
+
+func notifyTransfer(native *native.NativeService, evt *TransferEvent) error {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil
+	}
+	return contract.Notify(native, TRANSFER_NAME, evt)
+}
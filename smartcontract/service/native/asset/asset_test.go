@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package asset
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+func TestCheckAssetId(t *testing.T) {
+	assert.Nil(t, checkAssetId("USD"))
+	assert.Nil(t, checkAssetId("gold-1_oz"))
+	assert.NotNil(t, checkAssetId(""))
+	assert.NotNil(t, checkAssetId("US D"))
+	assert.NotNil(t, checkAssetId(string(bytes.Repeat([]byte{'a'}, MAX_ASSET_ID_LEN+1))))
+}
+
+func TestBalanceKey(t *testing.T) {
+	addr := common.Address{1, 2, 3}
+	assert.NotEqual(t, genBalanceKey("AB", addr), genBalanceKey("A", common.Address{'B', 1, 2, 3}))
+	assert.NotEqual(t, AssetAddress("USD"), AssetAddress("EUR"))
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package asset is the registry of assets issued on chain. An issuer ONX ID creates asset with a unique
+//id, and assigns roles of mint and burn of the asset to ONX IDs by auth contract under AssetAddress.
+//Holders transfer, approve and transferFrom assets the same as ONX.
+package asset
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	CREATE_ASSET_NAME  = "createAsset"
+	MINT_NAME          = "mint"
+	BURN_NAME          = "burn"
+	TRANSFER_NAME      = "transfer"
+	APPROVE_NAME       = "approve"
+	TRANSFER_FROM_NAME = "transferFrom"
+	BALANCEOF_NAME     = "balanceOf"
+	ALLOWANCE_NAME     = "allowance"
+	TOTALSUPPLY_NAME   = "totalSupply"
+	GET_ASSET_NAME     = "getAsset"
+)
+
+var contract *framework.Contract
+
+func init() {
+	contract = framework.NewContract("asset", utils.AssetContractAddress).
+		Method(CREATE_ASSET_NAME, CreateAsset).
+		Method(MINT_NAME, Mint).
+		Method(BURN_NAME, Burn).
+		Method(TRANSFER_NAME, Transfer).
+		Method(APPROVE_NAME, Approve).
+		Method(TRANSFER_FROM_NAME, TransferFrom).
+		Method(BALANCEOF_NAME, BalanceOf).
+		Method(ALLOWANCE_NAME, Allowance).
+		Method(TOTALSUPPLY_NAME, TotalSupply).
+		Method(GET_ASSET_NAME, GetAsset).
+		Event(CREATE_ASSET_NAME, CreateAssetEvent{}).
+		Event(TRANSFER_NAME, TransferEvent{})
+}
+
+//Init installs asset registry as native contract
+func Init() {
+	contract.Install()
+}
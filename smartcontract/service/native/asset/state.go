@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package asset
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	MAX_ASSET_ID_LEN   = 32
+	MAX_ASSET_NAME_LEN = 64
+	MAX_DECIMALS       = 18
+)
+
+var (
+	PreAsset   = []byte{0x01}
+	PreBalance = []byte{0x02}
+	PreApprove = []byte{0x03}
+)
+
+//AssetInfo is the registered asset, total supply changes with mint and burn
+type AssetInfo struct {
+	Id          string
+	Name        string
+	Decimals    uint8
+	Issuer      []byte //ONX ID of issuer, admin of asset in auth contract
+	TotalSupply uint64
+}
+
+//AssetAddress returns address of asset in auth contract, roles to mint and burn the asset are
+//assigned by issuer under this address
+func AssetAddress(id string) common.Address {
+	return common.AddressFromVmCode(utils.ConcatKey(utils.AssetContractAddress, []byte(id)))
+}
+
+func checkAssetId(id string) error {
+	if len(id) == 0 || len(id) > MAX_ASSET_ID_LEN {
+		return fmt.Errorf("length of asset id should be 1 to %d", MAX_ASSET_ID_LEN)
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return fmt.Errorf("invalid character %q in asset id", c)
+		}
+	}
+	return nil
+}
+
+//assetIdKey prefixes id with its length, so that keys of different assets do not overlap
+func assetIdKey(id string) []byte {
+	return append([]byte{byte(len(id))}, id...)
+}
+
+func genAssetKey(id string) []byte {
+	return utils.ConcatKey(utils.AssetContractAddress, PreAsset, []byte(id))
+}
+
+func genBalanceKey(id string, addr common.Address) []byte {
+	return utils.ConcatKey(utils.AssetContractAddress, PreBalance, assetIdKey(id), addr[:])
+}
+
+func genApproveKey(id string, from, to common.Address) []byte {
+	return utils.ConcatKey(utils.AssetContractAddress, PreApprove, assetIdKey(id), from[:], to[:])
+}
+
+//getAsset returns nil if asset is not created
+func getAsset(native *native.NativeService, id string) (*AssetInfo, error) {
+	item, err := utils.GetStorageItem(native, genAssetKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("get asset %s error:%s", id, err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	info := new(AssetInfo)
+	if err := framework.Decode(item.Value, info); err != nil {
+		return nil, fmt.Errorf("decode asset %s error:%s", id, err)
+	}
+	return info, nil
+}
+
+func mustGetAsset(native *native.NativeService, id string) (*AssetInfo, error) {
+	info, err := getAsset(native, id)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("asset %s not found", id)
+	}
+	return info, nil
+}
+
+func putAsset(native *native.NativeService, info *AssetInfo) error {
+	data, err := framework.Encode(info)
+	if err != nil {
+		return fmt.Errorf("encode asset %s error:%s", info.Id, err)
+	}
+	utils.PutBytes(native, genAssetKey(info.Id), data)
+	return nil
+}
+
+func fromTransfer(native *native.NativeService, key []byte, value uint64) error {
+	balance, err := utils.GetStorageUInt64(native, key)
+	if err != nil {
+		return err
+	}
+	if balance < value {
+		return fmt.Errorf("balance insufficient, balance:%d, transfer amount:%d", balance, value)
+	} else if balance == value {
+		native.CacheDB.Delete(key)
+	} else {
+		native.CacheDB.Put(key, utils.GenUInt64StorageItem(balance-value).ToArray())
+	}
+	return nil
+}
+
+func toTransfer(native *native.NativeService, key []byte, value uint64) error {
+	balance, err := utils.GetStorageUInt64(native, key)
+	if err != nil {
+		return err
+	}
+	native.CacheDB.Put(key, utils.GenUInt64StorageItem(balance+value).ToArray())
+	return nil
+}
@@ -677,4 +677,6 @@ func RegisterAuthContract(native *native.NativeService) {
 	native.Register("assignOnxIDsToRole", AssignOnxIDsToRole)
 	native.Register("verifyToken", VerifyToken)
 	native.Register("transfer", Transfer)
+	native.Register("getContractRoles", GetContractRoles)
+	native.Register("getOnxIDRoles", GetOnxIDRoles)
 }
@@ -358,3 +358,47 @@ func (this *VerifyTokenParam) Deserialize(rd io.Reader) error {
 	}
 	return nil
 }
+
+/* **********************************************   */
+type ContractRolesParam struct {
+	ContractAddr common.Address
+}
+
+func (this *ContractRolesParam) Serialize(w io.Writer) error {
+	return serializeAddress(w, this.ContractAddr)
+}
+
+func (this *ContractRolesParam) Deserialize(rd io.Reader) error {
+	var err error
+	if this.ContractAddr, err = utils.ReadAddress(rd); err != nil {
+		return err
+	}
+	return nil
+}
+
+/* **********************************************   */
+type OnxIDRolesParam struct {
+	ContractAddr common.Address
+	OnxID        []byte
+}
+
+func (this *OnxIDRolesParam) Serialize(w io.Writer) error {
+	if err := serializeAddress(w, this.ContractAddr); err != nil {
+		return err
+	}
+	if err := serialization.WriteVarBytes(w, this.OnxID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (this *OnxIDRolesParam) Deserialize(rd io.Reader) error {
+	var err error
+	if this.ContractAddr, err = utils.ReadAddress(rd); err != nil {
+		return err
+	}
+	if this.OnxID, err = serialization.ReadVarBytes(rd); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+)
+
+//GetContractRoles returns admin of contract, and functions and holders of its roles, so that contract
+//owner can audit permissions. Roles and holders are found by scanning auth storage of contract.
+func GetContractRoles(native *native.NativeService) ([]byte, error) {
+	param := new(ContractRolesParam)
+	if err := param.Deserialize(bytes.NewReader(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getContractRoles] deserialize param failed: %v", err)
+	}
+	admin, err := getContractAdmin(native, param.ContractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("[getContractRoles] getContractAdmin failed: %v", err)
+	}
+	roles, holders, err := contractRoles(native, param.ContractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("[getContractRoles] %v", err)
+	}
+	result := &ContractRoles{Admin: admin, Roles: make([]*RoleInfo, 0, len(roles.items))}
+	for _, role := range roles.items {
+		info := &RoleInfo{Role: role, Funcs: make([]string, 0), Grants: make([]*RoleGrant, 0)}
+		funcs, err := getRoleFunc(native, param.ContractAddr, role)
+		if err != nil {
+			return nil, fmt.Errorf("[getContractRoles] getRoleFunc failed: %v", err)
+		}
+		if funcs != nil {
+			info.Funcs = funcs.funcNames
+		}
+		for _, onxID := range holders[string(role)].items {
+			grants, err := grantsOf(native, param.ContractAddr, onxID, role)
+			if err != nil {
+				return nil, fmt.Errorf("[getContractRoles] %v", err)
+			}
+			info.Grants = append(info.Grants, grants...)
+		}
+		result.Roles = append(result.Roles, info)
+	}
+	return framework.Encode(result)
+}
+
+//contractRoles returns roles of contract and holders of each role, found in functions, tokens and
+//delegate status of contract
+func contractRoles(native *native.NativeService, contractAddr common.Address) (*bytesList,
+	map[string]*bytesList, error) {
+	roles := new(bytesList)
+	holders := make(map[string]*bytesList)
+	addRole := func(role []byte) {
+		if roles.add(role) {
+			holders[string(role)] = new(bytesList)
+		}
+	}
+	addHolder := func(role, onxID []byte) {
+		addRole(role)
+		holders[string(role)].add(onxID)
+	}
+	err := scanContract(native, contractAddr, PreRoleFunc, func(role, value []byte) error {
+		addRole(role)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan roleFuncs failed: %v", err)
+	}
+	err = scanContract(native, contractAddr, PreRoleToken, func(onxID, value []byte) error {
+		tokens := new(roleTokens)
+		if err := tokens.Deserialize(bytes.NewReader(value)); err != nil {
+			return fmt.Errorf("deserialize roleTokens object failed. data: %x", value)
+		}
+		for _, token := range tokens.tokens {
+			addHolder(token.role, onxID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan roleTokens failed: %v", err)
+	}
+	err = scanContract(native, contractAddr, PreDelegateStatus, func(onxID, value []byte) error {
+		status := new(Status)
+		if err := status.Deserialize(bytes.NewReader(value)); err != nil {
+			return fmt.Errorf("deserialize Status object failed. data: %x", value)
+		}
+		for _, s := range status.status {
+			addHolder(s.role, onxID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan delegate status failed: %v", err)
+	}
+	return roles, holders, nil
+}
+
+//scanContract calls fn with key suffix and value of each item stored under prefix for contract
+func scanContract(native *native.NativeService, contractAddr common.Address, prefix []byte,
+	fn func(suffix, value []byte) error) error {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, prefix...)
+	iter := native.CacheDB.NewIterator(key)
+	defer iter.Release()
+	for has := iter.First(); has; has = iter.Next() {
+		value, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return err
+		}
+		suffix := append([]byte{}, iter.Key()[len(key):]...)
+		if err := fn(suffix, value); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+//GetOnxIDRoles returns roles held by ONX ID in contract, including expired delegates
+func GetOnxIDRoles(native *native.NativeService) ([]byte, error) {
+	param := new(OnxIDRolesParam)
+	if err := param.Deserialize(bytes.NewReader(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getOnxIDRoles] deserialize param failed: %v", err)
+	}
+	grants, err := grantsOf(native, param.ContractAddr, param.OnxID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[getOnxIDRoles] %v", err)
+	}
+	return framework.Encode(&OnxIDRoles{Grants: grants})
+}
+
+//grantsOf returns roles assigned or delegated to ONX ID, only grants of role if role is not nil
+func grantsOf(native *native.NativeService, contractAddr common.Address, onxID, role []byte) ([]*RoleGrant, error) {
+	grants := make([]*RoleGrant, 0)
+	tokens, err := getOnxIDToken(native, contractAddr, onxID)
+	if err != nil {
+		return nil, fmt.Errorf("getOnxIDToken failed: %v", err)
+	}
+	if tokens != nil {
+		for _, token := range tokens.tokens {
+			if role != nil && !bytes.Equal(token.role, role) {
+				continue
+			}
+			grants = append(grants, newRoleGrant(native, onxID, token, nil))
+		}
+	}
+	status, err := getDelegateStatus(native, contractAddr, onxID)
+	if err != nil {
+		return nil, fmt.Errorf("getDelegateStatus failed: %v", err)
+	}
+	if status != nil {
+		for _, s := range status.status {
+			if role != nil && !bytes.Equal(s.role, role) {
+				continue
+			}
+			grants = append(grants, newRoleGrant(native, onxID, &s.AuthToken, s.root))
+		}
+	}
+	return grants, nil
+}
+
+//newRoleGrant returns grant of auth token, which is expired since block time reaches its expire time
+func newRoleGrant(native *native.NativeService, onxID []byte, token *AuthToken, delegator []byte) *RoleGrant {
+	return &RoleGrant{
+		OnxID:      onxID,
+		Role:       token.role,
+		Level:      token.level,
+		ExpireTime: token.expireTime,
+		Delegator:  delegator,
+		Expired:    native.Time >= token.expireTime,
+	}
+}
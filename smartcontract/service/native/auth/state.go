@@ -19,6 +19,7 @@
 package auth
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
@@ -187,3 +188,47 @@ func (this *roleTokens) Deserialize(rd io.Reader) error {
 	}
 	return nil
 }
+
+//bytesList is list of distinct byte slices in order of addition
+type bytesList struct {
+	items [][]byte
+}
+
+//add appends item if it is not in list, returns false if it is
+func (this *bytesList) add(item []byte) bool {
+	for _, v := range this.items {
+		if bytes.Equal(v, item) {
+			return false
+		}
+	}
+	this.items = append(this.items, item)
+	return true
+}
+
+//RoleGrant is role held by ONX ID, assigned by admin or delegated by holder of role. Delegator is
+//empty for role assigned by admin.
+type RoleGrant struct {
+	OnxID      []byte
+	Role       []byte
+	Level      uint8
+	ExpireTime uint32
+	Delegator  []byte
+	Expired    bool
+}
+
+type RoleInfo struct {
+	Role   []byte
+	Funcs  []string
+	Grants []*RoleGrant
+}
+
+//ContractRoles is admin and roles of contract, returned by getContractRoles
+type ContractRoles struct {
+	Admin []byte
+	Roles []*RoleInfo
+}
+
+//OnxIDRoles is roles held by ONX ID in contract, returned by getOnxIDRoles
+type OnxIDRoles struct {
+	Grants []*RoleGrant
+}
@@ -58,6 +58,60 @@ func getContractAdmin(native *native.NativeService, contractAddr common.Address)
 	return item.Value, nil
 }
 
+//VerifyContractAdmin verifies signature of admin ONX ID of contract, used by other native contracts
+//to authorize operations of contract admin. Returns false if admin is not set.
+func VerifyContractAdmin(native *native.NativeService, contractAddr common.Address, keyNo uint64) (bool, error) {
+	key := utils.ConcatKey(utils.AuthContractAddress, contractAddr[:], PreAdmin)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return false, err
+	}
+	if item == nil {
+		return false, nil
+	}
+	return verifySig(native, item.Value, keyNo)
+}
+
+//InitContractAdminOf sets admin ONX ID of contract if it is not set, used by native contracts to make
+//auth manage sub contracts of their own, like assets of asset registry. Returns false if admin is already set.
+func InitContractAdminOf(native *native.NativeService, contractAddr common.Address, onxID []byte) (bool, error) {
+	key := utils.ConcatKey(utils.AuthContractAddress, contractAddr[:], PreAdmin)
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return false, err
+	}
+	if item != nil {
+		return false, nil
+	}
+	utils.PutBytes(native, key, onxID)
+	return true, nil
+}
+
+//VerifyContractToken verifies that caller signs with key keyNo and holds auth token of function fn
+//of contract, the same as verifyToken invoked by the contract itself
+func VerifyContractToken(native *native.NativeService, contractAddr common.Address, caller []byte, fn string,
+	keyNo uint64) (bool, error) {
+	param := &VerifyTokenParam{ContractAddr: contractAddr, Caller: caller, Fn: fn, KeyNo: keyNo}
+	bf := new(bytes.Buffer)
+	if err := param.Serialize(bf); err != nil {
+		return false, err
+	}
+	ret, err := native.NativeCall(utils.AuthContractAddress, "verifyToken", bf.Bytes())
+	if err != nil {
+		return false, err
+	}
+	valid, ok := ret.([]byte)
+	if !ok {
+		return false, fmt.Errorf("verifyToken return non-bool value")
+	}
+	return bytes.Equal(valid, utils.BYTE_TRUE), nil
+}
+
+//VerifyOnxIDSig verifies that ONX ID signs with key keyNo
+func VerifyOnxIDSig(native *native.NativeService, onxID []byte, keyNo uint64) (bool, error) {
+	return verifySig(native, onxID, keyNo)
+}
+
 func putContractAdmin(native *native.NativeService, contractAddr common.Address, adminOnxID []byte) error {
 	key := concatContractAdminKey(native, contractAddr)
 	utils.PutBytes(native, key, adminOnxID)
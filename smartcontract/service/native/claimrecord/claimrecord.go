@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package claimrecord
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+)
+
+type CommitParam struct {
+	ClaimId     []byte
+	Issuer      []byte
+	IssuerKeyNo uint32
+	Subject     []byte
+	Expiration  uint32
+}
+
+type RevokeParam struct {
+	ClaimId []byte
+	Issuer  []byte
+	KeyNo   uint32
+}
+
+type ClaimIdParam struct {
+	ClaimId []byte
+	Issuer  []byte
+}
+
+type CommitEvent struct {
+	ClaimId    []byte
+	Issuer     []byte
+	Subject    []byte
+	Expiration uint32
+}
+
+type RevokeEvent struct {
+	ClaimId []byte
+	Issuer  []byte
+}
+
+//Commit records claim of issuer about subject, signed by key IssuerKeyNo of issuer. Claim id can not be
+//reused by the same issuer, even if claim committed with it is revoked or expired.
+func Commit(native *native.NativeService, param *CommitParam) (bool, error) {
+	if err := checkClaimId(param.ClaimId); err != nil {
+		return false, err
+	}
+	if err := checkID(param.Subject); err != nil {
+		return false, err
+	}
+	if param.Expiration != NEVER_EXPIRE && param.Expiration <= native.Time {
+		return false, fmt.Errorf("expiration %d is passed", param.Expiration)
+	}
+	claim, err := getClaim(native, param.Issuer, param.ClaimId)
+	if err != nil {
+		return false, err
+	}
+	if claim != nil {
+		return false, fmt.Errorf("claim %x of %s is committed", param.ClaimId, param.Issuer)
+	}
+	ok, err := auth.VerifyOnxIDSig(native, param.Issuer, uint64(param.IssuerKeyNo))
+	if err != nil {
+		return false, fmt.Errorf("verify signature of issuer error:%s", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("verify signature of issuer failed")
+	}
+	claim = &Claim{
+		ClaimId:    param.ClaimId,
+		Issuer:     param.Issuer,
+		Subject:    param.Subject,
+		Committed:  native.Time,
+		Expiration: param.Expiration,
+		Status:     CLAIM_VALID,
+	}
+	if err := putClaim(native, claim); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, COMMIT_NAME, &CommitEvent{
+		ClaimId:    claim.ClaimId,
+		Issuer:     claim.Issuer,
+		Subject:    claim.Subject,
+		Expiration: claim.Expiration,
+	})
+	return true, err
+}
+
+//Revoke revokes claim, signed by key KeyNo of its issuer. Expired claim can be revoked too, so that
+//revocation is recorded.
+func Revoke(native *native.NativeService, param *RevokeParam) (bool, error) {
+	claim, err := getClaim(native, param.Issuer, param.ClaimId)
+	if err != nil {
+		return false, err
+	}
+	if claim == nil {
+		return false, fmt.Errorf("claim %x of %s not found", param.ClaimId, param.Issuer)
+	}
+	if claim.Status == CLAIM_REVOKED {
+		return false, fmt.Errorf("claim %x of %s is revoked", param.ClaimId, param.Issuer)
+	}
+	ok, err := auth.VerifyOnxIDSig(native, param.Issuer, uint64(param.KeyNo))
+	if err != nil {
+		return false, fmt.Errorf("verify signature of issuer error:%s", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("verify signature of issuer failed")
+	}
+	claim.Status = CLAIM_REVOKED
+	if err := putClaim(native, claim); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, REVOKE_NAME, &RevokeEvent{
+		ClaimId: claim.ClaimId,
+		Issuer:  claim.Issuer,
+	})
+	return true, err
+}
+
+//GetClaim returns claim of issuer with its status at block time
+func GetClaim(native *native.NativeService, param *ClaimIdParam) (*Claim, error) {
+	claim, err := getClaim(native, param.Issuer, param.ClaimId)
+	if err != nil {
+		return nil, err
+	}
+	if claim == nil {
+		return nil, fmt.Errorf("claim %x of %s not found", param.ClaimId, param.Issuer)
+	}
+	claim.Status = claim.statusAt(native.Time)
+	return claim, nil
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package claimrecord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaim_StatusAt(t *testing.T) {
+	claim := &Claim{Expiration: 1000, Status: CLAIM_VALID}
+	assert.Equal(t, CLAIM_VALID, claim.statusAt(999))
+	assert.Equal(t, CLAIM_EXPIRED, claim.statusAt(1000))
+
+	claim.Status = CLAIM_REVOKED
+	assert.Equal(t, CLAIM_REVOKED, claim.statusAt(1000))
+
+	claim = &Claim{Expiration: NEVER_EXPIRE, Status: CLAIM_VALID}
+	assert.Equal(t, CLAIM_VALID, claim.statusAt(1<<31))
+}
+
+func TestCheckClaimId(t *testing.T) {
+	assert.NotNil(t, checkClaimId(nil))
+	assert.Nil(t, checkClaimId(make([]byte, 32)))
+	assert.Nil(t, checkClaimId(make([]byte, MAX_CLAIM_ID_LEN)))
+	assert.NotNil(t, checkClaimId(make([]byte, MAX_CLAIM_ID_LEN+1)))
+}
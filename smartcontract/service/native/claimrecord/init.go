@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package claimrecord implements registry of verifiable claims issued by ONX IDs. Issuer commits hash of
+//credential about subject ONX ID, signed by one of keys of issuer in onxid, and only issuer can revoke it. Claim
+//is identified by issuer and claim id, so that issuers can not take claim ids of each other. Credential itself
+//is kept off chain by subject, and verifier checks status of its hash in registry.
+package claimrecord
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	COMMIT_NAME    = "commit"
+	REVOKE_NAME    = "revoke"
+	GET_CLAIM_NAME = "getClaim"
+)
+
+var contract *framework.Contract
+
+func init() {
+	contract = framework.NewContract("claimRecord", utils.ClaimContractAddress).
+		Method(COMMIT_NAME, Commit).
+		Method(REVOKE_NAME, Revoke).
+		Method(GET_CLAIM_NAME, GetClaim).
+		Event(COMMIT_NAME, CommitEvent{}).
+		Event(REVOKE_NAME, RevokeEvent{})
+}
+
+//Init installs claim record as native contract
+func Init() {
+	contract.Install()
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package claimrecord
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//status of claim, CLAIM_EXPIRED is never stored but reported by GetClaim once expiration is passed
+const (
+	CLAIM_VALID   uint8 = 0
+	CLAIM_REVOKED uint8 = 1
+	CLAIM_EXPIRED uint8 = 2
+)
+
+const (
+	MAX_CLAIM_ID_LEN = 64
+	NEVER_EXPIRE     = 0
+)
+
+var (
+	PreClaim = []byte{0x01}
+)
+
+//Claim is commitment of issuer to credential about subject, identified by ClaimId under issuer, which is
+//supposed to be hash of credential. Expiration is block timestamp in seconds, 0 means claim never expires.
+type Claim struct {
+	ClaimId    []byte
+	Issuer     []byte
+	Subject    []byte
+	Committed  uint32
+	Expiration uint32
+	Status     uint8
+}
+
+//statusAt returns status of claim at block time
+func (this *Claim) statusAt(time uint32) uint8 {
+	if this.Status == CLAIM_VALID && this.Expiration != NEVER_EXPIRE && time >= this.Expiration {
+		return CLAIM_EXPIRED
+	}
+	return this.Status
+}
+
+func checkClaimId(claimId []byte) error {
+	if len(claimId) == 0 || len(claimId) > MAX_CLAIM_ID_LEN {
+		return fmt.Errorf("length of claim id should be in 1..%d", MAX_CLAIM_ID_LEN)
+	}
+	return nil
+}
+
+func checkID(id []byte) error {
+	if !account.VerifyID(string(id)) {
+		return fmt.Errorf("invalid ONX ID %s", id)
+	}
+	return nil
+}
+
+//genClaimKey prefixes claim id with length of issuer, so that claim ids of different issuers never collide
+func genClaimKey(issuer, claimId []byte) []byte {
+	bf := new(bytes.Buffer)
+	serialization.WriteVarBytes(bf, issuer)
+	return utils.ConcatKey(utils.ClaimContractAddress, PreClaim, bf.Bytes(), claimId)
+}
+
+//getClaim returns nil if no claim is committed by issuer with claimId
+func getClaim(native *native.NativeService, issuer, claimId []byte) (*Claim, error) {
+	item, err := utils.GetStorageItem(native, genClaimKey(issuer, claimId))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+	claim := new(Claim)
+	if err := framework.Decode(item.Value, claim); err != nil {
+		return nil, fmt.Errorf("decode claim %x error:%s", claimId, err)
+	}
+	return claim, nil
+}
+
+func putClaim(native *native.NativeService, claim *Claim) error {
+	data, err := framework.Encode(claim)
+	if err != nil {
+		return err
+	}
+	utils.PutBytes(native, genClaimKey(claim.Issuer, claim.ClaimId), data)
+	return nil
+}
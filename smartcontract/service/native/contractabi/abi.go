@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contractabi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+)
+
+//max size of abi json
+const MAX_ABI_SIZE = 64 * 1024
+
+const (
+	ABI_PREFIX      = "abi"
+	DEPLOYER_PREFIX = "deployer"
+)
+
+//AbiKey returns key of contract abi in registry storage, without registry address
+func AbiKey(contract common.Address) []byte {
+	return append([]byte(ABI_PREFIX), contract[:]...)
+}
+
+//DeployerKey returns key of contract deployer in registry storage, without registry address
+func DeployerKey(contract common.Address) []byte {
+	return append([]byte(DEPLOYER_PREFIX), contract[:]...)
+}
+
+//PutDeployer records payer of deploy transaction as deployer of contract, who can register abi of it
+func PutDeployer(cache *storage.CacheDB, contract, deployer common.Address) {
+	key := utils.ConcatKey(utils.AbiContractAddress, DeployerKey(contract))
+	cache.Put(key, states.GenRawStorageItem(deployer[:]))
+}
+
+type RegisterAbiParam struct {
+	Contract common.Address
+	Abi      []byte
+}
+
+func (this *RegisterAbiParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Contract); err != nil {
+		return fmt.Errorf("serialize contract error:%v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Abi); err != nil {
+		return fmt.Errorf("serialize abi error:%v", err)
+	}
+	return nil
+}
+
+func (this *RegisterAbiParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Contract, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("deserialize contract error:%v", err)
+	}
+	if this.Abi, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("deserialize abi error:%v", err)
+	}
+	return nil
+}
+
+//RegisterAbi stores abi json of contract, signed by deployer of contract or called by contract itself
+func RegisterAbi(native *native.NativeService) ([]byte, error) {
+	param := new(RegisterAbiParam)
+	if err := param.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("registerAbi, %s", err)
+	}
+	if len(param.Abi) == 0 || len(param.Abi) > MAX_ABI_SIZE {
+		return utils.BYTE_FALSE, fmt.Errorf("registerAbi, abi size should be in (0, %d]", MAX_ABI_SIZE)
+	}
+	if !json.Valid(param.Abi) {
+		return utils.BYTE_FALSE, fmt.Errorf("registerAbi, abi is not valid json")
+	}
+	if err := checkAuthorization(native, param.Contract); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("registerAbi, %s", err)
+	}
+	utils.PutBytes(native, utils.ConcatKey(utils.AbiContractAddress, AbiKey(param.Contract)), param.Abi)
+	notifyAbi(native, REGISTER_ABI, param.Contract)
+	return utils.BYTE_TRUE, nil
+}
+
+//RemoveAbi deletes abi of contract, with the same authorization as RegisterAbi
+func RemoveAbi(native *native.NativeService) ([]byte, error) {
+	contract, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("removeAbi, %s", err)
+	}
+	if err := checkAuthorization(native, contract); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("removeAbi, %s", err)
+	}
+	native.CacheDB.Delete(utils.ConcatKey(utils.AbiContractAddress, AbiKey(contract)))
+	notifyAbi(native, REMOVE_ABI, contract)
+	return utils.BYTE_TRUE, nil
+}
+
+//GetAbi returns abi json of contract, empty if not registered
+func GetAbi(native *native.NativeService) ([]byte, error) {
+	contract, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getAbi, %s", err)
+	}
+	return getBytes(native, AbiKey(contract))
+}
+
+//GetDeployer returns address of contract deployer, empty if unknown
+func GetDeployer(native *native.NativeService) ([]byte, error) {
+	contract, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getDeployer, %s", err)
+	}
+	return getBytes(native, DeployerKey(contract))
+}
+
+func checkAuthorization(native *native.NativeService, contract common.Address) error {
+	dep, err := native.CacheDB.GetContract(contract)
+	if err != nil {
+		return fmt.Errorf("get contract error:%s", err)
+	}
+	if dep == nil {
+		return fmt.Errorf("contract %s not found", contract.ToHexString())
+	}
+	if native.ContextRef.CheckWitness(contract) {
+		return nil
+	}
+	deployer, err := getBytes(native, DeployerKey(contract))
+	if err != nil {
+		return err
+	}
+	if len(deployer) == 0 {
+		return fmt.Errorf("deployer of contract %s unknown, abi should be registered by contract itself", contract.ToHexString())
+	}
+	addr, err := common.AddressParseFromBytes(deployer)
+	if err != nil {
+		return err
+	}
+	return utils.ValidateOwner(native, addr)
+}
+
+func getBytes(native *native.NativeService, key []byte) ([]byte, error) {
+	item, err := utils.GetStorageItem(native, utils.ConcatKey(utils.AbiContractAddress, key))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+	return item.Value, nil
+}
+
+func notifyAbi(native *native.NativeService, method string, contract common.Address) {
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: utils.AbiContractAddress,
+			States:          []interface{}{method, contract.ToHexString()},
+		})
+}
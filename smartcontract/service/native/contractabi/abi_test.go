@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contractabi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+func TestRegisterAbiParam_Serialize(t *testing.T) {
+	param := &RegisterAbiParam{
+		Contract: common.Address{1, 2, 3},
+		Abi:      []byte(`{"hash":"0102","functions":[]}`),
+	}
+	bf := new(bytes.Buffer)
+	err := param.Serialize(bf)
+	assert.Nil(t, err)
+
+	param2 := &RegisterAbiParam{}
+	err = param2.Deserialize(bytes.NewReader(bf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, param, param2)
+}
+
+func TestAbiKey(t *testing.T) {
+	contract := common.Address{1}
+	assert.NotEqual(t, AbiKey(contract), DeployerKey(contract))
+	assert.Equal(t, AbiKey(contract), AbiKey(common.Address{1}))
+	assert.NotEqual(t, AbiKey(contract), AbiKey(common.Address{2}))
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contractabi
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	REGISTER_ABI = "registerAbi"
+	REMOVE_ABI   = "removeAbi"
+	GET_ABI      = "getAbi"
+	GET_DEPLOYER = "getDeployer"
+)
+
+func Init() {
+	native.Contracts[utils.AbiContractAddress] = RegisterAbiContract
+}
+
+func RegisterAbiContract(native *native.NativeService) {
+	native.Register(REGISTER_ABI, RegisterAbi)
+	native.Register(REMOVE_ABI, RemoveAbi)
+	native.Register(GET_ABI, GetAbi)
+	native.Register(GET_DEPLOYER, GetDeployer)
+}
@@ -0,0 +1,340 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package escrow
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type CreateVestingParam struct {
+	Creator     common.Address `native:"creator,witness"`
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+	ByHeight    bool
+	Start       uint32
+	Cliff       uint32
+	Duration    uint32
+}
+
+type ClaimParam struct {
+	Id          uint64
+	Beneficiary common.Address `native:"beneficiary,witness"`
+}
+
+type CreateEscrowParam struct {
+	Depositor   common.Address `native:"depositor,witness"`
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+	Arbiter     []byte
+	ByHeight    bool
+	Timeout     uint32
+}
+
+//SettleParam is param of release and refund, key number is of arbiter ONX ID
+type SettleParam struct {
+	Id    uint64
+	KeyNo uint64
+}
+
+type IdParam struct {
+	Id uint64
+}
+
+type LockedBalanceParam struct {
+	Account common.Address
+	Asset   common.Address
+}
+
+//VestingInfo is vesting with amount claimable at current block
+type VestingInfo struct {
+	Vesting   *Vesting
+	Claimable uint64
+}
+
+//LockedBalance is amount of asset locked in vestings of beneficiary and open escrows of depositor. Claimable is
+//part of locked which account can take at current block, vested amount not claimed and escrows refundable
+//after timeout.
+type LockedBalance struct {
+	Locked    uint64
+	Claimable uint64
+}
+
+type CreateVestingEvent struct {
+	Id          uint64
+	Creator     common.Address
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+}
+
+type CreateEscrowEvent struct {
+	Id          uint64
+	Depositor   common.Address
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+	Arbiter     string
+}
+
+//PayoutEvent is event of claim, release and refund
+type PayoutEvent struct {
+	Id     uint64
+	To     common.Address
+	Asset  common.Address
+	Amount uint64
+}
+
+func CreateVesting(native *native.NativeService, param *CreateVestingParam) (uint64, error) {
+	if err := checkAsset(param.Asset); err != nil {
+		return 0, err
+	}
+	if param.Amount == 0 {
+		return 0, fmt.Errorf("amount of vesting should be positive")
+	}
+	if param.Cliff > param.Duration {
+		return 0, fmt.Errorf("cliff %d is longer than duration %d", param.Cliff, param.Duration)
+	}
+	id, err := nextId(native)
+	if err != nil {
+		return 0, err
+	}
+	if err := appCallTransfer(native, param.Asset, param.Creator, utils.EscrowContractAddress, param.Amount); err != nil {
+		return 0, fmt.Errorf("lock vesting amount error:%s", err)
+	}
+	vesting := &Vesting{
+		Id:          id,
+		Creator:     param.Creator,
+		Beneficiary: param.Beneficiary,
+		Asset:       param.Asset,
+		Amount:      param.Amount,
+		ByHeight:    param.ByHeight,
+		Start:       param.Start,
+		Cliff:       param.Cliff,
+		Duration:    param.Duration,
+	}
+	if err := putVesting(native, vesting); err != nil {
+		return 0, err
+	}
+	utils.PutBytes(native, genVestingIndexKey(vesting.Beneficiary, id), idBytes(id))
+	err = contract.Notify(native, CREATE_VESTING_NAME, &CreateVestingEvent{
+		Id:          id,
+		Creator:     vesting.Creator,
+		Beneficiary: vesting.Beneficiary,
+		Asset:       vesting.Asset,
+		Amount:      vesting.Amount,
+	})
+	return id, err
+}
+
+//Claim transfers vested amount not claimed to beneficiary, returns amount claimed
+func Claim(native *native.NativeService, param *ClaimParam) (uint64, error) {
+	vesting, err := getVesting(native, param.Id)
+	if err != nil {
+		return 0, err
+	}
+	if vesting.Beneficiary != param.Beneficiary {
+		return 0, fmt.Errorf("%s is not beneficiary of vesting %d", param.Beneficiary.ToBase58(), param.Id)
+	}
+	amount := vesting.Claimable(now(native, vesting.ByHeight))
+	if amount == 0 {
+		return 0, nil
+	}
+	vesting.Claimed += amount
+	if err := putVesting(native, vesting); err != nil {
+		return 0, err
+	}
+	if vesting.Claimed == vesting.Amount {
+		native.CacheDB.Delete(genVestingIndexKey(vesting.Beneficiary, vesting.Id))
+	}
+	if err := appCallTransfer(native, vesting.Asset, utils.EscrowContractAddress, vesting.Beneficiary, amount); err != nil {
+		return 0, fmt.Errorf("pay vesting error:%s", err)
+	}
+	err = contract.Notify(native, CLAIM_NAME, &PayoutEvent{
+		Id:     vesting.Id,
+		To:     vesting.Beneficiary,
+		Asset:  vesting.Asset,
+		Amount: amount,
+	})
+	return amount, err
+}
+
+func CreateEscrow(native *native.NativeService, param *CreateEscrowParam) (uint64, error) {
+	if err := checkAsset(param.Asset); err != nil {
+		return 0, err
+	}
+	if param.Amount == 0 {
+		return 0, fmt.Errorf("amount of escrow should be positive")
+	}
+	if !account.VerifyID(string(param.Arbiter)) {
+		return 0, fmt.Errorf("invalid arbiter ONX ID %s", param.Arbiter)
+	}
+	if param.Timeout <= now(native, param.ByHeight) {
+		return 0, fmt.Errorf("timeout %d is passed", param.Timeout)
+	}
+	id, err := nextId(native)
+	if err != nil {
+		return 0, err
+	}
+	if err := appCallTransfer(native, param.Asset, param.Depositor, utils.EscrowContractAddress, param.Amount); err != nil {
+		return 0, fmt.Errorf("lock escrow amount error:%s", err)
+	}
+	escrow := &Escrow{
+		Id:          id,
+		Depositor:   param.Depositor,
+		Beneficiary: param.Beneficiary,
+		Asset:       param.Asset,
+		Amount:      param.Amount,
+		Arbiter:     param.Arbiter,
+		ByHeight:    param.ByHeight,
+		Timeout:     param.Timeout,
+		Status:      ESCROW_OPEN,
+	}
+	if err := putEscrow(native, escrow); err != nil {
+		return 0, err
+	}
+	utils.PutBytes(native, genEscrowIndexKey(escrow.Depositor, id), idBytes(id))
+	err = contract.Notify(native, CREATE_ESCROW_NAME, &CreateEscrowEvent{
+		Id:          id,
+		Depositor:   escrow.Depositor,
+		Beneficiary: escrow.Beneficiary,
+		Asset:       escrow.Asset,
+		Amount:      escrow.Amount,
+		Arbiter:     string(escrow.Arbiter),
+	})
+	return id, err
+}
+
+//Release pays escrow to beneficiary, signed by arbiter before timeout
+func Release(native *native.NativeService, param *SettleParam) (bool, error) {
+	escrow, err := getOpenEscrow(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if now(native, escrow.ByHeight) >= escrow.Timeout {
+		return false, fmt.Errorf("escrow %d is timeout", escrow.Id)
+	}
+	if err := checkArbiter(native, escrow, param.KeyNo); err != nil {
+		return false, err
+	}
+	return true, settle(native, escrow, ESCROW_RELEASED, escrow.Beneficiary, RELEASE_NAME)
+}
+
+//Refund pays escrow back to depositor, signed by arbiter before timeout, or by depositor after timeout
+func Refund(native *native.NativeService, param *SettleParam) (bool, error) {
+	escrow, err := getOpenEscrow(native, param.Id)
+	if err != nil {
+		return false, err
+	}
+	if now(native, escrow.ByHeight) < escrow.Timeout {
+		if err := checkArbiter(native, escrow, param.KeyNo); err != nil {
+			return false, err
+		}
+	} else if err := utils.ValidateOwner(native, escrow.Depositor); err != nil {
+		return false, fmt.Errorf("refund escrow %d, %s", escrow.Id, err)
+	}
+	return true, settle(native, escrow, ESCROW_REFUNDED, escrow.Depositor, REFUND_NAME)
+}
+
+func GetVesting(native *native.NativeService, param *IdParam) (*VestingInfo, error) {
+	vesting, err := getVesting(native, param.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &VestingInfo{Vesting: vesting, Claimable: vesting.Claimable(now(native, vesting.ByHeight))}, nil
+}
+
+func GetEscrow(native *native.NativeService, param *IdParam) (*Escrow, error) {
+	return getEscrow(native, param.Id)
+}
+
+func GetLockedBalance(native *native.NativeService, param *LockedBalanceParam) (*LockedBalance, error) {
+	vestings, err := vestingsOf(native, param.Account)
+	if err != nil {
+		return nil, err
+	}
+	balance := new(LockedBalance)
+	for _, vesting := range vestings {
+		if vesting.Asset != param.Asset {
+			continue
+		}
+		balance.Locked += vesting.Amount - vesting.Claimed
+		balance.Claimable += vesting.Claimable(now(native, vesting.ByHeight))
+	}
+	escrows, err := escrowsOf(native, param.Account)
+	if err != nil {
+		return nil, err
+	}
+	for _, escrow := range escrows {
+		if escrow.Asset != param.Asset {
+			continue
+		}
+		balance.Locked += escrow.Amount
+		if now(native, escrow.ByHeight) >= escrow.Timeout {
+			balance.Claimable += escrow.Amount
+		}
+	}
+	return balance, nil
+}
+
+func getOpenEscrow(native *native.NativeService, id uint64) (*Escrow, error) {
+	escrow, err := getEscrow(native, id)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.Status != ESCROW_OPEN {
+		return nil, fmt.Errorf("escrow %d is settled", id)
+	}
+	return escrow, nil
+}
+
+func checkArbiter(native *native.NativeService, escrow *Escrow, keyNo uint64) error {
+	ok, err := auth.VerifyOnxIDSig(native, escrow.Arbiter, keyNo)
+	if err != nil {
+		return fmt.Errorf("verify signature of arbiter error:%s", err)
+	}
+	if !ok {
+		return fmt.Errorf("verify signature of arbiter of escrow %d failed", escrow.Id)
+	}
+	return nil
+}
+
+func settle(native *native.NativeService, escrow *Escrow, status uint8, to common.Address, evt string) error {
+	escrow.Status = status
+	if err := putEscrow(native, escrow); err != nil {
+		return err
+	}
+	native.CacheDB.Delete(genEscrowIndexKey(escrow.Depositor, escrow.Id))
+	if err := appCallTransfer(native, escrow.Asset, utils.EscrowContractAddress, to, escrow.Amount); err != nil {
+		return fmt.Errorf("pay escrow error:%s", err)
+	}
+	return contract.Notify(native, evt, &PayoutEvent{
+		Id:     escrow.Id,
+		To:     to,
+		Asset:  escrow.Asset,
+		Amount: escrow.Amount,
+	})
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package escrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVesting_Vested(t *testing.T) {
+	vesting := &Vesting{Amount: 1000, Start: 100, Cliff: 50, Duration: 200}
+	assert.Equal(t, uint64(0), vesting.Vested(0))
+	assert.Equal(t, uint64(0), vesting.Vested(149))
+	assert.Equal(t, uint64(250), vesting.Vested(150))
+	assert.Equal(t, uint64(500), vesting.Vested(200))
+	assert.Equal(t, uint64(1000), vesting.Vested(300))
+	assert.Equal(t, uint64(1000), vesting.Vested(1<<32-1))
+
+	vesting.Claimed = 250
+	assert.Equal(t, uint64(250), vesting.Claimable(200))
+
+	lump := &Vesting{Amount: 1000, Start: 100}
+	assert.Equal(t, uint64(0), lump.Vested(99))
+	assert.Equal(t, uint64(1000), lump.Vested(100))
+
+	large := &Vesting{Amount: 1 << 63, Duration: 4}
+	assert.Equal(t, uint64(1<<62), large.Vested(2))
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package escrow locks ONX and OXG in vesting schedules and escrows. Vesting of beneficiary has cliff and linear
+//schedule, escrow is released by arbiter ONX ID or refunded after timeout. Assets are held by the contract
+//account in ONX and OXG contracts, and moved by their transfer.
+package escrow
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	CREATE_VESTING_NAME = "createVesting"
+	CLAIM_NAME          = "claim"
+	CREATE_ESCROW_NAME  = "createEscrow"
+	RELEASE_NAME        = "release"
+	REFUND_NAME         = "refund"
+	GET_VESTING_NAME    = "getVesting"
+	GET_ESCROW_NAME     = "getEscrow"
+	LOCKED_BALANCE_NAME = "lockedBalance"
+)
+
+var contract *framework.Contract
+
+func init() {
+	contract = framework.NewContract("escrow", utils.EscrowContractAddress).
+		Method(CREATE_VESTING_NAME, CreateVesting).
+		Method(CLAIM_NAME, Claim).
+		Method(CREATE_ESCROW_NAME, CreateEscrow).
+		Method(RELEASE_NAME, Release).
+		Method(REFUND_NAME, Refund).
+		Method(GET_VESTING_NAME, GetVesting).
+		Method(GET_ESCROW_NAME, GetEscrow).
+		Method(LOCKED_BALANCE_NAME, GetLockedBalance).
+		Event(CREATE_VESTING_NAME, CreateVestingEvent{}).
+		Event(CLAIM_NAME, PayoutEvent{}).
+		Event(CREATE_ESCROW_NAME, CreateEscrowEvent{}).
+		Event(RELEASE_NAME, PayoutEvent{}).
+		Event(REFUND_NAME, PayoutEvent{})
+}
+
+//Init installs escrow as native contract
+func Init() {
+	contract.Install()
+}
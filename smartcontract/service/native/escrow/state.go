@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package escrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//status of escrow
+const (
+	ESCROW_OPEN     uint8 = 0
+	ESCROW_RELEASED uint8 = 1
+	ESCROW_REFUNDED uint8 = 2
+)
+
+var (
+	PreNextId       = []byte{0x01}
+	PreVesting      = []byte{0x02}
+	PreVestingIndex = []byte{0x03}
+	PreEscrow       = []byte{0x04}
+	PreEscrowIndex  = []byte{0x05}
+)
+
+//Vesting is cliff and linear vesting schedule. Amount vests linearly from start to start+duration, and nothing
+//vests before start+cliff. Times are block heights if ByHeight, otherwise block timestamps in seconds.
+type Vesting struct {
+	Id          uint64
+	Creator     common.Address
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+	Claimed     uint64
+	ByHeight    bool
+	Start       uint32
+	Cliff       uint32
+	Duration    uint32
+}
+
+//Vested returns amount vested at time now
+func (this *Vesting) Vested(now uint32) uint64 {
+	if now < this.Start || now-this.Start < this.Cliff {
+		return 0
+	}
+	elapsed := now - this.Start
+	if elapsed >= this.Duration {
+		return this.Amount
+	}
+	vested := new(big.Int).Mul(new(big.Int).SetUint64(this.Amount), big.NewInt(int64(elapsed)))
+	return vested.Div(vested, big.NewInt(int64(this.Duration))).Uint64()
+}
+
+//Claimable returns amount vested but not claimed at time now
+func (this *Vesting) Claimable(now uint32) uint64 {
+	return this.Vested(now) - this.Claimed
+}
+
+//Escrow is released to beneficiary by arbiter ONX ID before timeout, or refunded to depositor.
+//Timeout is block height if ByHeight, otherwise block timestamp in seconds.
+type Escrow struct {
+	Id          uint64
+	Depositor   common.Address
+	Beneficiary common.Address
+	Asset       common.Address
+	Amount      uint64
+	Arbiter     []byte
+	ByHeight    bool
+	Timeout     uint32
+	Status      uint8
+}
+
+func checkAsset(asset common.Address) error {
+	if asset != utils.OnxContractAddress && asset != utils.OxgContractAddress {
+		return fmt.Errorf("unsupported asset %s, should be onx or oxg", asset.ToHexString())
+	}
+	return nil
+}
+
+//now returns current block height or timestamp
+func now(native *native.NativeService, byHeight bool) uint32 {
+	if byHeight {
+		return native.Height
+	}
+	return native.Time
+}
+
+func idBytes(id uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id)
+	return buf[:]
+}
+
+func genVestingKey(id uint64) []byte {
+	return utils.ConcatKey(utils.EscrowContractAddress, PreVesting, idBytes(id))
+}
+
+func genVestingIndexKey(beneficiary common.Address, id uint64) []byte {
+	return utils.ConcatKey(utils.EscrowContractAddress, PreVestingIndex, beneficiary[:], idBytes(id))
+}
+
+func genEscrowKey(id uint64) []byte {
+	return utils.ConcatKey(utils.EscrowContractAddress, PreEscrow, idBytes(id))
+}
+
+func genEscrowIndexKey(depositor common.Address, id uint64) []byte {
+	return utils.ConcatKey(utils.EscrowContractAddress, PreEscrowIndex, depositor[:], idBytes(id))
+}
+
+//nextId returns id of new vesting or escrow, ids of them are in the same sequence
+func nextId(native *native.NativeService) (uint64, error) {
+	key := utils.ConcatKey(utils.EscrowContractAddress, PreNextId)
+	id, err := utils.GetStorageUInt64(native, key)
+	if err != nil {
+		return 0, err
+	}
+	native.CacheDB.Put(key, utils.GenUInt64StorageItem(id+1).ToArray())
+	return id, nil
+}
+
+func getVesting(native *native.NativeService, id uint64) (*Vesting, error) {
+	item, err := utils.GetStorageItem(native, genVestingKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("vesting %d not found", id)
+	}
+	vesting := new(Vesting)
+	if err := framework.Decode(item.Value, vesting); err != nil {
+		return nil, fmt.Errorf("decode vesting %d error:%s", id, err)
+	}
+	return vesting, nil
+}
+
+func putVesting(native *native.NativeService, vesting *Vesting) error {
+	data, err := framework.Encode(vesting)
+	if err != nil {
+		return err
+	}
+	utils.PutBytes(native, genVestingKey(vesting.Id), data)
+	return nil
+}
+
+//vestingsOf returns vestings of beneficiary which are not fully claimed
+func vestingsOf(native *native.NativeService, beneficiary common.Address) ([]*Vesting, error) {
+	ids, err := indexedIds(native, utils.ConcatKey(utils.EscrowContractAddress, PreVestingIndex, beneficiary[:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid vesting index:%s", err)
+	}
+	vestings := make([]*Vesting, 0, len(ids))
+	for _, id := range ids {
+		vesting, err := getVesting(native, id)
+		if err != nil {
+			return nil, err
+		}
+		vestings = append(vestings, vesting)
+	}
+	return vestings, nil
+}
+
+//escrowsOf returns open escrows of depositor
+func escrowsOf(native *native.NativeService, depositor common.Address) ([]*Escrow, error) {
+	ids, err := indexedIds(native, utils.ConcatKey(utils.EscrowContractAddress, PreEscrowIndex, depositor[:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow index:%s", err)
+	}
+	escrows := make([]*Escrow, 0, len(ids))
+	for _, id := range ids {
+		escrow, err := getEscrow(native, id)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, escrow)
+	}
+	return escrows, nil
+}
+
+//indexedIds returns ids in index of prefix
+func indexedIds(native *native.NativeService, prefix []byte) ([]uint64, error) {
+	iter := native.CacheDB.NewIterator(prefix)
+	defer iter.Release()
+	var ids []uint64
+	for has := iter.First(); has; has = iter.Next() {
+		value, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if len(value) != 8 {
+			return nil, fmt.Errorf("length of id %d", len(value))
+		}
+		ids = append(ids, binary.BigEndian.Uint64(value))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func getEscrow(native *native.NativeService, id uint64) (*Escrow, error) {
+	item, err := utils.GetStorageItem(native, genEscrowKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("escrow %d not found", id)
+	}
+	escrow := new(Escrow)
+	if err := framework.Decode(item.Value, escrow); err != nil {
+		return nil, fmt.Errorf("decode escrow %d error:%s", id, err)
+	}
+	return escrow, nil
+}
+
+func putEscrow(native *native.NativeService, escrow *Escrow) error {
+	data, err := framework.Encode(escrow)
+	if err != nil {
+		return err
+	}
+	utils.PutBytes(native, genEscrowKey(escrow.Id), data)
+	return nil
+}
+
+//appCallTransfer transfers onx or oxg by transfer of the asset contract, the same as governance
+func appCallTransfer(native *native.NativeService, asset, from, to common.Address, amount uint64) error {
+	transfers := onx.Transfers{States: []onx.State{{From: from, To: to, Value: amount}}}
+	sink := common.NewZeroCopySink(nil)
+	transfers.Serialization(sink)
+	if _, err := native.NativeCall(asset, onx.TRANSFER_NAME, sink.Bytes()); err != nil {
+		return fmt.Errorf("appCallTransfer, appCall error: %v", err)
+	}
+	return nil
+}
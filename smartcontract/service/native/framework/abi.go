@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//Param types of native contract abi
+const (
+	ABI_TYPE_BOOL      = "Bool"
+	ABI_TYPE_BYTE      = "Byte"
+	ABI_TYPE_INTEGER   = "Int"
+	ABI_TYPE_STRING    = "String"
+	ABI_TYPE_BYTEARRAY = "ByteArray"
+	ABI_TYPE_ARRAY     = "Array"
+	ABI_TYPE_ADDRESS   = "Address"
+	ABI_TYPE_UINT256   = "Uint256"
+	ABI_TYPE_STRUCT    = "Struct"
+)
+
+//ContractAbi is abi of native contract, in the format of native_abi_script
+type ContractAbi struct {
+	Address   string         `json:"hash"`
+	Functions []*FunctionAbi `json:"functions"`
+	Events    []*EventAbi    `json:"events"`
+}
+
+type FunctionAbi struct {
+	Name       string      `json:"name"`
+	Parameters []*ParamAbi `json:"parameters"`
+	ReturnType string      `json:"returntype"`
+}
+
+type ParamAbi struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	SubType []*ParamAbi `json:"subType,omitempty"`
+}
+
+type EventAbi struct {
+	Name       string      `json:"name"`
+	Parameters []*ParamAbi `json:"parameters"`
+}
+
+//Abi generates abi of contract from declared methods and events
+func (this *Contract) Abi() *ContractAbi {
+	abi := &ContractAbi{
+		Address:   this.Address.ToHexString(),
+		Functions: make([]*FunctionAbi, 0, len(this.methods)),
+		Events:    make([]*EventAbi, 0, len(this.events)),
+	}
+	for _, m := range this.methods {
+		abi.Functions = append(abi.Functions, m.abi)
+	}
+	for _, e := range this.events {
+		abi.Events = append(abi.Events, &EventAbi{Name: e.name, Parameters: e.params})
+	}
+	return abi
+}
+
+//AbiJson returns abi of contract as json file content of native_abi_script
+func (this *Contract) AbiJson() ([]byte, error) {
+	return json.MarshalIndent(this.Abi(), "", "  ")
+}
+
+//structParams returns abi of exported fields of struct type t
+func structParams(t reflect.Type) ([]*ParamAbi, error) {
+	params := make([]*ParamAbi, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isExported(field) {
+			continue
+		}
+		param, err := typeAbi(paramName(field), field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", field.Name, err)
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+//typeAbi returns abi of go type, error if the type is not supported by codec
+func typeAbi(name string, t reflect.Type) (*ParamAbi, error) {
+	param := &ParamAbi{Name: name}
+	switch t {
+	case addressType:
+		param.Type = ABI_TYPE_ADDRESS
+		return param, nil
+	case uint256Type:
+		param.Type = ABI_TYPE_UINT256
+		return param, nil
+	case bytesType:
+		param.Type = ABI_TYPE_BYTEARRAY
+		return param, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		param.Type = ABI_TYPE_BOOL
+	case reflect.Uint8:
+		param.Type = ABI_TYPE_BYTE
+	case reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		param.Type = ABI_TYPE_INTEGER
+	case reflect.String:
+		param.Type = ABI_TYPE_STRING
+	case reflect.Slice:
+		item, err := typeAbi(strings.TrimSuffix(name, "s"), t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		param.Type, param.SubType = ABI_TYPE_ARRAY, []*ParamAbi{item}
+	case reflect.Struct:
+		fields, err := structParams(t)
+		if err != nil {
+			return nil, err
+		}
+		param.Type, param.SubType = ABI_TYPE_STRUCT, fields
+	case reflect.Ptr:
+		if t.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unsupported type %s", t)
+		}
+		return typeAbi(name, t.Elem())
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+	return param, nil
+}
+
+//parseTag parses native tag of struct field, `native:"name,witness"`
+func parseTag(field reflect.StructField) (name string, witness bool) {
+	parts := strings.Split(field.Tag.Get("native"), ",")
+	for _, opt := range parts[1:] {
+		if opt == "witness" {
+			witness = true
+		}
+	}
+	return parts[0], witness
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+)
+
+//Values of native contract params are encoded the same as NeoVM stack items converted by native invoke:
+//  Address, Uint256, ByteArray, String - var bytes
+//  Int, Byte                           - var bytes of big int, see utils.EncodeVarUint
+//  Bool                                - one byte
+//  Array                               - count as Int, followed by items
+//  Struct                              - fields in order
+//Go types are mapped to them as common.Address, common.Uint256, []byte, string, uint*, bool, slice and struct.
+
+var (
+	addressType = reflect.TypeOf(common.Address{})
+	uint256Type = reflect.TypeOf(common.Uint256{})
+	bytesType   = reflect.TypeOf([]byte{})
+)
+
+//Encode serializes value of supported type as native contract param
+func Encode(value interface{}) ([]byte, error) {
+	sink := common.NewZeroCopySink(nil)
+	if err := encodeValue(sink, reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+	return sink.Bytes(), nil
+}
+
+//Decode deserializes native contract param into value, which should be a pointer
+func Decode(data []byte, value interface{}) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("decode into non-pointer %s", v.Type())
+	}
+	return decodeValue(common.NewZeroCopySource(data), v.Elem())
+}
+
+//encodeResult encodes result of method as hand written native contracts do, scalar is raw value without
+//length prefix: bool is one byte, int is bytes of big int, others are bytes. Array and struct are encoded as param.
+func encodeResult(v reflect.Value) ([]byte, error) {
+	switch v.Type() {
+	case addressType:
+		addr := v.Interface().(common.Address)
+		return addr[:], nil
+	case uint256Type:
+		hash := v.Interface().(common.Uint256)
+		return hash[:], nil
+	case bytesType:
+		return v.Bytes(), nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return utils.BYTE_TRUE, nil
+		}
+		return utils.BYTE_FALSE, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return types.BigIntToBytes(new(big.Int).SetUint64(v.Uint())), nil
+	case reflect.String:
+		return []byte(v.String()), nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := encodeValue(sink, v); err != nil {
+		return nil, err
+	}
+	return sink.Bytes(), nil
+}
+
+func encodeValue(sink *common.ZeroCopySink, v reflect.Value) error {
+	switch v.Type() {
+	case addressType:
+		addr := v.Interface().(common.Address)
+		utils.EncodeAddress(sink, addr)
+		return nil
+	case uint256Type:
+		hash := v.Interface().(common.Uint256)
+		sink.WriteVarBytes(hash[:])
+		return nil
+	case bytesType:
+		sink.WriteVarBytes(v.Bytes())
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		sink.WriteBool(v.Bool())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		utils.EncodeVarUint(sink, v.Uint())
+	case reflect.String:
+		sink.WriteString(v.String())
+	case reflect.Slice:
+		utils.EncodeVarUint(sink, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(sink, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isExported(v.Type().Field(i)) {
+				continue
+			}
+			if err := encodeValue(sink, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return fmt.Errorf("encode nil %s", v.Type())
+		}
+		return encodeValue(sink, v.Elem())
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func decodeValue(source *common.ZeroCopySource, v reflect.Value) error {
+	//var bytes read at end of source is empty instead of eof, and values except struct take one byte at least
+	if source.Len() == 0 && v.Kind() != reflect.Struct && v.Kind() != reflect.Ptr {
+		return io.ErrUnexpectedEOF
+	}
+	switch v.Type() {
+	case addressType:
+		addr, err := utils.DecodeAddress(source)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(addr))
+		return nil
+	case uint256Type:
+		data, err := decodeVarBytes(source)
+		if err != nil {
+			return err
+		}
+		hash, err := common.Uint256ParseFromBytes(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(hash))
+		return nil
+	case bytesType:
+		data, err := decodeVarBytes(source)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(append([]byte{}, data...))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b, irregular, eof := source.NextBool()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		if irregular {
+			return common.ErrIrregularData
+		}
+		v.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		n, err := utils.DecodeVarUint(source)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("value %d overflows %s", n, v.Type())
+		}
+		v.SetUint(n)
+	case reflect.String:
+		data, err := decodeVarBytes(source)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(data))
+	case reflect.Slice:
+		n, err := utils.DecodeVarUint(source)
+		if err != nil {
+			return err
+		}
+		//every item takes one byte at least
+		if n > source.Len() {
+			return fmt.Errorf("array count %d exceeds data size", n)
+		}
+		slice := reflect.MakeSlice(v.Type(), int(n), int(n))
+		for i := 0; i < int(n); i++ {
+			if err := decodeValue(source, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isExported(v.Type().Field(i)) {
+				continue
+			}
+			if err := decodeValue(source, v.Field(i)); err != nil {
+				return fmt.Errorf("%s: %s", v.Type().Field(i).Name, err)
+			}
+		}
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(source, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func decodeVarBytes(source *common.ZeroCopySource) ([]byte, error) {
+	data, _, irregular, eof := source.NextVarBytes()
+	if eof {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if irregular {
+		return nil, common.ErrIrregularData
+	}
+	return data, nil
+}
+
+func isExported(field reflect.StructField) bool {
+	return field.PkgPath == ""
+}
+
+//paramName returns abi name of struct field, from native tag or field name with lower first letter
+func paramName(field reflect.StructField) string {
+	if name, _ := parseTag(field); name != "" {
+		return name
+	}
+	r, size := utf8.DecodeRuneInString(field.Name)
+	return string(unicode.ToLower(r)) + field.Name[size:]
+}
@@ -0,0 +1,364 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package framework declares native contracts by typed methods. Params of a method are decoded from
+//invoke args, witness of tagged params is checked, and result is encoded as return value, so that
+//contract code only handles business logic. Abi of contract is generated from the declaration.
+//
+//Contract package declares its contract in a package variable set in init, since handlers refer to it
+//to notify events.
+package framework
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var (
+	serviceType = reflect.TypeOf((*native.NativeService)(nil))
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+
+	installed = make(map[string]*Contract)
+)
+
+//Contract is a native contract declared by methods and events, e.g.
+//
+//  type TransferParam struct {
+//      From  common.Address `native:"from,witness"`
+//      To    common.Address
+//      Value uint64
+//  }
+//
+//  func Transfer(native *native.NativeService, param *TransferParam) (bool, error)
+//
+//  contract := framework.NewContract("token", address).Method("transfer", Transfer)
+//
+//Handler of method is func(*native.NativeService) (R, error) or func(*native.NativeService, *P) (R, error).
+//Exported fields of struct P are params of method in order, and fields tagged witness, which should be
+//common.Address or []common.Address, must be signed by invoker, including those in nested struct and array.
+//R can be any type supported by codec, scalar result is returned as raw value like hand written contracts.
+type Contract struct {
+	Name    string //name of abi file in native_abi_script
+	Address common.Address
+	methods []*method
+	events  []*eventDecl
+}
+
+type method struct {
+	name    string
+	handler reflect.Value
+	param   reflect.Type //struct type of param, nil if method has no param
+	witness bool         //whether param has fields to check witness
+	abi     *FunctionAbi
+}
+
+type eventDecl struct {
+	name   string
+	typ    reflect.Type
+	params []*ParamAbi
+}
+
+//NewContract returns contract declaration without methods
+func NewContract(name string, address common.Address) *Contract {
+	return &Contract{Name: name, Address: address}
+}
+
+//Method declares method of contract, it panics if handler is invalid, which is a programming error
+func (this *Contract) Method(name string, handler interface{}) *Contract {
+	m, err := newMethod(name, handler)
+	if err != nil {
+		panic(fmt.Sprintf("native contract %s method %s: %s", this.Name, name, err))
+	}
+	if this.getMethod(name) != nil {
+		panic(fmt.Sprintf("native contract %s method %s: duplicated", this.Name, name))
+	}
+	this.methods = append(this.methods, m)
+	return this
+}
+
+//Event declares event of contract, params is struct whose exported fields are params of event.
+//It panics if params is invalid, which is a programming error.
+func (this *Contract) Event(name string, params interface{}) *Contract {
+	t := reflect.TypeOf(params)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("native contract %s event %s: params should be struct", this.Name, name))
+	}
+	fields, err := structParams(t)
+	if err != nil {
+		panic(fmt.Sprintf("native contract %s event %s: %s", this.Name, name, err))
+	}
+	if this.getEvent(name) != nil {
+		panic(fmt.Sprintf("native contract %s event %s: duplicated", this.Name, name))
+	}
+	this.events = append(this.events, &eventDecl{name: name, typ: t, params: fields})
+	return this
+}
+
+//Install registers contract to native service, it should be called in Init of contract package
+func (this *Contract) Install() {
+	native.Contracts[this.Address] = this.Register
+	installed[this.Name] = this
+}
+
+//Register registers handlers of declared methods to native service
+func (this *Contract) Register(native *native.NativeService) {
+	for _, m := range this.methods {
+		native.Register(m.name, m.invoke)
+	}
+}
+
+//Notify emits declared event, states of notify are event name and params converted to readable values
+func (this *Contract) Notify(native *native.NativeService, name string, params interface{}) error {
+	evt := this.getEvent(name)
+	if evt == nil {
+		return fmt.Errorf("event %s not declared", name)
+	}
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Type() != evt.typ {
+		return fmt.Errorf("event %s params should be %s", name, evt.typ)
+	}
+	states := []interface{}{name}
+	for i := 0; i < v.NumField(); i++ {
+		if isExported(v.Type().Field(i)) {
+			states = append(states, readableValue(v.Field(i)))
+		}
+	}
+	native.Notifications = append(native.Notifications, &event.NotifyEventInfo{
+		ContractAddress: this.Address,
+		States:          states,
+	})
+	return nil
+}
+
+//Contracts returns installed contracts, sorted by name
+func Contracts() []*Contract {
+	contracts := make([]*Contract, 0, len(installed))
+	for _, c := range installed {
+		contracts = append(contracts, c)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Name < contracts[j].Name
+	})
+	return contracts
+}
+
+func (this *Contract) getMethod(name string) *method {
+	for _, m := range this.methods {
+		if m.name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (this *Contract) getEvent(name string) *eventDecl {
+	for _, e := range this.events {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func newMethod(name string, handler interface{}) (*method, error) {
+	h := reflect.ValueOf(handler)
+	t := h.Type()
+	if t.Kind() != reflect.Func || t.NumIn() < 1 || t.NumIn() > 2 || t.In(0) != serviceType {
+		return nil, fmt.Errorf("handler should be func(*native.NativeService[, *Param]) (Result, error)")
+	}
+	if t.NumOut() != 2 || t.Out(1) != errorType {
+		return nil, fmt.Errorf("handler should return (Result, error)")
+	}
+	result, err := typeAbi("", t.Out(0))
+	if err != nil {
+		return nil, fmt.Errorf("result: %s", err)
+	}
+	m := &method{
+		name:    name,
+		handler: h,
+		abi:     &FunctionAbi{Name: name, Parameters: []*ParamAbi{}, ReturnType: result.Type},
+	}
+	if t.NumIn() == 1 {
+		return m, nil
+	}
+	if t.In(1).Kind() != reflect.Ptr || t.In(1).Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("param should be pointer to struct")
+	}
+	m.param = t.In(1).Elem()
+	if m.abi.Parameters, err = structParams(m.param); err != nil {
+		return nil, fmt.Errorf("param: %s", err)
+	}
+	if m.witness, err = checkWitnessTags(m.param, make(map[reflect.Type]bool)); err != nil {
+		return nil, fmt.Errorf("param: %s", err)
+	}
+	return m, nil
+}
+
+//checkWitnessTags returns whether type t has witness fields, error if witness field is not address
+func checkWitnessTags(t reflect.Type, visited map[reflect.Type]bool) (bool, error) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return checkWitnessTags(t.Elem(), visited)
+	case reflect.Struct:
+		if visited[t] {
+			return false, nil
+		}
+		visited[t] = true
+		has := false
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !isExported(field) {
+				continue
+			}
+			if _, witness := parseTag(field); witness {
+				if field.Type != addressType && field.Type != reflect.SliceOf(addressType) {
+					return false, fmt.Errorf("witness field %s should be address or address array", field.Name)
+				}
+				has = true
+				continue
+			}
+			nested, err := checkWitnessTags(field.Type, visited)
+			if err != nil {
+				return false, err
+			}
+			has = has || nested
+		}
+		return has, nil
+	}
+	return false, nil
+}
+
+//witnesses appends addresses of witness fields in v to addrs
+func witnesses(v reflect.Value, addrs []common.Address) []common.Address {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !isExported(field) {
+				continue
+			}
+			if _, witness := parseTag(field); !witness {
+				addrs = witnesses(v.Field(i), addrs)
+				continue
+			}
+			switch addr := v.Field(i).Interface().(type) {
+			case common.Address:
+				addrs = append(addrs, addr)
+			case []common.Address:
+				addrs = append(addrs, addr...)
+			}
+		}
+	case reflect.Slice:
+		if v.Type() != bytesType {
+			for i := 0; i < v.Len(); i++ {
+				addrs = witnesses(v.Index(i), addrs)
+			}
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			addrs = witnesses(v.Elem(), addrs)
+		}
+	}
+	return addrs
+}
+
+func (this *method) invoke(native *native.NativeService) ([]byte, error) {
+	args := []reflect.Value{reflect.ValueOf(native)}
+	if this.param != nil {
+		param := reflect.New(this.param)
+		if err := decodeValue(common.NewZeroCopySource(native.Input), param.Elem()); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("%s, decode param error: %s", this.name, err)
+		}
+		if err := this.checkWitness(native, param.Elem()); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("%s, %s", this.name, err)
+		}
+		args = append(args, param)
+	}
+	out := this.handler.Call(args)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	result, err := encodeResult(out[0])
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("%s, encode result error: %s", this.name, err)
+	}
+	return result, nil
+}
+
+func (this *method) checkWitness(native *native.NativeService, param reflect.Value) error {
+	if !this.witness {
+		return nil
+	}
+	for _, addr := range witnesses(param, nil) {
+		if err := utils.ValidateOwner(native, addr); err != nil {
+			return fmt.Errorf("check witness of %s error: %s", addr.ToBase58(), err)
+		}
+	}
+	return nil
+}
+
+//readableValue converts value to notify state, address is base58, hash and byte array are hex
+func readableValue(v reflect.Value) interface{} {
+	switch v.Type() {
+	case addressType:
+		addr := v.Interface().(common.Address)
+		return addr.ToBase58()
+	case uint256Type:
+		hash := v.Interface().(common.Uint256)
+		return hash.ToHexString()
+	case bytesType:
+		return hex.EncodeToString(v.Bytes())
+	}
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return v.Uint()
+	case reflect.Slice:
+		values := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, readableValue(v.Index(i)))
+		}
+		return values
+	case reflect.Struct:
+		values := make([]interface{}, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if isExported(v.Type().Field(i)) {
+				values = append(values, readableValue(v.Field(i)))
+			}
+		}
+		return values
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return readableValue(v.Elem())
+	}
+	return v.Interface()
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/context"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type testState struct {
+	From  common.Address `native:"from,witness"`
+	To    common.Address
+	Value uint64
+}
+
+type testParam struct {
+	States  []testState `native:"states"`
+	Memo    string
+	Data    []byte
+	Flag    bool
+	Kind    uint8
+	TxHash  common.Uint256
+	Signers []common.Address `native:"signers,witness"`
+	hidden  uint64
+}
+
+type testEvent struct {
+	From  common.Address
+	Value uint64
+	Data  []byte
+}
+
+type witnessRef struct {
+	context.ContextRef
+	signers map[common.Address]bool
+}
+
+func (this *witnessRef) CheckWitness(address common.Address) bool {
+	return this.signers[address]
+}
+
+func TestCodec(t *testing.T) {
+	param := &testParam{
+		States:  []testState{{From: common.Address{1}, To: common.Address{2}, Value: 100}},
+		Memo:    "memo",
+		Data:    []byte{1, 2, 3},
+		Flag:    true,
+		Kind:    3,
+		TxHash:  common.Uint256{4},
+		Signers: []common.Address{{5}, {6}},
+		hidden:  7,
+	}
+	data, err := Encode(param)
+	assert.Nil(t, err)
+	decoded := new(testParam)
+	assert.Nil(t, Decode(data, decoded))
+	param.hidden = 0
+	assert.Equal(t, param, decoded)
+
+	//same as hand written encoding of native contracts
+	sink := common.NewZeroCopySink(nil)
+	utils.EncodeAddress(sink, common.Address{1})
+	utils.EncodeAddress(sink, common.Address{2})
+	utils.EncodeVarUint(sink, 100)
+	data, err = Encode(param.States[0])
+	assert.Nil(t, err)
+	assert.Equal(t, sink.Bytes(), data)
+
+	assert.NotNil(t, Decode(data[:len(data)-1], new(testState)))
+	assert.NotNil(t, Decode(data, testState{}))
+	data, _ = Encode(uint64(256))
+	assert.NotNil(t, Decode(data, new(uint8)))
+	_, err = Encode(1.5)
+	assert.NotNil(t, err)
+}
+
+func newTestContract() *Contract {
+	return NewContract("test", common.Address{0x10}).
+		Method("transfer", func(native *native.NativeService, param *testParam) (bool, error) {
+			if param.Kind == 0 {
+				return false, fmt.Errorf("transfer, invalid kind")
+			}
+			return true, nil
+		}).
+		Method("name", func(native *native.NativeService) (string, error) {
+			return "test", nil
+		}).
+		Event("transfer", testEvent{})
+}
+
+func TestInvoke(t *testing.T) {
+	contract := newTestContract()
+	from := common.Address{1}
+	param := &testParam{States: []testState{{From: from, To: common.Address{2}, Value: 1}}, Kind: 1}
+	input, _ := Encode(param)
+	ref := &witnessRef{signers: map[common.Address]bool{}}
+	service := &native.NativeService{ServiceMap: make(map[string]native.Handler), ContextRef: ref}
+	contract.Register(service)
+
+	service.Input = input
+	_, err := service.ServiceMap["transfer"](service)
+	assert.NotNil(t, err)
+
+	ref.signers[from] = true
+	res, err := service.ServiceMap["transfer"](service)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, res)
+
+	param.Kind = 0
+	service.Input, _ = Encode(param)
+	res, err = service.ServiceMap["transfer"](service)
+	assert.NotNil(t, err)
+	assert.Equal(t, utils.BYTE_FALSE, res)
+
+	service.Input = input[:len(input)-1]
+	_, err = service.ServiceMap["transfer"](service)
+	assert.NotNil(t, err)
+
+	res, err = service.ServiceMap["name"](service)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("test"), res)
+}
+
+func TestNotify(t *testing.T) {
+	contract := newTestContract()
+	service := &native.NativeService{}
+	evt := &testEvent{From: common.Address{1}, Value: 10, Data: []byte{0xab}}
+	assert.Nil(t, contract.Notify(service, "transfer", evt))
+	assert.Equal(t, 1, len(service.Notifications))
+	assert.Equal(t, contract.Address, service.Notifications[0].ContractAddress)
+	assert.Equal(t, []interface{}{"transfer", evt.From.ToBase58(), uint64(10), "ab"},
+		service.Notifications[0].States)
+
+	assert.NotNil(t, contract.Notify(service, "unknown", evt))
+	assert.NotNil(t, contract.Notify(service, "transfer", &testState{}))
+}
+
+func TestAbi(t *testing.T) {
+	abi := newTestContract().Abi()
+	assert.Equal(t, "0000000000000000000000000000000000000010", abi.Address)
+	assert.Equal(t, 2, len(abi.Functions))
+
+	transfer := abi.Functions[0]
+	assert.Equal(t, "Bool", transfer.ReturnType)
+	names, types := []string{}, []string{}
+	for _, p := range transfer.Parameters {
+		names, types = append(names, p.Name), append(types, p.Type)
+	}
+	assert.Equal(t, []string{"states", "memo", "data", "flag", "kind", "txHash", "signers"}, names)
+	assert.Equal(t, []string{"Array", "String", "ByteArray", "Bool", "Byte", "Uint256", "Array"}, types)
+	state := transfer.Parameters[0].SubType[0]
+	assert.Equal(t, "state", state.Name)
+	assert.Equal(t, "Struct", state.Type)
+	assert.Equal(t, "from", state.SubType[0].Name)
+	assert.Equal(t, "Address", state.SubType[0].Type)
+	assert.Equal(t, "Int", state.SubType[2].Type)
+
+	assert.Equal(t, "String", abi.Functions[1].ReturnType)
+	assert.Equal(t, 0, len(abi.Functions[1].Parameters))
+	assert.Equal(t, "transfer", abi.Events[0].Name)
+	assert.Equal(t, 3, len(abi.Events[0].Parameters))
+
+	data, err := newTestContract().AbiJson()
+	assert.Nil(t, err)
+	decoded := new(ContractAbi)
+	assert.Nil(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, abi, decoded)
+}
+
+func TestInvalidDeclaration(t *testing.T) {
+	contract := NewContract("invalid", common.Address{0x11})
+	assert.Panics(t, func() {
+		contract.Method("noError", func(native *native.NativeService) bool { return true })
+	})
+	assert.Panics(t, func() {
+		contract.Method("valueParam", func(native *native.NativeService, param testParam) (bool, error) { return true, nil })
+	})
+	assert.Panics(t, func() {
+		contract.Method("float", func(native *native.NativeService) (float64, error) { return 0, nil })
+	})
+	assert.Panics(t, func() {
+		contract.Method("witness", func(native *native.NativeService, param *struct {
+			Value uint64 `native:"value,witness"`
+		}) (bool, error) {
+			return true, nil
+		})
+	})
+	assert.Panics(t, func() {
+		contract.Event("event", 1)
+	})
+	contract.Method("name", func(native *native.NativeService) (string, error) { return "", nil })
+	assert.Panics(t, func() {
+		contract.Method("name", func(native *native.NativeService) (string, error) { return "", nil })
+	})
+}
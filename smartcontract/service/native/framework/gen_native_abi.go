@@ -0,0 +1,92 @@
+// +build ignore
+
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Generates abi files of native contracts declared by native framework into cmd/abi/native_abi_script,
+//and compiles all files there into native_abi_data.go. Run by go generate in native framework.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	_ "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/init"
+)
+
+const (
+	ABI_DIR   = "../../../../cmd/abi/native_abi_script"
+	DATA_FILE = "native_abi_data.go"
+)
+
+func main() {
+	if err := generate(); err != nil {
+		fmt.Fprintf(os.Stderr, "generate native abi error:%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate() error {
+	for _, contract := range framework.Contracts() {
+		data, err := contract.AbiJson()
+		if err != nil {
+			return fmt.Errorf("contract %s abi error:%s", contract.Name, err)
+		}
+		file := filepath.Join(ABI_DIR, contract.Name+".json")
+		if err := ioutil.WriteFile(file, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+
+	header, err := ioutil.ReadFile(DATA_FILE)
+	if err != nil {
+		return err
+	}
+	index := bytes.Index(header, []byte("var defaultNativeAbis"))
+	if index < 0 {
+		return fmt.Errorf("defaultNativeAbis not found in %s", DATA_FILE)
+	}
+	files, err := ioutil.ReadDir(ABI_DIR)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+
+	out := bytes.NewBuffer(nil)
+	out.Write(header[:index])
+	out.WriteString("var defaultNativeAbis = map[string]string{\n")
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(ABI_DIR, name))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\t\"%s\": `%s`,\n", name, strings.TrimRight(string(data), " \t\r\n"))
+	}
+	out.WriteString("}\n")
+	return ioutil.WriteFile(DATA_FILE, out.Bytes(), 0644)
+}
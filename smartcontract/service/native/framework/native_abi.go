@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+//go:generate go run gen_native_abi.go
+
+//abi of native contracts by address, parsed from compiled in abi data
+var nativeAbis = parseNativeAbis()
+
+func parseNativeAbis() map[common.Address]*ContractAbi {
+	abis := make(map[common.Address]*ContractAbi, len(defaultNativeAbis))
+	for _, data := range defaultNativeAbis {
+		abi := new(ContractAbi)
+		if err := json.Unmarshal([]byte(data), abi); err != nil {
+			continue
+		}
+		address, err := common.AddressFromHexString(abi.Address)
+		if err != nil {
+			continue
+		}
+		abis[address] = abi
+	}
+	return abis
+}
+
+//NativeAbi returns compiled in abi of native contract, nil if the contract has no abi
+func NativeAbi(address common.Address) *ContractAbi {
+	return nativeAbis[address]
+}
+
+//NativeAbiData returns compiled in abi json of native contracts by file name, for clients to load their own abi
+func NativeAbiData() map[string]string {
+	data := make(map[string]string, len(defaultNativeAbis))
+	for name, abi := range defaultNativeAbis {
+		data[name] = abi
+	}
+	return data
+}
+
+//GetFunc returns abi of method, name is case insensitive
+func (this *ContractAbi) GetFunc(name string) *FunctionAbi {
+	for _, funcAbi := range this.Functions {
+		if strings.EqualFold(funcAbi.Name, name) {
+			return funcAbi
+		}
+	}
+	return nil
+}
+
+//AbiType returns ABI_TYPE_* constant of case insensitive param type, or the type itself if it is unknown
+func AbiType(t string) string {
+	for _, abiType := range []string{ABI_TYPE_BOOL, ABI_TYPE_BYTE, ABI_TYPE_INTEGER, ABI_TYPE_STRING,
+		ABI_TYPE_BYTEARRAY, ABI_TYPE_ARRAY, ABI_TYPE_ADDRESS, ABI_TYPE_UINT256, ABI_TYPE_STRUCT} {
+		if strings.EqualFold(abiType, t) {
+			return abiType
+		}
+	}
+	return t
+}
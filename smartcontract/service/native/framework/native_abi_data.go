@@ -0,0 +1,3052 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+//Abi of native contracts in cmd/abi/native_abi_script, compiled in so that NeoVM and clients marshal and decode native
+//calls the same. Regenerated from native_abi_script by go generate, which is checked by TestNativeAbiData.
+var defaultNativeAbis = map[string]string{
+	"asset.json": `{
+  "hash": "0a00000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "createAsset",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "decimals",
+          "type": "Byte"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "mint",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "caller",
+          "type": "ByteArray"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "burn",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "caller",
+          "type": "ByteArray"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "states",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "state",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "from",
+                  "type": "Address"
+                },
+                {
+                  "name": "to",
+                  "type": "Address"
+                },
+                {
+                  "name": "value",
+                  "type": "Int"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "approve",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transferFrom",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "sender",
+          "type": "Address"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "balanceOf",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "account",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "allowance",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "totalSupply",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "getAsset",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        }
+      ],
+      "returntype": "Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "createAsset",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "String"
+        },
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "decimals",
+          "type": "Byte"
+        },
+        {
+          "name": "issuer",
+          "type": "String"
+        },
+        {
+          "name": "address",
+          "type": "Address"
+        }
+      ]
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        },
+        {
+          "name": "id",
+          "type": "String"
+        }
+      ]
+    }
+  ]
+}`,
+	"auth.json": `{
+  "hash":"0600000000000000000000000000000000000000",
+  "functions":[
+    {
+      "name":"initContractAdmin",
+      "parameters":[
+        {
+          "name":"adminOnxID",
+          "type":"ByteArray"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"transfer",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"newAdminOnxID",
+          "type":"ByteArray"
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"assignFuncsToRole",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"adminOnxID",
+          "type":"ByteArray"
+        },
+        {
+          "name":"role",
+          "type":"ByteArray"
+        },
+        {
+          "name":"funcNames",
+          "type":"Array",
+          "subType": [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"assignOnxIDsToRole",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"adminOnxID",
+          "type":"ByteArray"
+        },
+        {
+          "name":"role",
+          "type":"ByteArray"
+        },
+        {
+          "name":"persons",
+          "type":"Array",
+          "subType": [
+            {
+              "name": "",
+              "type": "ByteArray"
+            }
+          ]
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"delegate",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"from",
+          "type":"ByteArray"
+        },
+        {
+          "name":"to",
+          "type":"ByteArray"
+        },
+        {
+          "name":"role",
+          "type":"ByteArray"
+        },
+        {
+          "name":"period",
+          "type":"Int"
+        },
+        {
+          "name":"level",
+          "type":"Int"
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"withdraw",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"initiator",
+          "type":"ByteArray"
+        },
+        {
+          "name":"delegate",
+          "type":"ByteArray"
+        },
+        {
+          "name":"role",
+          "type":"ByteArray"
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"verifyToken",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"caller",
+          "type":"ByteArray"
+        },
+        {
+          "name":"fn",
+          "type":"String"
+        },
+        {
+          "name":"keyNo",
+          "type":"Int"
+        }
+      ],
+      "returntype":"Bool"
+    },
+    {
+      "name":"getContractRoles",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        }
+      ],
+      "returntype":"Struct"
+    },
+    {
+      "name":"getOnxIDRoles",
+      "parameters":[
+        {
+          "name":"contractAddr",
+          "type":"Address"
+        },
+        {
+          "name":"onxID",
+          "type":"ByteArray"
+        }
+      ],
+      "returntype":"Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "initContractAdmin",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "adminOnxID",
+          "type": "ByteArray"
+        }
+      ]
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    },
+    {
+      "name": "assignFuncsToRole",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    },
+    {
+      "name": "assignOnxIDsToRole",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    },
+    {
+      "name": "delegate",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "from",
+          "type": "ByteArray"
+        },
+        {
+          "name": "to",
+          "type": "ByteArray"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    },
+    {
+      "name": "withdraw",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "initiator",
+          "type": "ByteArray"
+        },
+        {
+          "name": "delegate",
+          "type": "ByteArray"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    },
+    {
+      "name": "verifyToken",
+      "parameters": [
+        {
+          "name": "contractAddr",
+          "type": "String"
+        },
+        {
+          "name": "caller",
+          "type": "ByteArray"
+        },
+        {
+          "name": "function",
+          "type": "String"
+        },
+        {
+          "name": "ret",
+          "type": "Bool"
+        }
+      ]
+    }
+  ]
+}`,
+	"claimrecord.json": `{
+  "hash": "0d00000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "commit",
+      "parameters": [
+        {
+          "name": "claimId",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuerKeyNo",
+          "type": "Int"
+        },
+        {
+          "name": "subject",
+          "type": "ByteArray"
+        },
+        {
+          "name": "expiration",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "revoke",
+      "parameters": [
+        {
+          "name": "claimId",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "getClaim",
+      "parameters": [
+        {
+          "name": "claimId",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        }
+      ],
+      "returntype": "Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "commit",
+      "parameters": [
+        {
+          "name": "claimId",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        },
+        {
+          "name": "subject",
+          "type": "ByteArray"
+        },
+        {
+          "name": "expiration",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "revoke",
+      "parameters": [
+        {
+          "name": "claimId",
+          "type": "ByteArray"
+        },
+        {
+          "name": "issuer",
+          "type": "ByteArray"
+        }
+      ]
+    }
+  ]
+}`,
+	"escrow.json": `{
+  "hash": "0b00000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "createVesting",
+      "parameters": [
+        {
+          "name": "creator",
+          "type": "Address"
+        },
+        {
+          "name": "beneficiary",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        },
+        {
+          "name": "byHeight",
+          "type": "Bool"
+        },
+        {
+          "name": "start",
+          "type": "Int"
+        },
+        {
+          "name": "cliff",
+          "type": "Int"
+        },
+        {
+          "name": "duration",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "claim",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "beneficiary",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "createEscrow",
+      "parameters": [
+        {
+          "name": "depositor",
+          "type": "Address"
+        },
+        {
+          "name": "beneficiary",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        },
+        {
+          "name": "arbiter",
+          "type": "ByteArray"
+        },
+        {
+          "name": "byHeight",
+          "type": "Bool"
+        },
+        {
+          "name": "timeout",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "release",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "refund",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "keyNo",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "getVesting",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Struct"
+    },
+    {
+      "name": "getEscrow",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Struct"
+    },
+    {
+      "name": "lockedBalance",
+      "parameters": [
+        {
+          "name": "account",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "createVesting",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "creator",
+          "type": "Address"
+        },
+        {
+          "name": "beneficiary",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "claim",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "createEscrow",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "depositor",
+          "type": "Address"
+        },
+        {
+          "name": "beneficiary",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        },
+        {
+          "name": "arbiter",
+          "type": "String"
+        }
+      ]
+    },
+    {
+      "name": "release",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "refund",
+      "parameters": [
+        {
+          "name": "id",
+          "type": "Int"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    }
+  ]
+}`,
+	"global_param.json": `{
+  "hash": "0400000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "init",
+      "parameters": [
+        {
+          "name": "initParams",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "Key",
+                  "type": "String"
+                },
+                {
+                  "name": "Value",
+                  "type": "String"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "name": "adminAddress",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "acceptAdmin",
+      "parameters": [
+        {
+          "name": "adminAddress",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transferAdmin",
+      "parameters": [
+        {
+          "name": "adminAddress",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setOperator",
+      "parameters": [
+        {
+          "name": "operatorAddress",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setGlobalParam",
+      "parameters": [
+        {
+          "name": "params",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "Key",
+                  "type": "String"
+                },
+                {
+                  "name": "Value",
+                  "type": "String"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "getGlobalParam",
+      "parameters": [
+        {
+          "name": "paramNameList",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        }
+      ],
+      "returntype": "Array"
+    },
+    {
+      "name": "createSnapshot",
+      "parameters": [],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setGasSchedule",
+      "parameters": [
+        {
+          "name": "version",
+          "type": "Int"
+        },
+        {
+          "name": "activationHeight",
+          "type": "Int"
+        },
+        {
+          "name": "prices",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "price",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "name",
+                  "type": "String"
+                },
+                {
+                  "name": "price",
+                  "type": "Int"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "getGasSchedule",
+      "parameters": [
+        {
+          "name": "height",
+          "type": "Int"
+        }
+      ],
+      "returntype": "ByteArray"
+    }
+  ],
+  "events": [
+    {
+      "name": "setGasSchedule",
+      "parameters": [
+        {
+          "name": "version",
+          "type": "Int"
+        },
+        {
+          "name": "activationHeight",
+          "type": "Int"
+        }
+      ]
+    }
+  ]
+}`,
+	"governance.json": `{
+  "hash":"0700000000000000000000000000000000000000",
+  "functions":
+  [
+    {
+      "name":"initConfig",
+      "parameters":
+      [
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"registerCandidate",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"InitPos",
+          "type":"Int"
+        },
+        {
+          "name":"Caller",
+          "type":"ByteArray"
+        },
+        {
+          "name":"KeyNo",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"registerCandidateTransferFrom",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"InitPos",
+          "type":"Int"
+        },
+        {
+          "name":"Caller",
+          "type":"ByteArray"
+        },
+        {
+          "name":"KeyNo",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"unRegisterCandidate",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"approveCandidate",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"rejectCandidate",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"blackNode",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkeyList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"whiteNode",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"quitNode",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"authorizeForPeer",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"PeerPubkeyList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        },
+        {
+          "name":"PosList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "Int"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"authorizeForPeerTransferFrom",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"PeerPubkeyList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        },
+        {
+          "name":"PosList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "Int"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"unAuthorizeForPeer",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"PeerPubkeyList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        },
+        {
+          "name":"PosList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "Int"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"withdraw",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"PeerPubkeyList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "String"
+            }
+          ]
+        },
+        {
+          "name":"WithdrawList",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "Int"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"withdrawOxg",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"commitDpos",
+      "parameters":
+      [
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"updateConfig",
+      "parameters":
+      [
+        {
+          "name":"N",
+          "type":"Int"
+        },
+        {
+          "name":"C",
+          "type":"Int"
+        },
+        {
+          "name":"K",
+          "type":"Int"
+        },
+        {
+          "name":"L",
+          "type":"Int"
+        },
+        {
+          "name":"BlockMsgDelay",
+          "type":"Int"
+        },
+        {
+          "name":"HashMsgDelay",
+          "type":"Int"
+        },
+        {
+          "name":"PeerHandshakeTimeout",
+          "type":"Int"
+        },
+        {
+          "name":"MaxBlockChangeView",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"updateGlobalParam",
+      "parameters":
+      [
+        {
+          "name":"CandidateFee",
+          "type":"Int"
+        },
+        {
+          "name":"MinInitStake",
+          "type":"Int"
+        },
+        {
+          "name":"CandidateNum",
+          "type":"Int"
+        },
+        {
+          "name":"PosLimit",
+          "type":"Int"
+        },
+        {
+          "name":"A",
+          "type":"Int"
+        },
+        {
+          "name":"B",
+          "type":"Int"
+        },
+        {
+          "name":"Yita",
+          "type":"Int"
+        },
+        {
+          "name":"Penalty",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"updateSplitCurve",
+      "parameters":
+      [
+        {
+          "name":"Yi",
+          "type":"Array",
+          "subType":
+          [
+            {
+              "name": "",
+              "type": "Int"
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"callSplit",
+      "parameters":
+      [
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"transferPenalty",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"changeMaxAuthorizations",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"MaxAuthorize",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"setPeerCost",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"PeerCost",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"withdrawFee",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"addInitPos",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"Pos",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"reduceInitPos",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Address",
+          "type":"Address"
+        },
+        {
+          "name":"Pos",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"updateGlobalParam2",
+      "parameters":
+      [
+        {
+          "name":"MinAuthorizePos",
+          "type":"Int"
+        },
+        {
+          "name":"CandidateFeeSplitNum",
+          "type":"Int"
+        },
+        {
+          "name":"Field1",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Field2",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Field3",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Field4",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Field5",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Field6",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"setPromisePos",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Pos",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"createProposal",
+      "parameters":
+      [
+        {
+          "name":"Proposer",
+          "type":"Address"
+        },
+        {
+          "name":"Kind",
+          "type":"Int"
+        },
+        {
+          "name":"Payload",
+          "type":"ByteArray"
+        },
+        {
+          "name":"Description",
+          "type":"String"
+        },
+        {
+          "name":"ExecuteHeight",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"voteProposal",
+      "parameters":
+      [
+        {
+          "name":"Voter",
+          "type":"Address"
+        },
+        {
+          "name":"Id",
+          "type":"Int"
+        },
+        {
+          "name":"Approve",
+          "type":"Boolean"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"reportEquivocation",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Headers",
+          "type":"Array"
+        },
+        {
+          "name":"Sigs",
+          "type":"Array"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"getPeerPoolInfo",
+      "parameters":
+      [
+      ],
+      "returnType":"Struct"
+    },
+    {
+      "name":"getStakeInfo",
+      "parameters":
+      [
+        {
+          "name":"Address",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Struct"
+    }
+  ],
+  "events":
+  [
+    {
+      "name":"createProposal",
+      "parameters":
+      [
+        {
+          "name":"Id",
+          "type":"Int"
+        },
+        {
+          "name":"Proposer",
+          "type":"String"
+        },
+        {
+          "name":"ExecuteHeight",
+          "type":"Int"
+        }
+      ]
+    },
+    {
+      "name":"executed",
+      "parameters":
+      [
+        {
+          "name":"Id",
+          "type":"Int"
+        },
+        {
+          "name":"Proposer",
+          "type":"String"
+        },
+        {
+          "name":"ExecuteHeight",
+          "type":"Int"
+        }
+      ]
+    },
+    {
+      "name":"rejected",
+      "parameters":
+      [
+        {
+          "name":"Id",
+          "type":"Int"
+        },
+        {
+          "name":"Proposer",
+          "type":"String"
+        },
+        {
+          "name":"ExecuteHeight",
+          "type":"Int"
+        }
+      ]
+    },
+    {
+      "name":"failed",
+      "parameters":
+      [
+        {
+          "name":"Id",
+          "type":"Int"
+        },
+        {
+          "name":"Proposer",
+          "type":"String"
+        },
+        {
+          "name":"ExecuteHeight",
+          "type":"Int"
+        }
+      ]
+    },
+    {
+      "name":"reportEquivocation",
+      "parameters":
+      [
+        {
+          "name":"PeerPubkey",
+          "type":"String"
+        },
+        {
+          "name":"Height",
+          "type":"Int"
+        }
+      ]
+    }
+  ]
+}`,
+	"htlc.json": `{
+  "hash": "0c00000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "lock",
+      "parameters": [
+        {
+          "name": "sender",
+          "type": "Address"
+        },
+        {
+          "name": "recipient",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        },
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        },
+        {
+          "name": "hashType",
+          "type": "Byte"
+        },
+        {
+          "name": "timelock",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "claim",
+      "parameters": [
+        {
+          "name": "preimage",
+          "type": "ByteArray"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "refund",
+      "parameters": [
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "getSwap",
+      "parameters": [
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        }
+      ],
+      "returntype": "Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "lock",
+      "parameters": [
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        },
+        {
+          "name": "sender",
+          "type": "Address"
+        },
+        {
+          "name": "recipient",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        },
+        {
+          "name": "timelock",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "claim",
+      "parameters": [
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        },
+        {
+          "name": "preimage",
+          "type": "ByteArray"
+        },
+        {
+          "name": "recipient",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "refund",
+      "parameters": [
+        {
+          "name": "hashlock",
+          "type": "ByteArray"
+        },
+        {
+          "name": "sender",
+          "type": "Address"
+        },
+        {
+          "name": "asset",
+          "type": "Address"
+        },
+        {
+          "name": "amount",
+          "type": "Int"
+        }
+      ]
+    }
+  ]
+}`,
+	"nameservice.json": `{
+  "hash": "0e00000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "register",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "owner",
+          "type": "Address"
+        },
+        {
+          "name": "years",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "renew",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "payer",
+          "type": "Address"
+        },
+        {
+          "name": "years",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setSubname",
+      "parameters": [
+        {
+          "name": "parent",
+          "type": "String"
+        },
+        {
+          "name": "label",
+          "type": "String"
+        },
+        {
+          "name": "owner",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setRecords",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "address",
+          "type": "Address"
+        },
+        {
+          "name": "onxID",
+          "type": "ByteArray"
+        },
+        {
+          "name": "contract",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "setReverse",
+      "parameters": [
+        {
+          "name": "address",
+          "type": "Address"
+        },
+        {
+          "name": "name",
+          "type": "String"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "resolve",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        }
+      ],
+      "returntype": "Struct"
+    },
+    {
+      "name": "reverse",
+      "parameters": [
+        {
+          "name": "address",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Struct"
+    }
+  ],
+  "events": [
+    {
+      "name": "register",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "owner",
+          "type": "Address"
+        },
+        {
+          "name": "expiration",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "renew",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "payer",
+          "type": "Address"
+        },
+        {
+          "name": "expiration",
+          "type": "Int"
+        }
+      ]
+    },
+    {
+      "name": "setSubname",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "owner",
+          "type": "Address"
+        }
+      ]
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        }
+      ]
+    },
+    {
+      "name": "setRecords",
+      "parameters": [
+        {
+          "name": "name",
+          "type": "String"
+        },
+        {
+          "name": "address",
+          "type": "Address"
+        },
+        {
+          "name": "onxID",
+          "type": "ByteArray"
+        },
+        {
+          "name": "contract",
+          "type": "Address"
+        }
+      ]
+    },
+    {
+      "name": "setReverse",
+      "parameters": [
+        {
+          "name": "address",
+          "type": "Address"
+        },
+        {
+          "name": "name",
+          "type": "String"
+        }
+      ]
+    }
+  ]
+}`,
+	"onx.json": `{
+  "hash": "0100000000000000000000000000000000000000",
+  "functions": [
+    {
+      "name": "init",
+      "parameters": [],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "states",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "state",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "from",
+                  "type": "Address"
+                },
+                {
+                  "name": "to",
+                  "type": "Address"
+                },
+                {
+                  "name": "value",
+                  "type": "Int"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "approve",
+      "parameters": [
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transferFrom",
+      "parameters": [
+        {
+          "name": "sender",
+          "type": "Address"
+        },
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "transferMulti",
+      "parameters": [
+        {
+          "name": "states",
+          "type": "Array",
+          "subType": [
+            {
+              "name": "state",
+              "type": "Struct",
+              "subType": [
+                {
+                  "name": "from",
+                  "type": "Address"
+                },
+                {
+                  "name": "to",
+                  "type": "Address"
+                },
+                {
+                  "name": "value",
+                  "type": "Int"
+                },
+                {
+                  "name": "memo",
+                  "type": "String"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returntype": "Bool"
+    },
+    {
+      "name": "name",
+      "parameters": [],
+      "returntype": "String"
+    },
+    {
+      "name": "symbol",
+      "parameters": [],
+      "returntype": "String"
+    },
+    {
+      "name": "decimals",
+      "parameters": [],
+      "returntype": "Int"
+    },
+    {
+      "name": "totalSupply",
+      "parameters": [],
+      "returntype": "Int"
+    },
+    {
+      "name": "balanceOf",
+      "parameters": [
+        {
+          "name": "account",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Int"
+    },
+    {
+      "name": "allowance",
+      "parameters": [
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        }
+      ],
+      "returntype": "Int"
+    }
+  ],
+  "events": [
+    {
+      "name": "transfer",
+      "parameters": [
+        {
+          "name": "from",
+          "type": "Address"
+        },
+        {
+          "name": "to",
+          "type": "Address"
+        },
+        {
+          "name": "value",
+          "type": "Int"
+        }
+      ]
+    }
+  ]
+}`,
+	"onxid.json": `{
+  "hash":"0300000000000000000000000000000000000000",
+  "functions":[
+    {
+      "name":"regIDWithPublicKey",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"publicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"addKey",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"newPublicKey",
+          "type":"ByteArray"
+        },
+        {
+          "name":"userxPublicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"removeKey",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"removedKey",
+          "type":"ByteArray"
+        },
+        {
+          "name":"userPublicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"addRecovery",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"recovery",
+          "type":"Address"
+        },
+        {
+          "name":"userPublicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"changeRecovery",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"newRecovery",
+          "type":"Address"
+        },
+        {
+          "name":"oldRecovery",
+          "type":"Address"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"regIDWithAttributes",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"publicKey",
+          "type":"ByteArray"
+        },
+        {
+          "name":"attributes",
+          "type":"Array",
+          "subType":[
+            {
+              "name":"",
+              "type":"Struct",
+              "subType":[
+                {
+                  "name":"key",
+                  "type":"ByteArray"
+                },
+                {
+                  "name":"type",
+                  "type":"ByteArray"
+                },
+                {
+                  "name":"value",
+                  "type":"ByteArray"
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"addAttributes",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"attributes",
+          "type":"Array",
+          "subType":[
+            {
+              "name":"",
+              "type":"Struct",
+              "subType":[
+                {
+                  "name":"path",
+                  "type":"ByteArray"
+                },
+                {
+                  "name":"type",
+                  "type":"ByteArray"
+                },
+                {
+                  "name":"value",
+                  "type":"ByteArray"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "name":"userPublicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"removeAttribute",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"attributePath",
+          "type":"ByteArray"
+        },
+        {
+          "name":"userPublicKey",
+          "type":"ByteArray"
+        }
+      ],
+      "returnType":"Bool"
+    },
+    {
+      "name":"getPublicKeys",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        }
+      ],
+      "returnType":"ByteArray"
+    },
+    {
+      "name":"getKeyState",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"keyIndex",
+          "type":"Int"
+        }
+      ],
+      "returnType":"String"
+    },
+    {
+      "name":"getAttributes",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        }
+      ],
+      "returnType":"ByteArray"
+    },
+    {
+      "name":"getDDO",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        }
+      ],
+      "returnType":"ByteArray"
+    },
+    {
+      "name":"verifySignature",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"keyIndex",
+          "type":"Int"
+        }
+      ],
+      "returnType":"Bool"
+    }
+  ],
+  "events":[
+    {
+      "name":"Register",
+      "parameters":[
+        {
+          "name":"id",
+          "type":"String"
+        }
+      ]
+    },
+    {
+      "name":"PublicKey",
+      "parameters":[
+        {
+          "name":"operation",
+          "type":"String"
+        },
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"index",
+          "type":"Int"
+        },
+        {
+          "name":"publicKey",
+          "type":"ByteArray"
+        }
+      ]
+    },
+    {
+      "name":"Attribute",
+      "parameters":[
+        {
+          "name":"operation",
+          "type":"String"
+        },
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"attributePath",
+          "type":"ByteArray"
+        }
+      ]
+    },
+    {
+      "name":"Recovery",
+      "parameters":[
+        {
+          "name":"operation",
+          "type":"String"
+        },
+        {
+          "name":"id",
+          "type":"String"
+        },
+        {
+          "name":"recoveryAddress",
+          "type":"Address"
+        }
+      ]
+    }
+  ]
+}`,
+	"oxg.json": `{
+    "hash": "0200000000000000000000000000000000000000",
+    "functions": [
+        {
+            "name": "init",
+            "parameters": [],
+            "returntype": "Bool"
+        },
+        {
+            "name": "transfer",
+            "parameters": [
+                {
+                    "name": "states",
+                    "type": "Array",
+                    "subType": [
+                        {
+                            "name": "state",
+                            "type": "Struct",
+                            "subType": [
+                                {
+                                    "name": "from",
+                                    "type": "Address"
+                                },
+                                {
+                                    "name": "to",
+                                    "type": "Address"
+                                },
+                                {
+                                    "name": "value",
+                                    "type": "Int"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "returntype": "Bool"
+        },
+        {
+            "name": "approve",
+            "parameters": [
+                {
+                    "name": "from",
+                    "type": "Address"
+                },
+                {
+                    "name": "to",
+                    "type": "Address"
+                },
+                {
+                    "name": "value",
+                    "type": "Int"
+                }
+            ],
+            "returntype": "Bool"
+        },
+        {
+            "name": "transferFrom",
+            "parameters": [
+                {
+                    "name": "sender",
+                    "type": "Address"
+                },
+                {
+                    "name": "from",
+                    "type": "Address"
+                },
+                {
+                    "name": "to",
+                    "type": "Address"
+                },
+                {
+                    "name": "value",
+                    "type": "Int"
+                }
+            ],
+            "returntype": "Bool"
+        },
+        {
+            "name": "transferMulti",
+            "parameters": [
+                {
+                    "name": "states",
+                    "type": "Array",
+                    "subType": [
+                        {
+                            "name": "state",
+                            "type": "Struct",
+                            "subType": [
+                                {
+                                    "name": "from",
+                                    "type": "Address"
+                                },
+                                {
+                                    "name": "to",
+                                    "type": "Address"
+                                },
+                                {
+                                    "name": "value",
+                                    "type": "Int"
+                                },
+                                {
+                                    "name": "memo",
+                                    "type": "String"
+                                }
+                            ]
+                        }
+                    ]
+                }
+            ],
+            "returntype": "Bool"
+        },
+        {
+            "name": "name",
+            "parameters": [],
+            "returntype": "String"
+        },
+        {
+            "name": "symbol",
+            "parameters": [],
+            "returntype": "String"
+        },
+        {
+            "name": "decimals",
+            "parameters": [],
+            "returntype": "Int"
+        },
+        {
+            "name": "totalSupply",
+            "parameters": [],
+            "returntype": "Int"
+        },
+        {
+            "name": "balanceOf",
+            "parameters": [
+                {
+                    "name": "account",
+                    "type": "Address"
+                }
+            ],
+            "returntype": "Int"
+        },
+        {
+            "name": "allowance",
+            "parameters": [
+                {
+                    "name": "from",
+                    "type": "Address"
+                },
+                {
+                    "name": "to",
+                    "type": "Address"
+                }
+            ],
+            "returntype": "Int"
+        }
+    ],
+    "events": [
+        {
+            "name": "transfer",
+            "parameters": [
+                {
+                    "name": "from",
+                    "type": "Address"
+                },
+                {
+                    "name": "to",
+                    "type": "Address"
+                },
+                {
+                    "name": "value",
+                    "type": "Int"
+                }
+            ]
+        }
+    ]
+}`,
+	"upgrade.json": `{
+    "hash": "0900000000000000000000000000000000000000",
+    "functions": [
+        {
+            "name": "upgradeContract",
+            "parameters": [
+                {
+                    "name": "contract",
+                    "type": "Address"
+                },
+                {
+                    "name": "code",
+                    "type": "ByteArray"
+                },
+                {
+                    "name": "needStorage",
+                    "type": "Bool"
+                },
+                {
+                    "name": "name",
+                    "type": "String"
+                },
+                {
+                    "name": "version",
+                    "type": "String"
+                },
+                {
+                    "name": "author",
+                    "type": "String"
+                },
+                {
+                    "name": "email",
+                    "type": "String"
+                },
+                {
+                    "name": "description",
+                    "type": "String"
+                },
+                {
+                    "name": "keyNo",
+                    "type": "Int"
+                }
+            ],
+            "returntype": "Bool"
+        },
+        {
+            "name": "getVersions",
+            "parameters": [
+                {
+                    "name": "contract",
+                    "type": "Address"
+                }
+            ],
+            "returntype": "ByteArray"
+        }
+    ],
+    "events": [
+        {
+            "name": "deploy",
+            "parameters": [
+                {
+                    "name": "address",
+                    "type": "Address"
+                },
+                {
+                    "name": "index",
+                    "type": "Int"
+                },
+                {
+                    "name": "codeHash",
+                    "type": "Address"
+                },
+                {
+                    "name": "version",
+                    "type": "String"
+                },
+                {
+                    "name": "from",
+                    "type": "Address"
+                }
+            ]
+        },
+        {
+            "name": "migrate",
+            "parameters": [
+                {
+                    "name": "address",
+                    "type": "Address"
+                },
+                {
+                    "name": "index",
+                    "type": "Int"
+                },
+                {
+                    "name": "codeHash",
+                    "type": "Address"
+                },
+                {
+                    "name": "version",
+                    "type": "String"
+                },
+                {
+                    "name": "from",
+                    "type": "Address"
+                }
+            ]
+        },
+        {
+            "name": "upgrade",
+            "parameters": [
+                {
+                    "name": "address",
+                    "type": "Address"
+                },
+                {
+                    "name": "index",
+                    "type": "Int"
+                },
+                {
+                    "name": "codeHash",
+                    "type": "Address"
+                },
+                {
+                    "name": "version",
+                    "type": "String"
+                },
+                {
+                    "name": "from",
+                    "type": "Address"
+                }
+            ]
+        }
+    ]
+}`,
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package framework
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//abi files compiled into native_abi_data.go
+const abiDir = "../../../../cmd/abi/native_abi_script/"
+
+func TestNativeAbiData(t *testing.T) {
+	files, err := ioutil.ReadDir(abiDir)
+	assert.Nil(t, err)
+	data := NativeAbiData()
+	assert.Equal(t, len(files), len(data))
+	for _, file := range files {
+		content, err := ioutil.ReadFile(abiDir + file.Name())
+		assert.Nil(t, err)
+		assert.Equal(t, strings.TrimSpace(string(content)), data[file.Name()], file.Name())
+	}
+	assert.Equal(t, len(files), len(nativeAbis))
+}
+
+func TestNativeAbi(t *testing.T) {
+	onxAbi := NativeAbi(utils.OnxContractAddress)
+	assert.NotNil(t, onxAbi)
+	assert.Equal(t, utils.OnxContractAddress.ToHexString(), onxAbi.Address)
+	assert.NotNil(t, onxAbi.GetFunc("Transfer"))
+	assert.Nil(t, onxAbi.GetFunc("unknown"))
+	assert.Nil(t, NativeAbi(common.Address{0xff}))
+	for _, address := range []common.Address{utils.AssetContractAddress, utils.EscrowContractAddress,
+		utils.HtlcContractAddress, utils.ClaimContractAddress} {
+		assert.NotNil(t, NativeAbi(address), address.ToHexString())
+	}
+
+	assert.Equal(t, ABI_TYPE_ADDRESS, AbiType("address"))
+	assert.Equal(t, ABI_TYPE_BYTEARRAY, AbiType("bytearray"))
+	assert.Equal(t, "unknown", AbiType("unknown"))
+}
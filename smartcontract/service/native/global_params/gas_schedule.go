@@ -0,0 +1,276 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package global_params
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	GAS_SCHEDULE       = "gasSchedule"
+	GAS_SCHEDULE_COUNT = "gasScheduleCount"
+
+	MAX_GAS_SCHEDULE_SIZE = 1024
+)
+
+//GasSchedule is a full table of gas prices by name of opcode, syscall, storage byte and native method,
+//which takes effect from block of ActivationHeight until activation of next version.
+type GasSchedule struct {
+	Version          uint32
+	ActivationHeight uint32
+	Prices           map[string]uint64
+}
+
+func (this *GasSchedule) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(this.Version)); err != nil {
+		return fmt.Errorf("serialize version error:%v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.ActivationHeight)); err != nil {
+		return fmt.Errorf("serialize activation height error:%v", err)
+	}
+	names := make([]string, 0, len(this.Prices))
+	for name := range this.Prices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := utils.WriteVarUint(w, uint64(len(names))); err != nil {
+		return fmt.Errorf("serialize prices length error:%v", err)
+	}
+	for _, name := range names {
+		if err := serialization.WriteString(w, name); err != nil {
+			return fmt.Errorf("serialize price name error:%v", err)
+		}
+		if err := utils.WriteVarUint(w, this.Prices[name]); err != nil {
+			return fmt.Errorf("serialize price of %s error:%v", name, err)
+		}
+	}
+	return nil
+}
+
+func (this *GasSchedule) Deserialize(r io.Reader) error {
+	version, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize version error:%v", err)
+	}
+	height, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize activation height error:%v", err)
+	}
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("deserialize prices length error:%v", err)
+	}
+	if n > MAX_GAS_SCHEDULE_SIZE {
+		return fmt.Errorf("too many prices, max %d", MAX_GAS_SCHEDULE_SIZE)
+	}
+	this.Version, this.ActivationHeight = uint32(version), uint32(height)
+	this.Prices = make(map[string]uint64, n)
+	for i := uint64(0); i < n; i++ {
+		name, err := serialization.ReadString(r)
+		if err != nil {
+			return fmt.Errorf("deserialize price name error:%v", err)
+		}
+		if this.Prices[name], err = utils.ReadVarUint(r); err != nil {
+			return fmt.Errorf("deserialize price of %s error:%v", name, err)
+		}
+	}
+	return nil
+}
+
+//GasScheduleKey returns key of gas schedule of version, without contract address
+func GasScheduleKey(version uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, version)
+	return append([]byte(GAS_SCHEDULE), key...)
+}
+
+//StorageGetter reads value of key in global params contract storage, key is without contract address.
+//It returns nil if key not exist.
+type StorageGetter func(key []byte) ([]byte, error)
+
+func getGasScheduleCount(get StorageGetter) (uint32, error) {
+	value, err := get([]byte(GAS_SCHEDULE_COUNT))
+	if err != nil || len(value) == 0 {
+		return 0, err
+	}
+	count, err := serialization.ReadUint32(bytes.NewBuffer(value))
+	if err != nil {
+		return 0, fmt.Errorf("deserialize gas schedule count error:%v", err)
+	}
+	return count, nil
+}
+
+//GetGasSchedule returns gas schedule of version, version starts from 1
+func GetGasSchedule(get StorageGetter, version uint32) (*GasSchedule, error) {
+	value, err := get(GasScheduleKey(version))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("gas schedule of version %d not found", version)
+	}
+	schedule := new(GasSchedule)
+	if err := schedule.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+//GetGasSchedules returns all gas schedules, ordered by version
+func GetGasSchedules(get StorageGetter) ([]*GasSchedule, error) {
+	count, err := getGasScheduleCount(get)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]*GasSchedule, 0, count)
+	for version := uint32(1); version <= count; version++ {
+		schedule, err := GetGasSchedule(get, version)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+//GetGasScheduleAt returns gas schedule active at block height, nil if no schedule is active.
+//Activation height of versions is increasing, so result of a height never changes once the block is executed.
+func GetGasScheduleAt(get StorageGetter, height uint32) (*GasSchedule, error) {
+	count, err := getGasScheduleCount(get)
+	if err != nil {
+		return nil, err
+	}
+	for version := count; version >= 1; version-- {
+		schedule, err := GetGasSchedule(get, version)
+		if err != nil {
+			return nil, err
+		}
+		if schedule.ActivationHeight <= height {
+			return schedule, nil
+		}
+	}
+	return nil, nil
+}
+
+func nativeStorageGetter(native *native.NativeService, contract common.Address) StorageGetter {
+	return func(key []byte) ([]byte, error) {
+		item, err := utils.GetStorageItem(native, utils.ConcatKey(contract, key))
+		if err != nil || item == nil {
+			return nil, err
+		}
+		return item.Value, nil
+	}
+}
+
+//SetGasSchedule sets a full gas schedule activated at a future height. It can only be called by governance
+//contract when executing passed proposal. Latest schedule can be replaced before its activation, active
+//schedules are never changed.
+func SetGasSchedule(native *native.NativeService) ([]byte, error) {
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	caller := native.ContextRef.CallingContext()
+	if caller == nil || caller.ContractAddress != utils.GovernanceContractAddress {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, only governance contract can set gas schedule")
+	}
+	schedule := new(GasSchedule)
+	if err := schedule.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, %s", err)
+	}
+	if len(schedule.Prices) == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, prices is empty")
+	}
+	if schedule.ActivationHeight <= native.Height {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, activation height %d should be greater than current height %d",
+			schedule.ActivationHeight, native.Height)
+	}
+	get := nativeStorageGetter(native, contract)
+	count, err := getGasScheduleCount(get)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, %s", err)
+	}
+	schedule.Version = count + 1
+	if count > 0 {
+		latest, err := GetGasSchedule(get, count)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, %s", err)
+		}
+		if latest.ActivationHeight > native.Height {
+			//latest is not active yet, replace it
+			schedule.Version = count
+			if count > 1 {
+				if latest, err = GetGasSchedule(get, count-1); err != nil {
+					return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, %s", err)
+				}
+			}
+		}
+		if schedule.Version > 1 && schedule.ActivationHeight <= latest.ActivationHeight {
+			return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, activation height should be greater than %d",
+				latest.ActivationHeight)
+		}
+	}
+	bf := new(bytes.Buffer)
+	if err := schedule.Serialize(bf); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set gas schedule, %s", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, GasScheduleKey(schedule.Version)), cstates.GenRawStorageItem(bf.Bytes()))
+	countBuf := new(bytes.Buffer)
+	serialization.WriteUint32(countBuf, schedule.Version)
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(GAS_SCHEDULE_COUNT)), cstates.GenRawStorageItem(countBuf.Bytes()))
+
+	if config.DefConfig.Common.EnableEventLog {
+		native.Notifications = append(native.Notifications,
+			&event.NotifyEventInfo{
+				ContractAddress: contract,
+				States:          []interface{}{SET_GAS_SCHEDULE_NAME, schedule.Version, schedule.ActivationHeight},
+			})
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//GetGasScheduleByHeight returns serialized gas schedule active at height in input, empty if no schedule is active
+func GetGasScheduleByHeight(native *native.NativeService) ([]byte, error) {
+	height, err := utils.ReadVarUint(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("get gas schedule, deserialize height error:%s", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	schedule, err := GetGasScheduleAt(nativeStorageGetter(native, contract), uint32(height))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("get gas schedule, %s", err)
+	}
+	if schedule == nil {
+		return nil, nil
+	}
+	bf := new(bytes.Buffer)
+	if err := schedule.Serialize(bf); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("get gas schedule, %s", err)
+	}
+	return bf.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package global_params
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasSchedule_Serialize(t *testing.T) {
+	schedule := &GasSchedule{
+		Version:          2,
+		ActivationHeight: 100,
+		Prices:           map[string]uint64{"Opcode": 2, "System.Storage.Put": 5000, "Storage.Byte": 1},
+	}
+	bf := new(bytes.Buffer)
+	err := schedule.Serialize(bf)
+	assert.Nil(t, err)
+
+	schedule2 := new(GasSchedule)
+	err = schedule2.Deserialize(bytes.NewReader(bf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, schedule, schedule2)
+}
+
+func TestGetGasScheduleAt(t *testing.T) {
+	store := make(map[string][]byte)
+	get := func(key []byte) ([]byte, error) {
+		return store[string(key)], nil
+	}
+	schedule, err := GetGasScheduleAt(get, 100)
+	assert.Nil(t, err)
+	assert.Nil(t, schedule)
+
+	for version, height := range []uint32{10, 20} {
+		s := &GasSchedule{Version: uint32(version + 1), ActivationHeight: height, Prices: map[string]uint64{"Opcode": uint64(version + 1)}}
+		bf := new(bytes.Buffer)
+		assert.Nil(t, s.Serialize(bf))
+		store[string(GasScheduleKey(s.Version))] = bf.Bytes()
+	}
+	store[GAS_SCHEDULE_COUNT] = []byte{2, 0, 0, 0}
+
+	for height, version := range map[uint32]uint32{9: 0, 10: 1, 19: 1, 20: 2, 1000: 2} {
+		schedule, err := GetGasScheduleAt(get, height)
+		assert.Nil(t, err)
+		if version == 0 {
+			assert.Nil(t, schedule)
+		} else {
+			assert.Equal(t, version, schedule.Version)
+		}
+	}
+	schedules, err := GetGasSchedules(get)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(schedules))
+}
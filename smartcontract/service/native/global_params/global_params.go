@@ -42,6 +42,9 @@ const (
 	SET_GLOBAL_PARAM_NAME                    = "setGlobalParam"
 	GET_GLOBAL_PARAM_NAME                    = "getGlobalParam"
 	CREATE_SNAPSHOT_NAME                     = "createSnapshot"
+	APPLY_PARAMS_NAME                        = "applyParams"
+	SET_GAS_SCHEDULE_NAME                    = "setGasSchedule"
+	GET_GAS_SCHEDULE_NAME                    = "getGasSchedule"
 )
 
 func InitGlobalParams() {
@@ -56,6 +59,8 @@ func RegisterParamContract(native *native.NativeService) {
 	native.Register(SET_GLOBAL_PARAM_NAME, SetGlobalParam)
 	native.Register(GET_GLOBAL_PARAM_NAME, GetGlobalParam)
 	native.Register(CREATE_SNAPSHOT_NAME, CreateSnapshot)
+	native.Register(SET_GAS_SCHEDULE_NAME, SetGasSchedule)
+	native.Register(GET_GAS_SCHEDULE_NAME, GetGasScheduleByHeight)
 }
 
 func ParamInit(native *native.NativeService) ([]byte, error) {
@@ -241,3 +246,24 @@ func CreateSnapshot(native *native.NativeService) ([]byte, error) {
 	NotifyParamChange(native, contract, CREATE_SNAPSHOT_NAME, prepareParam)
 	return utils.BYTE_TRUE, nil
 }
+
+//ApplyParams makes params effective at once without operator, used by governance contract to execute passed
+//proposals. Params are set in prepare value as well, so that next snapshot does not revert them.
+func ApplyParams(native *native.NativeService, params Params) error {
+	if len(params) == 0 {
+		return errors.NewErr("apply params, params is nil!")
+	}
+	contract := utils.ParamContractAddress
+	for _, valueType := range []paramType{PREPARE_VALUE, CURRENT_VALUE} {
+		storageParams, err := getStorageParam(native, generateParamKey(contract, valueType))
+		if err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "apply params, read storage param error!")
+		}
+		for _, param := range params {
+			storageParams.SetParam(param)
+		}
+		native.CacheDB.Put(generateParamKey(contract, valueType), getParamStorageItem(storageParams).ToArray())
+	}
+	NotifyParamChange(native, contract, APPLY_PARAMS_NAME, params)
+	return nil
+}
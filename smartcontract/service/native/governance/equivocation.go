@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	vbftconfig "github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	//in each height an honest peer signs blocks of other proposers only in endorsements and commit: at most one
+	//block and one empty block it endorses, and one block it commits. So four different blocks signed by the peer
+	//prove that it made conflicting endorsements or commits. vbft server persists blocks it endorses and commits
+	//before sending the msgs, so it signs no other blocks at the height after restart. Blocks proposed by the peer
+	//itself are not counted, since a proposer may propose again at the same height after restart.
+	MIN_EQUIVOCATION_BLOCKS = 4
+	MAX_EQUIVOCATION_BLOCKS = 16
+)
+
+//report evidence of a peer endorsing or committing conflicting blocks at the same height. The evidence is verified
+//by signatures of the peer, and the peer is put into black list, its init pos is penalized when it quits
+func ReportEquivocation(native *native.NativeService) ([]byte, error) {
+	params := new(ReportEquivocationParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize reportEquivocationParam error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[params.PeerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peerPubkey is not in peerPoolMap")
+	}
+	if peerPoolItem.Status == BlackStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peer is already in black list")
+	}
+
+	height, err := verifyEquivocation(params.PeerPubkey, peerPoolItem.Index, params.Headers, params.Sigs)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, invalid evidence: %v", err)
+	}
+	if height >= native.Height {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, evidence of future height %d", height)
+	}
+	reported, err := isEquivocationReported(native, contract, params.PeerPubkey, height)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("isEquivocationReported, %v", err)
+	}
+	if reported {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, equivocation of height %d is already reported", height)
+	}
+
+	commit, err := blackPeer(native, contract, peerPoolMap, params.PeerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("blackPeer, %v", err)
+	}
+	err = putPeerPoolMap(native, contract, view, peerPoolMap)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
+	}
+	err = putEquivocationReported(native, contract, params.PeerPubkey, height)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putEquivocationReported, %v", err)
+	}
+
+	//commitDpos
+	if commit {
+		err = executeCommitDpos(native, contract)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
+		}
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{REPORT_EQUIVOCATION, params.PeerPubkey, height},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+//verifyEquivocation checks headers are at the same height and proposed by other peers, and each of them is
+//signed by peer of index, returns the height if there are at least MIN_EQUIVOCATION_BLOCKS different blocks
+func verifyEquivocation(peerPubkey string, index uint32, headers [][]byte, sigs [][]byte) (uint32, error) {
+	if len(sigs) != len(headers) {
+		return 0, fmt.Errorf("length of sigs is not equal to length of headers")
+	}
+	pubkey, err := vbftconfig.Pubkey(peerPubkey)
+	if err != nil {
+		return 0, fmt.Errorf("invalid peerPubkey: %v", err)
+	}
+	var height uint32
+	hashes := make(map[common.Uint256]bool)
+	for i, raw := range headers {
+		header := new(types.Header)
+		if err := header.Deserialize(bytes.NewBuffer(raw)); err != nil {
+			return 0, fmt.Errorf("deserialize header error: %v", err)
+		}
+		if i == 0 {
+			height = header.Height
+		} else if header.Height != height {
+			return 0, fmt.Errorf("headers are not of the same height")
+		}
+		blockInfo := new(vbftconfig.VbftBlockInfo)
+		if err := json.Unmarshal(header.ConsensusPayload, blockInfo); err != nil {
+			return 0, fmt.Errorf("unmarshal consensus payload error: %v", err)
+		}
+		if blockInfo.Proposer == index {
+			return 0, fmt.Errorf("block of height %d is proposed by peer", height)
+		}
+		hash := header.Hash()
+		if err := signature.Verify(pubkey, hash[:], sigs[i]); err != nil {
+			return 0, fmt.Errorf("block %s is not signed by peer: %v", hash.ToHexString(), err)
+		}
+		hashes[hash] = true
+	}
+	if len(hashes) < MIN_EQUIVOCATION_BLOCKS {
+		return 0, fmt.Errorf("need at least %d different blocks, got %d", MIN_EQUIVOCATION_BLOCKS, len(hashes))
+	}
+	return height, nil
+}
+
+func isEquivocationReported(native *native.NativeService, contract common.Address, peerPubkey string, height uint32) (bool, error) {
+	key, err := equivocationKey(contract, peerPubkey, height)
+	if err != nil {
+		return false, err
+	}
+	value, err := native.CacheDB.Get(key)
+	if err != nil {
+		return false, fmt.Errorf("get equivocation error: %v", err)
+	}
+	return value != nil, nil
+}
+
+func putEquivocationReported(native *native.NativeService, contract common.Address, peerPubkey string, height uint32) error {
+	key, err := equivocationKey(contract, peerPubkey, height)
+	if err != nil {
+		return err
+	}
+	native.CacheDB.Put(key, cstates.GenRawStorageItem(utils.BYTE_TRUE))
+	return nil
+}
+
+func equivocationKey(contract common.Address, peerPubkey string, height uint32) ([]byte, error) {
+	peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	heightBytes, err := GetUint32Bytes(height)
+	if err != nil {
+		return nil, fmt.Errorf("getUint32Bytes, get heightBytes error: %v", err)
+	}
+	return utils.ConcatKey(contract, []byte(EQUIVOCATION), peerPubkeyPrefix, heightBytes), nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	vbftconfig "github.com/OnyxPay/OnyxChain-legacy/consensus/vbft/config"
+	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+)
+
+//header of block proposed by proposer, and signature of acc on the block
+func signedHeader(t *testing.T, acc *account.Account, proposer uint32, height uint32, nonce uint64) ([]byte, []byte) {
+	payload, err := json.Marshal(&vbftconfig.VbftBlockInfo{Proposer: proposer})
+	assert.Nil(t, err)
+	header := &types.Header{
+		Height:           height,
+		ConsensusData:    nonce,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	assert.Nil(t, err)
+	return header.ToArray(), sig
+}
+
+func TestVerifyEquivocation(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	peerPubkey := vbftconfig.PubkeyID(acc.PublicKey)
+
+	var headers, sigs [][]byte
+	for i := 0; i < MIN_EQUIVOCATION_BLOCKS; i++ {
+		header, sig := signedHeader(t, acc, uint32(i+2), 10, uint64(i))
+		headers = append(headers, header)
+		sigs = append(sigs, sig)
+	}
+	height, err := verifyEquivocation(peerPubkey, 1, headers, sigs)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	//endorsements of a block and an empty block, and commit of another block
+	_, err = verifyEquivocation(peerPubkey, 1, headers[:3], sigs[:3])
+	assert.NotNil(t, err)
+	_, err = verifyEquivocation(peerPubkey, 1, append(headers[:3:3], headers[0]), append(sigs[:3:3], sigs[0]))
+	assert.NotNil(t, err)
+	_, err = verifyEquivocation(peerPubkey, 1, headers, sigs[:3])
+	assert.NotNil(t, err)
+
+	//blocks proposed by peer itself, which may be proposed again after restart
+	_, err = verifyEquivocation(peerPubkey, 2, headers, sigs)
+	assert.NotNil(t, err)
+
+	header, sig := signedHeader(t, acc, 5, 11, 3)
+	_, err = verifyEquivocation(peerPubkey, 1, append(headers[:3:3], header), append(sigs[:3:3], sig))
+	assert.NotNil(t, err)
+
+	other := account.NewAccount("SHA256withECDSA")
+	header, sig = signedHeader(t, other, 5, 10, 3)
+	_, err = verifyEquivocation(peerPubkey, 1, append(headers[:3:3], header), append(sigs[:3:3], sig))
+	assert.NotNil(t, err)
+}
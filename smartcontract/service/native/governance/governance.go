@@ -75,6 +75,11 @@ const (
 	ADD_INIT_POS                     = "addInitPos"
 	REDUCE_INIT_POS                  = "reduceInitPos"
 	SET_PROMISE_POS                  = "setPromisePos"
+	CREATE_PROPOSAL                  = "createProposal"
+	VOTE_PROPOSAL                    = "voteProposal"
+	REPORT_EQUIVOCATION              = "reportEquivocation"
+	GET_PEER_POOL_INFO               = "getPeerPoolInfo"
+	GET_STAKE_INFO                   = "getStakeInfo"
 
 	//key prefix
 	GLOBAL_PARAM      = "globalParam"
@@ -93,6 +98,12 @@ const (
 	SPLIT_FEE_ADDRESS = "splitFeeAddress"
 	PROMISE_POS       = "promisePos"
 	PRE_CONFIG        = "preConfig"
+	PROPOSAL_CONFIG   = "proposalConfig"
+	PROPOSAL_COUNT    = "proposalCount"
+	PROPOSAL          = "proposal"
+	PROPOSAL_VOTE     = "proposalVote"
+	PROPOSAL_SCHEDULE = "proposalSchedule"
+	EQUIVOCATION      = "equivocation"
 
 	//global
 	PRECISE           = 1000000
@@ -135,6 +146,11 @@ func RegisterGovernanceContract(native *native.NativeService) {
 	native.Register(WITHDRAW_FEE, WithdrawFee)
 	native.Register(ADD_INIT_POS, AddInitPos)
 	native.Register(REDUCE_INIT_POS, ReduceInitPos)
+	native.Register(CREATE_PROPOSAL, CreateProposal)
+	native.Register(VOTE_PROPOSAL, VoteProposal)
+	native.Register(REPORT_EQUIVOCATION, ReportEquivocation)
+	native.Register(GET_PEER_POOL_INFO, GetPeerPoolInfo)
+	native.Register(GET_STAKE_INFO, GetStakeInfo)
 
 	native.Register(INIT_CONFIG, InitConfig)
 	native.Register(APPROVE_CANDIDATE, ApproveCandidate)
@@ -617,32 +633,11 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	}
 	commit := false
 	for _, peerPubkey := range params.PeerPubkeyList {
-		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
+		consensus, err := blackPeer(native, contract, peerPoolMap, peerPubkey)
 		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
-		}
-		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
-		if !ok {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
-		}
-
-		blackListItem := &BlackListItem{
-			PeerPubkey: peerPoolItem.PeerPubkey,
-			Address:    peerPoolItem.Address,
-			InitPos:    peerPoolItem.InitPos,
-		}
-		bf := new(bytes.Buffer)
-		if err := blackListItem.Serialize(bf); err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("serialize, serialize blackListItem error: %v", err)
+			return utils.BYTE_FALSE, fmt.Errorf("blackNode, %v", err)
 		}
-		//put peer into black list
-		native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(bf.Bytes()))
-		//change peerPool status
-		if peerPoolItem.Status == ConsensusStatus {
-			commit = true
-		}
-		peerPoolItem.Status = BlackStatus
-		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+		commit = commit || consensus
 	}
 	err = putPeerPoolMap(native, contract, view, peerPoolMap)
 	if err != nil {
@@ -1016,26 +1011,34 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	//get globalParam
-	globalParam, err := getGlobalParam(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
-	}
-
 	configuration := new(Configuration)
 	if err := configuration.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize configuration error: %v", err)
 	}
+	if err := updateConfig(native, contract, configuration); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//check configuration and put it as preConfig, shared by updateConfig and proposal
+func updateConfig(native *native.NativeService, contract common.Address, configuration *Configuration) error {
+	//get globalParam
+	globalParam, err := getGlobalParam(native, contract)
+	if err != nil {
+		return fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+	}
 
 	//get current view
 	view, err := GetView(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+		return fmt.Errorf("getView, get view error: %v", err)
 	}
 	//get peerPoolMap
 	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
 	}
 	candidateNum := 0
 	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
@@ -1046,31 +1049,31 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 
 	//check the configuration
 	if configuration.C == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. C can not be 0 in config")
+		return fmt.Errorf("updateConfig. C can not be 0 in config")
 	}
 	if int(configuration.K) > candidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
+		return fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
 	}
 	if configuration.L < 16*configuration.K || configuration.L%configuration.K != 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
+		return fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
 	}
 	if configuration.K < 2*configuration.C+1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
+		return fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
 	}
 	if 4*configuration.K > globalParam.CandidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
+		return fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
 	}
 	if configuration.N < configuration.K || configuration.K < 7 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. config not match N >= K >= 7")
+		return fmt.Errorf("updateConfig. config not match N >= K >= 7")
 	}
 	if configuration.BlockMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
+		return fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
 	}
 	if configuration.HashMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
+		return fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
 	}
 	if configuration.PeerHandshakeTimeout < 10 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
+		return fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
 	}
 
 	preConfig := &PreConfig{
@@ -1079,10 +1082,9 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	}
 	err = putPreConfig(native, contract, preConfig)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putPreConfig, put preConfig error: %v", err)
+		return fmt.Errorf("putPreConfig, put preConfig error: %v", err)
 	}
-
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update global params of this governance contract
@@ -1101,42 +1103,49 @@ func UpdateGlobalParam(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	// get config
-	config, err := getConfig(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
-	}
-
 	globalParam := new(GlobalParam)
 	if err := globalParam.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam error: %v", err)
 	}
+	if err := updateGlobalParam(native, contract, globalParam); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//check and put globalParam, shared by updateGlobalParam and proposal
+func updateGlobalParam(native *native.NativeService, contract common.Address, globalParam *GlobalParam) error {
+	// get config
+	config, err := getConfig(native, contract)
+	if err != nil {
+		return fmt.Errorf("getConfig, get config error: %v", err)
+	}
 
 	//check the globalParam
 	if (globalParam.A + globalParam.B) != 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. A + B must equal to 100")
+		return fmt.Errorf("updateGlobalParam. A + B must equal to 100")
 	}
 	if globalParam.Yita == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Yita must > 0")
+		return fmt.Errorf("updateGlobalParam. Yita must > 0")
 	}
 	if globalParam.Penalty > 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Penalty must <= 100")
+		return fmt.Errorf("updateGlobalParam. Penalty must <= 100")
 	}
 	if globalParam.PosLimit < 1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
+		return fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
 	}
 	if globalParam.CandidateNum < 4*config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
+		return fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
 	}
 	if globalParam.CandidateFee != 0 && globalParam.CandidateFee < MIN_CANDIDATE_FEE {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
+		return fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
 	}
 	err = putGlobalParam(native, contract, globalParam)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
+		return fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
 	}
-
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update global params of this governance contract
@@ -1162,26 +1171,33 @@ func UpdateGlobalParam2(native *native.NativeService) ([]byte, error) {
 	if err := globalParam2.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam2 error: %v", err)
 	}
+	if err := updateGlobalParam2(native, contract, globalParam2); err != nil {
+		return utils.BYTE_FALSE, err
+	}
 
+	return utils.BYTE_TRUE, nil
+}
+
+//check and put globalParam2, shared by updateGlobalParam2 and proposal
+func updateGlobalParam2(native *native.NativeService, contract common.Address, globalParam2 *GlobalParam2) error {
 	//check the globalParam
 	if globalParam2.MinAuthorizePos == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("globalParam2.MinAuthorizePos can not be 0")
+		return fmt.Errorf("globalParam2.MinAuthorizePos can not be 0")
 	}
 	// get config
 	config, err := getConfig(native, contract)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
+		return fmt.Errorf("getConfig, get config error: %v", err)
 	}
 	if globalParam2.CandidateFeeSplitNum < config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
+		return fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
 	}
 
 	err = putGlobalParam2(native, contract, globalParam2)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
+		return fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
 	}
-
-	return utils.BYTE_TRUE, nil
+	return nil
 }
 
 //Update split curve
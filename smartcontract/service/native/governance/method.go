@@ -305,6 +305,35 @@ func normalQuit(native *native.NativeService, contract common.Address, peerPoolI
 	return nil
 }
 
+//put peer into black list, returns true if peer is consensus node, then commitDpos is needed
+func blackPeer(native *native.NativeService, contract common.Address, peerPoolMap *PeerPoolMap, peerPubkey string) (bool, error) {
+	peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
+	if err != nil {
+		return false, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
+	if !ok {
+		return false, fmt.Errorf("peerPubkey is not in peerPoolMap")
+	}
+
+	blackListItem := &BlackListItem{
+		PeerPubkey: peerPoolItem.PeerPubkey,
+		Address:    peerPoolItem.Address,
+		InitPos:    peerPoolItem.InitPos,
+	}
+	bf := new(bytes.Buffer)
+	if err := blackListItem.Serialize(bf); err != nil {
+		return false, fmt.Errorf("serialize, serialize blackListItem error: %v", err)
+	}
+	//put peer into black list
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(bf.Bytes()))
+	//change peerPool status
+	consensus := peerPoolItem.Status == ConsensusStatus
+	peerPoolItem.Status = BlackStatus
+	peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+	return consensus, nil
+}
+
 func blackQuit(native *native.NativeService, contract common.Address, peerPoolItem *PeerPoolItem) error {
 	// onx transfer to trigger unboundoxg
 	err := appCallTransferOnx(native, utils.GovernanceContractAddress, utils.GovernanceContractAddress, peerPoolItem.InitPos)
@@ -803,31 +832,50 @@ func executeSplit(native *native.NativeService, contract common.Address, view ui
 
 func executeSplit2(native *native.NativeService, contract common.Address, view uint32) (uint64, error) {
 	var splitSum uint64 = 0
+	nodeSplits, err := calcNodeSplit2(native, contract, view)
+	if err != nil {
+		return splitSum, err
+	}
+	for _, nodeSplit := range nodeSplits {
+		err = splitNodeFee(native, contract, nodeSplit.PeerPubkey, nodeSplit.Address, nodeSplit.IfConsensus,
+			nodeSplit.TotalPos, nodeSplit.Amount)
+		if err != nil {
+			return splitSum, fmt.Errorf("executeSplit2, splitNodeFee error: %v", err)
+		}
+		splitSum += nodeSplit.Amount
+	}
+
+	return splitSum, nil
+}
+
+//calculate fee split amount of each consensus and candidate node, based on peer pool of view-1
+func calcNodeSplit2(native *native.NativeService, contract common.Address, view uint32) ([]*NodeSplitInfo, error) {
+	nodeSplits := []*NodeSplitInfo{}
 	// get config
 	config, err := getConfig(native, contract)
 	if err != nil {
-		return splitSum, fmt.Errorf("getConfig, get config error: %v", err)
+		return nil, fmt.Errorf("getConfig, get config error: %v", err)
 	}
 
 	//get peerPoolMap
 	peerPoolMap, err := GetPeerPoolMap(native, contract, view-1)
 	if err != nil {
-		return splitSum, fmt.Errorf("executeSplit, get peerPoolMap error: %v", err)
+		return nil, fmt.Errorf("executeSplit, get peerPoolMap error: %v", err)
 	}
 
 	balance, err := getOxgBalance(native, utils.GovernanceContractAddress)
 	if err != nil {
-		return splitSum, fmt.Errorf("executeSplit, getOxgBalance error: %v", err)
+		return nil, fmt.Errorf("executeSplit, getOxgBalance error: %v", err)
 	}
 	splitFee, err := getSplitFee(native, contract)
 	if err != nil {
-		return splitSum, fmt.Errorf("getSplitFee, getSplitFee error: %v", err)
+		return nil, fmt.Errorf("getSplitFee, getSplitFee error: %v", err)
 	}
 	income := balance - splitFee
 	//get globalParam
 	globalParam, err := getGlobalParam(native, contract)
 	if err != nil {
-		return splitSum, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+		return nil, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
 	}
 
 	peersCandidate := []*CandidateSplitInfo{}
@@ -861,30 +909,31 @@ func executeSplit2(native *native.NativeService, contract common.Address, view u
 	}
 	// if sum = 0, means consensus peer in config, do not split
 	if sum < uint64(config.K) {
-		return splitSum, nil
+		return nodeSplits, nil
 	}
 	avg := sum / uint64(config.K)
 	var sumS uint64
 	for i := 0; i < int(config.K); i++ {
 		peersCandidate[i].S, err = splitCurve(native, contract, peersCandidate[i].Stake, avg, uint64(globalParam.Yita))
 		if err != nil {
-			return splitSum, fmt.Errorf("splitCurve, calculate splitCurve error: %v", err)
+			return nil, fmt.Errorf("splitCurve, calculate splitCurve error: %v", err)
 		}
 		sumS += peersCandidate[i].S
 	}
 	if sumS == 0 {
-		return splitSum, fmt.Errorf("executeSplit, sumS is 0")
+		return nil, fmt.Errorf("executeSplit, sumS is 0")
 	}
 
 	//fee split of consensus peer
 	for i := 0; i < int(config.K); i++ {
 		nodeAmount := income * uint64(globalParam.A) / 100 * peersCandidate[i].S / sumS
-		err = splitNodeFee(native, contract, peersCandidate[i].PeerPubkey, peersCandidate[i].Address, true,
-			peerPoolMap.PeerPoolMap[peersCandidate[i].PeerPubkey].TotalPos, nodeAmount)
-		if err != nil {
-			return splitSum, fmt.Errorf("executeSplit2, splitNodeFee error: %v", err)
-		}
-		splitSum += nodeAmount
+		nodeSplits = append(nodeSplits, &NodeSplitInfo{
+			PeerPubkey:  peersCandidate[i].PeerPubkey,
+			Address:     peersCandidate[i].Address,
+			IfConsensus: true,
+			TotalPos:    peerPoolMap.PeerPoolMap[peersCandidate[i].PeerPubkey].TotalPos,
+			Amount:      nodeAmount,
+		})
 	}
 
 	//fee split of candidate peer
@@ -892,7 +941,7 @@ func executeSplit2(native *native.NativeService, contract common.Address, view u
 	//get globalParam2
 	globalParam2, err := getGlobalParam2(native, contract)
 	if err != nil {
-		return splitSum, fmt.Errorf("getGlobalParam2, getGlobalParam2 error: %v", err)
+		return nil, fmt.Errorf("getGlobalParam2, getGlobalParam2 error: %v", err)
 	}
 	var length int
 	if int(globalParam2.CandidateFeeSplitNum) >= len(peersCandidate) {
@@ -905,29 +954,32 @@ func executeSplit2(native *native.NativeService, contract common.Address, view u
 		sum += peersCandidate[i].Stake
 	}
 	if sum == 0 {
-		return splitSum, nil
+		return nodeSplits, nil
 	}
 	for i := int(config.K); i < length; i++ {
 		nodeAmount := income * uint64(globalParam.B) / 100 * peersCandidate[i].Stake / sum
-		err = splitNodeFee(native, contract, peersCandidate[i].PeerPubkey, peersCandidate[i].Address, false,
-			peerPoolMap.PeerPoolMap[peersCandidate[i].PeerPubkey].TotalPos, nodeAmount)
-		if err != nil {
-			return splitSum, fmt.Errorf("executeSplit2, splitNodeFee error: %v", err)
-		}
-		splitSum += nodeAmount
+		nodeSplits = append(nodeSplits, &NodeSplitInfo{
+			PeerPubkey:  peersCandidate[i].PeerPubkey,
+			Address:     peersCandidate[i].Address,
+			IfConsensus: false,
+			TotalPos:    peerPoolMap.PeerPoolMap[peersCandidate[i].PeerPubkey].TotalPos,
+			Amount:      nodeAmount,
+		})
 	}
 
-	return splitSum, nil
+	return nodeSplits, nil
 }
 
-func executeAddressSplit(native *native.NativeService, contract common.Address, authorizeInfo *AuthorizeInfo, ifConsensus bool, totalPos uint64, totalAmount uint64) (uint64, error) {
-	var validatePos uint64
+//pos of authorizer which takes part in fee split of peer
+func validatePosOf(authorizeInfo *AuthorizeInfo, ifConsensus bool) uint64 {
 	if ifConsensus {
-		validatePos = authorizeInfo.ConsensusPos + authorizeInfo.WithdrawConsensusPos
-	} else {
-		validatePos = authorizeInfo.CandidatePos + authorizeInfo.WithdrawCandidatePos
+		return authorizeInfo.ConsensusPos + authorizeInfo.WithdrawConsensusPos
 	}
+	return authorizeInfo.CandidatePos + authorizeInfo.WithdrawCandidatePos
+}
 
+func executeAddressSplit(native *native.NativeService, contract common.Address, authorizeInfo *AuthorizeInfo, ifConsensus bool, totalPos uint64, totalAmount uint64) (uint64, error) {
+	validatePos := validatePosOf(authorizeInfo, ifConsensus)
 	if validatePos == 0 {
 		return 0, nil
 	}
@@ -992,3 +992,253 @@ func (this *ChangeInitPosParam) Deserialize(r io.Reader) error {
 	this.Pos = uint32(pos)
 	return nil
 }
+
+type ProposalConfig struct {
+	VotingPeriod     uint32 //blocks proposal can be voted after created
+	MinExecuteDelay  uint32 //min blocks between end of voting and execution
+	Quorum           uint32 //min percent of total stake voted for proposal
+	Threshold        uint32 //min percent of approved stake in voted stake
+	MinProposerStake uint64 //min stake of proposer
+}
+
+func (this *ProposalConfig) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(this.VotingPeriod)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize votingPeriod error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.MinExecuteDelay)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize minExecuteDelay error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.Quorum)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize quorum error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.Threshold)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize threshold error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.MinProposerStake); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize minProposerStake error: %v", err)
+	}
+	return nil
+}
+
+func (this *ProposalConfig) Deserialize(r io.Reader) error {
+	votingPeriod, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize votingPeriod error: %v", err)
+	}
+	minExecuteDelay, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize minExecuteDelay error: %v", err)
+	}
+	quorum, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize quorum error: %v", err)
+	}
+	threshold, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize threshold error: %v", err)
+	}
+	minProposerStake, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize minProposerStake error: %v", err)
+	}
+	if votingPeriod > math.MaxUint32 {
+		return fmt.Errorf("votingPeriod larger than max of uint32")
+	}
+	if minExecuteDelay > math.MaxUint32 {
+		return fmt.Errorf("minExecuteDelay larger than max of uint32")
+	}
+	if quorum > math.MaxUint32 {
+		return fmt.Errorf("quorum larger than max of uint32")
+	}
+	if threshold > math.MaxUint32 {
+		return fmt.Errorf("threshold larger than max of uint32")
+	}
+	this.VotingPeriod = uint32(votingPeriod)
+	this.MinExecuteDelay = uint32(minExecuteDelay)
+	this.Quorum = uint32(quorum)
+	this.Threshold = uint32(threshold)
+	this.MinProposerStake = minProposerStake
+	return nil
+}
+
+type CreateProposalParam struct {
+	Proposer      common.Address
+	Kind          uint8
+	Payload       []byte
+	Description   string
+	ExecuteHeight uint32
+}
+
+func (this *CreateProposalParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Proposer[:]); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize proposer error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.Kind)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize kind error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Payload); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize payload error: %v", err)
+	}
+	if err := serialization.WriteString(w, this.Description); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize description error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(this.ExecuteHeight)); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize executeHeight error: %v", err)
+	}
+	return nil
+}
+
+func (this *CreateProposalParam) Deserialize(r io.Reader) error {
+	proposer, err := utils.ReadAddress(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize proposer error: %v", err)
+	}
+	kind, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize kind error: %v", err)
+	}
+	if kind > math.MaxUint8 {
+		return fmt.Errorf("kind larger than max of uint8")
+	}
+	payload, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize payload error: %v", err)
+	}
+	description, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize description error: %v", err)
+	}
+	executeHeight, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize executeHeight error: %v", err)
+	}
+	if executeHeight > math.MaxUint32 {
+		return fmt.Errorf("executeHeight larger than max of uint32")
+	}
+	this.Proposer = proposer
+	this.Kind = uint8(kind)
+	this.Payload = payload
+	this.Description = description
+	this.ExecuteHeight = uint32(executeHeight)
+	return nil
+}
+
+type VoteProposalParam struct {
+	Voter   common.Address
+	Id      uint64
+	Approve bool
+}
+
+func (this *VoteProposalParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Voter[:]); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize voter error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, this.Id); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize id error: %v", err)
+	}
+	if err := serialization.WriteBool(w, this.Approve); err != nil {
+		return fmt.Errorf("serialization.WriteBool, serialize approve error: %v", err)
+	}
+	return nil
+}
+
+func (this *VoteProposalParam) Deserialize(r io.Reader) error {
+	voter, err := utils.ReadAddress(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize voter error: %v", err)
+	}
+	id, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	approve, err := serialization.ReadBool(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	this.Voter = voter
+	this.Id = id
+	this.Approve = approve
+	return nil
+}
+
+type StakeInfoParam struct {
+	Address common.Address
+}
+
+func (this *StakeInfoParam) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.Address[:]); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize address error: %v", err)
+	}
+	return nil
+}
+
+func (this *StakeInfoParam) Deserialize(r io.Reader) error {
+	address, err := utils.ReadAddress(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize address error: %v", err)
+	}
+	this.Address = address
+	return nil
+}
+
+type ReportEquivocationParam struct {
+	PeerPubkey string
+	Headers    [][]byte //headers of blocks at the same height proposed by other peers
+	Sigs       [][]byte //signature of peer on each block, from its endorsements or commits
+}
+
+func (this *ReportEquivocationParam) Serialize(w io.Writer) error {
+	if len(this.Headers) > MAX_EQUIVOCATION_BLOCKS {
+		return fmt.Errorf("length of headers > %d", MAX_EQUIVOCATION_BLOCKS)
+	}
+	if len(this.Sigs) != len(this.Headers) {
+		return fmt.Errorf("length of sigs is not equal to length of headers")
+	}
+	if err := serialization.WriteString(w, this.PeerPubkey); err != nil {
+		return fmt.Errorf("serialization.WriteString, request peerPubkey error: %v", err)
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.Headers))); err != nil {
+		return fmt.Errorf("utils.WriteVarUint, serialize headers length error: %v", err)
+	}
+	for i, header := range this.Headers {
+		if err := serialization.WriteVarBytes(w, header); err != nil {
+			return fmt.Errorf("serialization.WriteVarBytes, serialize header error: %v", err)
+		}
+		if err := serialization.WriteVarBytes(w, this.Sigs[i]); err != nil {
+			return fmt.Errorf("serialization.WriteVarBytes, serialize sig error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *ReportEquivocationParam) Deserialize(r io.Reader) error {
+	peerPubkey, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize peerPubkey error: %v", err)
+	}
+	n, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize headers length error: %v", err)
+	}
+	if n > MAX_EQUIVOCATION_BLOCKS {
+		return fmt.Errorf("length of headers > %d", MAX_EQUIVOCATION_BLOCKS)
+	}
+	headers := make([][]byte, 0, n)
+	sigs := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		header, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadVarBytes, deserialize header error: %v", err)
+		}
+		sig, err := serialization.ReadVarBytes(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadVarBytes, deserialize sig error: %v", err)
+		}
+		headers = append(headers, header)
+		sigs = append(sigs, sig)
+	}
+	this.PeerPubkey = peerPubkey
+	this.Headers = headers
+	this.Sigs = sigs
+	return nil
+}
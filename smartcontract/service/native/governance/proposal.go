@@ -0,0 +1,465 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+)
+
+const (
+	//proposal kind
+	PROPOSAL_GLOBAL_PARAM      uint8 = iota //params of global params contract
+	PROPOSAL_VBFT_CONFIG                    //vbft config, the same as updateConfig
+	PROPOSAL_GOVERNANCE_PARAM               //global param of governance, the same as updateGlobalParam
+	PROPOSAL_GOVERNANCE_PARAM2              //global param2 of governance, the same as updateGlobalParam2
+	PROPOSAL_PROPOSAL_CONFIG                //config of proposal voting
+	PROPOSAL_GAS_SCHEDULE                   //gas schedule of global params contract, the same as setGasSchedule
+)
+
+const (
+	//proposal status
+	PROPOSAL_PENDING uint8 = iota
+	PROPOSAL_EXECUTED
+	PROPOSAL_REJECTED
+	PROPOSAL_FAILED
+)
+
+const (
+	//default proposal config
+	DEFAULT_VOTING_PERIOD      = 86400
+	DEFAULT_MIN_EXECUTE_DELAY  = 17280
+	DEFAULT_QUORUM             = 30
+	DEFAULT_THRESHOLD          = 67
+	DEFAULT_MIN_PROPOSER_STAKE = 10000
+)
+
+const (
+	//bound work of executing proposals, which is done for free before transactions of block
+	MAX_PROPOSALS_PER_HEIGHT = 16   //proposals scheduled at one execute height
+	MAX_PROPOSAL_VOTERS      = 1024 //voters of one proposal
+)
+
+var ProposalKindNames = map[uint8]string{
+	PROPOSAL_GLOBAL_PARAM:      "param",
+	PROPOSAL_VBFT_CONFIG:       "config",
+	PROPOSAL_GOVERNANCE_PARAM:  "globalparam",
+	PROPOSAL_GOVERNANCE_PARAM2: "globalparam2",
+	PROPOSAL_PROPOSAL_CONFIG:   "proposalconfig",
+	PROPOSAL_GAS_SCHEDULE:      "gasschedule",
+}
+
+var ProposalStatusNames = map[uint8]string{
+	PROPOSAL_PENDING:  "pending",
+	PROPOSAL_EXECUTED: "executed",
+	PROPOSAL_REJECTED: "rejected",
+	PROPOSAL_FAILED:   "failed",
+}
+
+//Create proposal voted by stakers, it is executed at execute height if passed
+func CreateProposal(native *native.NativeService) ([]byte, error) {
+	params := new(CreateProposalParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize createProposalParam error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Proposer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, checkWitness error: %v", err)
+	}
+
+	//check payload
+	payload, err := decodeProposalPayload(params.Kind, params.Payload)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, invalid payload: %v", err)
+	}
+	if schedule, ok := payload.(*global_params.GasSchedule); ok && schedule.ActivationHeight <= params.ExecuteHeight {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, activation height of gas schedule must > execute height %d",
+			params.ExecuteHeight)
+	}
+
+	proposalConfig, err := getProposalConfig(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalConfig, get proposalConfig error: %v", err)
+	}
+	weight, err := getVoteWeight(native, contract, params.Proposer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getVoteWeight, get vote weight error: %v", err)
+	}
+	if weight == 0 || weight < proposalConfig.MinProposerStake {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, stake of proposer is not enough")
+	}
+	endHeight := uint64(native.Height) + uint64(proposalConfig.VotingPeriod)
+	if uint64(params.ExecuteHeight) < endHeight+uint64(proposalConfig.MinExecuteDelay) {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, execute height must >= %d",
+			endHeight+uint64(proposalConfig.MinExecuteDelay))
+	}
+
+	count, err := getProposalCount(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalCount, get proposal count error: %v", err)
+	}
+	proposal := &Proposal{
+		Id:            count + 1,
+		Proposer:      params.Proposer,
+		Kind:          params.Kind,
+		Payload:       params.Payload,
+		Description:   params.Description,
+		StartHeight:   native.Height,
+		EndHeight:     uint32(endHeight),
+		ExecuteHeight: params.ExecuteHeight,
+		Status:        PROPOSAL_PENDING,
+	}
+	if err := putProposal(native, contract, proposal); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposal, put proposal error: %v", err)
+	}
+	if err := putProposalCount(native, contract, proposal.Id); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposalCount, put proposal count error: %v", err)
+	}
+	scheduled, err := getScheduledProposals(native, contract, proposal.ExecuteHeight)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getScheduledProposals, get scheduled proposals error: %v", err)
+	}
+	if len(scheduled.Ids) >= MAX_PROPOSALS_PER_HEIGHT {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, %d proposals are scheduled at height %d already",
+			MAX_PROPOSALS_PER_HEIGHT, proposal.ExecuteHeight)
+	}
+	scheduled.Ids = append(scheduled.Ids, proposal.Id)
+	if err := putScheduledProposals(native, contract, proposal.ExecuteHeight, scheduled); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putScheduledProposals, put scheduled proposals error: %v", err)
+	}
+	notifyProposal(native, contract, CREATE_PROPOSAL, proposal)
+
+	return utils.BYTE_TRUE, nil
+}
+
+//Vote for proposal in voting period with stake of voter, vote again to change the vote
+func VoteProposal(native *native.NativeService) ([]byte, error) {
+	params := new(VoteProposalParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize voteProposalParam error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, checkWitness error: %v", err)
+	}
+
+	proposal, err := getProposal(native, contract, params.Id)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+	}
+	if proposal == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, proposal %d does not exist", params.Id)
+	}
+	if proposal.Status != PROPOSAL_PENDING || native.Height < proposal.StartHeight || native.Height >= proposal.EndHeight {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, proposal %d is not in voting period", params.Id)
+	}
+
+	weight, err := getVoteWeight(native, contract, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getVoteWeight, get vote weight error: %v", err)
+	}
+	if weight == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, voter has no stake")
+	}
+	vote, err := getProposalVote(native, contract, params.Id, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalVote, get proposal vote error: %v", err)
+	}
+	if vote == nil {
+		if len(proposal.Voters) >= MAX_PROPOSAL_VOTERS {
+			return utils.BYTE_FALSE, fmt.Errorf("voteProposal, proposal %d has %d voters already", params.Id,
+				MAX_PROPOSAL_VOTERS)
+		}
+		proposal.Voters = append(proposal.Voters, params.Voter)
+	} else if vote.Approve {
+		proposal.YesWeight = proposal.YesWeight - vote.Weight
+	} else {
+		proposal.NoWeight = proposal.NoWeight - vote.Weight
+	}
+	if params.Approve {
+		proposal.YesWeight = proposal.YesWeight + weight
+	} else {
+		proposal.NoWeight = proposal.NoWeight + weight
+	}
+	vote = &ProposalVote{
+		Voter:   params.Voter,
+		Approve: params.Approve,
+		Weight:  weight,
+	}
+	if err := putProposalVote(native, contract, params.Id, vote); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposalVote, put proposal vote error: %v", err)
+	}
+	if err := putProposal(native, contract, proposal); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putProposal, put proposal error: %v", err)
+	}
+
+	return utils.BYTE_TRUE, nil
+}
+
+//ExecuteProposals tallies and executes proposals scheduled at current height, called by ledger before
+//transactions of each block. Proposal not passed is rejected, and the one failed to apply is marked failed.
+func ExecuteProposals(native *native.NativeService) error {
+	contract := utils.GovernanceContractAddress
+	scheduled, err := getScheduledProposals(native, contract, native.Height)
+	if err != nil {
+		return fmt.Errorf("getScheduledProposals, get scheduled proposals error: %v", err)
+	}
+	if len(scheduled.Ids) == 0 {
+		return nil
+	}
+	proposalConfig, err := getProposalConfig(native, contract)
+	if err != nil {
+		return fmt.Errorf("getProposalConfig, get proposalConfig error: %v", err)
+	}
+	for _, id := range scheduled.Ids {
+		proposal, err := getProposal(native, contract, id)
+		if err != nil {
+			return fmt.Errorf("getProposal, get proposal error: %v", err)
+		}
+		if proposal == nil || proposal.Status != PROPOSAL_PENDING {
+			continue
+		}
+		passed, err := tallyProposal(native, contract, proposalConfig, proposal)
+		if err != nil {
+			return fmt.Errorf("tallyProposal, tally proposal %d error: %v", id, err)
+		}
+		if !passed {
+			proposal.Status = PROPOSAL_REJECTED
+		} else if err := executeProposalRevertible(native, contract, proposal); err != nil {
+			log.Warnf("execute proposal %d error: %v", id, err)
+			proposal.Status = PROPOSAL_FAILED
+		} else {
+			proposal.Status = PROPOSAL_EXECUTED
+		}
+		if err := putProposal(native, contract, proposal); err != nil {
+			return fmt.Errorf("putProposal, put proposal error: %v", err)
+		}
+		notifyProposal(native, contract, ProposalStatusNames[proposal.Status], proposal)
+	}
+	return putScheduledProposals(native, contract, native.Height, &ProposalIds{})
+}
+
+//recount votes with current stake of voters, which can not exceed stake when voted, and check quorum and threshold
+func tallyProposal(native *native.NativeService, contract common.Address, proposalConfig *ProposalConfig,
+	proposal *Proposal) (bool, error) {
+	view, err := GetView(native, contract)
+	if err != nil {
+		return false, fmt.Errorf("getView, get view error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return false, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	var yesWeight, noWeight uint64
+	for _, voter := range proposal.Voters {
+		vote, err := getProposalVote(native, contract, proposal.Id, voter)
+		if err != nil {
+			return false, fmt.Errorf("getProposalVote, get proposal vote error: %v", err)
+		}
+		if vote == nil {
+			continue
+		}
+		weight, err := voteWeightOf(native, contract, peerPoolMap, voter)
+		if err != nil {
+			return false, fmt.Errorf("voteWeightOf, get vote weight error: %v", err)
+		}
+		if weight > vote.Weight {
+			weight = vote.Weight
+		}
+		if vote.Approve {
+			yesWeight = yesWeight + weight
+		} else {
+			noWeight = noWeight + weight
+		}
+	}
+	var totalWeight uint64
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			totalWeight = totalWeight + peerPoolItem.InitPos + peerPoolItem.TotalPos
+		}
+	}
+	proposal.YesWeight = yesWeight
+	proposal.NoWeight = noWeight
+	proposal.TotalWeight = totalWeight
+
+	votedWeight := yesWeight + noWeight
+	if totalWeight == 0 || votedWeight == 0 {
+		return false, nil
+	}
+	if votedWeight*100 < totalWeight*uint64(proposalConfig.Quorum) {
+		return false, nil
+	}
+	return yesWeight*100 >= votedWeight*uint64(proposalConfig.Threshold), nil
+}
+
+//executeProposalRevertible executes proposal on child cache, state changes and notifications of it are discarded
+//if it fails
+func executeProposalRevertible(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	cache, invokeParam, input, notifications := native.CacheDB, native.InvokeParam, native.Input, native.Notifications
+	err := native.ContextRef.Revertible(func(child *storage.CacheDB) error {
+		native.CacheDB = child
+		return executeProposal(native, contract, proposal)
+	})
+	native.CacheDB, native.InvokeParam, native.Input = cache, invokeParam, input
+	if err != nil {
+		native.Notifications = notifications
+	}
+	return err
+}
+
+func executeProposal(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	payload, err := decodeProposalPayload(proposal.Kind, proposal.Payload)
+	if err != nil {
+		return err
+	}
+	switch p := payload.(type) {
+	case global_params.Params:
+		return global_params.ApplyParams(native, p)
+	case *Configuration:
+		return updateConfig(native, contract, p)
+	case *GlobalParam:
+		return updateGlobalParam(native, contract, p)
+	case *GlobalParam2:
+		return updateGlobalParam2(native, contract, p)
+	case *ProposalConfig:
+		return putProposalConfig(native, contract, p)
+	case *global_params.GasSchedule:
+		_, err := native.NativeCall(utils.ParamContractAddress, global_params.SET_GAS_SCHEDULE_NAME, proposal.Payload)
+		return err
+	}
+	return fmt.Errorf("unknown proposal kind %d", proposal.Kind)
+}
+
+//decode payload of proposal by kind
+func decodeProposalPayload(kind uint8, payload []byte) (interface{}, error) {
+	buf := bytes.NewBuffer(payload)
+	switch kind {
+	case PROPOSAL_GLOBAL_PARAM:
+		params := global_params.Params{}
+		if err := params.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize params error: %v", err)
+		}
+		if len(params) == 0 {
+			return nil, fmt.Errorf("params is empty")
+		}
+		return params, nil
+	case PROPOSAL_VBFT_CONFIG:
+		configuration := new(Configuration)
+		if err := configuration.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize configuration error: %v", err)
+		}
+		return configuration, nil
+	case PROPOSAL_GOVERNANCE_PARAM:
+		globalParam := new(GlobalParam)
+		if err := globalParam.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize globalParam error: %v", err)
+		}
+		return globalParam, nil
+	case PROPOSAL_GOVERNANCE_PARAM2:
+		globalParam2 := new(GlobalParam2)
+		if err := globalParam2.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize globalParam2 error: %v", err)
+		}
+		return globalParam2, nil
+	case PROPOSAL_PROPOSAL_CONFIG:
+		proposalConfig := new(ProposalConfig)
+		if err := proposalConfig.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize proposalConfig error: %v", err)
+		}
+		if proposalConfig.VotingPeriod == 0 {
+			return nil, fmt.Errorf("votingPeriod can not be 0")
+		}
+		if proposalConfig.MinProposerStake == 0 {
+			return nil, fmt.Errorf("minProposerStake can not be 0")
+		}
+		if proposalConfig.Quorum == 0 || proposalConfig.Quorum > 100 {
+			return nil, fmt.Errorf("quorum must in (0, 100]")
+		}
+		if proposalConfig.Threshold <= 50 || proposalConfig.Threshold > 100 {
+			return nil, fmt.Errorf("threshold must in (50, 100]")
+		}
+		return proposalConfig, nil
+	case PROPOSAL_GAS_SCHEDULE:
+		schedule := new(global_params.GasSchedule)
+		if err := schedule.Deserialize(buf); err != nil {
+			return nil, fmt.Errorf("deserialize gas schedule error: %v", err)
+		}
+		if len(schedule.Prices) == 0 {
+			return nil, fmt.Errorf("prices of gas schedule is empty")
+		}
+		return schedule, nil
+	}
+	return nil, fmt.Errorf("unknown proposal kind %d", kind)
+}
+
+//get vote weight of address in current view
+func getVoteWeight(native *native.NativeService, contract common.Address, address common.Address) (uint64, error) {
+	view, err := GetView(native, contract)
+	if err != nil {
+		return 0, fmt.Errorf("getView, get view error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return 0, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	return voteWeightOf(native, contract, peerPoolMap, address)
+}
+
+//vote weight is the stake of address in candidate and consensus peers, include initPos of its own peers
+//and pos authorized to peers
+func voteWeightOf(native *native.NativeService, contract common.Address, peerPoolMap *PeerPoolMap,
+	address common.Address) (uint64, error) {
+	var weight uint64
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status != CandidateStatus && peerPoolItem.Status != ConsensusStatus {
+			continue
+		}
+		if peerPoolItem.Address == address {
+			weight = weight + peerPoolItem.InitPos
+		}
+		authorizeInfo, err := getAuthorizeInfo(native, contract, peerPoolItem.PeerPubkey, address)
+		if err != nil {
+			return 0, fmt.Errorf("getAuthorizeInfo, get authorizeInfo error: %v", err)
+		}
+		weight = weight + authorizeInfo.ConsensusPos + authorizeInfo.CandidatePos + authorizeInfo.NewPos
+	}
+	return weight, nil
+}
+
+func notifyProposal(native *native.NativeService, contract common.Address, name string, proposal *Proposal) {
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{name, proposal.Id, proposal.Proposer.ToBase58(), proposal.ExecuteHeight},
+		})
+}
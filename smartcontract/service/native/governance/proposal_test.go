@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+)
+
+func TestProposal_Serialize(t *testing.T) {
+	proposal := &Proposal{
+		Id:            3,
+		Proposer:      common.Address{1},
+		Kind:          PROPOSAL_GOVERNANCE_PARAM2,
+		Payload:       []byte{1, 2, 3},
+		Description:   "raise min authorize pos",
+		StartHeight:   100,
+		EndHeight:     200,
+		ExecuteHeight: 300,
+		Status:        PROPOSAL_EXECUTED,
+		YesWeight:     70,
+		NoWeight:      10,
+		TotalWeight:   100,
+		Voters:        []common.Address{{1}, {2}},
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, proposal.Serialize(bf))
+
+	proposal2 := new(Proposal)
+	assert.Nil(t, proposal2.Deserialize(bf))
+	assert.Equal(t, proposal, proposal2)
+}
+
+func TestDecodeProposalPayload(t *testing.T) {
+	params := global_params.Params{{Key: "gasPrice", Value: "500"}}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, params.Serialize(bf))
+	payload, err := decodeProposalPayload(PROPOSAL_GLOBAL_PARAM, bf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, params, payload)
+
+	proposalConfig := &ProposalConfig{VotingPeriod: 100, Quorum: 30, Threshold: 50}
+	bf = new(bytes.Buffer)
+	assert.Nil(t, proposalConfig.Serialize(bf))
+	_, err = decodeProposalPayload(PROPOSAL_PROPOSAL_CONFIG, bf.Bytes())
+	assert.NotNil(t, err)
+
+	proposalConfig.Threshold = 67
+	bf = new(bytes.Buffer)
+	assert.Nil(t, proposalConfig.Serialize(bf))
+	payload, err = decodeProposalPayload(PROPOSAL_PROPOSAL_CONFIG, bf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, proposalConfig, payload)
+
+	_, err = decodeProposalPayload(PROPOSAL_GOVERNANCE_PARAM, []byte{1})
+	assert.NotNil(t, err)
+	_, err = decodeProposalPayload(PROPOSAL_GAS_SCHEDULE+1, nil)
+	assert.NotNil(t, err)
+}
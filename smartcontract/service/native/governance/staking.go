@@ -0,0 +1,294 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//stake and fee split status of a peer in peer pool
+type PeerStatusInfo struct {
+	Index        uint32
+	PeerPubkey   string
+	Address      common.Address
+	Status       uint8
+	InitPos      uint64
+	TotalPos     uint64
+	MaxAuthorize uint64
+	TPeerCost    uint64
+	T1PeerCost   uint64
+	T2PeerCost   uint64
+	NextSplitFee uint64 //projected fee split to this peer and its authorizers in next commitDpos
+}
+
+type PeerPoolStatus struct {
+	View   uint32
+	Income uint64 //oxg income to be split in next commitDpos
+	Peers  []*PeerStatusInfo
+}
+
+//stake of an address in a peer
+type StakePosition struct {
+	PeerPubkey           string
+	InitPos              uint64 //init pos, only if address is owner of peer
+	ConsensusPos         uint64
+	CandidatePos         uint64
+	NewPos               uint64 //pending, take effect in next epoch
+	WithdrawConsensusPos uint64 //frozen
+	WithdrawCandidatePos uint64 //frozen
+	WithdrawUnfreezePos  uint64 //can be withdrawn
+	NextReward           uint64 //projected fee split in next commitDpos
+}
+
+type StakeInfo struct {
+	Address    common.Address
+	TotalStake uint64
+	SplitFee   uint64 //fee already split to address, can be withdrawn by withdrawFee
+	NextReward uint64 //projected fee split of all peers in next commitDpos
+	Positions  []*StakePosition
+}
+
+//get stake status of all peers in peer pool, with projected fee split of next commitDpos, only in pre-execution
+func GetPeerPoolInfo(native *native.NativeService) ([]byte, error) {
+	if !native.PreExec {
+		return nil, fmt.Errorf("getPeerPoolInfo, only can be pre-executed")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	view, err := GetView(native, contract)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPoolInfo, get view error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPoolInfo, get peerPoolMap error: %v", err)
+	}
+	income, err := getSplitIncome(native, contract)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPoolInfo, %v", err)
+	}
+	nodeSplits, err := projectNodeSplit(native, contract, view)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPoolInfo, projectNodeSplit error: %v", err)
+	}
+	splitFees := make(map[string]uint64, len(nodeSplits))
+	for _, nodeSplit := range nodeSplits {
+		splitFees[nodeSplit.PeerPubkey] = nodeSplit.Amount
+	}
+
+	status := &PeerPoolStatus{
+		View:   view,
+		Income: income,
+	}
+	for _, peerPoolItem := range sortedPeerPoolItems(peerPoolMap) {
+		peerAttributes, err := getPeerAttributes(native, contract, peerPoolItem.PeerPubkey)
+		if err != nil {
+			return nil, fmt.Errorf("getPeerPoolInfo, getPeerAttributes error: %v", err)
+		}
+		status.Peers = append(status.Peers, &PeerStatusInfo{
+			Index:        peerPoolItem.Index,
+			PeerPubkey:   peerPoolItem.PeerPubkey,
+			Address:      peerPoolItem.Address,
+			Status:       uint8(peerPoolItem.Status),
+			InitPos:      peerPoolItem.InitPos,
+			TotalPos:     peerPoolItem.TotalPos,
+			MaxAuthorize: peerAttributes.MaxAuthorize,
+			TPeerCost:    peerAttributes.TPeerCost,
+			T1PeerCost:   peerAttributes.T1PeerCost,
+			T2PeerCost:   peerAttributes.T2PeerCost,
+			NextSplitFee: splitFees[peerPoolItem.PeerPubkey],
+		})
+	}
+	return framework.Encode(status)
+}
+
+//get stake positions of an address in all peers, with projected fee split of next commitDpos, only in pre-execution
+func GetStakeInfo(native *native.NativeService) ([]byte, error) {
+	if !native.PreExec {
+		return nil, fmt.Errorf("getStakeInfo, only can be pre-executed")
+	}
+	params := new(StakeInfoParam)
+	if err := params.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize stakeInfoParam error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	view, err := GetView(native, contract)
+	if err != nil {
+		return nil, fmt.Errorf("getStakeInfo, get view error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return nil, fmt.Errorf("getStakeInfo, get peerPoolMap error: %v", err)
+	}
+	totalStake, err := getTotalStake(native, contract, params.Address)
+	if err != nil {
+		return nil, fmt.Errorf("getStakeInfo, getTotalStake error: %v", err)
+	}
+	splitFeeAddress, err := getSplitFeeAddress(native, contract, params.Address)
+	if err != nil {
+		return nil, fmt.Errorf("getStakeInfo, getSplitFeeAddress error: %v", err)
+	}
+	nodeSplits, err := projectNodeSplit(native, contract, view)
+	if err != nil {
+		return nil, fmt.Errorf("getStakeInfo, projectNodeSplit error: %v", err)
+	}
+	rewards := make(map[string]uint64, len(nodeSplits))
+	for _, nodeSplit := range nodeSplits {
+		rewards[nodeSplit.PeerPubkey], err = projectAddressSplit(native, contract, nodeSplit, params.Address)
+		if err != nil {
+			return nil, fmt.Errorf("getStakeInfo, projectAddressSplit error: %v", err)
+		}
+	}
+
+	info := &StakeInfo{
+		Address:    params.Address,
+		TotalStake: totalStake.Stake,
+		SplitFee:   splitFeeAddress.Amount,
+	}
+	for _, peerPoolItem := range sortedPeerPoolItems(peerPoolMap) {
+		authorizeInfo, err := getAuthorizeInfo(native, contract, peerPoolItem.PeerPubkey, params.Address)
+		if err != nil {
+			return nil, fmt.Errorf("getStakeInfo, getAuthorizeInfo error: %v", err)
+		}
+		position := &StakePosition{
+			PeerPubkey:           peerPoolItem.PeerPubkey,
+			ConsensusPos:         authorizeInfo.ConsensusPos,
+			CandidatePos:         authorizeInfo.CandidatePos,
+			NewPos:               authorizeInfo.NewPos,
+			WithdrawConsensusPos: authorizeInfo.WithdrawConsensusPos,
+			WithdrawCandidatePos: authorizeInfo.WithdrawCandidatePos,
+			WithdrawUnfreezePos:  authorizeInfo.WithdrawUnfreezePos,
+			NextReward:           rewards[peerPoolItem.PeerPubkey],
+		}
+		if peerPoolItem.Address == params.Address {
+			position.InitPos = peerPoolItem.InitPos
+		}
+		if position.isEmpty() {
+			continue
+		}
+		info.NextReward += position.NextReward
+		info.Positions = append(info.Positions, position)
+	}
+	return framework.Encode(info)
+}
+
+func (this *StakePosition) isEmpty() bool {
+	return this.InitPos == 0 && this.ConsensusPos == 0 && this.CandidatePos == 0 && this.NewPos == 0 &&
+		this.WithdrawConsensusPos == 0 && this.WithdrawCandidatePos == 0 && this.WithdrawUnfreezePos == 0 &&
+		this.NextReward == 0
+}
+
+//oxg income to be split in next commitDpos
+func getSplitIncome(native *native.NativeService, contract common.Address) (uint64, error) {
+	balance, err := getOxgBalance(native, utils.GovernanceContractAddress)
+	if err != nil {
+		return 0, fmt.Errorf("getOxgBalance error: %v", err)
+	}
+	splitFee, err := getSplitFee(native, contract)
+	if err != nil {
+		return 0, fmt.Errorf("getSplitFee error: %v", err)
+	}
+	return balance - splitFee, nil
+}
+
+//projected fee split of nodes in next commitDpos, empty if candidate and consensus peers are less than K
+func projectNodeSplit(native *native.NativeService, contract common.Address, view uint32) ([]*NodeSplitInfo, error) {
+	config, err := getConfig(native, contract)
+	if err != nil {
+		return nil, fmt.Errorf("getConfig, get config error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view-1)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	var num uint32
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			num++
+		}
+	}
+	if num < config.K {
+		return nil, nil
+	}
+	return calcNodeSplit2(native, contract, view)
+}
+
+//fee split of address from a node, calculated the same way as splitNodeFee without writing storage
+func projectAddressSplit(native *native.NativeService, contract common.Address, nodeSplit *NodeSplitInfo,
+	address common.Address) (uint64, error) {
+	peerPubkeyPrefix, err := hex.DecodeString(nodeSplit.PeerPubkey)
+	if err != nil {
+		return 0, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	peerCost, err := getPeerCost(native, contract, nodeSplit.PeerPubkey)
+	if err != nil {
+		return 0, fmt.Errorf("getPeerCost, getPeerCost error: %v", err)
+	}
+	amount := nodeSplit.Amount * (100 - peerCost) / 100
+	var sumAmount, addressAmount uint64
+	iter := native.CacheDB.NewIterator(utils.ConcatKey(contract, AUTHORIZE_INFO_POOL, peerPubkeyPrefix))
+	defer iter.Release()
+	for has := iter.First(); has; has = iter.Next() {
+		authorizeInfoStore, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return 0, fmt.Errorf("authorizeInfoStore is not available!:%v", err)
+		}
+		var authorizeInfo AuthorizeInfo
+		if err := authorizeInfo.Deserialize(bytes.NewBuffer(authorizeInfoStore)); err != nil {
+			return 0, fmt.Errorf("deserialize, deserialize authorizeInfo error: %v", err)
+		}
+		validatePos := validatePosOf(&authorizeInfo, nodeSplit.IfConsensus)
+		if validatePos == 0 {
+			continue
+		}
+		splitAmount := validatePos * amount / nodeSplit.TotalPos
+		sumAmount += splitAmount
+		if authorizeInfo.Address == address {
+			addressAmount += splitAmount
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if nodeSplit.Address == address {
+		addressAmount += nodeSplit.Amount - sumAmount
+	}
+	return addressAmount, nil
+}
+
+func sortedPeerPoolItems(peerPoolMap *PeerPoolMap) []*PeerPoolItem {
+	peerPoolItems := make([]*PeerPoolItem, 0, len(peerPoolMap.PeerPoolMap))
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		peerPoolItems = append(peerPoolItems, peerPoolItem)
+	}
+	sort.Slice(peerPoolItems, func(i, j int) bool {
+		return peerPoolItems[i].Index < peerPoolItems[j].Index
+	})
+	return peerPoolItems
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStakePosition_IsEmpty(t *testing.T) {
+	position := &StakePosition{PeerPubkey: "02a1b2"}
+	assert.True(t, position.isEmpty())
+	position.WithdrawUnfreezePos = 1
+	assert.False(t, position.isEmpty())
+}
+
+func TestValidatePosOf(t *testing.T) {
+	authorizeInfo := &AuthorizeInfo{
+		ConsensusPos:         100,
+		CandidatePos:         50,
+		WithdrawConsensusPos: 10,
+		WithdrawCandidatePos: 5,
+	}
+	assert.Equal(t, uint64(110), validatePosOf(authorizeInfo, true))
+	assert.Equal(t, uint64(55), validatePosOf(authorizeInfo, false))
+}
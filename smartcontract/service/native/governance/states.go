@@ -426,6 +426,14 @@ type CandidateSplitInfo struct {
 	S          uint64 //fee split weight of this peer
 }
 
+type NodeSplitInfo struct {
+	PeerPubkey  string
+	Address     common.Address
+	IfConsensus bool
+	TotalPos    uint64 //total authorize pos of this peer
+	Amount      uint64 //fee split to this peer and its authorizers
+}
+
 type PeerAttributes struct {
 	PeerPubkey   string
 	MaxAuthorize uint64 //max authorzie pos this peer can receive(number of onx), set by peer owner
@@ -547,3 +555,215 @@ func (this *SplitFeeAddress) Deserialize(r io.Reader) error {
 	this.Amount = amount
 	return nil
 }
+
+type Proposal struct { //table record proposal voted by stakers
+	Id            uint64
+	Proposer      common.Address
+	Kind          uint8  //kind of payload, PROPOSAL_*
+	Payload       []byte //serialized params applied when executed
+	Description   string
+	StartHeight   uint32 //voting starts at this height
+	EndHeight     uint32 //voting ends before this height
+	ExecuteHeight uint32 //height proposal is tallied and executed
+	Status        uint8
+	YesWeight     uint64 //stake approved, recounted when executed
+	NoWeight      uint64 //stake disapproved, recounted when executed
+	TotalWeight   uint64 //total stake when executed
+	Voters        []common.Address
+}
+
+func (this *Proposal) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint64(w, this.Id); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize id error: %v", err)
+	}
+	if err := this.Proposer.Serialize(w); err != nil {
+		return fmt.Errorf("address.Serialize, serialize proposer error: %v", err)
+	}
+	if err := serialization.WriteByte(w, this.Kind); err != nil {
+		return fmt.Errorf("serialization.WriteByte, serialize kind error: %v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Payload); err != nil {
+		return fmt.Errorf("serialization.WriteVarBytes, serialize payload error: %v", err)
+	}
+	if err := serialization.WriteString(w, this.Description); err != nil {
+		return fmt.Errorf("serialization.WriteString, serialize description error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.StartHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize startHeight error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.EndHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize endHeight error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, this.ExecuteHeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize executeHeight error: %v", err)
+	}
+	if err := serialization.WriteByte(w, this.Status); err != nil {
+		return fmt.Errorf("serialization.WriteByte, serialize status error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.YesWeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize yesWeight error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.NoWeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize noWeight error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.TotalWeight); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize totalWeight error: %v", err)
+	}
+	if err := serialization.WriteUint32(w, uint32(len(this.Voters))); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize voters length error: %v", err)
+	}
+	for _, voter := range this.Voters {
+		if err := voter.Serialize(w); err != nil {
+			return fmt.Errorf("address.Serialize, serialize voter error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *Proposal) Deserialize(r io.Reader) error {
+	id, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize id error: %v", err)
+	}
+	proposer := new(common.Address)
+	if err := proposer.Deserialize(r); err != nil {
+		return fmt.Errorf("address.Deserialize, deserialize proposer error: %v", err)
+	}
+	kind, err := serialization.ReadByte(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadByte, deserialize kind error: %v", err)
+	}
+	payload, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize payload error: %v", err)
+	}
+	description, err := serialization.ReadString(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize description error: %v", err)
+	}
+	startHeight, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize startHeight error: %v", err)
+	}
+	endHeight, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize endHeight error: %v", err)
+	}
+	executeHeight, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize executeHeight error: %v", err)
+	}
+	status, err := serialization.ReadByte(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadByte, deserialize status error: %v", err)
+	}
+	yesWeight, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize yesWeight error: %v", err)
+	}
+	noWeight, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize noWeight error: %v", err)
+	}
+	totalWeight, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize totalWeight error: %v", err)
+	}
+	n, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize voters length error: %v", err)
+	}
+	voters := make([]common.Address, 0)
+	for i := 0; uint32(i) < n; i++ {
+		voter := new(common.Address)
+		if err := voter.Deserialize(r); err != nil {
+			return fmt.Errorf("address.Deserialize, deserialize voter error: %v", err)
+		}
+		voters = append(voters, *voter)
+	}
+	this.Id = id
+	this.Proposer = *proposer
+	this.Kind = kind
+	this.Payload = payload
+	this.Description = description
+	this.StartHeight = startHeight
+	this.EndHeight = endHeight
+	this.ExecuteHeight = executeHeight
+	this.Status = status
+	this.YesWeight = yesWeight
+	this.NoWeight = noWeight
+	this.TotalWeight = totalWeight
+	this.Voters = voters
+	return nil
+}
+
+type ProposalVote struct { //table record vote of each voter for proposal
+	Voter   common.Address
+	Approve bool
+	Weight  uint64 //stake of voter when voted
+}
+
+func (this *ProposalVote) Serialize(w io.Writer) error {
+	if err := this.Voter.Serialize(w); err != nil {
+		return fmt.Errorf("address.Serialize, serialize voter error: %v", err)
+	}
+	if err := serialization.WriteBool(w, this.Approve); err != nil {
+		return fmt.Errorf("serialization.WriteBool, serialize approve error: %v", err)
+	}
+	if err := serialization.WriteUint64(w, this.Weight); err != nil {
+		return fmt.Errorf("serialization.WriteUint64, serialize weight error: %v", err)
+	}
+	return nil
+}
+
+func (this *ProposalVote) Deserialize(r io.Reader) error {
+	voter := new(common.Address)
+	if err := voter.Deserialize(r); err != nil {
+		return fmt.Errorf("address.Deserialize, deserialize voter error: %v", err)
+	}
+	approve, err := serialization.ReadBool(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	weight, err := serialization.ReadUint64(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize weight error: %v", err)
+	}
+	this.Voter = *voter
+	this.Approve = approve
+	this.Weight = weight
+	return nil
+}
+
+type ProposalIds struct { //table record ids of proposals executed at same height
+	Ids []uint64
+}
+
+func (this *ProposalIds) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, uint32(len(this.Ids))); err != nil {
+		return fmt.Errorf("serialization.WriteUint32, serialize ids length error: %v", err)
+	}
+	for _, id := range this.Ids {
+		if err := serialization.WriteUint64(w, id); err != nil {
+			return fmt.Errorf("serialization.WriteUint64, serialize id error: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *ProposalIds) Deserialize(r io.Reader) error {
+	n, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize ids length error: %v", err)
+	}
+	ids := make([]uint64, 0)
+	for i := 0; uint32(i) < n; i++ {
+		id, err := serialization.ReadUint64(r)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadUint64, deserialize id error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	this.Ids = ids
+	return nil
+}
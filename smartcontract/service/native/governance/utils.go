@@ -794,3 +794,179 @@ func putPromisePos(native *native.NativeService, contract common.Address, promis
 		cstates.GenRawStorageItem(bf.Bytes()))
 	return nil
 }
+
+func getProposalConfig(native *native.NativeService, contract common.Address) (*ProposalConfig, error) {
+	proposalConfigBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_CONFIG)))
+	if err != nil {
+		return nil, fmt.Errorf("getProposalConfig, get proposalConfigBytes error: %v", err)
+	}
+	proposalConfig := &ProposalConfig{
+		VotingPeriod:     DEFAULT_VOTING_PERIOD,
+		MinExecuteDelay:  DEFAULT_MIN_EXECUTE_DELAY,
+		Quorum:           DEFAULT_QUORUM,
+		Threshold:        DEFAULT_THRESHOLD,
+		MinProposerStake: DEFAULT_MIN_PROPOSER_STAKE,
+	}
+	if proposalConfigBytes != nil {
+		value, err := cstates.GetValueFromRawStorageItem(proposalConfigBytes)
+		if err != nil {
+			return nil, fmt.Errorf("getProposalConfig, deserialize from raw storage item err:%v", err)
+		}
+		if err := proposalConfig.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize proposalConfig error: %v", err)
+		}
+	}
+	return proposalConfig, nil
+}
+
+func putProposalConfig(native *native.NativeService, contract common.Address, proposalConfig *ProposalConfig) error {
+	bf := new(bytes.Buffer)
+	if err := proposalConfig.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposalConfig error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_CONFIG)), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+func getProposalCount(native *native.NativeService, contract common.Address) (uint64, error) {
+	countBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, ProposalCountKey()))
+	if err != nil {
+		return 0, fmt.Errorf("getProposalCount, get countBytes error: %v", err)
+	}
+	if countBytes == nil {
+		return 0, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(countBytes)
+	if err != nil {
+		return 0, fmt.Errorf("getProposalCount, deserialize from raw storage item err:%v", err)
+	}
+	return GetBytesUint64(value)
+}
+
+func putProposalCount(native *native.NativeService, contract common.Address, count uint64) error {
+	countBytes, err := GetUint64Bytes(count)
+	if err != nil {
+		return fmt.Errorf("GetUint64Bytes, get countBytes error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, ProposalCountKey()), cstates.GenRawStorageItem(countBytes))
+	return nil
+}
+
+//ProposalCountKey returns key of proposal count in governance contract storage, without contract address
+func ProposalCountKey() []byte {
+	return []byte(PROPOSAL_COUNT)
+}
+
+//ProposalKey returns key of proposal in governance contract storage, without contract address
+func ProposalKey(id uint64) []byte {
+	idBytes, _ := GetUint64Bytes(id)
+	return append([]byte(PROPOSAL), idBytes...)
+}
+
+//get proposal by id, return nil if not exist
+func getProposal(native *native.NativeService, contract common.Address, id uint64) (*Proposal, error) {
+	proposalBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, ProposalKey(id)))
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, get proposalBytes error: %v", err)
+	}
+	if proposalBytes == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(proposalBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, deserialize from raw storage item err:%v", err)
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposal error: %v", err)
+	}
+	return proposal, nil
+}
+
+func putProposal(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	bf := new(bytes.Buffer)
+	if err := proposal.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposal error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, ProposalKey(proposal.Id)), cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+//get vote of voter for proposal, return nil if not voted
+func getProposalVote(native *native.NativeService, contract common.Address, id uint64, voter common.Address) (*ProposalVote, error) {
+	idBytes, err := GetUint64Bytes(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetUint64Bytes, get idBytes error: %v", err)
+	}
+	voteBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), idBytes, voter[:]))
+	if err != nil {
+		return nil, fmt.Errorf("getProposalVote, get voteBytes error: %v", err)
+	}
+	if voteBytes == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(voteBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposalVote, deserialize from raw storage item err:%v", err)
+	}
+	vote := new(ProposalVote)
+	if err := vote.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposalVote error: %v", err)
+	}
+	return vote, nil
+}
+
+func putProposalVote(native *native.NativeService, contract common.Address, id uint64, vote *ProposalVote) error {
+	idBytes, err := GetUint64Bytes(id)
+	if err != nil {
+		return fmt.Errorf("GetUint64Bytes, get idBytes error: %v", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := vote.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposalVote error: %v", err)
+	}
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), idBytes, vote.Voter[:]),
+		cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
+
+//get ids of proposals executed at height
+func getScheduledProposals(native *native.NativeService, contract common.Address, height uint32) (*ProposalIds, error) {
+	heightBytes, err := GetUint32Bytes(height)
+	if err != nil {
+		return nil, fmt.Errorf("getUint32Bytes, getUint32Bytes error: %v", err)
+	}
+	idsBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_SCHEDULE), heightBytes))
+	if err != nil {
+		return nil, fmt.Errorf("getScheduledProposals, get idsBytes error: %v", err)
+	}
+	proposalIds := new(ProposalIds)
+	if idsBytes != nil {
+		value, err := cstates.GetValueFromRawStorageItem(idsBytes)
+		if err != nil {
+			return nil, fmt.Errorf("getScheduledProposals, deserialize from raw storage item err:%v", err)
+		}
+		if err := proposalIds.Deserialize(bytes.NewBuffer(value)); err != nil {
+			return nil, fmt.Errorf("deserialize, deserialize proposalIds error: %v", err)
+		}
+	}
+	return proposalIds, nil
+}
+
+func putScheduledProposals(native *native.NativeService, contract common.Address, height uint32, proposalIds *ProposalIds) error {
+	heightBytes, err := GetUint32Bytes(height)
+	if err != nil {
+		return fmt.Errorf("getUint32Bytes, getUint32Bytes error: %v", err)
+	}
+	key := utils.ConcatKey(contract, []byte(PROPOSAL_SCHEDULE), heightBytes)
+	if len(proposalIds.Ids) == 0 {
+		native.CacheDB.Delete(key)
+		return nil
+	}
+	bf := new(bytes.Buffer)
+	if err := proposalIds.Serialize(bf); err != nil {
+		return fmt.Errorf("serialize, serialize proposalIds error: %v", err)
+	}
+	native.CacheDB.Put(key, cstates.GenRawStorageItem(bf.Bytes()))
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package htlc
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type LockParam struct {
+	Sender    common.Address `native:"sender,witness"`
+	Recipient common.Address
+	Asset     common.Address
+	Amount    uint64
+	Hashlock  []byte
+	HashType  uint8
+	Timelock  uint32
+}
+
+type ClaimParam struct {
+	Preimage []byte
+}
+
+type HashlockParam struct {
+	Hashlock []byte
+}
+
+type LockEvent struct {
+	Hashlock  []byte
+	Sender    common.Address
+	Recipient common.Address
+	Asset     common.Address
+	Amount    uint64
+	Timelock  uint32
+}
+
+type ClaimEvent struct {
+	Hashlock  []byte
+	Preimage  []byte
+	Recipient common.Address
+	Asset     common.Address
+	Amount    uint64
+}
+
+type RefundEvent struct {
+	Hashlock []byte
+	Sender   common.Address
+	Asset    common.Address
+	Amount   uint64
+}
+
+//Lock transfers amount of asset from sender to contract under hashlock. Hashlock can not be reused,
+//even if swap locked under it is settled, since its preimage may be public.
+func Lock(native *native.NativeService, param *LockParam) (bool, error) {
+	if err := checkHashlock(param.HashType, param.Hashlock); err != nil {
+		return false, err
+	}
+	if err := checkAsset(native, param.Asset); err != nil {
+		return false, err
+	}
+	if param.Amount == 0 {
+		return false, fmt.Errorf("amount of swap should be positive")
+	}
+	if param.Timelock <= native.Time {
+		return false, fmt.Errorf("timelock %d is passed", param.Timelock)
+	}
+	swap, err := getSwap(native, param.Hashlock)
+	if err != nil {
+		return false, err
+	}
+	if swap != nil {
+		return false, fmt.Errorf("hashlock %x is used", param.Hashlock)
+	}
+	if err := transfer(native, param.Asset, param.Sender, utils.HtlcContractAddress, param.Amount); err != nil {
+		return false, fmt.Errorf("lock swap amount error:%s", err)
+	}
+	swap = &Swap{
+		Hashlock:  param.Hashlock,
+		HashType:  param.HashType,
+		Sender:    param.Sender,
+		Recipient: param.Recipient,
+		Asset:     param.Asset,
+		Amount:    param.Amount,
+		Timelock:  param.Timelock,
+		Status:    SWAP_LOCKED,
+	}
+	if err := putSwap(native, swap); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, LOCK_NAME, &LockEvent{
+		Hashlock:  swap.Hashlock,
+		Sender:    swap.Sender,
+		Recipient: swap.Recipient,
+		Asset:     swap.Asset,
+		Amount:    swap.Amount,
+		Timelock:  swap.Timelock,
+	})
+	return true, err
+}
+
+//Claim pays swap locked under hash of preimage to recipient before timelock. Anyone knowing preimage
+//can claim, since swap is always paid to its recipient.
+func Claim(native *native.NativeService, param *ClaimParam) (bool, error) {
+	if len(param.Preimage) == 0 || len(param.Preimage) > MAX_PREIMAGE_LEN {
+		return false, fmt.Errorf("length of preimage should be in 1..%d", MAX_PREIMAGE_LEN)
+	}
+	swap, err := swapOfPreimage(native, param.Preimage)
+	if err != nil {
+		return false, err
+	}
+	if swap.Status != SWAP_LOCKED {
+		return false, fmt.Errorf("swap %x is settled", swap.Hashlock)
+	}
+	if native.Time >= swap.Timelock {
+		return false, fmt.Errorf("swap %x is timeout", swap.Hashlock)
+	}
+	swap.Preimage = param.Preimage
+	swap.Status = SWAP_CLAIMED
+	if err := putSwap(native, swap); err != nil {
+		return false, err
+	}
+	if err := transfer(native, swap.Asset, utils.HtlcContractAddress, swap.Recipient, swap.Amount); err != nil {
+		return false, fmt.Errorf("pay swap error:%s", err)
+	}
+	err = contract.Notify(native, CLAIM_NAME, &ClaimEvent{
+		Hashlock:  swap.Hashlock,
+		Preimage:  swap.Preimage,
+		Recipient: swap.Recipient,
+		Asset:     swap.Asset,
+		Amount:    swap.Amount,
+	})
+	return true, err
+}
+
+//Refund pays swap back to sender after timelock, signed by sender
+func Refund(native *native.NativeService, param *HashlockParam) (bool, error) {
+	swap, err := getSwap(native, param.Hashlock)
+	if err != nil {
+		return false, err
+	}
+	if swap == nil {
+		return false, fmt.Errorf("swap %x not found", param.Hashlock)
+	}
+	if swap.Status != SWAP_LOCKED {
+		return false, fmt.Errorf("swap %x is settled", swap.Hashlock)
+	}
+	if native.Time < swap.Timelock {
+		return false, fmt.Errorf("swap %x is locked until %d", swap.Hashlock, swap.Timelock)
+	}
+	if err := utils.ValidateOwner(native, swap.Sender); err != nil {
+		return false, fmt.Errorf("refund swap %x, %s", swap.Hashlock, err)
+	}
+	swap.Status = SWAP_REFUNDED
+	if err := putSwap(native, swap); err != nil {
+		return false, err
+	}
+	if err := transfer(native, swap.Asset, utils.HtlcContractAddress, swap.Sender, swap.Amount); err != nil {
+		return false, fmt.Errorf("refund swap error:%s", err)
+	}
+	err = contract.Notify(native, REFUND_NAME, &RefundEvent{
+		Hashlock: swap.Hashlock,
+		Sender:   swap.Sender,
+		Asset:    swap.Asset,
+		Amount:   swap.Amount,
+	})
+	return true, err
+}
+
+func GetSwap(native *native.NativeService, param *HashlockParam) (*Swap, error) {
+	swap, err := getSwap(native, param.Hashlock)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, fmt.Errorf("swap %x not found", param.Hashlock)
+	}
+	return swap, nil
+}
+
+//swapOfPreimage finds swap locked under SHA256 or HASH160 of preimage
+func swapOfPreimage(native *native.NativeService, preimage []byte) (*Swap, error) {
+	for _, hashType := range []uint8{HASH_SHA256, HASH_HASH160} {
+		hashlock, err := Hash(hashType, preimage)
+		if err != nil {
+			return nil, err
+		}
+		swap, err := getSwap(native, hashlock)
+		if err != nil {
+			return nil, err
+		}
+		if swap != nil && swap.HashType == hashType {
+			return swap, nil
+		}
+	}
+	return nil, fmt.Errorf("no swap is locked under hash of preimage")
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package htlc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+func TestHash(t *testing.T) {
+	data := []byte("secret of swap")
+	engine := vm.NewExecutionEngine()
+
+	engine.OpCode = vm.SHA256
+	hash, err := Hash(HASH_SHA256, data)
+	assert.Nil(t, err)
+	assert.Equal(t, vm.Hash(data, engine), hash)
+	assert.Nil(t, checkHashlock(HASH_SHA256, hash))
+
+	engine.OpCode = vm.HASH160
+	hash, err = Hash(HASH_HASH160, data)
+	assert.Nil(t, err)
+	assert.Equal(t, vm.Hash(data, engine), hash)
+	assert.Nil(t, checkHashlock(HASH_HASH160, hash))
+	assert.NotNil(t, checkHashlock(HASH_SHA256, hash))
+
+	_, err = Hash(2, data)
+	assert.NotNil(t, err)
+	assert.NotNil(t, checkHashlock(2, hash))
+}
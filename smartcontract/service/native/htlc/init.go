@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package htlc implements hash time-locked contracts for atomic swaps. Sender locks ONX, OXG or OEP-4 tokens
+//of NeoVM contract under hashlock until timelock, recipient gets them by revealing preimage of hashlock, and
+//sender gets them back after timelock. Swaps are indexed by hashlock, and preimage is kept in swap once claimed,
+//so counterparty of swap can read it from this chain.
+package htlc
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	LOCK_NAME     = "lock"
+	CLAIM_NAME    = "claim"
+	REFUND_NAME   = "refund"
+	GET_SWAP_NAME = "getSwap"
+)
+
+var contract *framework.Contract
+
+func init() {
+	contract = framework.NewContract("htlc", utils.HtlcContractAddress).
+		Method(LOCK_NAME, Lock).
+		Method(CLAIM_NAME, Claim).
+		Method(REFUND_NAME, Refund).
+		Method(GET_SWAP_NAME, GetSwap).
+		Event(LOCK_NAME, LockEvent{}).
+		Event(CLAIM_NAME, ClaimEvent{}).
+		Event(REFUND_NAME, RefundEvent{})
+}
+
+//Init installs htlc as native contract
+func Init() {
+	contract.Install()
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package htlc
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	vtypes "github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+	"golang.org/x/crypto/ripemd160"
+)
+
+//hash functions of hashlock, the same as opcodes SHA256 and HASH160 of NeoVM
+const (
+	HASH_SHA256  uint8 = 0
+	HASH_HASH160 uint8 = 1
+)
+
+//status of swap
+const (
+	SWAP_LOCKED   uint8 = 0
+	SWAP_CLAIMED  uint8 = 1
+	SWAP_REFUNDED uint8 = 2
+)
+
+const (
+	MAX_PREIMAGE_LEN = 256
+	OEP4_TRANSFER    = "transfer"
+)
+
+var (
+	PreSwap = []byte{0x01}
+)
+
+//Swap is amount of asset locked by sender under hashlock. Timelock is block timestamp in seconds, recipient
+//claims swap with preimage before it, and sender refunds swap after it. Preimage is set when swap is claimed.
+type Swap struct {
+	Hashlock  []byte
+	HashType  uint8
+	Sender    common.Address
+	Recipient common.Address
+	Asset     common.Address
+	Amount    uint64
+	Timelock  uint32
+	Preimage  []byte
+	Status    uint8
+}
+
+//Hash returns hash of data by hash function of hashType
+func Hash(hashType uint8, data []byte) ([]byte, error) {
+	switch hashType {
+	case HASH_SHA256:
+		hash := sha256.Sum256(data)
+		return hash[:], nil
+	case HASH_HASH160:
+		temp := sha256.Sum256(data)
+		md := ripemd160.New()
+		md.Write(temp[:])
+		return md.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("unsupported hash type %d", hashType)
+}
+
+func checkHashlock(hashType uint8, hashlock []byte) error {
+	size := 0
+	switch hashType {
+	case HASH_SHA256:
+		size = sha256.Size
+	case HASH_HASH160:
+		size = ripemd160.Size
+	default:
+		return fmt.Errorf("unsupported hash type %d", hashType)
+	}
+	if len(hashlock) != size {
+		return fmt.Errorf("length of hashlock should be %d, got %d", size, len(hashlock))
+	}
+	return nil
+}
+
+//checkAsset accepts onx, oxg and NeoVM contract deployed, which is supposed to be OEP-4 token
+func checkAsset(native *native.NativeService, asset common.Address) error {
+	if isNativeAsset(asset) {
+		return nil
+	}
+	dep, err := native.CacheDB.GetContract(asset)
+	if err != nil {
+		return err
+	}
+	if dep == nil {
+		return fmt.Errorf("unsupported asset %s, should be onx, oxg or OEP-4 contract", asset.ToHexString())
+	}
+	return nil
+}
+
+func isNativeAsset(asset common.Address) bool {
+	return asset == utils.OnxContractAddress || asset == utils.OxgContractAddress
+}
+
+func genSwapKey(hashlock []byte) []byte {
+	return utils.ConcatKey(utils.HtlcContractAddress, PreSwap, hashlock)
+}
+
+//getSwap returns nil if no swap is locked under hashlock
+func getSwap(native *native.NativeService, hashlock []byte) (*Swap, error) {
+	item, err := utils.GetStorageItem(native, genSwapKey(hashlock))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+	swap := new(Swap)
+	if err := framework.Decode(item.Value, swap); err != nil {
+		return nil, fmt.Errorf("decode swap %x error:%s", hashlock, err)
+	}
+	return swap, nil
+}
+
+func putSwap(native *native.NativeService, swap *Swap) error {
+	data, err := framework.Encode(swap)
+	if err != nil {
+		return err
+	}
+	utils.PutBytes(native, genSwapKey(swap.Hashlock), data)
+	return nil
+}
+
+//transfer moves onx or oxg by transfer of native contract, and OEP-4 token by transfer(from, to, amount)
+//of NeoVM contract, which should return true
+func transfer(native *native.NativeService, asset, from, to common.Address, amount uint64) error {
+	if isNativeAsset(asset) {
+		transfers := onx.Transfers{States: []onx.State{{From: from, To: to, Value: amount}}}
+		sink := common.NewZeroCopySink(nil)
+		transfers.Serialization(sink)
+		if _, err := native.NativeCall(asset, onx.TRANSFER_NAME, sink.Bytes()); err != nil {
+			return fmt.Errorf("transfer, appCall error: %v", err)
+		}
+		return nil
+	}
+	args := []interface{}{from[:], to[:], new(big.Int).SetUint64(amount)}
+	result, err := native.ContextRef.AppCall(asset, OEP4_TRANSFER, args)
+	if err != nil {
+		return fmt.Errorf("transfer, appCall error: %v", err)
+	}
+	item, ok := result.(vtypes.StackItems)
+	if !ok {
+		return fmt.Errorf("transfer, OEP-4 contract %s returns nothing", asset.ToHexString())
+	}
+	if ok, err := item.GetBoolean(); err != nil || !ok {
+		return fmt.Errorf("transfer, OEP-4 contract %s returns false", asset.ToHexString())
+	}
+	return nil
+}
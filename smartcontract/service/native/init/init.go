@@ -23,12 +23,19 @@ import (
 	"math/big"
 
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/asset"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/claimrecord"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/contractabi"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/escrow"
 	params "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/htlc"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/oxg"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onxid"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
@@ -45,6 +52,13 @@ func init() {
 	onxid.Init()
 	auth.Init()
 	governance.InitGovernance()
+	contractabi.Init()
+	upgrade.Init()
+	asset.Init()
+	escrow.Init()
+	htlc.Init()
+	claimrecord.Init()
+	nameservice.Init()
 }
 
 func InitBytes(addr common.Address, method string) []byte {
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package nameservice implements registry of human-readable names, such as alice.onx, resolved to address,
+//ONX ID and contract. Names under top level domain are registered for years with oxg fee, and owner of name
+//creates its subnames, which are valid as long as the name is. Address may set name resolved to it as its
+//reverse record, which is reported only while the name still resolves to the address.
+package nameservice
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	REGISTER_NAME    = "register"
+	RENEW_NAME       = "renew"
+	SET_SUBNAME_NAME = "setSubname"
+	TRANSFER_NAME    = "transfer"
+	SET_RECORDS_NAME = "setRecords"
+	SET_REVERSE_NAME = "setReverse"
+	RESOLVE_NAME     = "resolve"
+	REVERSE_NAME     = "reverse"
+)
+
+var contract *framework.Contract
+
+func init() {
+	contract = framework.NewContract("nameService", utils.NameContractAddress).
+		Method(REGISTER_NAME, Register).
+		Method(RENEW_NAME, Renew).
+		Method(SET_SUBNAME_NAME, SetSubname).
+		Method(TRANSFER_NAME, Transfer).
+		Method(SET_RECORDS_NAME, SetRecords).
+		Method(SET_REVERSE_NAME, SetReverse).
+		Method(RESOLVE_NAME, Resolve).
+		Method(REVERSE_NAME, Reverse).
+		Event(REGISTER_NAME, RegisterEvent{}).
+		Event(RENEW_NAME, RenewEvent{}).
+		Event(SET_SUBNAME_NAME, SetSubnameEvent{}).
+		Event(TRANSFER_NAME, TransferEvent{}).
+		Event(SET_RECORDS_NAME, SetRecordsEvent{}).
+		Event(SET_REVERSE_NAME, SetReverseEvent{})
+}
+
+//Init installs name service as native contract
+func Init() {
+	contract.Install()
+}
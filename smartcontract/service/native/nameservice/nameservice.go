@@ -0,0 +1,316 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nameservice
+
+import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type RegisterParam struct {
+	Name  string
+	Owner common.Address `native:"owner,witness"`
+	Years uint32
+}
+
+type RenewParam struct {
+	Name  string
+	Payer common.Address `native:"payer,witness"`
+	Years uint32
+}
+
+type SetSubnameParam struct {
+	Parent string
+	Label  string
+	Owner  common.Address
+}
+
+type TransferParam struct {
+	Name string
+	To   common.Address
+}
+
+type SetRecordsParam struct {
+	Name     string
+	Address  common.Address
+	OnxID    []byte
+	Contract common.Address
+}
+
+type SetReverseParam struct {
+	Address common.Address `native:"address,witness"`
+	Name    string
+}
+
+type NameParam struct {
+	Name string
+}
+
+type AddressParam struct {
+	Address common.Address
+}
+
+type RegisterEvent struct {
+	Name       string
+	Owner      common.Address
+	Expiration uint32
+}
+
+type RenewEvent struct {
+	Name       string
+	Payer      common.Address
+	Expiration uint32
+}
+
+type SetSubnameEvent struct {
+	Name  string
+	Owner common.Address
+}
+
+type TransferEvent struct {
+	Name string
+	From common.Address
+	To   common.Address
+}
+
+type SetRecordsEvent struct {
+	Name     string
+	Address  common.Address
+	OnxID    []byte
+	Contract common.Address
+}
+
+type SetReverseEvent struct {
+	Address common.Address
+	Name    string
+}
+
+//Register registers name under top level domain to owner for years, paying fee in oxg. Name expired for
+//grace period can be registered again by anyone, and its former records and subnames are dropped. Name
+//resolves to address of owner until its records are set.
+func Register(native *native.NativeService, param *RegisterParam) (bool, error) {
+	labels, err := splitName(param.Name)
+	if err != nil {
+		return false, err
+	}
+	if len(labels) != 2 {
+		return false, fmt.Errorf("name %s should be directly under top level domain, use setSubname", param.Name)
+	}
+	if len(labels[0]) < MIN_LABEL_LEN {
+		return false, fmt.Errorf("length of label of name should be at least %d", MIN_LABEL_LEN)
+	}
+	fee, err := registerFee(param.Years)
+	if err != nil {
+		return false, err
+	}
+	record, err := getName(native, param.Name)
+	if err != nil {
+		return false, err
+	}
+	if record != nil && native.Time < record.Expiration+GRACE_PERIOD {
+		return false, fmt.Errorf("name %s is registered", param.Name)
+	}
+	if err := payFee(native, param.Owner, fee); err != nil {
+		return false, fmt.Errorf("register name %s, %s", param.Name, err)
+	}
+	record = &Name{
+		Name:       param.Name,
+		Owner:      param.Owner,
+		Registered: native.Time,
+		Expiration: native.Time + param.Years*YEAR,
+		Address:    param.Owner,
+	}
+	if err := putName(native, record); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, REGISTER_NAME, &RegisterEvent{
+		Name:       record.Name,
+		Owner:      record.Owner,
+		Expiration: record.Expiration,
+	})
+	return true, err
+}
+
+//Renew extends expiration of name registered under top level domain by years, paid by anyone. Name can be
+//renewed until grace period after its expiration is passed, and at most MAX_YEARS ahead of block time.
+func Renew(native *native.NativeService, param *RenewParam) (bool, error) {
+	labels, err := splitName(param.Name)
+	if err != nil {
+		return false, err
+	}
+	if len(labels) != 2 {
+		return false, fmt.Errorf("subname %s expires with its parent", param.Name)
+	}
+	fee, err := registerFee(param.Years)
+	if err != nil {
+		return false, err
+	}
+	record, err := getName(native, param.Name)
+	if err != nil {
+		return false, err
+	}
+	if record == nil || native.Time >= record.Expiration+GRACE_PERIOD {
+		return false, fmt.Errorf("name %s is not registered", param.Name)
+	}
+	expiration := record.Expiration + param.Years*YEAR
+	if expiration > native.Time+MAX_YEARS*YEAR {
+		return false, fmt.Errorf("name %s can be registered at most %d years ahead", param.Name, MAX_YEARS)
+	}
+	if err := payFee(native, param.Payer, fee); err != nil {
+		return false, fmt.Errorf("renew name %s, %s", param.Name, err)
+	}
+	record.Expiration = expiration
+	if err := putName(native, record); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, RENEW_NAME, &RenewEvent{
+		Name:       record.Name,
+		Payer:      param.Payer,
+		Expiration: record.Expiration,
+	})
+	return true, err
+}
+
+//SetSubname sets label.parent to owner, signed by owner of parent. Subname set before is replaced, with
+//its records dropped, so owner of parent always controls its subnames.
+func SetSubname(native *native.NativeService, param *SetSubnameParam) (bool, error) {
+	name := param.Label + "." + param.Parent
+	if _, err := splitName(name); err != nil {
+		return false, err
+	}
+	parent, err := getActiveName(native, param.Parent)
+	if err != nil {
+		return false, err
+	}
+	if err := utils.ValidateOwner(native, parent.Owner); err != nil {
+		return false, fmt.Errorf("set subname %s, %s", name, err)
+	}
+	record := &Name{
+		Name:       name,
+		Owner:      param.Owner,
+		Registered: native.Time,
+		Address:    param.Owner,
+	}
+	if err := putName(native, record); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, SET_SUBNAME_NAME, &SetSubnameEvent{
+		Name:  record.Name,
+		Owner: record.Owner,
+	})
+	return true, err
+}
+
+//Transfer transfers name to new owner, signed by owner. Records of name are kept.
+func Transfer(native *native.NativeService, param *TransferParam) (bool, error) {
+	record, err := getActiveName(native, param.Name)
+	if err != nil {
+		return false, err
+	}
+	if err := utils.ValidateOwner(native, record.Owner); err != nil {
+		return false, fmt.Errorf("transfer name %s, %s", param.Name, err)
+	}
+	from := record.Owner
+	record.Owner = param.To
+	if err := putName(native, record); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, TRANSFER_NAME, &TransferEvent{
+		Name: record.Name,
+		From: from,
+		To:   record.Owner,
+	})
+	return true, err
+}
+
+//SetRecords sets address, ONX ID and contract which name resolves to, signed by owner. Empty address,
+//ONX ID or contract means name has no such record.
+func SetRecords(native *native.NativeService, param *SetRecordsParam) (bool, error) {
+	record, err := getActiveName(native, param.Name)
+	if err != nil {
+		return false, err
+	}
+	if len(param.OnxID) != 0 && !account.VerifyID(string(param.OnxID)) {
+		return false, fmt.Errorf("invalid ONX ID %s", param.OnxID)
+	}
+	if err := utils.ValidateOwner(native, record.Owner); err != nil {
+		return false, fmt.Errorf("set records of name %s, %s", param.Name, err)
+	}
+	record.Address = param.Address
+	record.OnxID = param.OnxID
+	record.Contract = param.Contract
+	if err := putName(native, record); err != nil {
+		return false, err
+	}
+	err = contract.Notify(native, SET_RECORDS_NAME, &SetRecordsEvent{
+		Name:     record.Name,
+		Address:  record.Address,
+		OnxID:    record.OnxID,
+		Contract: record.Contract,
+	})
+	return true, err
+}
+
+//SetReverse sets name as reverse record of address, signed by address. Name should resolve to the address,
+//and empty name clears reverse record.
+func SetReverse(native *native.NativeService, param *SetReverseParam) (bool, error) {
+	if param.Name != "" {
+		record, err := getActiveName(native, param.Name)
+		if err != nil {
+			return false, err
+		}
+		if record.Address != param.Address {
+			return false, fmt.Errorf("name %s does not resolve to %s", param.Name, param.Address.ToBase58())
+		}
+	}
+	putReverse(native, param.Address, param.Name)
+	err := contract.Notify(native, SET_REVERSE_NAME, &SetReverseEvent{
+		Address: param.Address,
+		Name:    param.Name,
+	})
+	return true, err
+}
+
+//Resolve returns record of name which is registered and not expired
+func Resolve(native *native.NativeService, param *NameParam) (*Name, error) {
+	return getActiveName(native, param.Name)
+}
+
+//Reverse returns record of reverse name of address, if the name still resolves to the address
+func Reverse(native *native.NativeService, param *AddressParam) (*Name, error) {
+	name, err := getReverse(native, param.Address)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%s has no reverse name", param.Address.ToBase58())
+	}
+	record, err := getActiveName(native, name)
+	if err != nil {
+		return nil, err
+	}
+	if record.Address != param.Address {
+		return nil, fmt.Errorf("reverse name %s does not resolve to %s", name, param.Address.ToBase58())
+	}
+	return record, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nameservice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitName(t *testing.T) {
+	labels, err := splitName("pay.alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pay", "alice", "onx"}, labels)
+	assert.True(t, IsName("my-shop2.onx"))
+
+	for _, name := range []string{"", "onx", "alice", "alice.com", "Alice.onx", "alice..onx", ".alice.onx",
+		"-alice.onx", "alice-.onx", "al_ice.onx", "alice.onx.", strings.Repeat("a", MAX_LABEL_LEN+1) + ".onx",
+		"AQf4Mzu1YJrhz9f3aRkkwSm9n3qhXGSh4p"} {
+		assert.False(t, IsName(name), name)
+	}
+	long := strings.Repeat(strings.Repeat("a", MAX_LABEL_LEN)+".", 4) + "onx"
+	assert.True(t, len(long) > MAX_NAME_LEN)
+	assert.False(t, IsName(long))
+}
+
+func TestRegisterFee(t *testing.T) {
+	fee, err := registerFee(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*REGISTER_FEE, fee)
+	_, err = registerFee(0)
+	assert.NotNil(t, err)
+	_, err = registerFee(MAX_YEARS + 1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nameservice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	MAX_NAME_LEN  = 253
+	MAX_LABEL_LEN = 63
+	MIN_LABEL_LEN = 3 //min length of label registered under top level domain
+
+	YEAR         uint32 = 365 * 24 * 3600
+	MAX_YEARS    uint32 = 10
+	GRACE_PERIOD uint32 = 30 * 24 * 3600 //name can only be renewed in grace period after expiration
+
+	REGISTER_FEE uint64 = 1000000000 //oxg paid to governance per year of registration, unit: 10^-9 oxg
+)
+
+var (
+	PreName    = []byte{0x01}
+	PreReverse = []byte{0x02}
+)
+
+//TopLevelDomains are domains under which names are registered
+var TopLevelDomains = map[string]bool{
+	"onx": true,
+}
+
+//Name is record of name. Registered is block time when name is registered or subname is set, subname set
+//before its parent is registered again is stale. Expiration of subname is not stored, it expires with name
+//registered under top level domain. Address, OnxID and Contract are records which name resolves to.
+type Name struct {
+	Name       string
+	Owner      common.Address
+	Registered uint32
+	Expiration uint32
+	Address    common.Address
+	OnxID      []byte
+	Contract   common.Address
+}
+
+//IsName returns whether s is valid name, so clients can tell it from base58 address, which has no dot
+func IsName(s string) bool {
+	_, err := splitName(s)
+	return err == nil
+}
+
+//splitName checks name and returns its labels. Label consists of lower case letters, digits and hyphen,
+//which is neither first nor last, and last label should be top level domain.
+func splitName(name string) ([]string, error) {
+	if len(name) > MAX_NAME_LEN {
+		return nil, fmt.Errorf("length of name should be at most %d", MAX_NAME_LEN)
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return nil, fmt.Errorf("name %s should be under top level domain", name)
+	}
+	for _, label := range labels {
+		if err := checkLabel(label); err != nil {
+			return nil, fmt.Errorf("invalid name %s, %s", name, err)
+		}
+	}
+	if !TopLevelDomains[labels[len(labels)-1]] {
+		return nil, fmt.Errorf("unsupported top level domain of name %s", name)
+	}
+	return labels, nil
+}
+
+func checkLabel(label string) error {
+	if len(label) == 0 || len(label) > MAX_LABEL_LEN {
+		return fmt.Errorf("length of label should be in 1..%d", MAX_LABEL_LEN)
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-' && i != 0 && i != len(label)-1:
+		default:
+			return fmt.Errorf("invalid char %q in label %s", c, label)
+		}
+	}
+	return nil
+}
+
+//registerFee returns oxg paid for registration or renewal of years
+func registerFee(years uint32) (uint64, error) {
+	if years == 0 || years > MAX_YEARS {
+		return 0, fmt.Errorf("years should be in 1..%d", MAX_YEARS)
+	}
+	return REGISTER_FEE * uint64(years), nil
+}
+
+func genNameKey(name string) []byte {
+	return utils.ConcatKey(utils.NameContractAddress, PreName, []byte(name))
+}
+
+func genReverseKey(addr common.Address) []byte {
+	return utils.ConcatKey(utils.NameContractAddress, PreReverse, addr[:])
+}
+
+//getName returns nil if name is never registered
+func getName(native *native.NativeService, name string) (*Name, error) {
+	item, err := utils.GetStorageItem(native, genNameKey(name))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, nil
+	}
+	record := new(Name)
+	if err := framework.Decode(item.Value, record); err != nil {
+		return nil, fmt.Errorf("decode name %s error:%s", name, err)
+	}
+	return record, nil
+}
+
+func putName(native *native.NativeService, record *Name) error {
+	data, err := framework.Encode(record)
+	if err != nil {
+		return err
+	}
+	utils.PutBytes(native, genNameKey(record.Name), data)
+	return nil
+}
+
+//getActiveName returns record of name which is registered and not expired, with expiration of its name
+//registered under top level domain if it is subname
+func getActiveName(native *native.NativeService, name string) (*Name, error) {
+	labels, err := splitName(name)
+	if err != nil {
+		return nil, err
+	}
+	record, err := getName(native, name)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("name %s is not registered", name)
+	}
+	if len(labels) == 2 {
+		if native.Time >= record.Expiration {
+			return nil, fmt.Errorf("name %s is expired", name)
+		}
+		return record, nil
+	}
+	parent, err := getActiveName(native, name[len(labels[0])+1:])
+	if err != nil {
+		return nil, err
+	}
+	if record.Registered < parent.Registered {
+		return nil, fmt.Errorf("name %s is not registered", name)
+	}
+	record.Expiration = parent.Expiration
+	return record, nil
+}
+
+func getReverse(native *native.NativeService, addr common.Address) (string, error) {
+	item, err := utils.GetStorageItem(native, genReverseKey(addr))
+	if err != nil {
+		return "", err
+	}
+	if item == nil {
+		return "", nil
+	}
+	return string(item.Value), nil
+}
+
+func putReverse(native *native.NativeService, addr common.Address, name string) {
+	if name == "" {
+		native.CacheDB.Delete(genReverseKey(addr))
+		return
+	}
+	utils.PutBytes(native, genReverseKey(addr), []byte(name))
+}
+
+//payFee transfers oxg fee from payer to governance
+func payFee(native *native.NativeService, payer common.Address, fee uint64) error {
+	transfers := onx.Transfers{States: []onx.State{{From: payer, To: utils.GovernanceContractAddress, Value: fee}}}
+	sink := common.NewZeroCopySink(nil)
+	transfers.Serialization(sink)
+	if _, err := native.NativeCall(utils.OxgContractAddress, onx.TRANSFER_NAME, sink.Bytes()); err != nil {
+		return fmt.Errorf("pay fee, appCall error: %v", err)
+	}
+	return nil
+}
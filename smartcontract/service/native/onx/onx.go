@@ -46,6 +46,7 @@ func RegisterOnxContract(native *native.NativeService) {
 	native.Register(TRANSFER_NAME, OnxTransfer)
 	native.Register(APPROVE_NAME, OnxApprove)
 	native.Register(TRANSFERFROM_NAME, OnxTransferFrom)
+	native.Register(TRANSFER_MULTI_NAME, OnxTransferMulti)
 	native.Register(NAME_NAME, OnxName)
 	native.Register(SYMBOL_NAME, OnxSymbol)
 	native.Register(DECIMALS_NAME, OnxDecimals)
@@ -170,6 +171,42 @@ func OnxTransferFrom(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//OnxTransferMulti transfers as OnxTransfer, with memo of each transfer emitted in transfer event
+func OnxTransferMulti(native *native.NativeService) ([]byte, error) {
+	var transfers MultiTransfers
+	source := common.NewZeroCopySource(native.Input)
+	if err := transfers.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[TransferMulti] Transfers deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	for _, v := range transfers.States {
+		if v.Value == 0 {
+			continue
+		}
+		if v.Value > constants.ONX_TOTAL_SUPPLY {
+			return utils.BYTE_FALSE, fmt.Errorf("transfer onx amount:%d over totalSupply:%d", v.Value, constants.ONX_TOTAL_SUPPLY)
+		}
+		if err := CheckMemo(v.Memo); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		fromBalance, toBalance, err := Transfer(native, contract, &State{From: v.From, To: v.To, Value: v.Value})
+		if err != nil {
+			return utils.BYTE_FALSE, err
+		}
+
+		if err := grantOxg(native, contract, v.From, fromBalance); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+
+		if err := grantOxg(native, contract, v.To, toBalance); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+
+		AddMemoNotifications(native, contract, &v)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 func OnxApprove(native *native.NativeService) ([]byte, error) {
 	var state State
 	source := common.NewZeroCopySource(native.Input)
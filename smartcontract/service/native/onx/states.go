@@ -141,6 +141,73 @@ func (this *State) Deserialization(source *common.ZeroCopySource) error {
 	return err
 }
 
+//MemoState is a transfer with memo, a payment reference emitted in transfer event
+type MemoState struct {
+	From  common.Address
+	To    common.Address
+	Value uint64
+	Memo  string
+}
+
+func (this *MemoState) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.From)
+	utils.EncodeAddress(sink, this.To)
+	utils.EncodeVarUint(sink, this.Value)
+	sink.WriteString(this.Memo)
+}
+
+func (this *MemoState) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.From, err = utils.DecodeAddress(source); err != nil {
+		return fmt.Errorf("[MemoState] deserialize from error:%v", err)
+	}
+	if this.To, err = utils.DecodeAddress(source); err != nil {
+		return fmt.Errorf("[MemoState] deserialize to error:%v", err)
+	}
+	if this.Value, err = utils.DecodeVarUint(source); err != nil {
+		return fmt.Errorf("[MemoState] deserialize value error:%v", err)
+	}
+	memo, _, irregular, eof := source.NextString()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Memo = memo
+	return nil
+}
+
+//MultiTransfers is param of transferMulti
+type MultiTransfers struct {
+	States []MemoState
+}
+
+func (this *MultiTransfers) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, uint64(len(this.States)))
+	for _, v := range this.States {
+		v.Serialization(sink)
+	}
+}
+
+func (this *MultiTransfers) Deserialization(source *common.ZeroCopySource) error {
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	if n > MAX_MULTI_TRANSFERS {
+		return fmt.Errorf("transfers count:%d over %d", n, MAX_MULTI_TRANSFERS)
+	}
+	for i := 0; uint64(i) < n; i++ {
+		var state MemoState
+		if err := state.Deserialization(source); err != nil {
+			return err
+		}
+		this.States = append(this.States, state)
+	}
+	return nil
+}
+
 type TransferFrom struct {
 	Sender common.Address
 	From   common.Address
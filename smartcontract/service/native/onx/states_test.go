@@ -44,3 +44,29 @@ func TestState_Serialize(t *testing.T) {
 
 	assert.Equal(t, state, state2)
 }
+
+func TestMultiTransfers_Serialization(t *testing.T) {
+	transfers := MultiTransfers{
+		States: []MemoState{
+			{From: common.AddressFromVmCode([]byte{1}), To: common.AddressFromVmCode([]byte{2}), Value: 1, Memo: "INV-001"},
+			{From: common.AddressFromVmCode([]byte{1}), To: common.AddressFromVmCode([]byte{3}), Value: 2},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	transfers.Serialization(sink)
+
+	var transfers2 MultiTransfers
+	err := transfers2.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, transfers, transfers2)
+
+	err = new(MultiTransfers).Deserialization(common.NewZeroCopySource(sink.Bytes()[:sink.Size()-1]))
+	assert.NotNil(t, err)
+}
+
+func TestCheckMemo(t *testing.T) {
+	assert.Nil(t, CheckMemo(""))
+	assert.Nil(t, CheckMemo("salary 2019-06"))
+	assert.NotNil(t, CheckMemo(string([]byte{0xff, 0xfe})))
+	assert.NotNil(t, CheckMemo(string(bytes.Repeat([]byte{'a'}, MAX_MEMO_LEN+1))))
+}
@@ -21,6 +21,7 @@ package onx
 import (
 	"bytes"
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
@@ -39,12 +40,18 @@ const (
 	TRANSFER_NAME       = "transfer"
 	APPROVE_NAME        = "approve"
 	TRANSFERFROM_NAME   = "transferFrom"
+	TRANSFER_MULTI_NAME = "transferMulti"
 	NAME_NAME           = "name"
 	SYMBOL_NAME         = "symbol"
 	DECIMALS_NAME       = "decimals"
 	TOTALSUPPLY_NAME    = "totalSupply"
 	BALANCEOF_NAME      = "balanceOf"
 	ALLOWANCE_NAME      = "allowance"
+
+	//max length of transfer memo in bytes
+	MAX_MEMO_LEN = 256
+	//max count of transfers in one transferMulti
+	MAX_MULTI_TRANSFERS = 500
 )
 
 func AddNotifications(native *native.NativeService, contract common.Address, state *State) {
@@ -58,6 +65,29 @@ func AddNotifications(native *native.NativeService, contract common.Address, sta
 		})
 }
 
+//AddMemoNotifications emits transfer event of transferMulti, memo follows states of transfer event
+func AddMemoNotifications(native *native.NativeService, contract common.Address, state *MemoState) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{TRANSFER_NAME, state.From.ToBase58(), state.To.ToBase58(), state.Value, state.Memo},
+		})
+}
+
+//CheckMemo checks memo of transfer is readable and not too long
+func CheckMemo(memo string) error {
+	if len(memo) > MAX_MEMO_LEN {
+		return fmt.Errorf("memo length:%d over %d", len(memo), MAX_MEMO_LEN)
+	}
+	if !utf8.ValidString(memo) {
+		return fmt.Errorf("memo is not valid utf8 string")
+	}
+	return nil
+}
+
 func GetToUInt64StorageItem(toBalance, value uint64) *cstates.StorageItem {
 	bf := new(bytes.Buffer)
 	serialization.WriteUint64(bf, toBalance+value)
@@ -61,3 +61,18 @@ func triggerRecoveryEvent(srvc *native.NativeService, op string, id []byte, addr
 	st := []string{"Recovery", op, string(id), addr.ToHexString()}
 	newEvent(srvc, st)
 }
+
+func triggerRecoveryGroupEvent(srvc *native.NativeService, id []byte, group *recoveryGroup) {
+	members := make([]string, len(group.members))
+	for i, v := range group.members {
+		members[i] = v.ToBase58()
+	}
+	st := []interface{}{"Recovery", "setGroup", string(id), group.threshold, members, group.delay}
+	newEvent(srvc, st)
+}
+
+func triggerPendingRecoveryEvent(srvc *native.NativeService, op string, id []byte, operator common.Address, pending *pendingRecovery) {
+	st := []interface{}{"Recovery", op, string(id), operator.ToBase58(), hex.EncodeToString(pending.newKey),
+		len(pending.approvals), pending.effectiveTime}
+	newEvent(srvc, st)
+}
@@ -32,6 +32,11 @@ func RegisterIDContract(srvc *native.NativeService) {
 	srvc.Register("removeKey", removeKey)
 	srvc.Register("addRecovery", addRecovery)
 	srvc.Register("changeRecovery", changeRecovery)
+	srvc.Register("setRecoveryGroup", setRecoveryGroup)
+	srvc.Register("requestRecovery", requestRecovery)
+	srvc.Register("approveRecovery", approveRecovery)
+	srvc.Register("executeRecovery", executeRecovery)
+	srvc.Register("cancelRecovery", cancelRecovery)
 	srvc.Register("regIDWithAttributes", regIdWithAttributes)
 	srvc.Register("addAttributes", addAttributes)
 	srvc.Register("removeAttribute", removeAttribute)
@@ -40,5 +45,7 @@ func RegisterIDContract(srvc *native.NativeService) {
 	srvc.Register("getKeyState", GetKeyState)
 	srvc.Register("getAttributes", GetAttributes)
 	srvc.Register("getDDO", GetDDO)
+	srvc.Register("getRecoveryGroup", GetRecoveryGroup)
+	srvc.Register("getPendingRecovery", GetPendingRecovery)
 	return
 }
@@ -312,6 +312,12 @@ func addRecovery(srvc *native.NativeService) ([]byte, error) {
 	if err == nil && len(re) > 0 {
 		return utils.BYTE_FALSE, errors.New("add recovery failed: already set recovery")
 	}
+	group, err := getRecoveryGroup(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("add recovery failed: " + err.Error())
+	} else if group != nil {
+		return utils.BYTE_FALSE, errors.New("add recovery failed: already set recovery group")
+	}
 
 	err = setRecovery(srvc, key, arg1)
 	if err != nil {
@@ -177,3 +177,17 @@ func isOwner(srvc *native.NativeService, encID, pub []byte) bool {
 	}
 	return true
 }
+
+//isActiveOwner returns whether pub is a public key of ID and not revoked
+func isActiveOwner(srvc *native.NativeService, encID, pub []byte) bool {
+	kID, err := findPk(srvc, encID, pub)
+	if err != nil || kID == 0 {
+		return false
+	}
+	pk, err := getPk(srvc, encID, kID)
+	if err != nil {
+		log.Debug(err)
+		return false
+	}
+	return !pk.revoked
+}
@@ -0,0 +1,546 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package onxid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	com "github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/constants"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//max delay of recovery is one year
+const MAX_RECOVERY_DELAY = 365 * 24 * 3600
+
+//recoveryGroup is M-of-N recovery addresses of ID. Recovery takes effect delay seconds after
+//threshold approvals are collected, in which time the owner can cancel it.
+type recoveryGroup struct {
+	threshold uint32
+	members   []com.Address
+	delay     uint32
+}
+
+func (this *recoveryGroup) Serialize(w io.Writer) error {
+	if err := utils.WriteVarUint(w, uint64(this.threshold)); err != nil {
+		return err
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.members))); err != nil {
+		return err
+	}
+	for _, addr := range this.members {
+		if err := utils.WriteAddress(w, addr); err != nil {
+			return err
+		}
+	}
+	return utils.WriteVarUint(w, uint64(this.delay))
+}
+
+func (this *recoveryGroup) Deserialize(r io.Reader) error {
+	threshold, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("read threshold error, %s", err)
+	}
+	num, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("read member number error, %s", err)
+	}
+	if num > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return fmt.Errorf("too many members: %d", num)
+	}
+	members := make([]com.Address, 0, num)
+	for i := uint64(0); i < num; i++ {
+		addr, err := utils.ReadAddress(r)
+		if err != nil {
+			return fmt.Errorf("read member error, %s", err)
+		}
+		members = append(members, addr)
+	}
+	delay, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("read delay error, %s", err)
+	}
+	if threshold > 0xFFFFFFFF || delay > 0xFFFFFFFF {
+		return errors.New("invalid threshold or delay")
+	}
+	this.threshold = uint32(threshold)
+	this.members = members
+	this.delay = uint32(delay)
+	return nil
+}
+
+func (this *recoveryGroup) check() error {
+	size := len(this.members)
+	if size == 0 || size > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return fmt.Errorf("invalid member number: %d", size)
+	}
+	if this.threshold == 0 || int(this.threshold) > size {
+		return fmt.Errorf("invalid threshold: %d", this.threshold)
+	}
+	if this.delay > MAX_RECOVERY_DELAY {
+		return fmt.Errorf("delay exceeds %d seconds", MAX_RECOVERY_DELAY)
+	}
+	for i, addr := range this.members {
+		for _, other := range this.members[i+1:] {
+			if addr == other {
+				return fmt.Errorf("duplicate member: %s", addr.ToBase58())
+			}
+		}
+	}
+	return nil
+}
+
+func (this *recoveryGroup) isMember(addr com.Address) bool {
+	for _, member := range this.members {
+		if member == addr {
+			return true
+		}
+	}
+	return false
+}
+
+//pendingRecovery is a recovery request waiting for approvals or delay
+type pendingRecovery struct {
+	newKey        []byte
+	approvals     []com.Address
+	requestTime   uint32
+	effectiveTime uint32 //0 before threshold approvals are collected
+}
+
+func (this *pendingRecovery) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarBytes(w, this.newKey); err != nil {
+		return err
+	}
+	if err := utils.WriteVarUint(w, uint64(len(this.approvals))); err != nil {
+		return err
+	}
+	for _, addr := range this.approvals {
+		if err := utils.WriteAddress(w, addr); err != nil {
+			return err
+		}
+	}
+	if err := serialization.WriteUint32(w, this.requestTime); err != nil {
+		return err
+	}
+	return serialization.WriteUint32(w, this.effectiveTime)
+}
+
+func (this *pendingRecovery) Deserialize(r io.Reader) error {
+	newKey, err := serialization.ReadVarBytes(r)
+	if err != nil {
+		return fmt.Errorf("read new key error, %s", err)
+	}
+	num, err := utils.ReadVarUint(r)
+	if err != nil {
+		return fmt.Errorf("read approval number error, %s", err)
+	}
+	if num > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return fmt.Errorf("too many approvals: %d", num)
+	}
+	approvals := make([]com.Address, 0, num)
+	for i := uint64(0); i < num; i++ {
+		addr, err := utils.ReadAddress(r)
+		if err != nil {
+			return fmt.Errorf("read approval error, %s", err)
+		}
+		approvals = append(approvals, addr)
+	}
+	requestTime, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("read request time error, %s", err)
+	}
+	effectiveTime, err := serialization.ReadUint32(r)
+	if err != nil {
+		return fmt.Errorf("read effective time error, %s", err)
+	}
+	this.newKey = newKey
+	this.approvals = approvals
+	this.requestTime = requestTime
+	this.effectiveTime = effectiveTime
+	return nil
+}
+
+func (this *pendingRecovery) hasApproved(addr com.Address) bool {
+	for _, approval := range this.approvals {
+		if approval == addr {
+			return true
+		}
+	}
+	return false
+}
+
+//approve adds approval of member, and starts delay when threshold is reached
+func (this *pendingRecovery) approve(srvc *native.NativeService, group *recoveryGroup, addr com.Address) {
+	this.approvals = append(this.approvals, addr)
+	if this.effectiveTime == 0 && uint32(len(this.approvals)) >= group.threshold {
+		this.effectiveTime = srvc.Time + group.delay
+	}
+}
+
+func fieldKey(encID []byte, field byte) []byte {
+	key := make([]byte, 0, len(encID)+1)
+	key = append(key, encID...)
+	return append(key, field)
+}
+
+func getRecoveryGroup(srvc *native.NativeService, encID []byte) (*recoveryGroup, error) {
+	item, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_RECOVERY_GROUP))
+	if err != nil {
+		return nil, fmt.Errorf("get recovery group error, %s", err)
+	} else if item == nil {
+		return nil, nil
+	}
+	group := new(recoveryGroup)
+	err = group.Deserialize(bytes.NewBuffer(item.Value))
+	if err != nil {
+		return nil, fmt.Errorf("deserialize recovery group error, %s", err)
+	}
+	return group, nil
+}
+
+func putRecoveryGroup(srvc *native.NativeService, encID []byte, group *recoveryGroup) error {
+	var buf bytes.Buffer
+	err := group.Serialize(&buf)
+	if err != nil {
+		return fmt.Errorf("serialize recovery group error, %s", err)
+	}
+	srvc.CacheDB.Put(fieldKey(encID, FIELD_RECOVERY_GROUP), states.GenRawStorageItem(buf.Bytes()))
+	return nil
+}
+
+func getPendingRecovery(srvc *native.NativeService, encID []byte) (*pendingRecovery, error) {
+	item, err := utils.GetStorageItem(srvc, fieldKey(encID, FIELD_PENDING_RECOVERY))
+	if err != nil {
+		return nil, fmt.Errorf("get pending recovery error, %s", err)
+	} else if item == nil {
+		return nil, nil
+	}
+	pending := new(pendingRecovery)
+	err = pending.Deserialize(bytes.NewBuffer(item.Value))
+	if err != nil {
+		return nil, fmt.Errorf("deserialize pending recovery error, %s", err)
+	}
+	return pending, nil
+}
+
+func putPendingRecovery(srvc *native.NativeService, encID []byte, pending *pendingRecovery) error {
+	var buf bytes.Buffer
+	err := pending.Serialize(&buf)
+	if err != nil {
+		return fmt.Errorf("serialize pending recovery error, %s", err)
+	}
+	srvc.CacheDB.Put(fieldKey(encID, FIELD_PENDING_RECOVERY), states.GenRawStorageItem(buf.Bytes()))
+	return nil
+}
+
+//readRecoveryMember reads ID and operator address, and checks operator is a witnessed member of recovery group
+func readRecoveryMember(srvc *native.NativeService, args io.Reader, id []byte) ([]byte, *recoveryGroup, com.Address, error) {
+	operator, err := utils.ReadAddress(args)
+	if err != nil {
+		return nil, nil, com.ADDRESS_EMPTY, fmt.Errorf("operator argument error, %s", err)
+	}
+	key, err := encodeID(id)
+	if err != nil {
+		return nil, nil, com.ADDRESS_EMPTY, err
+	}
+	if !checkIDExistence(srvc, key) {
+		return nil, nil, com.ADDRESS_EMPTY, errors.New("ID not registered")
+	}
+	group, err := getRecoveryGroup(srvc, key)
+	if err != nil {
+		return nil, nil, com.ADDRESS_EMPTY, err
+	} else if group == nil {
+		return nil, nil, com.ADDRESS_EMPTY, errors.New("recovery group not set")
+	}
+	if !group.isMember(operator) {
+		return nil, nil, com.ADDRESS_EMPTY, errors.New("operator is not recovery member")
+	}
+	if !srvc.ContextRef.CheckWitness(operator) {
+		return nil, nil, com.ADDRESS_EMPTY, errors.New("check witness failed, " + operator.ToBase58())
+	}
+	return key, group, operator, nil
+}
+
+func setRecoveryGroup(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: argument 0 error")
+	}
+	// arg1: recovery group
+	arg1 := new(recoveryGroup)
+	err = arg1.Deserialize(args)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: argument 1 error, %s", err)
+	}
+	// arg2: operator's public key
+	arg2, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: argument 2 error")
+	}
+
+	err = arg1.check()
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: %s", err)
+	}
+	err = checkWitness(srvc, arg2)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: %s", err)
+	}
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: %s", err)
+	}
+	if !checkIDExistence(srvc, key) {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: ID not registered")
+	}
+	if !isActiveOwner(srvc, key, arg2) {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: not authorized")
+	}
+	re, err := getRecovery(srvc, key)
+	if err == nil && len(re) > 0 {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: single recovery already set")
+	}
+	pending, err := getPendingRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: %s", err)
+	} else if pending != nil {
+		return utils.BYTE_FALSE, errors.New("set recovery group failed: recovery is pending")
+	}
+
+	err = putRecoveryGroup(srvc, key, arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("set recovery group failed: %s", err)
+	}
+	triggerRecoveryGroupEvent(srvc, arg0, arg1)
+	return utils.BYTE_TRUE, nil
+}
+
+func requestRecovery(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("request recovery failed: argument 0 error")
+	}
+	// arg1: new public key
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("request recovery failed: argument 1 error")
+	}
+	if _, err := keypair.DeserializePublicKey(arg1); err != nil {
+		return utils.BYTE_FALSE, errors.New("request recovery failed: invalid public key")
+	}
+	// arg2: operator's address, who should be recovery member
+	key, group, operator, err := readRecoveryMember(srvc, args, arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("request recovery failed: %s", err)
+	}
+	pending, err := getPendingRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("request recovery failed: %s", err)
+	} else if pending != nil {
+		return utils.BYTE_FALSE, errors.New("request recovery failed: recovery is pending")
+	}
+
+	pending = &pendingRecovery{
+		newKey:      arg1,
+		requestTime: srvc.Time,
+	}
+	pending.approve(srvc, group, operator)
+	err = putPendingRecovery(srvc, key, pending)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("request recovery failed: %s", err)
+	}
+	triggerPendingRecoveryEvent(srvc, "request", arg0, operator, pending)
+	return utils.BYTE_TRUE, nil
+}
+
+func approveRecovery(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("approve recovery failed: argument 0 error")
+	}
+	// arg1: operator's address, who should be recovery member
+	key, group, operator, err := readRecoveryMember(srvc, args, arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve recovery failed: %s", err)
+	}
+	pending, err := getPendingRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve recovery failed: %s", err)
+	} else if pending == nil {
+		return utils.BYTE_FALSE, errors.New("approve recovery failed: no pending recovery")
+	}
+	if pending.hasApproved(operator) {
+		return utils.BYTE_FALSE, errors.New("approve recovery failed: already approved")
+	}
+
+	pending.approve(srvc, group, operator)
+	err = putPendingRecovery(srvc, key, pending)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("approve recovery failed: %s", err)
+	}
+	triggerPendingRecoveryEvent(srvc, "approve", arg0, operator, pending)
+	return utils.BYTE_TRUE, nil
+}
+
+//executeRecovery adds new public key and revokes all the other keys of ID
+func executeRecovery(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("execute recovery failed: argument 0 error")
+	}
+	// arg1: operator's address, who should be recovery member
+	key, group, operator, err := readRecoveryMember(srvc, args, arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: %s", err)
+	}
+	pending, err := getPendingRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: %s", err)
+	} else if pending == nil {
+		return utils.BYTE_FALSE, errors.New("execute recovery failed: no pending recovery")
+	}
+	if uint32(len(pending.approvals)) < group.threshold {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: approvals %d less than threshold %d",
+			len(pending.approvals), group.threshold)
+	}
+	if srvc.Time < pending.effectiveTime {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: recovery takes effect at %d", pending.effectiveTime)
+	}
+
+	pkKey := fieldKey(key, FIELD_PK)
+	owners, err := getAllPk(srvc, pkKey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: %s", err)
+	}
+	var keyID uint32
+	for i, v := range owners {
+		if bytes.Equal(v.key, pending.newKey) {
+			if v.revoked {
+				return utils.BYTE_FALSE, errors.New("execute recovery failed: new public key has been revoked")
+			}
+			keyID = uint32(i + 1)
+			continue
+		}
+		if !v.revoked {
+			v.revoked = true
+			triggerPublicEvent(srvc, "remove", arg0, v.key, uint32(i+1))
+		}
+	}
+	if keyID == 0 {
+		owners = append(owners, &owner{pending.newKey, false})
+		keyID = uint32(len(owners))
+		triggerPublicEvent(srvc, "add", arg0, pending.newKey, keyID)
+	}
+	err = putAllPk(srvc, pkKey, owners)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("execute recovery failed: %s", err)
+	}
+	srvc.CacheDB.Delete(fieldKey(key, FIELD_PENDING_RECOVERY))
+	triggerPendingRecoveryEvent(srvc, "execute", arg0, operator, pending)
+	return utils.BYTE_TRUE, nil
+}
+
+func cancelRecovery(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	// arg0: ID
+	arg0, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("cancel recovery failed: argument 0 error")
+	}
+	// arg1: operator's public key, who should be owner
+	arg1, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("cancel recovery failed: argument 1 error")
+	}
+	err = checkWitness(srvc, arg1)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel recovery failed: %s", err)
+	}
+	key, err := encodeID(arg0)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel recovery failed: %s", err)
+	}
+	if !isActiveOwner(srvc, key, arg1) {
+		return utils.BYTE_FALSE, errors.New("cancel recovery failed: not authorized")
+	}
+	pending, err := getPendingRecovery(srvc, key)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("cancel recovery failed: %s", err)
+	} else if pending == nil {
+		return utils.BYTE_FALSE, errors.New("cancel recovery failed: no pending recovery")
+	}
+	srvc.CacheDB.Delete(fieldKey(key, FIELD_PENDING_RECOVERY))
+	pk, _ := keypair.DeserializePublicKey(arg1)
+	triggerPendingRecoveryEvent(srvc, "cancel", arg0, types.AddressFromPubKey(pk), pending)
+	return utils.BYTE_TRUE, nil
+}
+
+func GetRecoveryGroup(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	did, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return nil, fmt.Errorf("get recovery group error: invalid argument, %s", err)
+	}
+	key, err := encodeID(did)
+	if err != nil {
+		return nil, fmt.Errorf("get recovery group error: %s", err)
+	}
+	item, err := utils.GetStorageItem(srvc, fieldKey(key, FIELD_RECOVERY_GROUP))
+	if err != nil {
+		return nil, fmt.Errorf("get recovery group error: %s", err)
+	} else if item == nil {
+		return nil, nil
+	}
+	return item.Value, nil
+}
+
+func GetPendingRecovery(srvc *native.NativeService) ([]byte, error) {
+	args := bytes.NewBuffer(srvc.Input)
+	did, err := serialization.ReadVarBytes(args)
+	if err != nil {
+		return nil, fmt.Errorf("get pending recovery error: invalid argument, %s", err)
+	}
+	key, err := encodeID(did)
+	if err != nil {
+		return nil, fmt.Errorf("get pending recovery error: %s", err)
+	}
+	item, err := utils.GetStorageItem(srvc, fieldKey(key, FIELD_PENDING_RECOVERY))
+	if err != nil {
+		return nil, fmt.Errorf("get pending recovery error: %s", err)
+	} else if item == nil {
+		return nil, nil
+	}
+	return item.Value, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package onxid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryGroup_Serialize(t *testing.T) {
+	group := recoveryGroup{
+		threshold: 2,
+		members: []common.Address{
+			common.AddressFromVmCode([]byte{1, 2, 3}),
+			common.AddressFromVmCode([]byte{4, 5, 6}),
+			common.AddressFromVmCode([]byte{7, 8, 9}),
+		},
+		delay: 3600,
+	}
+	bf := new(bytes.Buffer)
+	if err := group.Serialize(bf); err != nil {
+		t.Fatal("recovery group serialize fail!")
+	}
+
+	group2 := recoveryGroup{}
+	if err := group2.Deserialize(bf); err != nil {
+		t.Fatal("recovery group deserialize fail!")
+	}
+	assert.Equal(t, group, group2)
+	assert.Nil(t, group2.check())
+	assert.True(t, group2.isMember(group.members[1]))
+	assert.False(t, group2.isMember(common.AddressFromVmCode([]byte{0})))
+}
+
+func TestRecoveryGroup_Check(t *testing.T) {
+	addr := common.AddressFromVmCode([]byte{1, 2, 3})
+	group := recoveryGroup{threshold: 2, members: []common.Address{addr}}
+	assert.NotNil(t, group.check())
+
+	group = recoveryGroup{threshold: 0, members: []common.Address{addr}}
+	assert.NotNil(t, group.check())
+
+	group = recoveryGroup{threshold: 1, members: []common.Address{addr, addr}}
+	assert.NotNil(t, group.check())
+
+	group = recoveryGroup{threshold: 1, members: []common.Address{addr}, delay: MAX_RECOVERY_DELAY + 1}
+	assert.NotNil(t, group.check())
+}
+
+func TestPendingRecovery_Serialize(t *testing.T) {
+	pending := pendingRecovery{
+		newKey:        []byte{2, 3, 4},
+		approvals:     []common.Address{common.AddressFromVmCode([]byte{1, 2, 3})},
+		requestTime:   100,
+		effectiveTime: 3700,
+	}
+	bf := new(bytes.Buffer)
+	if err := pending.Serialize(bf); err != nil {
+		t.Fatal("pending recovery serialize fail!")
+	}
+
+	pending2 := pendingRecovery{}
+	if err := pending2.Deserialize(bf); err != nil {
+		t.Fatal("pending recovery deserialize fail!")
+	}
+	assert.Equal(t, pending, pending2)
+	assert.True(t, pending2.hasApproved(pending.approvals[0]))
+}
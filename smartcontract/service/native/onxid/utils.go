@@ -48,6 +48,8 @@ const (
 	FIELD_PK byte = 1 + iota
 	FIELD_ATTR
 	FIELD_RECOVERY
+	FIELD_RECOVERY_GROUP
+	FIELD_PENDING_RECOVERY
 )
 
 func encodeID(id []byte) ([]byte, error) {
@@ -40,6 +40,7 @@ func RegisterOxgContract(native *native.NativeService) {
 	native.Register(onx.TRANSFER_NAME, OxgTransfer)
 	native.Register(onx.APPROVE_NAME, OxgApprove)
 	native.Register(onx.TRANSFERFROM_NAME, OxgTransferFrom)
+	native.Register(onx.TRANSFER_MULTI_NAME, OxgTransferMulti)
 	native.Register(onx.NAME_NAME, OxgName)
 	native.Register(onx.SYMBOL_NAME, OxgSymbol)
 	native.Register(onx.DECIMALS_NAME, OxgDecimals)
@@ -88,6 +89,32 @@ func OxgTransfer(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//OxgTransferMulti transfers as OxgTransfer, with memo of each transfer emitted in transfer event
+func OxgTransferMulti(native *native.NativeService) ([]byte, error) {
+	var transfers onx.MultiTransfers
+	source := common.NewZeroCopySource(native.Input)
+	if err := transfers.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[OxgTransferMulti] Transfers deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	for _, v := range transfers.States {
+		if v.Value == 0 {
+			continue
+		}
+		if v.Value > constants.OXG_TOTAL_SUPPLY {
+			return utils.BYTE_FALSE, fmt.Errorf("transfer oxg amount:%d over totalSupply:%d", v.Value, constants.OXG_TOTAL_SUPPLY)
+		}
+		if err := onx.CheckMemo(v.Memo); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		if _, _, err := onx.Transfer(native, contract, &onx.State{From: v.From, To: v.To, Value: v.Value}); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		onx.AddMemoNotifications(native, contract, &v)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 func OxgApprove(native *native.NativeService) ([]byte, error) {
 	var state onx.State
 	source := common.NewZeroCopySource(native.Input)
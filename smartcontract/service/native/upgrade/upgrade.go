@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+const (
+	//same as contract migration of neovm
+	UPGRADE_CONTRACT_GAS      uint64 = 20000000
+	UINT_UPGRADE_CODE_LEN_GAS uint64 = 200000
+	PER_UNIT_CODE_LEN         int    = 1024
+
+	MAX_CODE_SIZE = 1024 * 1024
+)
+
+type UpgradeParam struct {
+	Contract    common.Address
+	Code        []byte
+	NeedStorage bool
+	Name        string
+	Version     string
+	Author      string
+	Email       string
+	Description string
+	KeyNo       uint64
+}
+
+func (this *UpgradeParam) Serialize(w io.Writer) error {
+	if err := utils.WriteAddress(w, this.Contract); err != nil {
+		return fmt.Errorf("serialize contract error:%v", err)
+	}
+	if err := serialization.WriteVarBytes(w, this.Code); err != nil {
+		return fmt.Errorf("serialize code error:%v", err)
+	}
+	if err := serialization.WriteBool(w, this.NeedStorage); err != nil {
+		return fmt.Errorf("serialize need storage error:%v", err)
+	}
+	for _, s := range []string{this.Name, this.Version, this.Author, this.Email, this.Description} {
+		if err := serialization.WriteString(w, s); err != nil {
+			return fmt.Errorf("serialize contract info error:%v", err)
+		}
+	}
+	if err := utils.WriteVarUint(w, this.KeyNo); err != nil {
+		return fmt.Errorf("serialize key number error:%v", err)
+	}
+	return nil
+}
+
+func (this *UpgradeParam) Deserialize(r io.Reader) error {
+	var err error
+	if this.Contract, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("deserialize contract error:%v", err)
+	}
+	if this.Code, err = serialization.ReadVarBytes(r); err != nil {
+		return fmt.Errorf("deserialize code error:%v", err)
+	}
+	if this.NeedStorage, err = serialization.ReadBool(r); err != nil {
+		return fmt.Errorf("deserialize need storage error:%v", err)
+	}
+	for _, s := range []*string{&this.Name, &this.Version, &this.Author, &this.Email, &this.Description} {
+		if *s, err = serialization.ReadString(r); err != nil {
+			return fmt.Errorf("deserialize contract info error:%v", err)
+		}
+	}
+	if this.KeyNo, err = utils.ReadVarUint(r); err != nil {
+		return fmt.Errorf("deserialize key number error:%v", err)
+	}
+	return nil
+}
+
+//UpgradeContract replaces code of contract in place, keeping its address and storage. It should be signed by
+//admin ONX ID of contract in auth contract, and invokes onUpgrade of new code with previous version.
+func UpgradeContract(native *native.NativeService) ([]byte, error) {
+	if !config.DefConfig.Genesis.Features.ContractUpgradeActive(native.Height) {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, not active at height %d", native.Height)
+	}
+	param := new(UpgradeParam)
+	if err := param.Deserialize(bytes.NewBuffer(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, %s", err)
+	}
+	if len(param.Code) == 0 || len(param.Code) > MAX_CODE_SIZE {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, code size should be in (0, %d]", MAX_CODE_SIZE)
+	}
+	gas := UPGRADE_CONTRACT_GAS + uint64(len(param.Code)/PER_UNIT_CODE_LEN)*UINT_UPGRADE_CODE_LEN_GAS
+	if !native.ContextRef.CheckUseGas(gas) {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, gas insufficient, need:%d", gas)
+	}
+	old, err := native.CacheDB.GetContract(param.Contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, get contract error:%s", err)
+	}
+	if old == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, contract %s not found", param.Contract.ToHexString())
+	}
+	if bytes.Equal(old.Code, param.Code) {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, code not changed")
+	}
+	ok, err := auth.VerifyContractAdmin(native, param.Contract, param.KeyNo)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, verify admin error:%s", err)
+	}
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, not signed by admin of contract %s", param.Contract.ToHexString())
+	}
+
+	contract := &payload.DeployCode{
+		Code:        param.Code,
+		NeedStorage: param.NeedStorage,
+		Name:        param.Name,
+		Version:     param.Version,
+		Author:      param.Author,
+		Email:       param.Email,
+		Description: param.Description,
+	}
+	if err := native.CacheDB.PutContractAt(param.Contract, contract); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, put contract error:%s", err)
+	}
+	notify, err := AddVersion(native.CacheDB, param.Contract, &ContractVersion{
+		Action:   ACTION_UPGRADE,
+		Address:  param.Contract,
+		CodeHash: contract.Address(),
+		Version:  param.Version,
+		Height:   native.Height,
+		TxHash:   native.Tx.Hash(),
+	})
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, %s", err)
+	}
+	native.Notifications = append(native.Notifications, notify)
+
+	if _, err := native.ContextRef.AppCall(param.Contract, ON_UPGRADE, []interface{}{[]byte(old.Version)}); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("upgradeContract, %s error:%s", ON_UPGRADE, err)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+//GetVersions returns serialized version history of contract
+func GetVersions(native *native.NativeService) ([]byte, error) {
+	contract, err := utils.ReadAddress(bytes.NewBuffer(native.Input))
+	if err != nil {
+		return nil, fmt.Errorf("getVersions, %s", err)
+	}
+	versions, err := GetContractVersions(native.CacheDB, contract)
+	if err != nil {
+		return nil, fmt.Errorf("getVersions, %s", err)
+	}
+	bf := new(bytes.Buffer)
+	if err := versions.Serialize(bf); err != nil {
+		return nil, fmt.Errorf("getVersions, %s", err)
+	}
+	return bf.Bytes(), nil
+}
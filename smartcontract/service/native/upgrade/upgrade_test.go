@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package upgrade
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+func TestContractVersions_Serialize(t *testing.T) {
+	versions := ContractVersions{
+		{Index: 0, Action: ACTION_DEPLOY, Address: common.Address{1}, CodeHash: common.Address{1}, Version: "1.0",
+			Height: 10, TxHash: common.Uint256{1}},
+		{Index: 1, Action: ACTION_UPGRADE, Address: common.Address{1}, CodeHash: common.Address{2}, Version: "2.0",
+			Height: 20, TxHash: common.Uint256{2}},
+	}
+	bf := new(bytes.Buffer)
+	err := versions.Serialize(bf)
+	assert.Nil(t, err)
+
+	var versions2 ContractVersions
+	err = versions2.Deserialize(bytes.NewReader(bf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, versions, versions2)
+}
+
+func TestUpgradeParam_Serialize(t *testing.T) {
+	param := &UpgradeParam{
+		Contract:    common.Address{1, 2, 3},
+		Code:        []byte{0x51, 0xc3},
+		NeedStorage: true,
+		Name:        "name",
+		Version:     "2.0",
+		Author:      "author",
+		Email:       "email",
+		Description: "desc",
+		KeyNo:       1,
+	}
+	bf := new(bytes.Buffer)
+	err := param.Serialize(bf)
+	assert.Nil(t, err)
+
+	param2 := &UpgradeParam{}
+	err = param2.Deserialize(bytes.NewReader(bf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, param, param2)
+}
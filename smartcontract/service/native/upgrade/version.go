@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+)
+
+const VERSIONS_PREFIX = "versions"
+
+const (
+	ACTION_DEPLOY  byte = 1
+	ACTION_MIGRATE byte = 2
+	ACTION_UPGRADE byte = 3
+)
+
+var ActionNames = map[byte]string{
+	ACTION_DEPLOY:  "deploy",
+	ACTION_MIGRATE: "migrate",
+	ACTION_UPGRADE: "upgrade",
+}
+
+//ContractVersion is one entry of contract version history
+type ContractVersion struct {
+	Index    uint32
+	Action   byte
+	Address  common.Address //address of contract after this action
+	CodeHash common.Address //hash of code, equals Address unless upgraded in place
+	Version  string
+	Height   uint32
+	TxHash   common.Uint256
+}
+
+func (this *ContractVersion) Serialize(w io.Writer) error {
+	if err := serialization.WriteUint32(w, this.Index); err != nil {
+		return fmt.Errorf("serialize index error:%v", err)
+	}
+	if err := serialization.WriteByte(w, this.Action); err != nil {
+		return fmt.Errorf("serialize action error:%v", err)
+	}
+	if err := utils.WriteAddress(w, this.Address); err != nil {
+		return fmt.Errorf("serialize address error:%v", err)
+	}
+	if err := utils.WriteAddress(w, this.CodeHash); err != nil {
+		return fmt.Errorf("serialize code hash error:%v", err)
+	}
+	if err := serialization.WriteString(w, this.Version); err != nil {
+		return fmt.Errorf("serialize version error:%v", err)
+	}
+	if err := serialization.WriteUint32(w, this.Height); err != nil {
+		return fmt.Errorf("serialize height error:%v", err)
+	}
+	if err := this.TxHash.Serialize(w); err != nil {
+		return fmt.Errorf("serialize tx hash error:%v", err)
+	}
+	return nil
+}
+
+func (this *ContractVersion) Deserialize(r io.Reader) error {
+	var err error
+	if this.Index, err = serialization.ReadUint32(r); err != nil {
+		return fmt.Errorf("deserialize index error:%v", err)
+	}
+	if this.Action, err = serialization.ReadByte(r); err != nil {
+		return fmt.Errorf("deserialize action error:%v", err)
+	}
+	if this.Address, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("deserialize address error:%v", err)
+	}
+	if this.CodeHash, err = utils.ReadAddress(r); err != nil {
+		return fmt.Errorf("deserialize code hash error:%v", err)
+	}
+	if this.Version, err = serialization.ReadString(r); err != nil {
+		return fmt.Errorf("deserialize version error:%v", err)
+	}
+	if this.Height, err = serialization.ReadUint32(r); err != nil {
+		return fmt.Errorf("deserialize height error:%v", err)
+	}
+	if err = this.TxHash.Deserialize(r); err != nil {
+		return fmt.Errorf("deserialize tx hash error:%v", err)
+	}
+	return nil
+}
+
+type ContractVersions []*ContractVersion
+
+func (this ContractVersions) Serialize(w io.Writer) error {
+	if err := serialization.WriteVarUint(w, uint64(len(this))); err != nil {
+		return fmt.Errorf("serialize versions length error:%v", err)
+	}
+	for _, v := range this {
+		if err := v.Serialize(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *ContractVersions) Deserialize(r io.Reader) error {
+	n, err := serialization.ReadVarUint(r, 0)
+	if err != nil {
+		return fmt.Errorf("deserialize versions length error:%v", err)
+	}
+	versions := make(ContractVersions, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v := new(ContractVersion)
+		if err := v.Deserialize(r); err != nil {
+			return err
+		}
+		versions = append(versions, v)
+	}
+	*this = versions
+	return nil
+}
+
+//VersionsKey returns key of contract version history in upgrade contract storage, without contract address
+func VersionsKey(contract common.Address) []byte {
+	return append([]byte(VERSIONS_PREFIX), contract[:]...)
+}
+
+//GetContractVersions returns version history of contract, empty if not recorded
+func GetContractVersions(cache *storage.CacheDB, contract common.Address) (ContractVersions, error) {
+	item, err := cache.Get(utils.ConcatKey(utils.UpgradeContractAddress, VersionsKey(contract)))
+	if err != nil {
+		return nil, fmt.Errorf("get versions of contract %s error:%v", contract.ToHexString(), err)
+	}
+	if item == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(item)
+	if err != nil {
+		return nil, err
+	}
+	var versions ContractVersions
+	if err := versions.Deserialize(bytes.NewBuffer(value)); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+//AddVersion appends v to version history of contract from, stores history at v.Address and returns event
+//for indexers. History of migrated contract is kept at both old and new address.
+func AddVersion(cache *storage.CacheDB, from common.Address, v *ContractVersion) (*event.NotifyEventInfo, error) {
+	versions, err := GetContractVersions(cache, from)
+	if err != nil {
+		return nil, err
+	}
+	v.Index = uint32(len(versions))
+	versions = append(versions, v)
+	bf := new(bytes.Buffer)
+	if err := versions.Serialize(bf); err != nil {
+		return nil, err
+	}
+	item := states.GenRawStorageItem(bf.Bytes())
+	cache.Put(utils.ConcatKey(utils.UpgradeContractAddress, VersionsKey(v.Address)), item)
+	if from != v.Address {
+		cache.Put(utils.ConcatKey(utils.UpgradeContractAddress, VersionsKey(from)), item)
+	}
+	return &event.NotifyEventInfo{
+		ContractAddress: utils.UpgradeContractAddress,
+		States: []interface{}{ActionNames[v.Action], v.Address.ToHexString(), v.Index,
+			v.CodeHash.ToHexString(), v.Version, from.ToHexString()},
+	}, nil
+}
@@ -45,6 +45,8 @@ var (
 	HASH160_GAS                   uint64 = 20
 	HASH256_GAS                   uint64 = 20
 	OPCODE_GAS                    uint64 = 1
+	STORAGE_BYTE_GAS              uint64 = 0
+	STORAGE_REFUND_PERCENT        uint64 = 50
 
 	PER_UNIT_CODE_LEN    int = 1024
 	METHOD_LENGTH_LIMIT  int = 1024
@@ -104,6 +106,9 @@ var (
 	RUNTIME_GETCURRENTBLOCKHASH_NAME = "OnyxChain.Runtime.GetCurrentBlockHash"
 
 	NATIVE_INVOKE_NAME = "OnyxChain.Native.Invoke"
+	NATIVE_CALL_NAME   = "OnyxChain.Native.Call" // typed native invoke by abi, priced as NATIVE_INVOKE_NAME
+
+	ON_UPGRADE_NAME = "onUpgrade" // method invoked by upgrade contract after code is replaced, rejected from other callers
 
 	GETSCRIPTCONTAINER_NAME     = "System.ExecutionEngine.GetScriptContainer"
 	GETEXECUTINGSCRIPTHASH_NAME = "System.ExecutionEngine.GetExecutingScriptHash"
@@ -118,6 +123,9 @@ var (
 	HASH256_NAME              = "HASH256"
 	UINT_DEPLOY_CODE_LEN_NAME = "Deploy.Code.Gas"
 	UINT_INVOKE_CODE_LEN_NAME = "Invoke.Code.Gas"
+	OPCODE_NAME               = "Opcode"         // default price of opcode without its own price
+	STORAGE_BYTE_NAME         = "Storage.Byte"   // price per byte of storage growth of contract, in addition to STORAGE_PUT_NAME per KB
+	STORAGE_REFUND_NAME       = "Storage.Refund" // percent of STORAGE_BYTE_NAME refunded per byte of storage freed
 
 	GAS_TABLE = initGAS_TABLE()
 
@@ -22,8 +22,10 @@ import (
 	"fmt"
 
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
@@ -40,6 +42,9 @@ func ContractCreate(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	}
 	if dep == nil {
 		service.CacheDB.PutContract(contract)
+		if err := addContractVersion(service, contractAddress, contractAddress, upgrade.ACTION_DEPLOY, contract); err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractCreate] add contract version error!")
+		}
 		dep = contract
 	}
 	vm.PushData(engine, dep)
@@ -76,6 +81,17 @@ func ContractMigrate(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	if err := iter.Error(); err != nil {
 		return err
 	}
+	if config.DefConfig.Genesis.Features.StorageUsageActive(service.Height) {
+		usage, err := service.CacheDB.GetStorageUsage(oldAddr)
+		if err != nil {
+			return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] get storage usage error!")
+		}
+		service.CacheDB.PutStorageUsage(newAddr, usage)
+		service.CacheDB.PutStorageUsage(oldAddr, 0)
+	}
+	if err := addContractVersion(service, oldAddr, newAddr, upgrade.ACTION_MIGRATE, contract); err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractMigrate] add contract version error!")
+	}
 
 	vm.PushData(engine, contract)
 	return nil
@@ -104,8 +120,11 @@ func ContractDestory(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	if err := iter.Error(); err != nil {
 		return err
 	}
-
-	return nil
+	usage, err := service.CacheDB.GetStorageUsage(addr)
+	if err != nil {
+		return errors.NewDetailErr(err, errors.ErrNoCode, "[ContractDestory] get storage usage error!")
+	}
+	return updateStorageUsage(service, addr, usage, 0)
 }
 
 // ContractGetStorageContext put contract storage context to vm stack
@@ -216,3 +235,23 @@ func isContractExist(service *NeoVmService, contractAddress common.Address) erro
 	}
 	return nil
 }
+
+//addContractVersion records version of contract in upgrade contract, from its activation height
+func addContractVersion(service *NeoVmService, from, address common.Address, action byte, contract *payload.DeployCode) error {
+	if !config.DefConfig.Genesis.Features.ContractUpgradeActive(service.Height) {
+		return nil
+	}
+	notify, err := upgrade.AddVersion(service.CacheDB, from, &upgrade.ContractVersion{
+		Action:   action,
+		Address:  address,
+		CodeHash: contract.Address(),
+		Version:  contract.Version,
+		Height:   service.Height,
+		TxHash:   service.Tx.Hash(),
+	})
+	if err != nil {
+		return err
+	}
+	service.Notifications = append(service.Notifications, notify)
+	return nil
+}
@@ -19,11 +19,64 @@
 package neovm
 
 import (
+	"fmt"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 )
 
-func StoreGasCost(engine *vm.ExecutionEngine) (uint64, error) {
+//GasTable is gas prices by name of opcode, syscall or native method, resolved for the block being executed.
+//Name without price in table falls back to built-in price.
+type GasTable map[string]uint64
+
+//DefaultGasTable returns built-in gas prices, used before any gas param or gas schedule is set
+func DefaultGasTable() GasTable {
+	table := GasTable{
+		OPCODE_NAME:         OPCODE_GAS,
+		STORAGE_BYTE_NAME:   STORAGE_BYTE_GAS,
+		STORAGE_REFUND_NAME: STORAGE_REFUND_PERCENT,
+	}
+	GAS_TABLE.Range(func(key, value interface{}) bool {
+		table[key.(string)] = value.(uint64)
+		return true
+	})
+	return table
+}
+
+//Price returns gas price of name, ok is false if neither table nor built-in prices have it
+func (this GasTable) Price(name string) (uint64, bool) {
+	if price, ok := this[name]; ok {
+		return price, true
+	}
+	if price, ok := GAS_TABLE.Load(name); ok {
+		return price.(uint64), true
+	}
+	return 0, false
+}
+
+//StorageRefundPercent returns percent of storage byte price refunded for freed storage
+func (this GasTable) StorageRefundPercent() uint64 {
+	if percent, ok := this[STORAGE_REFUND_NAME]; ok {
+		return percent
+	}
+	return STORAGE_REFUND_PERCENT
+}
+
+//OpcodePrice returns default gas price of opcode
+func (this GasTable) OpcodePrice() uint64 {
+	if price, ok := this[OPCODE_NAME]; ok {
+		return price
+	}
+	return OPCODE_GAS
+}
+
+//NativeMethodGasName returns name of gas price of native contract method, which overrides NATIVE_INVOKE_NAME
+func NativeMethodGasName(contract common.Address, method string) string {
+	return fmt.Sprintf("%s.%s.%s", NATIVE_INVOKE_NAME, contract.ToHexString(), method)
+}
+
+func StoreGasCost(gasTable GasTable, engine *vm.ExecutionEngine) (uint64, error) {
 	key, err := vm.PeekNByteArray(1, engine)
 	if err != nil {
 		return 0, err
@@ -32,21 +85,47 @@ func StoreGasCost(engine *vm.ExecutionEngine) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
-	if putCost, ok := GAS_TABLE.Load(STORAGE_PUT_NAME); ok {
-		return uint64(((len(key)+len(value)-1)/1024 + 1)) * putCost.(uint64), nil
-	} else {
+	putCost, ok := gasTable.Price(STORAGE_PUT_NAME)
+	if !ok {
 		return uint64(0), errors.NewErr("[StoreGasCost] get STORAGE_PUT_NAME gas failed")
 	}
+	return uint64(((len(key)+len(value)-1)/1024 + 1)) * putCost, nil
+}
+
+func NativeInvokeGasCost(gasTable GasTable, engine *vm.ExecutionEngine) (uint64, error) {
+	price, ok := gasTable.Price(NATIVE_INVOKE_NAME)
+	if !ok {
+		return uint64(0), errors.NewErr("[NativeInvokeGasCost] get NATIVE_INVOKE_NAME gas failed")
+	}
+	address, err := vm.PeekNByteArray(1, engine)
+	if err != nil {
+		return price, nil
+	}
+	method, err := vm.PeekNByteArray(2, engine)
+	if err != nil {
+		return price, nil
+	}
+	contract, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return price, nil
+	}
+	if methodPrice, ok := gasTable[NativeMethodGasName(contract, string(method))]; ok {
+		return methodPrice, nil
+	}
+	return price, nil
 }
 
-func GasPrice(engine *vm.ExecutionEngine, name string) (uint64, error) {
+//GasPrice returns gas price of opcode or syscall name by gas table of the block being executed
+func GasPrice(gasTable GasTable, engine *vm.ExecutionEngine, name string) (uint64, error) {
 	switch name {
 	case STORAGE_PUT_NAME:
-		return StoreGasCost(engine)
+		return StoreGasCost(gasTable, engine)
+	case NATIVE_INVOKE_NAME, NATIVE_CALL_NAME:
+		return NativeInvokeGasCost(gasTable, engine)
 	default:
-		if value, ok := GAS_TABLE.Load(name); ok {
-			return value.(uint64), nil
+		if value, ok := gasTable.Price(name); ok {
+			return value, nil
 		}
-		return OPCODE_GAS, nil
+		return gasTable.OpcodePrice(), nil
 	}
 }
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGasTable(t *testing.T) {
+	var table GasTable
+	price, ok := table.Price(STORAGE_GET_NAME)
+	assert.True(t, ok)
+	assert.Equal(t, STORAGE_GET_GAS, price)
+	assert.Equal(t, OPCODE_GAS, table.OpcodePrice())
+
+	table = DefaultGasTable()
+	table[STORAGE_GET_NAME] = 300
+	table[OPCODE_NAME] = 2
+	price, ok = table.Price(STORAGE_GET_NAME)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(300), price)
+	assert.Equal(t, uint64(2), table.OpcodePrice())
+	_, ok = table.Price("unknown")
+	assert.False(t, ok)
+
+	assert.NotEqual(t, NativeMethodGasName(common.Address{1}, "transfer"), NativeMethodGasName(common.Address{2}, "transfer"))
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+)
+
+//Error codes of native call, returned to NeoVM contract instead of aborting execution
+const (
+	NATIVE_CALL_SUCCESS       = 0
+	NATIVE_CALL_ERR_NOT_FOUND = 1 //native contract or method not found
+	NATIVE_CALL_ERR_PARAMS    = 2 //args not match abi of method
+	NATIVE_CALL_ERR_EXECUTE   = 3 //native method failed, its changes are reverted
+)
+
+//NativeCall invokes native contract with args marshaled by abi of native method, pops version, address,
+//method and args array with one item per param. It pushes array [code, result], where result is typed by
+//return type of method if code is NATIVE_CALL_SUCCESS, and empty otherwise. Failures of native method are
+//returned as code with state changes reverted, so that NeoVM contract can handle them.
+func NativeCall(service *NeoVmService, engine *vm.ExecutionEngine) error {
+	count := vm.EvaluationStackCount(engine)
+	if count < 4 {
+		return fmt.Errorf("call native contract invalid parameters %d < 4 ", count)
+	}
+	version, err := vm.PopInt(engine)
+	if err != nil {
+		return err
+	}
+	address, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	method, err := vm.PopByteArray(engine)
+	if err != nil {
+		return err
+	}
+	if len(method) > METHOD_LENGTH_LIMIT {
+		return fmt.Errorf("call native contract:%x method:%s too long, over max length 1024 limit", address, method)
+	}
+	args := vm.PopStackItem(engine)
+	if CircularRefAndDepthDetection(args) {
+		return fmt.Errorf("call native contract circular reference")
+	}
+
+	code, result := callNative(service, byte(version), address, string(method), args)
+	vm.PushData(engine, types.NewArray([]types.StackItems{types.NewInteger(big.NewInt(int64(code))), result}))
+	return nil
+}
+
+func callNative(service *NeoVmService, version byte, address []byte, method string, args types.StackItems) (int, types.StackItems) {
+	empty := types.NewByteArray(nil)
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return NATIVE_CALL_ERR_NOT_FOUND, empty
+	}
+	contractAbi := framework.NativeAbi(addr)
+	if contractAbi == nil {
+		return NATIVE_CALL_ERR_NOT_FOUND, empty
+	}
+	funcAbi := contractAbi.GetFunc(method)
+	if funcAbi == nil {
+		return NATIVE_CALL_ERR_NOT_FOUND, empty
+	}
+	if _, ok := native.Contracts[addr]; !ok {
+		return NATIVE_CALL_ERR_NOT_FOUND, empty
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := MarshalNativeParams(sink, args, funcAbi.Parameters); err != nil {
+		return NATIVE_CALL_ERR_PARAMS, empty
+	}
+
+	var result types.StackItems
+	err = service.ContextRef.Revertible(func(cache *storage.CacheDB) error {
+		native := &native.NativeService{
+			CacheDB: cache,
+			InvokeParam: states.ContractInvokeParam{
+				Version: version,
+				Address: addr,
+				Method:  funcAbi.Name,
+				Args:    sink.Bytes(),
+			},
+			Tx:         service.Tx,
+			Height:     service.Height,
+			Time:       service.Time,
+			BlockHash:  service.BlockHash,
+			ContextRef: service.ContextRef,
+			ServiceMap: make(map[string]native.Handler),
+		}
+		ret, err := native.Invoke()
+		if err != nil {
+			return err
+		}
+		data, _ := ret.([]byte)
+		result = UnmarshalNativeResult(data, funcAbi.ReturnType)
+		return nil
+	})
+	if err != nil {
+		return NATIVE_CALL_ERR_EXECUTE, empty
+	}
+	return NATIVE_CALL_SUCCESS, result
+}
+
+//MarshalNativeParams encodes args item of native call by param abi of native method. Args should be array or
+//struct with one item per param, and it is ignored if method has no param.
+func MarshalNativeParams(sink *common.ZeroCopySink, args types.StackItems, params []*framework.ParamAbi) error {
+	if len(params) == 0 {
+		return nil
+	}
+	items, err := stackItemList(args)
+	if err != nil {
+		return fmt.Errorf("args %s", err)
+	}
+	if len(items) != len(params) {
+		return fmt.Errorf("args count:%d not match abi:%d", len(items), len(params))
+	}
+	for i, param := range params {
+		if err := marshalNativeParam(sink, items[i], param); err != nil {
+			return fmt.Errorf("param:%s %s", param.Name, err)
+		}
+	}
+	return nil
+}
+
+func marshalNativeParam(sink *common.ZeroCopySink, item types.StackItems, param *framework.ParamAbi) error {
+	switch framework.AbiType(param.Type) {
+	case framework.ABI_TYPE_ADDRESS, framework.ABI_TYPE_UINT256:
+		data, err := item.GetByteArray()
+		if err != nil {
+			return err
+		}
+		size := common.ADDR_LEN
+		if framework.AbiType(param.Type) == framework.ABI_TYPE_UINT256 {
+			size = common.UINT256_SIZE
+		}
+		if len(data) != size {
+			return fmt.Errorf("length:%d should be %d", len(data), size)
+		}
+		sink.WriteVarBytes(data)
+	case framework.ABI_TYPE_INTEGER, framework.ABI_TYPE_BYTE:
+		value, err := item.GetBigInteger()
+		if err != nil {
+			return err
+		}
+		if value.Sign() < 0 {
+			return fmt.Errorf("should not be negative")
+		}
+		if framework.AbiType(param.Type) == framework.ABI_TYPE_BYTE && value.Cmp(big.NewInt(255)) > 0 {
+			return fmt.Errorf("byte overflow")
+		}
+		sink.WriteVarBytes(types.BigIntToBytes(value))
+	case framework.ABI_TYPE_BOOL:
+		value, err := item.GetBoolean()
+		if err != nil {
+			return err
+		}
+		sink.WriteBool(value)
+	case framework.ABI_TYPE_STRING, framework.ABI_TYPE_BYTEARRAY:
+		data, err := item.GetByteArray()
+		if err != nil {
+			return err
+		}
+		sink.WriteVarBytes(data)
+	case framework.ABI_TYPE_ARRAY:
+		if len(param.SubType) == 0 {
+			return fmt.Errorf("array without sub type")
+		}
+		items, err := stackItemList(item)
+		if err != nil {
+			return err
+		}
+		sink.WriteVarBytes(types.BigIntToBytes(big.NewInt(int64(len(items)))))
+		for _, v := range items {
+			if err := marshalNativeParam(sink, v, param.SubType[0]); err != nil {
+				return err
+			}
+		}
+	case framework.ABI_TYPE_STRUCT:
+		items, err := stackItemList(item)
+		if err != nil {
+			return err
+		}
+		if len(items) != len(param.SubType) {
+			return fmt.Errorf("fields count:%d not match abi:%d", len(items), len(param.SubType))
+		}
+		for i, field := range param.SubType {
+			if err := marshalNativeParam(sink, items[i], field); err != nil {
+				return fmt.Errorf("%s.%s", field.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type:%s", param.Type)
+	}
+	return nil
+}
+
+func stackItemList(item types.StackItems) ([]types.StackItems, error) {
+	switch v := item.(type) {
+	case *types.Array:
+		return v.GetArray()
+	case *types.Struct:
+		return v.GetStruct()
+	}
+	return nil, fmt.Errorf("should be array or struct")
+}
+
+//UnmarshalNativeResult converts result of native method to stack item by return type in abi. Results of native
+//contracts are raw value: bool is one byte, int is bytes of big int, others are byte array.
+func UnmarshalNativeResult(data []byte, returnType string) types.StackItems {
+	switch framework.AbiType(returnType) {
+	case framework.ABI_TYPE_BOOL:
+		return types.NewBoolean(len(data) > 0 && data[0] != 0)
+	case framework.ABI_TYPE_INTEGER, framework.ABI_TYPE_BYTE:
+		return types.NewInteger(types.BigIntFromBytes(data))
+	}
+	return types.NewByteArray(data)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalNativeParams(t *testing.T) {
+	onxAbi := framework.NativeAbi(utils.OnxContractAddress)
+	assert.NotNil(t, onxAbi)
+	from, to := common.Address{1}, common.Address{2}
+
+	//transfer(states) takes array of struct, same as hand built struct by NeoVM contract
+	state := types.NewStruct([]types.StackItems{
+		types.NewByteArray(from[:]), types.NewByteArray(to[:]), types.NewInteger(big.NewInt(100)),
+	})
+	args := types.NewArray([]types.StackItems{types.NewArray([]types.StackItems{state})})
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, MarshalNativeParams(sink, args, onxAbi.GetFunc("transfer").Parameters))
+	buf := new(bytes.Buffer)
+	assert.Nil(t, BuildParamToNative(buf, types.NewArray([]types.StackItems{state})))
+	assert.Equal(t, buf.Bytes(), sink.Bytes())
+
+	//approve(from, to, value) takes params as array instead of struct
+	args = types.NewArray([]types.StackItems{
+		types.NewByteArray(from[:]), types.NewByteArray(to[:]), types.NewInteger(big.NewInt(100)),
+	})
+	sink = common.NewZeroCopySink(nil)
+	assert.Nil(t, MarshalNativeParams(sink, args, onxAbi.GetFunc("approve").Parameters))
+	buf.Reset()
+	assert.Nil(t, BuildParamToNative(buf, state))
+	assert.Equal(t, buf.Bytes(), sink.Bytes())
+
+	//invalid address, negative value and params count
+	params := onxAbi.GetFunc("approve").Parameters
+	args = types.NewArray([]types.StackItems{
+		types.NewByteArray([]byte{1}), types.NewByteArray(to[:]), types.NewInteger(big.NewInt(100)),
+	})
+	assert.NotNil(t, MarshalNativeParams(common.NewZeroCopySink(nil), args, params))
+	args = types.NewArray([]types.StackItems{
+		types.NewByteArray(from[:]), types.NewByteArray(to[:]), types.NewInteger(big.NewInt(-1)),
+	})
+	assert.NotNil(t, MarshalNativeParams(common.NewZeroCopySink(nil), args, params))
+	args = types.NewArray([]types.StackItems{types.NewByteArray(from[:])})
+	assert.NotNil(t, MarshalNativeParams(common.NewZeroCopySink(nil), args, params))
+	assert.NotNil(t, MarshalNativeParams(common.NewZeroCopySink(nil), types.NewByteArray(from[:]), params))
+
+	//params of method without param are ignored
+	sink = common.NewZeroCopySink(nil)
+	assert.Nil(t, MarshalNativeParams(sink, types.NewByteArray(nil), nil))
+	assert.Equal(t, 0, len(sink.Bytes()))
+
+	bools := []*framework.ParamAbi{{Name: "flag", Type: "Bool"}}
+	sink = common.NewZeroCopySink(nil)
+	assert.Nil(t, MarshalNativeParams(sink, types.NewArray([]types.StackItems{types.NewInteger(big.NewInt(1))}), bools))
+	assert.Equal(t, []byte{1}, sink.Bytes())
+}
+
+func TestUnmarshalNativeResult(t *testing.T) {
+	b, err := UnmarshalNativeResult([]byte{1}, "Bool").GetBoolean()
+	assert.Nil(t, err)
+	assert.True(t, b)
+	b, err = UnmarshalNativeResult([]byte{0}, "Bool").GetBoolean()
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	n, err := UnmarshalNativeResult(types.BigIntToBytes(big.NewInt(1000)), "Int").GetBigInteger()
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1000), n)
+
+	data, err := UnmarshalNativeResult([]byte("ONX"), "String").GetByteArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("ONX"), data)
+}
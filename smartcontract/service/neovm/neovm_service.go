@@ -24,6 +24,7 @@ import (
 
 	"github.com/OnyxPay/OnyxChain-crypto/keypair"
 	scommon "github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
 	"github.com/OnyxPay/OnyxChain-legacy/core/signature"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store"
@@ -31,7 +32,9 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/context"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/trace"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
 	ntypes "github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
 )
@@ -74,6 +77,7 @@ var (
 		RUNTIME_SERIALIZE_NAME:               {Execute: RuntimeSerialize, Validator: validatorSerialize},
 		RUNTIME_DESERIALIZE_NAME:             {Execute: RuntimeDeserialize, Validator: validatorDeserialize},
 		NATIVE_INVOKE_NAME:                   {Execute: NativeInvoke},
+		NATIVE_CALL_NAME:                     {Execute: NativeCall},
 		STORAGE_GET_NAME:                     {Execute: StorageGet},
 		STORAGE_PUT_NAME:                     {Execute: StoragePut},
 		STORAGE_DELETE_NAME:                  {Execute: StorageDelete},
@@ -91,6 +95,23 @@ var (
 	}
 )
 
+//interop services recorded by tx tracer with their arguments
+func init() {
+	trace.RegisterSysCall(STORAGE_GET_NAME, 2, trace.SYSCALL_STORAGE_GET)
+	trace.RegisterSysCall(STORAGE_PUT_NAME, 3, trace.SYSCALL_STORAGE_PUT)
+	trace.RegisterSysCall(STORAGE_DELETE_NAME, 2, trace.SYSCALL_STORAGE_DELETE)
+	trace.RegisterSysCall(NATIVE_INVOKE_NAME, 4, trace.SYSCALL_NATIVE)
+	trace.RegisterSysCall(NATIVE_CALL_NAME, 4, trace.SYSCALL_NATIVE)
+	for _, name := range []string{RUNTIME_CHECKWITNESS_NAME, RUNTIME_NOTIFY_NAME, RUNTIME_LOG_NAME,
+		RUNTIME_SERIALIZE_NAME, RUNTIME_DESERIALIZE_NAME, RUNTIME_BASE58TOADDRESS_NAME, RUNTIME_ADDRESSTOBASE58_NAME,
+		BLOCKCHAIN_GETHEADER_NAME, BLOCKCHAIN_GETBLOCK_NAME, BLOCKCHAIN_GETTRANSACTION_NAME,
+		BLOCKCHAIN_GETCONTRACT_NAME, BLOCKCHAIN_GETTRANSACTIONHEIGHT_NAME} {
+		trace.RegisterSysCall(name, 1, trace.SYSCALL_OTHER)
+	}
+	trace.RegisterSysCall(CONTRACT_CREATE_NAME, 7, trace.SYSCALL_OTHER)
+	trace.RegisterSysCall(CONTRACT_MIGRATE_NAME, 7, trace.SYSCALL_OTHER)
+}
+
 var (
 	ERR_CHECK_STACK_SIZE  = errors.NewErr("[NeoVmService] vm over max stack size!")
 	ERR_EXECUTE_CODE      = errors.NewErr("[NeoVmService] vm execute code invalid!")
@@ -128,6 +149,8 @@ type NeoVmService struct {
 	BlockHash     scommon.Uint256
 	Engine        *vm.ExecutionEngine
 	PreExec       bool
+	ContractAddr  scommon.Address // address of contract, hash of Code if empty. Differs from hash of Code after upgrade
+	GasTable      GasTable        // gas prices of the block being executed, built-in prices if nil
 }
 
 // Invoke a smart contract
@@ -135,7 +158,15 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 	if len(this.Code) == 0 {
 		return nil, ERR_EXECUTE_CODE
 	}
-	this.ContextRef.PushContext(&context.Context{ContractAddress: scommon.AddressFromVmCode(this.Code), Code: this.Code})
+	contractAddress := this.ContractAddr
+	if contractAddress == scommon.ADDRESS_EMPTY {
+		contractAddress = scommon.AddressFromVmCode(this.Code)
+	}
+	if err := this.checkOnUpgrade(); err != nil {
+		return nil, err
+	}
+	this.ContextRef.PushContext(&context.Context{ContractAddress: contractAddress, Code: this.Code})
+	this.Engine.EnterContract(contractAddress)
 	this.Engine.PushContext(vm.NewExecutionContext(this.Engine, this.Code))
 	for {
 		//check the execution step count
@@ -151,20 +182,21 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 		if err := this.Engine.ExecuteCode(); err != nil {
 			return nil, err
 		}
+		offset := this.Engine.Context.GetInstructionPointer() - 1
 		if this.Engine.Context.GetInstructionPointer() < len(this.Engine.Context.Code) {
 			if ok := checkStackSize(this.Engine); !ok {
 				return nil, ERR_CHECK_STACK_SIZE
 			}
 		}
 		if this.Engine.OpCode >= vm.PUSHBYTES1 && this.Engine.OpCode <= vm.PUSHBYTES75 {
-			if !this.ContextRef.CheckUseGas(OPCODE_GAS) {
+			if !this.ContextRef.CheckUseGas(this.GasTable.OpcodePrice()) {
 				return nil, ERR_GAS_INSUFFICIENT
 			}
 		} else {
 			if err := this.Engine.ValidateOp(); err != nil {
 				return nil, err
 			}
-			price, err := GasPrice(this.Engine, this.Engine.OpExec.Name)
+			price, err := GasPrice(this.GasTable, this.Engine, this.Engine.OpExec.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -172,6 +204,9 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 				return nil, ERR_GAS_INSUFFICIENT
 			}
 		}
+		if err := this.Engine.BeforeOp(offset); err != nil {
+			return nil, err
+		}
 		switch this.Engine.OpCode {
 		case vm.VERIFY:
 			if vm.EvaluationStackCount(this.Engine) < 3 {
@@ -229,6 +264,7 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
+			service.(*NeoVmService).ContractAddr = addr
 			this.Engine.EvaluationStack.CopyTo(service.(*NeoVmService).Engine.EvaluationStack)
 			result, err := service.Invoke()
 			if err != nil {
@@ -245,6 +281,9 @@ func (this *NeoVmService) Invoke() (interface{}, error) {
 				return nil, VM_EXEC_FAULT
 			}
 		}
+		if err := this.Engine.AfterOp(offset); err != nil {
+			return nil, err
+		}
 	}
 	this.ContextRef.PopContext()
 	this.ContextRef.PushNotifications(this.Notifications)
@@ -261,12 +300,15 @@ func (this *NeoVmService) SystemCall(engine *vm.ExecutionEngine) error {
 	if !ok {
 		return errors.NewErr(fmt.Sprintf("[SystemCall] service not support: %s", serviceName))
 	}
+	if err := engine.SysCall(serviceName); err != nil {
+		return err
+	}
 	if service.Validator != nil {
 		if err := service.Validator(engine); err != nil {
 			return errors.NewDetailErr(err, errors.ErrNoCode, "[SystemCall] service validator error!")
 		}
 	}
-	price, err := GasPrice(engine, serviceName)
+	price, err := GasPrice(this.GasTable, engine, serviceName)
 	if err != nil {
 		return err
 	}
@@ -279,6 +321,23 @@ func (this *NeoVmService) SystemCall(engine *vm.ExecutionEngine) error {
 	return nil
 }
 
+//checkOnUpgrade rejects invocation of ON_UPGRADE_NAME unless the caller is upgrade contract, from activation
+//height of upgrade contract. Method is passed on top of evaluation stack, by APPCALL or AppCall of native contract.
+func (this *NeoVmService) checkOnUpgrade() error {
+	if !config.DefConfig.Genesis.Features.ContractUpgradeActive(this.Height) || this.Engine.EvaluationStack.Count() == 0 {
+		return nil
+	}
+	method, err := this.Engine.EvaluationStack.Peek(0).GetByteArray()
+	if err != nil || string(method) != ON_UPGRADE_NAME {
+		return nil
+	}
+	caller := this.ContextRef.CurrentContext()
+	if caller == nil || caller.ContractAddress != utils.UpgradeContractAddress {
+		return fmt.Errorf("%s can only be invoked by upgrade contract", ON_UPGRADE_NAME)
+	}
+	return nil
+}
+
 func (this *NeoVmService) getContract(address scommon.Address) ([]byte, error) {
 	dep, err := this.CacheDB.GetContract(address)
 	if err != nil {
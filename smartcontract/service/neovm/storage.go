@@ -21,6 +21,7 @@ package neovm
 import (
 	"fmt"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/core/states"
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
@@ -55,7 +56,15 @@ func StoragePut(service *NeoVmService, engine *vm.ExecutionEngine) error {
 		return err
 	}
 
-	service.CacheDB.Put(genStorageKey(context.Address, key), states.GenRawStorageItem(value))
+	storageKey := genStorageKey(context.Address, key)
+	oldSize, err := getStorageItemSize(service, storageKey, len(key))
+	if err != nil {
+		return err
+	}
+	if err := updateStorageUsage(service, context.Address, oldSize, uint64(len(key)+len(value))); err != nil {
+		return err
+	}
+	service.CacheDB.Put(storageKey, states.GenRawStorageItem(value))
 	return nil
 }
 
@@ -78,7 +87,15 @@ func StorageDelete(service *NeoVmService, engine *vm.ExecutionEngine) error {
 	if err != nil {
 		return err
 	}
-	service.CacheDB.Delete(genStorageKey(context.Address, ba))
+	storageKey := genStorageKey(context.Address, ba)
+	oldSize, err := getStorageItemSize(service, storageKey, len(ba))
+	if err != nil {
+		return err
+	}
+	if err := updateStorageUsage(service, context.Address, oldSize, 0); err != nil {
+		return err
+	}
+	service.CacheDB.Delete(storageKey)
 
 	return nil
 }
@@ -135,6 +152,63 @@ func checkStorageContext(service *NeoVmService, context *StorageContext) error {
 	return nil
 }
 
+//getStorageItemSize returns size of key and value of storage item, 0 if not exist
+func getStorageItemSize(service *NeoVmService, storageKey []byte, keyLen int) (uint64, error) {
+	raw, err := service.CacheDB.Get(storageKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(raw)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(keyLen + len(value)), nil
+}
+
+//updateStorageUsage tracks storage of contract changed from oldSize to newSize bytes from activation height,
+//growth is charged per byte and freed bytes are partly refunded when transaction succeeds
+func updateStorageUsage(service *NeoVmService, address common.Address, oldSize, newSize uint64) error {
+	if oldSize == newSize || !config.DefConfig.Genesis.Features.StorageUsageActive(service.Height) {
+		return nil
+	}
+	usage, err := service.CacheDB.GetStorageUsage(address)
+	if err != nil {
+		return err
+	}
+	byteCost, _ := service.GasTable.Price(STORAGE_BYTE_NAME)
+	if newSize > oldSize {
+		growth := newSize - oldSize
+		gas, overflow := common.SafeMul(growth, byteCost)
+		if overflow {
+			return fmt.Errorf("[updateStorageUsage] gas of %d bytes overflow", growth)
+		}
+		if !service.ContextRef.CheckUseGas(gas) {
+			return ERR_GAS_INSUFFICIENT
+		}
+		usage += growth
+	} else {
+		freed := oldSize - newSize
+		//storage written before usage tracking is not refunded
+		if freed > usage {
+			freed = usage
+		}
+		usage -= freed
+		gas, overflow := common.SafeMul(freed, byteCost)
+		if !overflow {
+			gas, overflow = common.SafeMul(gas, service.GasTable.StorageRefundPercent())
+		}
+		if overflow {
+			return fmt.Errorf("[updateStorageUsage] refund of %d bytes overflow", freed)
+		}
+		service.ContextRef.RefundGas(gas / 100)
+	}
+	service.CacheDB.PutStorageUsage(address, usage)
+	return nil
+}
+
 func getContext(engine *vm.ExecutionEngine) (*StorageContext, error) {
 	opInterface, err := vm.PopInteropInterface(engine)
 	if err != nil {
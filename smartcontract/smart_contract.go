@@ -30,6 +30,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
 	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	vtypes "github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
 )
 
 const (
@@ -44,8 +45,10 @@ type SmartContract struct {
 	Config        *Config
 	Notifications []*event.NotifyEventInfo // all execute smart contract event notify info
 	Gas           uint64
+	GasRefund     uint64 // gas refunded for freed storage, applied when transaction succeeds
 	ExecStep      int
 	PreExec       bool
+	DebugHook     vm.DebugHook // hook of neovm engines, used by debugger
 }
 
 // Config describe smart contract need parameters configuration
@@ -54,6 +57,7 @@ type Config struct {
 	Height    uint32              // current block height
 	BlockHash common.Uint256      // current block hash
 	Tx        *ctypes.Transaction // current transaction
+	GasTable  neovm.GasTable      // gas prices of current block, built-in prices if nil
 }
 
 // PushContext push current context to smart contract
@@ -113,6 +117,29 @@ func (this *SmartContract) CheckUseGas(gas uint64) bool {
 	return true
 }
 
+//RefundGas records gas refunded to transaction sender
+func (this *SmartContract) RefundGas(gas uint64) {
+	this.GasRefund += gas
+}
+
+//Revertible runs call on a child of cache, which is committed if call succeeds. If it fails, the child is
+//discarded, and notifications, contexts and gas refund made by it are reverted.
+func (this *SmartContract) Revertible(call func(cache *storage.CacheDB) error) error {
+	parent := this.CacheDB
+	notifications, contexts, refund := len(this.Notifications), len(this.Contexts), this.GasRefund
+	this.CacheDB = parent.NewChild()
+	err := call(this.CacheDB)
+	if err != nil {
+		this.Notifications = this.Notifications[:notifications]
+		this.Contexts = this.Contexts[:contexts]
+		this.GasRefund = refund
+	} else {
+		this.CacheDB.Commit()
+	}
+	this.CacheDB = parent
+	return err
+}
+
 func (this *SmartContract) checkContexts() bool {
 	if len(this.Contexts) > MAX_EXECUTE_ENGINE {
 		return false
@@ -126,6 +153,8 @@ func (this *SmartContract) NewExecuteEngine(code []byte) (context.Engine, error)
 	if !this.checkContexts() {
 		return nil, fmt.Errorf("%s", "engine over max limit!")
 	}
+	engine := vm.NewExecutionEngine()
+	engine.Hook = this.DebugHook
 	service := &neovm.NeoVmService{
 		Store:      this.Store,
 		CacheDB:    this.CacheDB,
@@ -135,12 +164,38 @@ func (this *SmartContract) NewExecuteEngine(code []byte) (context.Engine, error)
 		Time:       this.Config.Time,
 		Height:     this.Config.Height,
 		BlockHash:  this.Config.BlockHash,
-		Engine:     vm.NewExecutionEngine(),
+		Engine:     engine,
 		PreExec:    this.PreExec,
+		GasTable:   this.Config.GasTable,
 	}
 	return service, nil
 }
 
+// AppCall invokes method of NeoVM contract at address with args, used by native contracts to call NeoVM contracts.
+// Args should be values supported by neovm stack item, such as []byte, bool and integers
+func (this *SmartContract) AppCall(address common.Address, method string, args []interface{}) (interface{}, error) {
+	dep, err := this.CacheDB.GetContract(address)
+	if err != nil {
+		return nil, err
+	}
+	if dep == nil {
+		return nil, fmt.Errorf("contract %s not exist", address.ToHexString())
+	}
+	engine, err := this.NewExecuteEngine(dep.Code)
+	if err != nil {
+		return nil, err
+	}
+	service := engine.(*neovm.NeoVmService)
+	service.ContractAddr = address
+	items := make([]vtypes.StackItems, 0, len(args))
+	for _, arg := range args {
+		items = append(items, vm.NewStackItem(arg))
+	}
+	vm.PushData(service.Engine, items)
+	vm.PushData(service.Engine, []byte(method))
+	return service.Invoke()
+}
+
 func (this *SmartContract) NewNativeService() (*native.NativeService, error) {
 	if !this.checkContexts() {
 		return nil, fmt.Errorf("%s", "engine over max limit!")
@@ -153,6 +208,7 @@ func (this *SmartContract) NewNativeService() (*native.NativeService, error) {
 		Height:     this.Config.Height,
 		BlockHash:  this.Config.BlockHash,
 		ServiceMap: make(map[string]native.Handler),
+		PreExec:    this.PreExec,
 	}
 	return service, nil
 }
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package states
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+)
+
+//SimulateConfig sets block and state overrides of transaction bundle simulation
+type SimulateConfig struct {
+	Height    uint32 //block height of execution, 0 for next block
+	Time      uint32 //block time of execution, 0 for now
+	Balances  []*BalanceOverride
+	Storage   []*StorageOverride
+	Contracts []*ContractOverride
+}
+
+//BalanceOverride sets balance of account in ONX or OXG contract
+type BalanceOverride struct {
+	Asset   common.Address
+	Account common.Address
+	Value   uint64
+}
+
+//StorageOverride sets storage entry of contract, empty value deletes the entry
+type StorageOverride struct {
+	Contract common.Address
+	Key      []byte
+	Value    []byte
+}
+
+//ContractOverride replaces code of contract at address
+type ContractOverride struct {
+	Contract common.Address
+	Code     []byte
+}
+
+//SimulateResult is execution result of a transaction in bundle
+type SimulateResult struct {
+	TxHash common.Uint256
+	State  byte
+	Gas    uint64
+	Result interface{}
+	Notify []*event.NotifyEventInfo
+	Error  string
+}
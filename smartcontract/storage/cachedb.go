@@ -24,16 +24,25 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/overlaydb"
 	"github.com/syndtr/goleveldb/leveldb/util"
+	"io"
 )
 
 // CacheDB is smart contract execute cache, it contain transaction cache and block cache
 // When smart contract execute finish, need to commit transaction cache to block cache
 type CacheDB struct {
 	memdb      *overlaydb.MemDB
-	backend    *overlaydb.OverlayDB
+	backend    cacheBackend
 	keyScratch []byte
 }
 
+//cacheBackend is store under transaction cache with prefixed keys, block cache or parent transaction cache
+type cacheBackend interface {
+	Get(key []byte) ([]byte, error)
+	Put(key []byte, value []byte)
+	Delete(key []byte)
+	NewIterator(key []byte) common.StoreIterator
+}
+
 // NewCacheDB return a new contract cache
 func NewCacheDB(store *overlaydb.OverlayDB) *CacheDB {
 	return &CacheDB{
@@ -67,6 +76,42 @@ func (self *CacheDB) Commit() {
 	})
 }
 
+//NewChild returns cache layered on this cache. Changes of child are written to this cache by its Commit,
+//or discarded with the child.
+func (self *CacheDB) NewChild() *CacheDB {
+	return &CacheDB{
+		backend: &cacheLayer{cache: self},
+		memdb:   overlaydb.NewMemDB(0),
+	}
+}
+
+//cacheLayer is backend of child cache, which reads and writes transaction cache of parent with prefixed keys
+type cacheLayer struct {
+	cache *CacheDB
+}
+
+func (self *cacheLayer) Get(key []byte) ([]byte, error) {
+	value, unknown := self.cache.memdb.Get(key)
+	if unknown {
+		return self.cache.backend.Get(key)
+	}
+	return value, nil
+}
+
+func (self *cacheLayer) Put(key []byte, value []byte) {
+	self.cache.memdb.Put(key, value)
+}
+
+func (self *cacheLayer) Delete(key []byte) {
+	self.cache.memdb.Delete(key)
+}
+
+func (self *cacheLayer) NewIterator(key []byte) common.StoreIterator {
+	backIter := self.cache.backend.NewIterator(key)
+	memIter := self.cache.memdb.NewIterator(util.BytesPrefix(key))
+	return overlaydb.NewJoinIter(memIter, backIter)
+}
+
 func (self *CacheDB) Put(key []byte, value []byte) {
 	self.put(common.ST_STORAGE, key, value)
 }
@@ -94,8 +139,11 @@ func (self *CacheDB) GetContract(addr comm.Address) (*payload.DeployCode, error)
 }
 
 func (self *CacheDB) PutContract(contract *payload.DeployCode) error {
-	address := contract.Address()
+	return self.PutContractAt(contract.Address(), contract)
+}
 
+//PutContractAt stores contract at address regardless of its code, used by state override of simulation
+func (self *CacheDB) PutContractAt(address comm.Address, contract *payload.DeployCode) error {
 	sink := comm.NewZeroCopySink(nil)
 	err := contract.Serialization(sink)
 	if err != nil {
@@ -111,6 +159,33 @@ func (self *CacheDB) DeleteContract(address comm.Address) {
 	self.delete(common.ST_CONTRACT, address[:])
 }
 
+//GetStorageUsage returns bytes of storage tracked for contract
+func (self *CacheDB) GetStorageUsage(address comm.Address) (uint64, error) {
+	value, err := self.get(common.ST_STORAGE_USAGE, address[:])
+	if err != nil {
+		return 0, err
+	}
+	if len(value) == 0 {
+		return 0, nil
+	}
+	usage, eof := comm.NewZeroCopySource(value).NextUint64()
+	if eof {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return usage, nil
+}
+
+//PutStorageUsage updates bytes of storage tracked for contract, zero usage is deleted
+func (self *CacheDB) PutStorageUsage(address comm.Address, usage uint64) {
+	if usage == 0 {
+		self.delete(common.ST_STORAGE_USAGE, address[:])
+		return
+	}
+	sink := comm.NewZeroCopySink(make([]byte, 0, 8))
+	sink.WriteUint64(usage)
+	self.put(common.ST_STORAGE_USAGE, address[:], sink.Bytes())
+}
+
 func (self *CacheDB) Get(key []byte) ([]byte, error) {
 	return self.get(common.ST_STORAGE, key)
 }
@@ -18,6 +18,7 @@
 package storage
 
 import (
+	comm "github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/common"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/leveldbstore"
 	"github.com/OnyxPay/OnyxChain-legacy/core/store/overlaydb"
@@ -82,3 +83,81 @@ func TestCacheDB(t *testing.T) {
 	}
 
 }
+
+func TestCacheDBStorageUsage(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	overlay := overlaydb.NewOverlayDB(memback)
+	cache := NewCacheDB(overlay)
+
+	addr := comm.AddressFromVmCode([]byte("contract"))
+	usage, err := cache.GetStorageUsage(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), usage)
+
+	cache.PutStorageUsage(addr, 1234)
+	usage, err = cache.GetStorageUsage(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1234), usage)
+	cache.Commit()
+
+	cache = NewCacheDB(overlay)
+	usage, err = cache.GetStorageUsage(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1234), usage)
+
+	cache.PutStorageUsage(addr, 0)
+	cache.Commit()
+	raw, err := overlay.Get(append([]byte{byte(common.ST_STORAGE_USAGE)}, addr[:]...))
+	assert.Nil(t, err)
+	assert.Nil(t, raw)
+}
+
+func TestCacheDBChild(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	overlay := overlaydb.NewOverlayDB(memback)
+	overlay.Put(append([]byte{byte(common.ST_STORAGE)}, "d"...), []byte("0"))
+	cache := NewCacheDB(overlay)
+
+	cache.Put([]byte("a"), []byte("1"))
+	cache.Put([]byte("b"), []byte("2"))
+	cache.Delete([]byte("c"))
+
+	//discarded child does not change parent
+	child := cache.NewChild()
+	child.Put([]byte("a"), []byte("3"))
+	child.Delete([]byte("b"))
+	child.Put([]byte("c"), []byte("4"))
+	value, _ := child.Get([]byte("a"))
+	assert.Equal(t, []byte("3"), value)
+	value, _ = child.Get([]byte("b"))
+	assert.Nil(t, value)
+	value, _ = child.Get([]byte("d"))
+	assert.Equal(t, []byte("0"), value)
+	value, _ = cache.Get([]byte("a"))
+	assert.Equal(t, []byte("1"), value)
+	value, _ = cache.Get([]byte("c"))
+	assert.Nil(t, value)
+
+	//committed child writes changes to parent only
+	child = cache.NewChild()
+	child.Put([]byte("a"), []byte("3"))
+	child.Delete([]byte("d"))
+	child.Put([]byte("e"), []byte("5"))
+	var keys []string
+	iter := child.NewIterator(nil)
+	for has := iter.First(); has; has = iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"a", "b", "e"}, keys)
+	child.Commit()
+
+	value, _ = cache.Get([]byte("a"))
+	assert.Equal(t, []byte("3"), value)
+	value, _ = cache.Get([]byte("d"))
+	assert.Nil(t, value)
+	value, _ = cache.Get([]byte("e"))
+	assert.Equal(t, []byte("5"), value)
+	value, _ = overlay.Get(append([]byte{byte(common.ST_STORAGE)}, "a"...))
+	assert.Nil(t, value)
+}
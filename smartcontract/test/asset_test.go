@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/asset"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var (
+	assetReceiver = common.Address{0x01}
+	assetSpender  = common.Address{0x02}
+)
+
+//assetEnv invokes asset contract in nativeEnv, issuer creates asset USD and minter holds roles to mint and burn
+type assetEnv struct {
+	*nativeEnv
+	issuer *onxID
+	minter *onxID
+}
+
+func newAssetEnv(t *testing.T) *assetEnv {
+	env := &assetEnv{nativeEnv: newNativeEnv(t)}
+	env.issuer, env.minter = env.registerID(), env.registerID()
+	err := env.invoke(100, env.issuer.signer, asset.CREATE_ASSET_NAME, &asset.CreateAssetParam{
+		Id:       "USD",
+		Name:     "US dollar",
+		Decimals: 2,
+		Issuer:   env.issuer.id,
+		KeyNo:    1,
+	})
+	assert.Nil(t, err)
+
+	contract, role := asset.AssetAddress("USD"), []byte("minter")
+	funcs := &auth.FuncsToRoleParam{ContractAddr: contract, AdminOnxID: env.issuer.id, Role: role,
+		FuncNames: []string{asset.MINT_NAME, asset.BURN_NAME}, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, env.issuer.signer, "assignFuncsToRole", funcs))
+	persons := &auth.OnxIDsToRoleParam{ContractAddr: contract, AdminOnxID: env.issuer.id, Role: role,
+		Persons: [][]byte{env.minter.id}, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, env.issuer.signer, "assignOnxIDsToRole", persons))
+	return env
+}
+
+func (this *assetEnv) invoke(time uint32, signer common.Address, method string, param interface{}) error {
+	_, err := this.invokeFramework(time, signer, utils.AssetContractAddress, method, param)
+	return err
+}
+
+func (this *assetEnv) balanceOf(account common.Address) uint64 {
+	balance, err := asset.BalanceOf(this.newService(0), &asset.BalanceOfParam{Id: "USD", Account: account})
+	assert.Nil(this.t, err)
+	return balance
+}
+
+func (this *assetEnv) getAsset() *asset.AssetInfo {
+	info, err := asset.GetAsset(this.newService(0), &asset.AssetParam{Id: "USD"})
+	assert.Nil(this.t, err)
+	return info
+}
+
+func TestAssetCreate(t *testing.T) {
+	env := newAssetEnv(t)
+	info := env.getAsset()
+	assert.Equal(t, "US dollar", info.Name)
+	assert.Equal(t, uint8(2), info.Decimals)
+	assert.Equal(t, env.issuer.id, info.Issuer)
+	assert.Equal(t, uint64(0), info.TotalSupply)
+
+	other := env.registerID()
+	param := &asset.CreateAssetParam{Id: "USD", Name: "US dollar", Issuer: other.id, KeyNo: 1}
+	err := env.invoke(110, other.signer, asset.CREATE_ASSET_NAME, param)
+	assert.NotNil(t, err, "asset id is taken")
+	param.Id = "EUR"
+	err = env.invoke(110, env.issuer.signer, asset.CREATE_ASSET_NAME, param)
+	assert.NotNil(t, err, "create without signature of issuer")
+	param.Name = ""
+	err = env.invoke(110, other.signer, asset.CREATE_ASSET_NAME, param)
+	assert.NotNil(t, err, "empty asset name")
+	param.Name = "Euro"
+	param.Decimals = asset.MAX_DECIMALS + 1
+	err = env.invoke(110, other.signer, asset.CREATE_ASSET_NAME, param)
+	assert.NotNil(t, err, "decimals over max")
+	param.Decimals = 2
+	err = env.invoke(110, other.signer, asset.CREATE_ASSET_NAME, param)
+	assert.Nil(t, err)
+}
+
+//TestAssetMintBurn mints to and burns from account of minter, since burn is signed by both minter and holder
+func TestAssetMintBurn(t *testing.T) {
+	env := newAssetEnv(t)
+	holder := env.minter.signer
+	mint := &asset.MintParam{Id: "USD", Caller: env.issuer.id, KeyNo: 1, To: holder, Value: 100}
+	err := env.invoke(110, env.issuer.signer, asset.MINT_NAME, mint)
+	assert.NotNil(t, err, "issuer holds no role to mint")
+	mint.Caller = env.minter.id
+	err = env.invoke(110, env.issuer.signer, asset.MINT_NAME, mint)
+	assert.NotNil(t, err, "mint without signature of minter")
+	err = env.invoke(110, env.minter.signer, asset.MINT_NAME, mint)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), env.balanceOf(holder))
+	assert.Equal(t, uint64(100), env.getAsset().TotalSupply)
+
+	mint.Value = ^uint64(0)
+	err = env.invoke(110, env.minter.signer, asset.MINT_NAME, mint)
+	assert.NotNil(t, err, "total supply overflows")
+
+	burn := &asset.BurnParam{Id: "USD", Caller: env.minter.id, KeyNo: 1, From: holder, Value: 101}
+	err = env.invoke(120, env.minter.signer, asset.BURN_NAME, burn)
+	assert.NotNil(t, err, "burn over balance")
+	burn.Value = 30
+	err = env.invoke(120, env.minter.signer, asset.BURN_NAME, burn)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(70), env.balanceOf(holder))
+	assert.Equal(t, uint64(70), env.getAsset().TotalSupply)
+}
+
+func TestAssetTransfer(t *testing.T) {
+	env := newAssetEnv(t)
+	holder := env.minter.signer
+	mint := &asset.MintParam{Id: "USD", Caller: env.minter.id, KeyNo: 1, To: holder, Value: 100}
+	assert.Nil(t, env.invoke(110, env.minter.signer, asset.MINT_NAME, mint))
+
+	transfer := &asset.TransferParam{Id: "USD", States: []asset.State{{From: holder, To: assetReceiver, Value: 40}}}
+	err := env.invoke(120, assetReceiver, asset.TRANSFER_NAME, transfer)
+	assert.NotNil(t, err, "transfer without signature of holder")
+	err = env.invoke(120, holder, asset.TRANSFER_NAME, transfer)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(60), env.balanceOf(holder))
+	assert.Equal(t, uint64(40), env.balanceOf(assetReceiver))
+	transfer.States[0].Value = 61
+	err = env.invoke(120, holder, asset.TRANSFER_NAME, transfer)
+	assert.NotNil(t, err, "transfer over balance")
+
+	approve := &asset.ApproveParam{Id: "USD", From: holder, To: assetSpender, Value: 50}
+	assert.Nil(t, env.invoke(130, holder, asset.APPROVE_NAME, approve))
+	allowance, err := asset.Allowance(env.newService(0), &asset.AllowanceParam{Id: "USD", From: holder, To: assetSpender})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), allowance)
+
+	transferFrom := &asset.TransferFromParam{Id: "USD", Sender: assetSpender, From: holder, To: assetReceiver, Value: 51}
+	err = env.invoke(140, assetSpender, asset.TRANSFER_FROM_NAME, transferFrom)
+	assert.NotNil(t, err, "transferFrom over allowance")
+	transferFrom.Value = 50
+	err = env.invoke(140, assetSpender, asset.TRANSFER_FROM_NAME, transferFrom)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), env.balanceOf(holder))
+	assert.Equal(t, uint64(90), env.balanceOf(assetReceiver))
+	allowance, err = asset.Allowance(env.newService(0), &asset.AllowanceParam{Id: "USD", From: holder, To: assetSpender})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), allowance)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+type authParam interface {
+	Serialize(w io.Writer) error
+}
+
+//callAuth invokes method of auth contract and returns its result, since auth returns false instead of error
+//if permission is denied, and fails if method returns error
+func (this *nativeEnv) callAuth(time uint32, signer common.Address, method string, param authParam) []byte {
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, param.Serialize(bf))
+	result, _, err := this.call(time, signer, utils.AuthContractAddress, method, bf.Bytes())
+	assert.Nil(this.t, err)
+	return result.([]byte)
+}
+
+func TestAuthRoles(t *testing.T) {
+	env := newNativeEnv(t)
+	admin, alice, bob := env.registerID(), env.registerID(), env.registerID()
+	contract := common.Address{0xaa}
+	role := []byte("operator")
+	env.db.Put(utils.ConcatKey(utils.AuthContractAddress, contract[:], auth.PreAdmin), states.GenRawStorageItem(admin.id))
+
+	funcs := &auth.FuncsToRoleParam{ContractAddr: contract, AdminOnxID: admin.id, Role: role,
+		FuncNames: []string{"mint", "burn"}, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_FALSE, env.callAuth(100, alice.signer, "assignFuncsToRole",
+		&auth.FuncsToRoleParam{ContractAddr: contract, AdminOnxID: alice.id, Role: role, FuncNames: funcs.FuncNames, KeyNo: 1}))
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, admin.signer, "assignFuncsToRole", funcs))
+	persons := &auth.OnxIDsToRoleParam{ContractAddr: contract, AdminOnxID: admin.id, Role: role,
+		Persons: [][]byte{alice.id}, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, admin.signer, "assignOnxIDsToRole", persons))
+	delegate := &auth.DelegateParam{ContractAddr: contract, From: alice.id, To: bob.id, Role: role,
+		Period: 100, Level: 1, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, alice.signer, "delegate", delegate))
+
+	roles := new(auth.ContractRoles)
+	data := env.callAuth(150, common.ADDRESS_EMPTY, "getContractRoles", &auth.ContractRolesParam{ContractAddr: contract})
+	assert.Nil(t, framework.Decode(data, roles))
+	assert.Equal(t, admin.id, roles.Admin)
+	assert.Equal(t, 1, len(roles.Roles))
+	assert.Equal(t, role, roles.Roles[0].Role)
+	assert.ElementsMatch(t, []string{"mint", "burn"}, roles.Roles[0].Funcs)
+	grants := roles.Roles[0].Grants
+	assert.Equal(t, 2, len(grants))
+	assert.Equal(t, alice.id, grants[0].OnxID)
+	assert.Empty(t, grants[0].Delegator)
+	assert.Equal(t, uint8(2), grants[0].Level)
+	assert.Equal(t, bob.id, grants[1].OnxID)
+	assert.Equal(t, alice.id, grants[1].Delegator)
+	assert.Equal(t, uint8(1), grants[1].Level)
+	assert.Equal(t, uint32(200), grants[1].ExpireTime)
+	assert.False(t, grants[1].Expired)
+
+	bobRoles := new(auth.OnxIDRoles)
+	data = env.callAuth(200, common.ADDRESS_EMPTY, "getOnxIDRoles", &auth.OnxIDRolesParam{ContractAddr: contract, OnxID: bob.id})
+	assert.Nil(t, framework.Decode(data, bobRoles))
+	assert.Equal(t, 1, len(bobRoles.Grants))
+	assert.True(t, bobRoles.Grants[0].Expired)
+
+	withdraw := &auth.WithdrawParam{ContractAddr: contract, Initiator: alice.id, Delegate: bob.id, Role: role, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(150, alice.signer, "withdraw", withdraw))
+	data = env.callAuth(150, common.ADDRESS_EMPTY, "getContractRoles", &auth.ContractRolesParam{ContractAddr: contract})
+	assert.Nil(t, framework.Decode(data, roles))
+	assert.Equal(t, 1, len(roles.Roles[0].Grants))
+	assert.Equal(t, alice.id, roles.Roles[0].Grants[0].OnxID)
+}
+
+func TestAuthRolesOfContract(t *testing.T) {
+	env := newNativeEnv(t)
+	admin, alice, bob := env.registerID(), env.registerID(), env.registerID()
+	contracts := []common.Address{{0xaa}, {0xab}}
+	roles := [][]byte{[]byte("operator"), []byte("minter")}
+	for i, contract := range contracts {
+		env.db.Put(utils.ConcatKey(utils.AuthContractAddress, contract[:], auth.PreAdmin), states.GenRawStorageItem(admin.id))
+		funcs := &auth.FuncsToRoleParam{ContractAddr: contract, AdminOnxID: admin.id, Role: roles[i],
+			FuncNames: []string{"mint"}, KeyNo: 1}
+		assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, admin.signer, "assignFuncsToRole", funcs))
+	}
+	persons := &auth.OnxIDsToRoleParam{ContractAddr: contracts[0], AdminOnxID: admin.id, Role: roles[0],
+		Persons: [][]byte{alice.id}, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, admin.signer, "assignOnxIDsToRole", persons))
+	delegate := &auth.DelegateParam{ContractAddr: contracts[0], From: alice.id, To: bob.id, Role: roles[0],
+		Period: 100, Level: 1, KeyNo: 1}
+	assert.Equal(t, utils.BYTE_TRUE, env.callAuth(100, alice.signer, "delegate", delegate))
+
+	contractRoles := new(auth.ContractRoles)
+	data := env.callAuth(150, common.ADDRESS_EMPTY, "getContractRoles", &auth.ContractRolesParam{ContractAddr: contracts[0]})
+	assert.Nil(t, framework.Decode(data, contractRoles))
+	assert.Equal(t, 1, len(contractRoles.Roles))
+	assert.Equal(t, roles[0], contractRoles.Roles[0].Role)
+	holders := make([][]byte, 0)
+	for _, grant := range contractRoles.Roles[0].Grants {
+		holders = append(holders, grant.OnxID)
+	}
+	assert.ElementsMatch(t, [][]byte{alice.id, bob.id}, holders)
+
+	//role without holders
+	contractRoles = new(auth.ContractRoles)
+	data = env.callAuth(150, common.ADDRESS_EMPTY, "getContractRoles", &auth.ContractRolesParam{ContractAddr: contracts[1]})
+	assert.Nil(t, framework.Decode(data, contractRoles))
+	assert.Equal(t, 1, len(contractRoles.Roles))
+	assert.Equal(t, roles[1], contractRoles.Roles[0].Role)
+	assert.Equal(t, []string{"mint"}, contractRoles.Roles[0].Funcs)
+	assert.Equal(t, 0, len(contractRoles.Roles[0].Grants))
+}
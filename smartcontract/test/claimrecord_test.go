@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/claimrecord"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+//claimEnv invokes claim record contract in nativeEnv
+type claimEnv struct {
+	*nativeEnv
+}
+
+func newClaimEnv(t *testing.T) *claimEnv {
+	return &claimEnv{newNativeEnv(t)}
+}
+
+func (this *claimEnv) invoke(time uint32, signer common.Address, method string, param interface{}) error {
+	_, err := this.invokeFramework(time, signer, utils.ClaimContractAddress, method, param)
+	return err
+}
+
+func (this *claimEnv) getClaim(time uint32, issuer, claimId []byte) (*claimrecord.Claim, error) {
+	param := &claimrecord.ClaimIdParam{ClaimId: claimId, Issuer: issuer}
+	return claimrecord.GetClaim(this.newService(time), param)
+}
+
+func TestClaimRecordCommit(t *testing.T) {
+	env := newClaimEnv(t)
+	issuer, subject, other := env.registerID(), env.registerID(), env.registerID()
+
+	claimId := bytes.Repeat([]byte{0x01}, 32)
+	commit := &claimrecord.CommitParam{
+		ClaimId:     claimId,
+		Issuer:      issuer.id,
+		IssuerKeyNo: 1,
+		Subject:     subject.id,
+		Expiration:  200,
+	}
+	err := env.invoke(100, subject.signer, claimrecord.COMMIT_NAME, commit)
+	assert.NotNil(t, err, "commit without signature of issuer")
+	commit.IssuerKeyNo = 2
+	err = env.invoke(100, issuer.signer, claimrecord.COMMIT_NAME, commit)
+	assert.NotNil(t, err, "commit with unknown key of issuer")
+	commit.IssuerKeyNo = 1
+	commit.Subject = []byte("did:onx:unknown")
+	err = env.invoke(100, issuer.signer, claimrecord.COMMIT_NAME, commit)
+	assert.NotNil(t, err, "commit about invalid subject")
+	commit.Subject = subject.id
+	err = env.invoke(100, issuer.signer, claimrecord.COMMIT_NAME, commit)
+	assert.Nil(t, err)
+	err = env.invoke(100, issuer.signer, claimrecord.COMMIT_NAME, commit)
+	assert.NotNil(t, err, "claim id is reused")
+
+	_, err = env.getClaim(150, other.id, claimId)
+	assert.NotNil(t, err, "claim id is taken by another issuer")
+	commit.Issuer = other.id
+	commit.Expiration = 0
+	err = env.invoke(120, other.signer, claimrecord.COMMIT_NAME, commit)
+	assert.Nil(t, err, "claim id of one issuer is free for another")
+
+	claim, err := env.getClaim(150, issuer.id, claimId)
+	assert.Nil(t, err)
+	assert.Equal(t, issuer.id, claim.Issuer)
+	assert.Equal(t, subject.id, claim.Subject)
+	assert.Equal(t, uint32(100), claim.Committed)
+	assert.Equal(t, claimrecord.CLAIM_VALID, claim.Status)
+
+	claim, err = env.getClaim(200, issuer.id, claimId)
+	assert.Nil(t, err)
+	assert.Equal(t, claimrecord.CLAIM_EXPIRED, claim.Status)
+
+	claim, err = env.getClaim(200, other.id, claimId)
+	assert.Nil(t, err)
+	assert.Equal(t, other.id, claim.Issuer)
+	assert.Equal(t, uint32(120), claim.Committed)
+	assert.Equal(t, claimrecord.CLAIM_VALID, claim.Status)
+
+	_, err = env.getClaim(150, issuer.id, []byte{0x02})
+	assert.NotNil(t, err)
+}
+
+func TestClaimRecordRevoke(t *testing.T) {
+	env := newClaimEnv(t)
+	issuer, subject, other := env.registerID(), env.registerID(), env.registerID()
+
+	claimId := bytes.Repeat([]byte{0x01}, 32)
+	commit := &claimrecord.CommitParam{
+		ClaimId:     claimId,
+		Issuer:      issuer.id,
+		IssuerKeyNo: 1,
+		Subject:     subject.id,
+	}
+	assert.Nil(t, env.invoke(100, issuer.signer, claimrecord.COMMIT_NAME, commit))
+
+	commit.Issuer = other.id
+	assert.Nil(t, env.invoke(100, other.signer, claimrecord.COMMIT_NAME, commit))
+
+	revoke := &claimrecord.RevokeParam{ClaimId: claimId, Issuer: subject.id, KeyNo: 1}
+	err := env.invoke(110, subject.signer, claimrecord.REVOKE_NAME, revoke)
+	assert.NotNil(t, err, "subject has no claim to revoke")
+	revoke.Issuer = issuer.id
+	err = env.invoke(110, subject.signer, claimrecord.REVOKE_NAME, revoke)
+	assert.NotNil(t, err, "revoke by subject")
+	err = env.invoke(110, issuer.signer, claimrecord.REVOKE_NAME, revoke)
+	assert.Nil(t, err)
+	err = env.invoke(120, issuer.signer, claimrecord.REVOKE_NAME, revoke)
+	assert.NotNil(t, err, "claim is revoked twice")
+
+	claim, err := env.getClaim(130, issuer.id, claimId)
+	assert.Nil(t, err)
+	assert.Equal(t, claimrecord.CLAIM_REVOKED, claim.Status)
+
+	claim, err = env.getClaim(130, other.id, claimId)
+	assert.Nil(t, err)
+	assert.Equal(t, claimrecord.CLAIM_VALID, claim.Status, "claim of another issuer with the same id")
+}
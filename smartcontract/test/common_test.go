@@ -19,13 +19,138 @@
 package test
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
 	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/core/store/leveldbstore"
+	"github.com/OnyxPay/OnyxChain-legacy/core/store/overlaydb"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/smartcontract"
-	"github.com/stretchr/testify/assert"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	_ "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/init"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	sstates "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/storage"
 )
 
+//contracts are tested with all features active from genesis, unlike main net where they are scheduled
+func init() {
+	genesis := *config.DefConfig.Genesis
+	genesis.Features = &config.FeatureHeights{}
+	config.DefConfig.Genesis = &genesis
+}
+
+//nativeEnv executes each invocation of native contract as a transaction signed by signer at block time,
+//all the transactions share one in-memory storage
+type nativeEnv struct {
+	t      *testing.T
+	db     *storage.CacheDB
+	height uint32
+}
+
+//onxID is ONX ID registered with one key, whose signer signs transactions of the ID
+type onxID struct {
+	id     []byte
+	signer common.Address
+	pub    keypair.PublicKey
+}
+
+func newNativeEnv(t *testing.T) *nativeEnv {
+	store, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	return &nativeEnv{t: t, db: storage.NewCacheDB(overlaydb.NewOverlayDB(store)), height: 1}
+}
+
+func (this *nativeEnv) newContract(time uint32, signer common.Address) *smartcontract.SmartContract {
+	return &smartcontract.SmartContract{
+		Config: &smartcontract.Config{
+			Time:   time,
+			Height: this.height,
+			Tx:     &types.Transaction{SignedAddr: []common.Address{signer}},
+		},
+		CacheDB: this.db,
+		Gas:     1 << 40,
+	}
+}
+
+//newService returns native service at block time without signer, which is used to query contracts
+func (this *nativeEnv) newService(time uint32) *native.NativeService {
+	service, err := this.newContract(time, common.ADDRESS_EMPTY).NewNativeService()
+	assert.Nil(this.t, err)
+	return service
+}
+
+//call invokes method of native contract with raw args and returns its result and notifications
+func (this *nativeEnv) call(time uint32, signer, addr common.Address, method string,
+	args []byte) (interface{}, []*event.NotifyEventInfo, error) {
+	sc := this.newContract(time, signer)
+	service, err := sc.NewNativeService()
+	assert.Nil(this.t, err)
+	service.InvokeParam = sstates.ContractInvokeParam{Address: addr, Method: method, Args: args}
+	result, err := service.Invoke()
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, sc.Notifications, nil
+}
+
+func (this *nativeEnv) invokeNative(time uint32, signer, addr common.Address, method string, args []byte) error {
+	_, _, err := this.call(time, signer, addr, method, args)
+	return err
+}
+
+//invokeFramework invokes method of native contract built on framework, whose param is encoded by framework
+func (this *nativeEnv) invokeFramework(time uint32, signer, addr common.Address, method string,
+	param interface{}) ([]*event.NotifyEventInfo, error) {
+	args, err := framework.Encode(param)
+	assert.Nil(this.t, err)
+	_, notifies, err := this.call(time, signer, addr, method, args)
+	return notifies, err
+}
+
+func (this *nativeEnv) setBalance(asset, addr common.Address, balance uint64) {
+	this.db.Put(onx.GenBalanceKey(asset, addr), utils.GenUInt64StorageItem(balance).ToArray())
+}
+
+func (this *nativeEnv) balanceOf(asset, addr common.Address) uint64 {
+	balance, err := utils.GetStorageUInt64(this.newService(0), onx.GenBalanceKey(asset, addr))
+	assert.Nil(this.t, err)
+	return balance
+}
+
+func (this *nativeEnv) deploy(code []byte) common.Address {
+	dep := &payload.DeployCode{Code: code}
+	assert.Nil(this.t, this.db.PutContract(dep))
+	return dep.Address()
+}
+
+//registerID registers ONX ID with a new key
+func (this *nativeEnv) registerID() *onxID {
+	id, err := account.GenerateID()
+	assert.Nil(this.t, err)
+	_, pub, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	assert.Nil(this.t, err)
+	signer := types.AddressFromPubKey(pub)
+
+	bf := new(bytes.Buffer)
+	serialization.WriteVarBytes(bf, []byte(id))
+	serialization.WriteVarBytes(bf, keypair.SerializePublicKey(pub))
+	err = this.invokeNative(0, signer, utils.OnxIDContractAddress, "regIDWithPublicKey", bf.Bytes())
+	assert.Nil(this.t, err)
+	return &onxID{id: []byte(id), signer: signer, pub: pub}
+}
+
 func TestConvertNeoVmTypeHexString(t *testing.T) {
 	code := `00c57676c8681553797374656d2e52756e74696d652e4e6f74696679`
 
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/escrow"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var (
+	escrowDepositor   = common.Address{0x01}
+	escrowBeneficiary = common.Address{0x02}
+)
+
+//escrowEnv invokes escrow contract in nativeEnv, assets are OXG
+type escrowEnv struct {
+	*nativeEnv
+}
+
+func newEscrowEnv(t *testing.T) *escrowEnv {
+	env := &escrowEnv{newNativeEnv(t)}
+	env.setBalance(utils.OxgContractAddress, escrowDepositor, 1000)
+	return env
+}
+
+func (this *escrowEnv) invoke(time uint32, signer common.Address, method string, param interface{}) error {
+	_, err := this.invokeFramework(time, signer, utils.EscrowContractAddress, method, param)
+	return err
+}
+
+func (this *escrowEnv) lockedBalance(time uint32, account common.Address) *escrow.LockedBalance {
+	balance, err := escrow.GetLockedBalance(this.newService(time),
+		&escrow.LockedBalanceParam{Account: account, Asset: utils.OxgContractAddress})
+	assert.Nil(this.t, err)
+	return balance
+}
+
+func (this *escrowEnv) getEscrow(id uint64) *escrow.Escrow {
+	e, err := escrow.GetEscrow(this.newService(0), &escrow.IdParam{Id: id})
+	assert.Nil(this.t, err)
+	return e
+}
+
+//createEscrow creates escrow of 100 from depositor to beneficiary with timeout at 500
+func (this *escrowEnv) createEscrow(arbiter *onxID) {
+	err := this.invoke(100, escrowDepositor, escrow.CREATE_ESCROW_NAME, &escrow.CreateEscrowParam{
+		Depositor:   escrowDepositor,
+		Beneficiary: escrowBeneficiary,
+		Asset:       utils.OxgContractAddress,
+		Amount:      100,
+		Arbiter:     arbiter.id,
+		Timeout:     500,
+	})
+	assert.Nil(this.t, err)
+}
+
+func TestVestingClaim(t *testing.T) {
+	env := newEscrowEnv(t)
+	create := &escrow.CreateVestingParam{
+		Creator:     escrowDepositor,
+		Beneficiary: escrowBeneficiary,
+		Asset:       utils.OxgContractAddress,
+		Amount:      1000,
+		Start:       100,
+		Cliff:       50,
+		Duration:    200,
+	}
+	err := env.invoke(10, escrowBeneficiary, escrow.CREATE_VESTING_NAME, create)
+	assert.NotNil(t, err, "create without signature of creator")
+	create.Cliff = 300
+	err = env.invoke(10, escrowDepositor, escrow.CREATE_VESTING_NAME, create)
+	assert.NotNil(t, err, "cliff longer than duration")
+	create.Cliff = 50
+	assert.Nil(t, env.invoke(10, escrowDepositor, escrow.CREATE_VESTING_NAME, create))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, escrowDepositor))
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, utils.EscrowContractAddress))
+	assert.Equal(t, &escrow.LockedBalance{Locked: 1000}, env.lockedBalance(149, escrowBeneficiary))
+
+	claim := &escrow.ClaimParam{Id: 0, Beneficiary: escrowBeneficiary}
+	assert.Nil(t, env.invoke(149, escrowBeneficiary, escrow.CLAIM_NAME, claim))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+
+	err = env.invoke(200, escrowDepositor, escrow.CLAIM_NAME, &escrow.ClaimParam{Id: 0, Beneficiary: escrowDepositor})
+	assert.NotNil(t, err, "claim by other than beneficiary")
+	assert.Equal(t, &escrow.LockedBalance{Locked: 1000, Claimable: 500}, env.lockedBalance(200, escrowBeneficiary))
+	assert.Nil(t, env.invoke(200, escrowBeneficiary, escrow.CLAIM_NAME, claim))
+	assert.Equal(t, uint64(500), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+	assert.Equal(t, &escrow.LockedBalance{Locked: 500}, env.lockedBalance(200, escrowBeneficiary))
+
+	assert.Nil(t, env.invoke(1000, escrowBeneficiary, escrow.CLAIM_NAME, claim))
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, utils.EscrowContractAddress))
+	assert.Equal(t, &escrow.LockedBalance{}, env.lockedBalance(1000, escrowBeneficiary))
+	assert.Nil(t, env.invoke(1100, escrowBeneficiary, escrow.CLAIM_NAME, claim))
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+}
+
+func TestEscrowCreate(t *testing.T) {
+	env := newEscrowEnv(t)
+	arbiter := env.registerID()
+	create := &escrow.CreateEscrowParam{
+		Depositor:   escrowDepositor,
+		Beneficiary: escrowBeneficiary,
+		Asset:       utils.OxgContractAddress,
+		Amount:      100,
+		Arbiter:     []byte("did:onx:unknown"),
+		Timeout:     500,
+	}
+	err := env.invoke(100, escrowDepositor, escrow.CREATE_ESCROW_NAME, create)
+	assert.NotNil(t, err, "invalid arbiter")
+	create.Arbiter = arbiter.id
+	err = env.invoke(500, escrowDepositor, escrow.CREATE_ESCROW_NAME, create)
+	assert.NotNil(t, err, "timeout is passed")
+
+	env.createEscrow(arbiter)
+	assert.Equal(t, uint64(900), env.balanceOf(utils.OxgContractAddress, escrowDepositor))
+	assert.Equal(t, uint64(100), env.balanceOf(utils.OxgContractAddress, utils.EscrowContractAddress))
+	assert.Equal(t, escrow.ESCROW_OPEN, env.getEscrow(0).Status)
+	assert.Equal(t, &escrow.LockedBalance{Locked: 100}, env.lockedBalance(499, escrowDepositor))
+	assert.Equal(t, &escrow.LockedBalance{Locked: 100, Claimable: 100}, env.lockedBalance(500, escrowDepositor))
+	assert.Equal(t, &escrow.LockedBalance{}, env.lockedBalance(499, escrowBeneficiary))
+
+	create.Amount = 1000
+	err = env.invoke(100, escrowDepositor, escrow.CREATE_ESCROW_NAME, create)
+	assert.NotNil(t, err, "amount over balance")
+}
+
+func TestEscrowRelease(t *testing.T) {
+	env := newEscrowEnv(t)
+	arbiter, other := env.registerID(), env.registerID()
+	env.createEscrow(arbiter)
+
+	err := env.invoke(200, other.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1})
+	assert.NotNil(t, err, "release without signature of arbiter")
+	err = env.invoke(200, arbiter.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 0, KeyNo: 2})
+	assert.NotNil(t, err, "release with unknown key of arbiter")
+	assert.Nil(t, env.invoke(200, arbiter.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1}))
+	assert.Equal(t, escrow.ESCROW_RELEASED, env.getEscrow(0).Status)
+	assert.Equal(t, uint64(100), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, utils.EscrowContractAddress))
+	assert.Equal(t, &escrow.LockedBalance{}, env.lockedBalance(200, escrowDepositor))
+
+	err = env.invoke(300, arbiter.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1})
+	assert.NotNil(t, err, "escrow is settled")
+	err = env.invoke(300, arbiter.signer, escrow.REFUND_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1})
+	assert.NotNil(t, err, "escrow is settled")
+
+	env.createEscrow(arbiter)
+	err = env.invoke(500, arbiter.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 1, KeyNo: 1})
+	assert.NotNil(t, err, "release after timeout")
+	assert.Equal(t, escrow.ESCROW_OPEN, env.getEscrow(1).Status)
+}
+
+func TestEscrowRefund(t *testing.T) {
+	env := newEscrowEnv(t)
+	arbiter := env.registerID()
+
+	//arbiter refunds before timeout, depositor can not
+	env.createEscrow(arbiter)
+	err := env.invoke(200, escrowDepositor, escrow.REFUND_NAME, &escrow.SettleParam{Id: 0})
+	assert.NotNil(t, err, "refund by depositor before timeout")
+	assert.Nil(t, env.invoke(200, arbiter.signer, escrow.REFUND_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1}))
+	assert.Equal(t, escrow.ESCROW_REFUNDED, env.getEscrow(0).Status)
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, escrowDepositor))
+
+	//depositor refunds after timeout, arbiter and beneficiary can not
+	env.createEscrow(arbiter)
+	err = env.invoke(500, arbiter.signer, escrow.REFUND_NAME, &escrow.SettleParam{Id: 1, KeyNo: 1})
+	assert.NotNil(t, err, "refund by arbiter after timeout")
+	err = env.invoke(500, escrowBeneficiary, escrow.REFUND_NAME, &escrow.SettleParam{Id: 1})
+	assert.NotNil(t, err, "refund by beneficiary")
+	assert.Nil(t, env.invoke(500, escrowDepositor, escrow.REFUND_NAME, &escrow.SettleParam{Id: 1}))
+	assert.Equal(t, escrow.ESCROW_REFUNDED, env.getEscrow(1).Status)
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, escrowDepositor))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, escrowBeneficiary))
+	assert.Equal(t, &escrow.LockedBalance{}, env.lockedBalance(500, escrowDepositor))
+}
+
+func TestEscrowByHeight(t *testing.T) {
+	env := newEscrowEnv(t)
+	arbiter := env.registerID()
+	create := &escrow.CreateEscrowParam{
+		Depositor:   escrowDepositor,
+		Beneficiary: escrowBeneficiary,
+		Asset:       utils.OxgContractAddress,
+		Amount:      100,
+		Arbiter:     arbiter.id,
+		ByHeight:    true,
+		Timeout:     10,
+	}
+	env.height = 10
+	err := env.invoke(1, escrowDepositor, escrow.CREATE_ESCROW_NAME, create)
+	assert.NotNil(t, err, "timeout height is reached")
+	env.height = 5
+	assert.Nil(t, env.invoke(1000, escrowDepositor, escrow.CREATE_ESCROW_NAME, create))
+	assert.True(t, env.getEscrow(0).ByHeight)
+
+	//block time is over timeout, but timeout is of height
+	err = env.invoke(1000, escrowDepositor, escrow.REFUND_NAME, &escrow.SettleParam{Id: 0})
+	assert.NotNil(t, err, "refund by depositor before timeout height")
+	env.height = 10
+	err = env.invoke(1, arbiter.signer, escrow.RELEASE_NAME, &escrow.SettleParam{Id: 0, KeyNo: 1})
+	assert.NotNil(t, err, "release at timeout height")
+	assert.Nil(t, env.invoke(1, escrowDepositor, escrow.REFUND_NAME, &escrow.SettleParam{Id: 0}))
+	assert.Equal(t, escrow.ESCROW_REFUNDED, env.getEscrow(0).Status)
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, escrowDepositor))
+}
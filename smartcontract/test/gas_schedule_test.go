@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/context"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+func gasScheduleArgs(t *testing.T, activation uint32, price uint64) []byte {
+	schedule := &global_params.GasSchedule{ActivationHeight: activation, Prices: map[string]uint64{"PUSH1": price}}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, schedule.Serialize(bf))
+	return bf.Bytes()
+}
+
+//getGasSchedule returns gas schedule active at height by getGasSchedule of global params contract
+func (this *nativeEnv) getGasSchedule(height uint32) *global_params.GasSchedule {
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, utils.WriteVarUint(bf, uint64(height)))
+	result, _, err := this.call(0, common.ADDRESS_EMPTY, utils.ParamContractAddress,
+		global_params.GET_GAS_SCHEDULE_NAME, bf.Bytes())
+	assert.Nil(this.t, err)
+	if result == nil || len(result.([]byte)) == 0 {
+		return nil
+	}
+	schedule := new(global_params.GasSchedule)
+	assert.Nil(this.t, schedule.Deserialize(bytes.NewBuffer(result.([]byte))))
+	return schedule
+}
+
+func TestSetGasScheduleOnlyByGovernance(t *testing.T) {
+	env := newNativeEnv(t)
+	operator := common.Address{0x01}
+
+	err := env.invokeNative(0, operator, utils.ParamContractAddress, global_params.SET_GAS_SCHEDULE_NAME,
+		gasScheduleArgs(t, 10, 1))
+	assert.Error(t, err)
+	assert.Nil(t, env.getGasSchedule(10))
+
+	sc := env.newContract(0, operator)
+	sc.PushContext(&context.Context{ContractAddress: utils.GovernanceContractAddress})
+	service, err := sc.NewNativeService()
+	assert.Nil(t, err)
+	_, err = service.NativeCall(utils.ParamContractAddress, global_params.SET_GAS_SCHEDULE_NAME,
+		gasScheduleArgs(t, 10, 2))
+	assert.Nil(t, err)
+
+	assert.Nil(t, env.getGasSchedule(9))
+	schedule := env.getGasSchedule(10)
+	assert.Equal(t, uint32(1), schedule.Version)
+	assert.Equal(t, map[string]uint64{"PUSH1": 2}, schedule.Prices)
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/htlc"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+var (
+	htlcSender    = common.Address{0x01}
+	htlcRecipient = common.Address{0x02}
+)
+
+//htlcEnv invokes htlc contract in nativeEnv
+type htlcEnv struct {
+	*nativeEnv
+}
+
+func newHtlcEnv(t *testing.T) *htlcEnv {
+	return &htlcEnv{newNativeEnv(t)}
+}
+
+func (this *htlcEnv) invoke(time uint32, signer common.Address, method string,
+	param interface{}) ([]*event.NotifyEventInfo, error) {
+	return this.invokeFramework(time, signer, utils.HtlcContractAddress, method, param)
+}
+
+func (this *htlcEnv) getSwap(hashlock []byte) *htlc.Swap {
+	swap, err := htlc.GetSwap(this.newService(0), &htlc.HashlockParam{Hashlock: hashlock})
+	assert.Nil(this.t, err)
+	return swap
+}
+
+//deployToken deploys NeoVM contract as OEP-4 token. Its transfer notifies [method, args] and returns
+//CheckWitness(from), so transfers of tokens are recorded in notifications.
+func (this *nativeEnv) deployToken() common.Address {
+	code := []byte{byte(vm.PUSH2), byte(vm.PACK), byte(vm.DUP)}
+	code = append(code, syscall(neovm.RUNTIME_NOTIFY_NAME)...)
+	code = append(code, byte(vm.PUSH1), byte(vm.PICKITEM), byte(vm.PUSH0), byte(vm.PICKITEM))
+	code = append(code, syscall(neovm.RUNTIME_CHECKWITNESS_NAME)...)
+	code = append(code, byte(vm.RET))
+	return this.deploy(code)
+}
+
+//deployBrokenToken deploys NeoVM contract whose transfer always returns false
+func (this *nativeEnv) deployBrokenToken() common.Address {
+	return this.deploy([]byte{byte(vm.DROP), byte(vm.DROP), byte(vm.PUSH0), byte(vm.RET)})
+}
+
+func syscall(name string) []byte {
+	return append([]byte{byte(vm.SYSCALL), byte(len(name))}, name...)
+}
+
+func tokenTransfers(notifies []*event.NotifyEventInfo, token common.Address) [][]interface{} {
+	var transfers [][]interface{}
+	for _, notify := range notifies {
+		if notify.ContractAddress != token {
+			continue
+		}
+		states := notify.States.([]interface{})
+		transfers = append(transfers, states[1].([]interface{}))
+	}
+	return transfers
+}
+
+func TestHtlcClaim(t *testing.T) {
+	env := newHtlcEnv(t)
+	env.setBalance(utils.OxgContractAddress, htlcSender, 1000)
+
+	preimage := []byte("secret of swap")
+	hashlock, err := htlc.Hash(htlc.HASH_SHA256, preimage)
+	assert.Nil(t, err)
+	lock := &htlc.LockParam{
+		Sender:    htlcSender,
+		Recipient: htlcRecipient,
+		Asset:     utils.OxgContractAddress,
+		Amount:    400,
+		Hashlock:  hashlock,
+		HashType:  htlc.HASH_SHA256,
+		Timelock:  200,
+	}
+	_, err = env.invoke(100, htlcRecipient, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "lock without signature of sender")
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(600), env.balanceOf(utils.OxgContractAddress, htlcSender))
+	assert.Equal(t, uint64(400), env.balanceOf(utils.OxgContractAddress, utils.HtlcContractAddress))
+
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "hashlock is used")
+
+	_, err = env.invoke(150, htlcSender, htlc.REFUND_NAME, &htlc.HashlockParam{Hashlock: hashlock})
+	assert.NotNil(t, err, "refund before timelock")
+	_, err = env.invoke(150, htlcRecipient, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: []byte("wrong")})
+	assert.NotNil(t, err)
+
+	_, err = env.invoke(150, common.Address{0x03}, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: preimage})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(400), env.balanceOf(utils.OxgContractAddress, htlcRecipient))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, utils.HtlcContractAddress))
+
+	swap := env.getSwap(hashlock)
+	assert.Equal(t, htlc.SWAP_CLAIMED, swap.Status)
+	assert.Equal(t, preimage, swap.Preimage)
+
+	_, err = env.invoke(150, htlcRecipient, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: preimage})
+	assert.NotNil(t, err, "claim twice")
+	_, err = env.invoke(300, htlcSender, htlc.REFUND_NAME, &htlc.HashlockParam{Hashlock: hashlock})
+	assert.NotNil(t, err, "refund claimed swap")
+}
+
+func TestHtlcRefund(t *testing.T) {
+	env := newHtlcEnv(t)
+	env.setBalance(utils.OxgContractAddress, htlcSender, 1000)
+
+	preimage := []byte("secret of swap")
+	hashlock, err := htlc.Hash(htlc.HASH_HASH160, preimage)
+	assert.Nil(t, err)
+	lock := &htlc.LockParam{
+		Sender:    htlcSender,
+		Recipient: htlcRecipient,
+		Asset:     utils.OxgContractAddress,
+		Amount:    1000,
+		Hashlock:  hashlock,
+		HashType:  htlc.HASH_HASH160,
+		Timelock:  200,
+	}
+	_, err = env.invoke(200, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "timelock is passed")
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OxgContractAddress, htlcSender))
+
+	_, err = env.invoke(200, htlcRecipient, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: preimage})
+	assert.NotNil(t, err, "claim after timelock")
+	_, err = env.invoke(200, htlcRecipient, htlc.REFUND_NAME, &htlc.HashlockParam{Hashlock: hashlock})
+	assert.NotNil(t, err, "refund without signature of sender")
+
+	_, err = env.invoke(200, htlcSender, htlc.REFUND_NAME, &htlc.HashlockParam{Hashlock: hashlock})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1000), env.balanceOf(utils.OxgContractAddress, htlcSender))
+	assert.Equal(t, htlc.SWAP_REFUNDED, env.getSwap(hashlock).Status)
+
+	_, err = env.invoke(200, htlcSender, htlc.REFUND_NAME, &htlc.HashlockParam{Hashlock: hashlock})
+	assert.NotNil(t, err, "refund twice")
+}
+
+func TestHtlcOep4(t *testing.T) {
+	env := newHtlcEnv(t)
+	token := env.deployToken()
+
+	preimage := []byte("secret of swap")
+	hashlock, err := htlc.Hash(htlc.HASH_SHA256, preimage)
+	assert.Nil(t, err)
+	lock := &htlc.LockParam{
+		Sender:    htlcSender,
+		Recipient: htlcRecipient,
+		Asset:     token,
+		Amount:    100,
+		Hashlock:  hashlock,
+		HashType:  htlc.HASH_SHA256,
+		Timelock:  200,
+	}
+	notifies, err := env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.Nil(t, err)
+	transfers := tokenTransfers(notifies, token)
+	sender, recipient := common.ToHexString(htlcSender[:]), common.ToHexString(htlcRecipient[:])
+	contract := common.ToHexString(utils.HtlcContractAddress[:])
+	assert.Equal(t, [][]interface{}{{sender, contract, "64"}}, transfers)
+
+	notifies, err = env.invoke(150, htlcRecipient, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: preimage})
+	assert.Nil(t, err)
+	transfers = tokenTransfers(notifies, token)
+	assert.Equal(t, [][]interface{}{{contract, recipient, "64"}}, transfers)
+
+	broken := env.deployBrokenToken()
+	lock.Asset = broken
+	lock.Hashlock, err = htlc.Hash(htlc.HASH_SHA256, []byte("another secret"))
+	assert.Nil(t, err)
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "transfer of token returns false")
+
+	lock.Asset = common.Address{0x04}
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "asset is not deployed")
+}
+
+func TestHtlcOnx(t *testing.T) {
+	env := newHtlcEnv(t)
+	env.setBalance(utils.OnxContractAddress, htlcSender, 10)
+
+	preimage := []byte("secret of swap")
+	hashlock, err := htlc.Hash(htlc.HASH_SHA256, preimage)
+	assert.Nil(t, err)
+	lock := &htlc.LockParam{
+		Sender:    htlcSender,
+		Recipient: htlcRecipient,
+		Asset:     utils.OnxContractAddress,
+		Hashlock:  hashlock,
+		HashType:  htlc.HASH_HASH160,
+		Timelock:  200,
+	}
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "hashlock does not match hash type")
+	lock.HashType = htlc.HASH_SHA256
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "zero amount")
+	lock.Amount = 11
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.NotNil(t, err, "amount over balance")
+	lock.Amount = 10
+	_, err = env.invoke(100, htlcSender, htlc.LOCK_NAME, lock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), env.balanceOf(utils.OnxContractAddress, utils.HtlcContractAddress))
+
+	swap := env.getSwap(hashlock)
+	assert.Equal(t, htlcSender, swap.Sender)
+	assert.Equal(t, htlcRecipient, swap.Recipient)
+	assert.Equal(t, utils.OnxContractAddress, swap.Asset)
+	assert.Equal(t, uint64(10), swap.Amount)
+	assert.Equal(t, uint32(200), swap.Timelock)
+	assert.Equal(t, htlc.SWAP_LOCKED, swap.Status)
+	assert.Empty(t, swap.Preimage)
+
+	_, err = env.invoke(150, htlcRecipient, htlc.CLAIM_NAME, &htlc.ClaimParam{Preimage: preimage})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), env.balanceOf(utils.OnxContractAddress, htlcRecipient))
+	assert.Equal(t, uint64(0), env.balanceOf(utils.OnxContractAddress, utils.HtlcContractAddress))
+}
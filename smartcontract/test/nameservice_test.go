@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/account"
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	ns "github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/nameservice"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var (
+	nameAlice = common.Address{0x11}
+	nameBob   = common.Address{0x12}
+	nameCarol = common.Address{0x13}
+	nameShop  = common.Address{0x14}
+)
+
+//nameEnv invokes name service in nativeEnv
+type nameEnv struct {
+	*nativeEnv
+}
+
+func newNameEnv(t *testing.T) *nameEnv {
+	return &nameEnv{newNativeEnv(t)}
+}
+
+func (this *nameEnv) invoke(time uint32, signer common.Address, method string, param interface{}) error {
+	_, err := this.invokeFramework(time, signer, utils.NameContractAddress, method, param)
+	return err
+}
+
+func (this *nameEnv) register(time uint32, name string, owner common.Address, years uint32) error {
+	return this.invoke(time, owner, ns.REGISTER_NAME, &ns.RegisterParam{Name: name, Owner: owner, Years: years})
+}
+
+func (this *nameEnv) resolve(time uint32, name string) (*ns.Name, error) {
+	return ns.Resolve(this.newService(time), &ns.NameParam{Name: name})
+}
+
+func (this *nameEnv) reverse(time uint32, addr common.Address) (*ns.Name, error) {
+	return ns.Reverse(this.newService(time), &ns.AddressParam{Address: addr})
+}
+
+func TestNameServiceRegister(t *testing.T) {
+	env := newNameEnv(t)
+	env.setBalance(utils.OxgContractAddress, nameAlice, 3*ns.REGISTER_FEE)
+	env.setBalance(utils.OxgContractAddress, nameBob, 3*ns.REGISTER_FEE)
+
+	err := env.invoke(100, nameBob, ns.REGISTER_NAME, &ns.RegisterParam{Name: "alice.onx", Owner: nameAlice, Years: 2})
+	assert.NotNil(t, err, "register without signature of owner")
+	assert.NotNil(t, env.register(100, "al.onx", nameAlice, 1), "label is too short")
+	assert.NotNil(t, env.register(100, "pay.alice.onx", nameAlice, 1), "register subname")
+	assert.NotNil(t, env.register(100, "alice.onx", nameAlice, 4), "fee is not enough")
+
+	assert.Nil(t, env.register(100, "alice.onx", nameAlice, 2))
+	assert.Equal(t, ns.REGISTER_FEE, env.balanceOf(utils.OxgContractAddress, nameAlice))
+	assert.Equal(t, 2*ns.REGISTER_FEE, env.balanceOf(utils.OxgContractAddress, utils.GovernanceContractAddress))
+	assert.NotNil(t, env.register(150, "alice.onx", nameBob, 1), "name is registered")
+
+	record, err := env.resolve(150, "alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameAlice, record.Owner)
+	assert.Equal(t, nameAlice, record.Address)
+	assert.Equal(t, 100+2*ns.YEAR, record.Expiration)
+
+	expiration := 100 + 2*ns.YEAR
+	_, err = env.resolve(expiration, "alice.onx")
+	assert.NotNil(t, err, "name is expired")
+	assert.NotNil(t, env.register(expiration, "alice.onx", nameBob, 1), "name is in grace period")
+
+	renew := &ns.RenewParam{Name: "alice.onx", Payer: nameBob, Years: 9}
+	assert.NotNil(t, env.invoke(150, nameBob, ns.RENEW_NAME, renew), "renew too many years ahead")
+	renew.Years = 1
+	assert.Nil(t, env.invoke(expiration, nameBob, ns.RENEW_NAME, renew))
+	record, err = env.resolve(expiration, "alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameAlice, record.Owner)
+	assert.Equal(t, expiration+ns.YEAR, record.Expiration)
+
+	expiration += ns.YEAR
+	assert.NotNil(t, env.invoke(expiration+ns.GRACE_PERIOD, nameBob, ns.RENEW_NAME, renew), "renew after grace period")
+	assert.Nil(t, env.register(expiration+ns.GRACE_PERIOD, "alice.onx", nameBob, 1))
+	record, err = env.resolve(expiration+ns.GRACE_PERIOD, "alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameBob, record.Owner)
+}
+
+func TestNameServiceRecords(t *testing.T) {
+	env := newNameEnv(t)
+	env.setBalance(utils.OxgContractAddress, nameAlice, ns.REGISTER_FEE)
+	env.setBalance(utils.OxgContractAddress, nameBob, ns.REGISTER_FEE)
+	assert.Nil(t, env.register(100, "alice.onx", nameAlice, 1))
+
+	sub := &ns.SetSubnameParam{Parent: "alice.onx", Label: "pay", Owner: nameCarol}
+	assert.NotNil(t, env.invoke(100, nameBob, ns.SET_SUBNAME_NAME, sub), "set subname without signature of parent owner")
+	assert.Nil(t, env.invoke(100, nameAlice, ns.SET_SUBNAME_NAME, sub))
+	record, err := env.resolve(100, "pay.alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameCarol, record.Address)
+	assert.Equal(t, 100+ns.YEAR, record.Expiration)
+
+	id, err := account.GenerateID()
+	assert.Nil(t, err)
+	records := &ns.SetRecordsParam{Name: "pay.alice.onx", Address: nameShop, OnxID: []byte("did:onx:unknown")}
+	assert.NotNil(t, env.invoke(100, nameCarol, ns.SET_RECORDS_NAME, records), "invalid ONX ID")
+	records.OnxID = []byte(id)
+	assert.NotNil(t, env.invoke(100, nameAlice, ns.SET_RECORDS_NAME, records), "set records without signature of owner")
+	assert.Nil(t, env.invoke(100, nameCarol, ns.SET_RECORDS_NAME, records))
+	record, err = env.resolve(100, "pay.alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameShop, record.Address)
+	assert.Equal(t, []byte(id), record.OnxID)
+
+	reverse := &ns.SetReverseParam{Address: nameCarol, Name: "pay.alice.onx"}
+	assert.NotNil(t, env.invoke(100, nameCarol, ns.SET_REVERSE_NAME, reverse), "name does not resolve to address")
+	reverse.Address = nameShop
+	assert.Nil(t, env.invoke(100, nameShop, ns.SET_REVERSE_NAME, reverse))
+	record, err = env.reverse(100, nameShop)
+	assert.Nil(t, err)
+	assert.Equal(t, "pay.alice.onx", record.Name)
+
+	assert.Nil(t, env.invoke(100, nameAlice, ns.TRANSFER_NAME, &ns.TransferParam{Name: "alice.onx", To: nameBob}))
+	assert.NotNil(t, env.invoke(100, nameAlice, ns.SET_SUBNAME_NAME, sub), "set subname by former owner")
+
+	records.Address = nameCarol
+	assert.Nil(t, env.invoke(100, nameCarol, ns.SET_RECORDS_NAME, records))
+	_, err = env.reverse(100, nameShop)
+	assert.NotNil(t, err, "reverse name does not resolve to address")
+
+	registered := 100 + ns.YEAR + ns.GRACE_PERIOD
+	assert.Nil(t, env.register(registered, "alice.onx", nameBob, 1))
+	_, err = env.resolve(registered, "pay.alice.onx")
+	assert.NotNil(t, err, "subname of former registration is stale")
+}
+
+func TestNameServiceTransfer(t *testing.T) {
+	env := newNameEnv(t)
+	env.setBalance(utils.OxgContractAddress, nameAlice, ns.REGISTER_FEE)
+	assert.Nil(t, env.register(100, "alice.onx", nameAlice, 1))
+	id, err := account.GenerateID()
+	assert.Nil(t, err)
+	records := &ns.SetRecordsParam{Name: "alice.onx", Address: nameShop, OnxID: []byte(id), Contract: utils.OnxContractAddress}
+	assert.Nil(t, env.invoke(100, nameAlice, ns.SET_RECORDS_NAME, records))
+	assert.Nil(t, env.invoke(100, nameShop, ns.SET_REVERSE_NAME, &ns.SetReverseParam{Address: nameShop, Name: "alice.onx"}))
+
+	transfer := &ns.TransferParam{Name: "alice.onx", To: nameBob}
+	assert.NotNil(t, env.invoke(110, nameBob, ns.TRANSFER_NAME, transfer), "transfer without signature of owner")
+	assert.Nil(t, env.invoke(110, nameAlice, ns.TRANSFER_NAME, transfer))
+	record, err := env.resolve(110, "alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameBob, record.Owner)
+	assert.Equal(t, nameShop, record.Address)
+	assert.Equal(t, []byte(id), record.OnxID)
+	assert.Equal(t, utils.OnxContractAddress, record.Contract)
+	assert.Equal(t, uint32(100), record.Registered)
+	assert.Equal(t, 100+ns.YEAR, record.Expiration)
+	record, err = env.reverse(110, nameShop)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice.onx", record.Name)
+
+	assert.NotNil(t, env.invoke(110, nameAlice, ns.SET_REVERSE_NAME, &ns.SetReverseParam{Address: nameShop}),
+		"clear reverse without signature of address")
+	assert.Nil(t, env.invoke(110, nameShop, ns.SET_REVERSE_NAME, &ns.SetReverseParam{Address: nameShop}))
+	_, err = env.reverse(110, nameShop)
+	assert.NotNil(t, err, "reverse record is cleared")
+
+	records.Address = nameAlice
+	assert.NotNil(t, env.invoke(120, nameAlice, ns.SET_RECORDS_NAME, records), "set records by former owner")
+	records.Address, records.OnxID, records.Contract = nameBob, nil, common.ADDRESS_EMPTY
+	assert.Nil(t, env.invoke(120, nameBob, ns.SET_RECORDS_NAME, records))
+	record, err = env.resolve(120, "alice.onx")
+	assert.Nil(t, err)
+	assert.Equal(t, nameBob, record.Address)
+	assert.Empty(t, record.OnxID)
+}
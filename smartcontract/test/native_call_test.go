@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+)
+
+//nativeTransferCode calls method of ONX contract by typed native call with transfers [[self, to, amount]],
+//and returns [code, result] of the call
+func nativeTransferCode(method string, to common.Address, amount int64) []byte {
+	value := types.BigIntToBytes(big.NewInt(amount))
+	code := append([]byte{byte(len(value))}, value...)
+	code = append(code, byte(common.ADDR_LEN))
+	code = append(code, to[:]...)
+	code = append(code, syscall(neovm.GETEXECUTINGSCRIPTHASH_NAME)...)
+	code = append(code, byte(vm.PUSH3), byte(vm.PACK), byte(vm.PUSH1), byte(vm.PACK), byte(vm.PUSH1), byte(vm.PACK))
+	code = append(code, byte(len(method)))
+	code = append(code, method...)
+	code = append(code, byte(common.ADDR_LEN))
+	code = append(code, utils.OnxContractAddress[:]...)
+	code = append(code, byte(vm.PUSH0))
+	code = append(code, syscall(neovm.NATIVE_CALL_NAME)...)
+	return append(code, byte(vm.RET))
+}
+
+//callNativeTransfer invokes contract and returns code and result of its native call with notifications
+func (this *nativeEnv) callNativeTransfer(contract common.Address) (int64, types.StackItems,
+	[]*event.NotifyEventInfo) {
+	sc := this.newContract(0, common.ADDRESS_EMPTY)
+	ret, err := sc.AppCall(contract, "main", nil)
+	assert.Nil(this.t, err)
+	items, err := ret.(types.StackItems).GetArray()
+	assert.Nil(this.t, err)
+	assert.Equal(this.t, 2, len(items))
+	code, err := items[0].GetBigInteger()
+	assert.Nil(this.t, err)
+	return code.Int64(), items[1], sc.Notifications
+}
+
+func TestNeoVmNativeCallOnxTransfer(t *testing.T) {
+	env := newNativeEnv(t)
+	to := common.Address{0x01}
+	contract := env.deploy(nativeTransferCode("transfer", to, 100))
+	env.setBalance(utils.OnxContractAddress, contract, 150)
+
+	code, result, notifies := env.callNativeTransfer(contract)
+	assert.Equal(t, int64(neovm.NATIVE_CALL_SUCCESS), code)
+	ok, err := result.GetBoolean()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(50), env.balanceOf(utils.OnxContractAddress, contract))
+	assert.Equal(t, uint64(100), env.balanceOf(utils.OnxContractAddress, to))
+	assert.Equal(t, 1, len(notifies))
+	assert.Equal(t, utils.OnxContractAddress, notifies[0].ContractAddress)
+	assert.Equal(t, []interface{}{"transfer", contract.ToBase58(), to.ToBase58(), uint64(100)}, notifies[0].States)
+
+	//insufficient balance is returned as error code, with changes and notifications of native call reverted
+	code, _, notifies = env.callNativeTransfer(contract)
+	assert.Equal(t, int64(neovm.NATIVE_CALL_ERR_EXECUTE), code)
+	assert.Equal(t, uint64(50), env.balanceOf(utils.OnxContractAddress, contract))
+	assert.Equal(t, uint64(100), env.balanceOf(utils.OnxContractAddress, to))
+	assert.Equal(t, 0, len(notifies))
+}
+
+func TestNeoVmNativeCallErrors(t *testing.T) {
+	env := newNativeEnv(t)
+	to := common.Address{0x01}
+
+	contract := env.deploy(nativeTransferCode("unknown", to, 100))
+	code, _, _ := env.callNativeTransfer(contract)
+	assert.Equal(t, int64(neovm.NATIVE_CALL_ERR_NOT_FOUND), code)
+
+	//approve takes from, to and value instead of array of transfers
+	contract = env.deploy(nativeTransferCode("approve", to, 100))
+	code, _, _ = env.callNativeTransfer(contract)
+	assert.Equal(t, int64(neovm.NATIVE_CALL_ERR_PARAMS), code)
+}
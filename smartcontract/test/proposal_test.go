@@ -0,0 +1,238 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/context"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/global_params"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var (
+	stakerA = common.Address{0xa1}
+	stakerB = common.Address{0xb1}
+	stakerC = common.Address{0xc1}
+)
+
+//setupProposalEnv puts peers of stakers a, b and c with init pos 60, 30 and 10 into view 1, voting lasts
+//10 blocks and executes at least 5 blocks later, quorum is 30% and threshold is 60%, proposer needs stake 10
+func setupProposalEnv(t *testing.T) *nativeEnv {
+	env := newNativeEnv(t)
+	contract := utils.GovernanceContractAddress
+
+	bf := new(bytes.Buffer)
+	assert.Nil(t, (&governance.GovernanceView{View: 1}).Serialize(bf))
+	env.db.Put(utils.ConcatKey(contract, []byte(governance.GOVERNANCE_VIEW)), cstates.GenRawStorageItem(bf.Bytes()))
+
+	env.putPeerPool(1)
+
+	proposalConfig := &governance.ProposalConfig{VotingPeriod: 10, MinExecuteDelay: 5, Quorum: 30, Threshold: 60,
+		MinProposerStake: 10}
+	bf = new(bytes.Buffer)
+	assert.Nil(t, proposalConfig.Serialize(bf))
+	env.db.Put(utils.ConcatKey(contract, []byte(governance.PROPOSAL_CONFIG)), cstates.GenRawStorageItem(bf.Bytes()))
+	return env
+}
+
+//putPeerPool puts peers of stakers a, b and c with init pos 60, 30 and 10 into peer pool of view
+func (this *nativeEnv) putPeerPool(view uint32) {
+	peerPoolMap := &governance.PeerPoolMap{PeerPoolMap: map[string]*governance.PeerPoolItem{
+		"02a1": {Index: 1, PeerPubkey: "02a1", Address: stakerA, Status: governance.ConsensusStatus, InitPos: 60},
+		"02b1": {Index: 2, PeerPubkey: "02b1", Address: stakerB, Status: governance.CandidateStatus, InitPos: 30},
+		"02c1": {Index: 3, PeerPubkey: "02c1", Address: stakerC, Status: governance.ConsensusStatus, InitPos: 10},
+	}}
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, peerPoolMap.Serialize(bf))
+	viewBytes, err := governance.GetUint32Bytes(view)
+	assert.Nil(this.t, err)
+	this.db.Put(utils.ConcatKey(utils.GovernanceContractAddress, []byte(governance.PEER_POOL), viewBytes),
+		cstates.GenRawStorageItem(bf.Bytes()))
+}
+
+func (this *nativeEnv) createProposal(proposer common.Address, kind uint8, payload []byte, executeHeight uint32) error {
+	param := &governance.CreateProposalParam{
+		Proposer:      proposer,
+		Kind:          kind,
+		Payload:       payload,
+		ExecuteHeight: executeHeight,
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, param.Serialize(bf))
+	return this.invokeNative(0, proposer, utils.GovernanceContractAddress, governance.CREATE_PROPOSAL, bf.Bytes())
+}
+
+func (this *nativeEnv) voteProposal(voter common.Address, id uint64, approve bool) error {
+	param := &governance.VoteProposalParam{Voter: voter, Id: id, Approve: approve}
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, param.Serialize(bf))
+	return this.invokeNative(0, voter, utils.GovernanceContractAddress, governance.VOTE_PROPOSAL, bf.Bytes())
+}
+
+//executeProposals executes proposals scheduled at height the same as ledger does before transactions of block
+func (this *nativeEnv) executeProposals(height uint32) []*event.NotifyEventInfo {
+	this.height = height
+	sc := this.newContract(0, common.ADDRESS_EMPTY)
+	service, err := sc.NewNativeService()
+	assert.Nil(this.t, err)
+	sc.PushContext(&context.Context{ContractAddress: utils.GovernanceContractAddress})
+	assert.Nil(this.t, governance.ExecuteProposals(service))
+	sc.PopContext()
+	return append(sc.Notifications, service.Notifications...)
+}
+
+func (this *nativeEnv) getProposal(id uint64) *governance.Proposal {
+	raw, err := this.db.Get(utils.ConcatKey(utils.GovernanceContractAddress, governance.ProposalKey(id)))
+	assert.Nil(this.t, err)
+	value, err := cstates.GetValueFromRawStorageItem(raw)
+	assert.Nil(this.t, err)
+	proposal := new(governance.Proposal)
+	assert.Nil(this.t, proposal.Deserialize(bytes.NewBuffer(value)))
+	return proposal
+}
+
+func globalParamPayload(t *testing.T) []byte {
+	params := global_params.Params{{Key: "gasPrice", Value: "500"}}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, params.Serialize(bf))
+	return bf.Bytes()
+}
+
+func TestProposalLifecycle(t *testing.T) {
+	env := setupProposalEnv(t)
+
+	//no stake, or execute height earlier than end of voting plus delay
+	assert.Error(t, env.createProposal(common.Address{0xff}, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 16))
+	assert.Error(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 15))
+	assert.Error(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, []byte{1}, 16))
+
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 16))
+	}
+	//1: quorum exactly reached
+	assert.Nil(t, env.voteProposal(stakerB, 1, true))
+	//2: quorum not reached
+	assert.Nil(t, env.voteProposal(stakerC, 2, true))
+	//3: threshold exactly reached
+	assert.Nil(t, env.voteProposal(stakerA, 3, true))
+	assert.Nil(t, env.voteProposal(stakerB, 3, false))
+	assert.Nil(t, env.voteProposal(stakerC, 3, false))
+	//4: threshold not reached, changed vote is counted once
+	assert.Nil(t, env.voteProposal(stakerA, 4, true))
+	assert.Nil(t, env.voteProposal(stakerA, 4, false))
+	assert.Nil(t, env.voteProposal(stakerB, 4, true))
+	assert.Nil(t, env.voteProposal(stakerC, 4, true))
+	//5: nobody votes
+	assert.Error(t, env.voteProposal(common.Address{0xff}, 5, true))
+	assert.Error(t, env.voteProposal(stakerA, 6, true))
+
+	env.height = 11
+	assert.Error(t, env.voteProposal(stakerA, 5, true))
+
+	assert.Empty(t, env.executeProposals(15))
+	for id := uint64(1); id <= 5; id++ {
+		assert.Equal(t, governance.PROPOSAL_PENDING, env.getProposal(id).Status)
+	}
+
+	notifies := env.executeProposals(16)
+	expected := []uint8{governance.PROPOSAL_EXECUTED, governance.PROPOSAL_REJECTED, governance.PROPOSAL_EXECUTED,
+		governance.PROPOSAL_REJECTED, governance.PROPOSAL_REJECTED}
+	for i, status := range expected {
+		proposal := env.getProposal(uint64(i + 1))
+		assert.Equal(t, status, proposal.Status)
+		assert.Equal(t, uint64(100), proposal.TotalWeight)
+	}
+	assert.Equal(t, uint64(40), env.getProposal(4).YesWeight)
+	assert.Equal(t, uint64(60), env.getProposal(4).NoWeight)
+
+	var names []interface{}
+	for _, notify := range notifies {
+		if notify.ContractAddress == utils.GovernanceContractAddress {
+			names = append(names, notify.States.([]interface{})[0])
+		}
+	}
+	assert.Equal(t, []interface{}{"executed", "rejected", "executed", "rejected", "rejected"}, names)
+
+	//executed only once
+	assert.Empty(t, env.executeProposals(16))
+}
+
+func TestGasScheduleProposal(t *testing.T) {
+	env := setupProposalEnv(t)
+
+	assert.Error(t, env.createProposal(stakerA, governance.PROPOSAL_GAS_SCHEDULE,
+		gasScheduleArgs(t, 100, 0)[:1], 16))
+	//1: applied by setGasSchedule of global params contract
+	assert.Nil(t, env.createProposal(stakerA, governance.PROPOSAL_GAS_SCHEDULE, gasScheduleArgs(t, 100, 2), 16))
+	//activation height is not after execute height
+	assert.Error(t, env.createProposal(stakerA, governance.PROPOSAL_GAS_SCHEDULE, gasScheduleArgs(t, 16, 3), 16))
+	assert.Nil(t, env.voteProposal(stakerA, 1, true))
+
+	notifies := env.executeProposals(16)
+	assert.Equal(t, governance.PROPOSAL_EXECUTED, env.getProposal(1).Status)
+	var states []interface{}
+	for _, notify := range notifies {
+		if notify.ContractAddress == utils.ParamContractAddress {
+			states = append(states, notify.States)
+		}
+	}
+	assert.Equal(t, []interface{}{[]interface{}{global_params.SET_GAS_SCHEDULE_NAME, uint32(1), uint32(100)}}, states)
+
+	assert.Nil(t, env.getGasSchedule(99))
+	schedule := env.getGasSchedule(100)
+	assert.Equal(t, uint32(1), schedule.Version)
+	assert.Equal(t, map[string]uint64{"PUSH1": 2}, schedule.Prices)
+}
+
+func TestProposalLimits(t *testing.T) {
+	env := setupProposalEnv(t)
+
+	for i := 0; i < governance.MAX_PROPOSALS_PER_HEIGHT; i++ {
+		assert.Nil(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 16))
+	}
+	assert.Error(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 16))
+	assert.Nil(t, env.createProposal(stakerC, governance.PROPOSAL_GLOBAL_PARAM, globalParamPayload(t), 17))
+
+	//voters of proposal are bounded, voter who has voted can still change vote
+	assert.Nil(t, env.voteProposal(stakerA, 1, true))
+	proposal := env.getProposal(1)
+	for len(proposal.Voters) < governance.MAX_PROPOSAL_VOTERS {
+		proposal.Voters = append(proposal.Voters, common.Address{0xee, byte(len(proposal.Voters))})
+	}
+	bf := new(bytes.Buffer)
+	assert.Nil(t, proposal.Serialize(bf))
+	env.db.Put(utils.ConcatKey(utils.GovernanceContractAddress, governance.ProposalKey(1)),
+		cstates.GenRawStorageItem(bf.Bytes()))
+	assert.Error(t, env.voteProposal(stakerB, 1, true))
+	assert.Nil(t, env.voteProposal(stakerA, 1, false))
+
+	//proposer stake can not be configured to 0
+	config := &governance.ProposalConfig{VotingPeriod: 10, MinExecuteDelay: 5, Quorum: 30, Threshold: 60}
+	bf = new(bytes.Buffer)
+	assert.Nil(t, config.Serialize(bf))
+	assert.Error(t, env.createProposal(stakerA, governance.PROPOSAL_PROPOSAL_CONFIG, bf.Bytes(), 18))
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-crypto/keypair"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/serialization"
+	"github.com/OnyxPay/OnyxChain-legacy/core/types"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+var recoveryMembers = []common.Address{{0x21}, {0x22}, {0x23}}
+
+//recoveryArgs serializes args of onxid methods: []byte as var bytes, uint32 as var uint, and
+//[]common.Address as var uint count followed by addresses
+func recoveryArgs(args ...interface{}) []byte {
+	bf := new(bytes.Buffer)
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			serialization.WriteVarBytes(bf, v)
+		case uint32:
+			utils.WriteVarUint(bf, uint64(v))
+		case common.Address:
+			utils.WriteAddress(bf, v)
+		case []common.Address:
+			utils.WriteVarUint(bf, uint64(len(v)))
+			for _, addr := range v {
+				utils.WriteAddress(bf, addr)
+			}
+		}
+	}
+	return bf.Bytes()
+}
+
+func (this *nativeEnv) invokeID(time uint32, signer common.Address, method string, args ...interface{}) error {
+	return this.invokeNative(time, signer, utils.OnxIDContractAddress, method, recoveryArgs(args...))
+}
+
+func (this *nativeEnv) keyState(id []byte, keyNo uint32) string {
+	result, _, err := this.call(0, common.ADDRESS_EMPTY, utils.OnxIDContractAddress, "getKeyState",
+		recoveryArgs(id, keyNo))
+	assert.Nil(this.t, err)
+	return string(result.([]byte))
+}
+
+func newPublicKey(t *testing.T) ([]byte, common.Address) {
+	_, pub, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	assert.Nil(t, err)
+	return keypair.SerializePublicKey(pub), types.AddressFromPubKey(pub)
+}
+
+func TestRecoveryGroupFlow(t *testing.T) {
+	env := newNativeEnv(t)
+	owner := env.registerID()
+	ownerKey := keypair.SerializePublicKey(owner.pub)
+	m1, m2, m3 := recoveryMembers[0], recoveryMembers[1], recoveryMembers[2]
+
+	revokedKey, revokedSigner := newPublicKey(t)
+	assert.Nil(t, env.invokeID(0, owner.signer, "addKey", owner.id, revokedKey, ownerKey))
+	assert.Nil(t, env.invokeID(0, owner.signer, "removeKey", owner.id, revokedKey, ownerKey))
+	assert.Equal(t, "revoked", env.keyState(owner.id, 2))
+
+	set := []interface{}{owner.id, uint32(2), recoveryMembers, uint32(100)}
+	err := env.invokeID(10, revokedSigner, "setRecoveryGroup", append(set, revokedKey)...)
+	assert.NotNil(t, err, "set recovery group by revoked key")
+	err = env.invokeID(10, owner.signer, "setRecoveryGroup", owner.id, uint32(4), recoveryMembers, uint32(100), ownerKey)
+	assert.NotNil(t, err, "threshold exceeds member number")
+	assert.Nil(t, env.invokeID(10, owner.signer, "setRecoveryGroup", append(set, ownerKey)...))
+
+	newKey, newSigner := newPublicKey(t)
+	err = env.invokeID(20, owner.signer, "requestRecovery", owner.id, newKey, owner.signer)
+	assert.NotNil(t, err, "request by non member")
+	err = env.invokeID(20, m2, "requestRecovery", owner.id, newKey, m1)
+	assert.NotNil(t, err, "request without signature of member")
+	assert.Nil(t, env.invokeID(20, m1, "requestRecovery", owner.id, newKey, m1))
+	err = env.invokeID(20, m2, "requestRecovery", owner.id, newKey, m2)
+	assert.NotNil(t, err, "recovery is pending")
+	err = env.invokeID(20, owner.signer, "setRecoveryGroup", append(set, ownerKey)...)
+	assert.NotNil(t, err, "change recovery group while recovery is pending")
+
+	err = env.invokeID(30, m1, "approveRecovery", owner.id, m1)
+	assert.NotNil(t, err, "approve twice")
+	err = env.invokeID(30, m1, "executeRecovery", owner.id, m1)
+	assert.NotNil(t, err, "execute before threshold approvals")
+	assert.Nil(t, env.invokeID(30, m2, "approveRecovery", owner.id, m2))
+	err = env.invokeID(129, m3, "executeRecovery", owner.id, m3)
+	assert.NotNil(t, err, "execute before delay")
+	assert.Nil(t, env.invokeID(130, m3, "executeRecovery", owner.id, m3))
+
+	assert.Equal(t, "revoked", env.keyState(owner.id, 1))
+	assert.Equal(t, "in use", env.keyState(owner.id, 3))
+	err = env.invokeID(140, m1, "approveRecovery", owner.id, m1)
+	assert.NotNil(t, err, "no pending recovery")
+
+	otherKey, _ := newPublicKey(t)
+	assert.Nil(t, env.invokeID(150, m1, "requestRecovery", owner.id, otherKey, m1))
+	err = env.invokeID(150, owner.signer, "cancelRecovery", owner.id, ownerKey)
+	assert.NotNil(t, err, "cancel by recovered key")
+	err = env.invokeID(150, owner.signer, "setRecoveryGroup", append(set, ownerKey)...)
+	assert.NotNil(t, err, "set recovery group by recovered key")
+	assert.Nil(t, env.invokeID(150, newSigner, "cancelRecovery", owner.id, newKey))
+	err = env.invokeID(150, newSigner, "cancelRecovery", owner.id, newKey)
+	assert.NotNil(t, err, "cancel twice")
+	assert.Nil(t, env.invokeID(160, newSigner, "setRecoveryGroup", append(set, newKey)...))
+}
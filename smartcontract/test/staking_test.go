@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	cstates "github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/framework"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/governance"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	sstates "github.com/OnyxPay/OnyxChain-legacy/smartcontract/states"
+)
+
+//preExecute invokes method of native contract in pre-execution
+func (this *nativeEnv) preExecute(addr common.Address, method string, args []byte) ([]byte, error) {
+	sc := this.newContract(0, common.ADDRESS_EMPTY)
+	sc.PreExec = true
+	service, err := sc.NewNativeService()
+	assert.Nil(this.t, err)
+	service.InvokeParam = sstates.ContractInvokeParam{Address: addr, Method: method, Args: args}
+	result, err := service.Invoke()
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+//setupStakingEnv puts the same peers into view 0 and 1, peers are less than K of vbft config
+func setupStakingEnv(t *testing.T) *nativeEnv {
+	env := setupProposalEnv(t)
+	env.putPeerPool(0)
+	bf := new(bytes.Buffer)
+	assert.Nil(t, (&governance.Configuration{N: 7, C: 2, K: 7, L: 112}).Serialize(bf))
+	env.db.Put(utils.ConcatKey(utils.GovernanceContractAddress, []byte(governance.VBFT_CONFIG)),
+		cstates.GenRawStorageItem(bf.Bytes()))
+	env.setBalance(utils.OxgContractAddress, utils.GovernanceContractAddress, 1000)
+	return env
+}
+
+func TestGetPeerPoolInfo(t *testing.T) {
+	env := setupStakingEnv(t)
+
+	_, _, err := env.call(0, stakerA, utils.GovernanceContractAddress, governance.GET_PEER_POOL_INFO, nil)
+	assert.Error(t, err)
+
+	result, err := env.preExecute(utils.GovernanceContractAddress, governance.GET_PEER_POOL_INFO, nil)
+	assert.Nil(t, err)
+	status := new(governance.PeerPoolStatus)
+	assert.Nil(t, framework.Decode(result, status))
+	assert.Equal(t, uint32(1), status.View)
+	assert.Equal(t, uint64(1000), status.Income)
+	assert.Equal(t, 3, len(status.Peers))
+	for i, pubkey := range []string{"02a1", "02b1", "02c1"} {
+		assert.Equal(t, pubkey, status.Peers[i].PeerPubkey)
+		//not split while peers are less than K
+		assert.Equal(t, uint64(0), status.Peers[i].NextSplitFee)
+	}
+}
+
+func TestGetStakeInfo(t *testing.T) {
+	env := setupStakingEnv(t)
+	contract := utils.GovernanceContractAddress
+	bf := new(bytes.Buffer)
+	assert.Nil(t, (&governance.TotalStake{Address: stakerB, Stake: 30}).Serialize(bf))
+	env.db.Put(utils.ConcatKey(contract, []byte(governance.TOTAL_STAKE), stakerB[:]), cstates.GenRawStorageItem(bf.Bytes()))
+	bf = new(bytes.Buffer)
+	assert.Nil(t, (&governance.SplitFeeAddress{Address: stakerB, Amount: 5}).Serialize(bf))
+	env.db.Put(utils.ConcatKey(contract, []byte(governance.SPLIT_FEE_ADDRESS), stakerB[:]),
+		cstates.GenRawStorageItem(bf.Bytes()))
+
+	bf = new(bytes.Buffer)
+	assert.Nil(t, (&governance.StakeInfoParam{Address: stakerB}).Serialize(bf))
+
+	_, _, err := env.call(0, stakerB, utils.GovernanceContractAddress, governance.GET_STAKE_INFO, bf.Bytes())
+	assert.Error(t, err)
+
+	result, err := env.preExecute(utils.GovernanceContractAddress, governance.GET_STAKE_INFO, bf.Bytes())
+	assert.Nil(t, err)
+	info := new(governance.StakeInfo)
+	assert.Nil(t, framework.Decode(result, info))
+	assert.Equal(t, stakerB, info.Address)
+	assert.Equal(t, uint64(30), info.TotalStake)
+	assert.Equal(t, uint64(5), info.SplitFee)
+	assert.Equal(t, 1, len(info.Positions))
+	assert.Equal(t, "02b1", info.Positions[0].PeerPubkey)
+	assert.Equal(t, uint64(30), info.Positions[0].InitPos)
+	assert.Equal(t, uint64(0), info.NextReward)
+}
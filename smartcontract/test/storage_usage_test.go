@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+//storagePutCode puts one byte value at one byte key
+func storagePutCode(key, value byte) []byte {
+	code := []byte{byte(vm.PUSHBYTES1), value, byte(vm.PUSHBYTES1), key}
+	code = append(code, syscall(neovm.STORAGE_GETCONTEXT_NAME)...)
+	code = append(code, syscall(neovm.STORAGE_PUT_NAME)...)
+	return append(code, byte(vm.PUSH1), byte(vm.RET))
+}
+
+func TestStorageUsageGasOverflow(t *testing.T) {
+	env := newNativeEnv(t)
+	contract := env.deploy(storagePutCode(1, 2))
+
+	//2 bytes at this price wrap to 0 gas
+	sc := env.newContract(0, common.ADDRESS_EMPTY)
+	sc.Config.GasTable = neovm.GasTable{neovm.STORAGE_BYTE_NAME: 1 << 63}
+	_, err := sc.AppCall(contract, "main", nil)
+	assert.Error(t, err)
+	usage, err := env.db.GetStorageUsage(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), usage)
+}
+
+func TestStorageUsageActivationHeight(t *testing.T) {
+	features := config.DefConfig.Genesis.Features
+	config.DefConfig.Genesis.Features = &config.FeatureHeights{StorageUsage: 2}
+	defer func() { config.DefConfig.Genesis.Features = features }()
+
+	env := newNativeEnv(t)
+	contract := env.deploy(storagePutCode(1, 2))
+	_, err := env.newContract(0, common.ADDRESS_EMPTY).AppCall(contract, "main", nil)
+	assert.Nil(t, err)
+	usage, err := env.db.GetStorageUsage(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), usage)
+
+	env.height = 2
+	other := env.deploy(storagePutCode(2, 3))
+	_, err = env.newContract(0, common.ADDRESS_EMPTY).AppCall(other, "main", nil)
+	assert.Nil(t, err)
+	usage, err = env.db.GetStorageUsage(other)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), usage)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/onx"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+)
+
+func (this *nativeEnv) transferMulti(asset, signer common.Address, states []onx.MemoState) ([]*event.NotifyEventInfo, error) {
+	sink := common.NewZeroCopySink(nil)
+	(&onx.MultiTransfers{States: states}).Serialization(sink)
+	_, notifies, err := this.call(0, signer, asset, onx.TRANSFER_MULTI_NAME, sink.Bytes())
+	return notifies, err
+}
+
+func TestTransferMulti(t *testing.T) {
+	from, to1, to2 := common.Address{0x01}, common.Address{0x02}, common.Address{0x03}
+	for _, asset := range []common.Address{utils.OnxContractAddress, utils.OxgContractAddress} {
+		env := newNativeEnv(t)
+		env.setBalance(asset, from, 1000)
+
+		notifies, err := env.transferMulti(asset, from, []onx.MemoState{
+			{From: from, To: to1, Value: 100, Memo: "INV-001"},
+			{From: from, To: to2, Value: 200},
+			{From: from, To: to1, Value: 0, Memo: "skipped"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(700), env.balanceOf(asset, from))
+		assert.Equal(t, uint64(100), env.balanceOf(asset, to1))
+		assert.Equal(t, uint64(200), env.balanceOf(asset, to2))
+
+		var events []interface{}
+		for _, notify := range notifies {
+			if notify.ContractAddress == asset {
+				events = append(events, notify.States)
+			}
+		}
+		assert.Equal(t, []interface{}{
+			[]interface{}{onx.TRANSFER_NAME, from.ToBase58(), to1.ToBase58(), uint64(100), "INV-001"},
+			[]interface{}{onx.TRANSFER_NAME, from.ToBase58(), to2.ToBase58(), uint64(200), ""},
+		}, events)
+
+		//transfer not signed by from, over balance and with invalid memo
+		_, err = env.transferMulti(asset, to1, []onx.MemoState{{From: from, To: to1, Value: 1}})
+		assert.Error(t, err)
+		_, err = env.transferMulti(asset, from, []onx.MemoState{{From: from, To: to1, Value: 701}})
+		assert.Error(t, err)
+		_, err = env.transferMulti(asset, from, []onx.MemoState{{From: from, To: to1, Value: 1,
+			Memo: string([]byte{0xff})}})
+		assert.Error(t, err)
+	}
+}
+
+func TestTransferMultiMaxCount(t *testing.T) {
+	from, to := common.Address{0x01}, common.Address{0x02}
+	for _, asset := range []common.Address{utils.OnxContractAddress, utils.OxgContractAddress} {
+		env := newNativeEnv(t)
+		env.setBalance(asset, from, 1000)
+
+		states := make([]onx.MemoState, onx.MAX_MULTI_TRANSFERS+1)
+		for i := range states {
+			states[i] = onx.MemoState{From: from, To: to, Value: 1}
+		}
+		_, err := env.transferMulti(asset, from, states)
+		assert.Error(t, err)
+		assert.Equal(t, uint64(1000), env.balanceOf(asset, from))
+
+		_, err = env.transferMulti(asset, from, states[:onx.MAX_MULTI_TRANSFERS])
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(1000-onx.MAX_MULTI_TRANSFERS), env.balanceOf(asset, from))
+		assert.Equal(t, uint64(onx.MAX_MULTI_TRANSFERS), env.balanceOf(asset, to))
+	}
+}
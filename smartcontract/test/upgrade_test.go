@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/common/config"
+	"github.com/OnyxPay/OnyxChain-legacy/core/payload"
+	"github.com/OnyxPay/OnyxChain-legacy/core/states"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/auth"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/upgrade"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/native/utils"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/service/neovm"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+//upgradeEnv holds NeoVM contract of version 1.0 whose admin is set in auth contract
+type upgradeEnv struct {
+	*nativeEnv
+	contract common.Address
+	admin    *onxID
+}
+
+func newUpgradeEnv(t *testing.T) *upgradeEnv {
+	env := newNativeEnv(t)
+	old := &payload.DeployCode{Code: []byte{byte(vm.PUSH0), byte(vm.RET)}, Version: "1.0"}
+	assert.Nil(t, env.db.PutContract(old))
+	contract := old.Address()
+	_, err := upgrade.AddVersion(env.db, contract, &upgrade.ContractVersion{Action: upgrade.ACTION_DEPLOY,
+		Address: contract, CodeHash: contract, Version: old.Version, Height: env.height})
+	assert.Nil(t, err)
+
+	admin := env.registerID()
+	env.db.Put(utils.ConcatKey(utils.AuthContractAddress, contract[:], auth.PreAdmin),
+		states.GenRawStorageItem(admin.id))
+	return &upgradeEnv{nativeEnv: env, contract: contract, admin: admin}
+}
+
+func (this *upgradeEnv) upgrade(signer common.Address, code []byte, version string) ([]*event.NotifyEventInfo, error) {
+	param := &upgrade.UpgradeParam{Contract: this.contract, Code: code, NeedStorage: true, Name: "test",
+		Version: version, KeyNo: 1}
+	bf := new(bytes.Buffer)
+	assert.Nil(this.t, param.Serialize(bf))
+	_, notifies, err := this.call(0, signer, utils.UpgradeContractAddress, upgrade.UPGRADE_CONTRACT, bf.Bytes())
+	return notifies, err
+}
+
+//notifyArgsCode notifies [method, args] it is invoked with
+func notifyArgsCode() []byte {
+	code := []byte{byte(vm.PUSH2), byte(vm.PACK)}
+	code = append(code, syscall(neovm.RUNTIME_NOTIFY_NAME)...)
+	return append(code, byte(vm.PUSH1), byte(vm.RET))
+}
+
+func TestUpgradeContract(t *testing.T) {
+	env := newUpgradeEnv(t)
+	code := notifyArgsCode()
+
+	other := env.registerID()
+	_, err := env.upgrade(other.signer, code, "2.0")
+	assert.Error(t, err)
+
+	notifies, err := env.upgrade(env.admin.signer, code, "2.0")
+	assert.Nil(t, err)
+	var onUpgrade []interface{}
+	for _, notify := range notifies {
+		if notify.ContractAddress == env.contract {
+			onUpgrade = append(onUpgrade, notify.States)
+		}
+	}
+	assert.Equal(t, []interface{}{[]interface{}{common.ToHexString([]byte(upgrade.ON_UPGRADE)),
+		[]interface{}{common.ToHexString([]byte("1.0"))}}}, onUpgrade)
+
+	dep, err := env.db.GetContract(env.contract)
+	assert.Nil(t, err)
+	assert.Equal(t, code, dep.Code)
+	assert.Equal(t, "2.0", dep.Version)
+
+	versions, err := upgrade.GetContractVersions(env.db, env.contract)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(versions))
+	assert.Equal(t, upgrade.ACTION_UPGRADE, versions[1].Action)
+	assert.Equal(t, uint32(1), versions[1].Index)
+	assert.Equal(t, env.contract, versions[1].Address)
+	assert.Equal(t, (&payload.DeployCode{Code: code}).Address(), versions[1].CodeHash)
+	assert.Equal(t, "2.0", versions[1].Version)
+
+	_, err = env.upgrade(env.admin.signer, code, "3.0")
+	assert.Error(t, err, "code not changed")
+}
+
+func TestOnUpgradeOnlyByUpgradeContract(t *testing.T) {
+	env := newUpgradeEnv(t)
+	_, err := env.upgrade(env.admin.signer, notifyArgsCode(), "2.0")
+	assert.Nil(t, err)
+
+	_, err = env.newContract(0, env.admin.signer).AppCall(env.contract, upgrade.ON_UPGRADE,
+		[]interface{}{[]byte("1.0")})
+	assert.Error(t, err)
+
+	version := []byte("1.0")
+	code := append([]byte{byte(len(version))}, version...)
+	code = append(code, byte(vm.PUSH1), byte(vm.PACK), byte(len(upgrade.ON_UPGRADE)))
+	code = append(code, upgrade.ON_UPGRADE...)
+	code = append(code, byte(vm.APPCALL))
+	code = append(code, env.contract[:]...)
+	code = append(code, byte(vm.RET))
+	caller := env.deploy(code)
+	_, err = env.newContract(0, env.admin.signer).AppCall(caller, "main", nil)
+	assert.Error(t, err)
+
+	_, err = env.newContract(0, env.admin.signer).AppCall(env.contract, "main", nil)
+	assert.Nil(t, err)
+}
+
+func TestUpgradeActivationHeight(t *testing.T) {
+	features := config.DefConfig.Genesis.Features
+	config.DefConfig.Genesis.Features = &config.FeatureHeights{ContractUpgrade: 2}
+	defer func() { config.DefConfig.Genesis.Features = features }()
+
+	env := newUpgradeEnv(t)
+	_, err := env.upgrade(env.admin.signer, notifyArgsCode(), "2.0")
+	assert.Error(t, err)
+
+	//onUpgrade is an ordinary method before activation
+	_, err = env.newContract(0, env.admin.signer).AppCall(env.contract, upgrade.ON_UPGRADE,
+		[]interface{}{[]byte("1.0")})
+	assert.Nil(t, err)
+
+	env.height = 2
+	_, err = env.upgrade(env.admin.signer, notifyArgsCode(), "2.0")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/debugger"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+)
+
+//max opcodes recorded for a transaction, gas of the rest is still counted in calls
+const MAX_TRACE_STEPS = 100000
+
+//SysCallKind tells how an interop service is recorded besides its arguments
+type SysCallKind byte
+
+const (
+	SYSCALL_OTHER          SysCallKind = iota
+	SYSCALL_STORAGE_GET                //storage get of key, value is recorded after service returns
+	SYSCALL_STORAGE_PUT                //storage put of key and value
+	SYSCALL_STORAGE_DELETE             //storage delete of key
+	SYSCALL_NATIVE                     //call of native contract by version, address, method and args, recorded as nested call
+)
+
+type sysCall struct {
+	args int
+	kind SysCallKind
+}
+
+//interop services recorded with arguments, registered by vm service which provides them
+var sysCalls = make(map[string]sysCall)
+
+//RegisterSysCall registers interop service, tracer records its top args stack items as arguments.
+//It is not safe for concurrent use and must be called on init of vm service.
+func RegisterSysCall(name string, args int, kind SysCallKind) {
+	sysCalls[name] = sysCall{args: args, kind: kind}
+}
+
+type frame struct {
+	call    *CallTrace
+	engine  *vm.ExecutionEngine //nil for native call
+	gas     uint64              //gas left when call started
+	lastGas uint64              //gas left after last opcode
+	step    *StepTrace          //opcode being executed
+	get     *StorageTrace       //storage get waiting for result
+}
+
+//Tracer implements vm.DebugHook and records calls, opcodes, syscalls and storage access of a transaction.
+//Gas of APPCALL and SYSCALL steps includes gas used by the called contract.
+type Tracer struct {
+	GasLeft   func() uint64 //returns gas left of the transaction
+	calls     []*CallTrace
+	frames    []*frame
+	steps     int
+	truncated bool
+	err       error
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{
+		GasLeft: func() uint64 { return 0 },
+	}
+}
+
+func (this *Tracer) BeforeOp(engine *vm.ExecutionEngine, offset int) error {
+	f := this.top()
+	if f == nil || f.engine != engine {
+		return nil
+	}
+	if this.steps >= MAX_TRACE_STEPS {
+		this.truncated = true
+		return nil
+	}
+	this.steps++
+	f.step = &StepTrace{
+		Offset: offset,
+		Op:     debugger.OpName(engine.OpCode),
+		Depth:  len(engine.Contexts),
+	}
+	f.call.Steps = append(f.call.Steps, f.step)
+	return nil
+}
+
+func (this *Tracer) AfterOp(engine *vm.ExecutionEngine, offset int) error {
+	//native calls invoked by the opcode are returned
+	for n := len(this.frames); n > 1 && this.frames[n-1].engine == nil && this.frames[n-2].engine == engine; n-- {
+		this.finishFrame()
+	}
+	f := this.top()
+	if f == nil || f.engine != engine {
+		return nil
+	}
+	gas := this.GasLeft()
+	if f.step != nil {
+		f.step.Gas = f.lastGas - gas
+		f.step = nil
+	}
+	f.lastGas = gas
+	if f.get != nil {
+		if engine.EvaluationStack.Count() > 0 {
+			f.get.Value = itemHex(engine.EvaluationStack.Peek(0))
+		}
+		f.get = nil
+	}
+	return nil
+}
+
+func (this *Tracer) SysCall(engine *vm.ExecutionEngine, name string) error {
+	f := this.top()
+	if f == nil || f.engine != engine {
+		return nil
+	}
+	offset := 0
+	if f.step != nil {
+		offset = f.step.Offset
+	}
+	stack := engine.EvaluationStack
+	service := sysCalls[name]
+	sysCall := &SysCallTrace{Offset: offset, Name: name}
+	for i := 0; i < service.args && i < stack.Count(); i++ {
+		sysCall.Args = append(sysCall.Args, debugger.FormatStackItem(stack.Peek(i)))
+	}
+	f.call.SysCalls = append(f.call.SysCalls, sysCall)
+
+	switch service.kind {
+	case SYSCALL_STORAGE_GET, SYSCALL_STORAGE_DELETE, SYSCALL_STORAGE_PUT:
+		if stack.Count() < 2 {
+			return nil
+		}
+		storage := &StorageTrace{Offset: offset, Key: itemHex(stack.Peek(1))}
+		switch service.kind {
+		case SYSCALL_STORAGE_GET:
+			storage.Op = STORAGE_GET
+			f.get = storage
+		case SYSCALL_STORAGE_DELETE:
+			storage.Op = STORAGE_DELETE
+		case SYSCALL_STORAGE_PUT:
+			storage.Op = STORAGE_PUT
+			if stack.Count() > 2 {
+				storage.Value = itemHex(stack.Peek(2))
+			}
+		}
+		f.call.Storage = append(f.call.Storage, storage)
+	case SYSCALL_NATIVE:
+		if stack.Count() < 3 {
+			return nil
+		}
+		call := &CallTrace{Type: CALL_NATIVE}
+		if address, err := stack.Peek(1).GetByteArray(); err == nil {
+			if addr, err := common.AddressParseFromBytes(address); err == nil {
+				call.Contract = addr.ToHexString()
+			} else {
+				call.Contract = hex.EncodeToString(address)
+			}
+		}
+		if method, err := stack.Peek(2).GetByteArray(); err == nil {
+			call.Method = string(method)
+		}
+		this.pushFrame(call, nil)
+	}
+	return nil
+}
+
+func (this *Tracer) EnterContract(engine *vm.ExecutionEngine, contract common.Address) {
+	this.pushFrame(&CallTrace{Type: CALL_NEOVM, Contract: contract.ToHexString()}, engine)
+}
+
+func (this *Tracer) PushContext(engine *vm.ExecutionEngine, context *vm.ExecutionContext) {
+}
+
+func (this *Tracer) PopContext(engine *vm.ExecutionEngine, context *vm.ExecutionContext) {
+	if len(engine.Contexts) != 0 {
+		return
+	}
+	if f := this.top(); f != nil && f.engine == engine {
+		this.finishFrame()
+	}
+}
+
+//Finish records error which aborted execution to the innermost call, and closes calls left by it
+func (this *Tracer) Finish(err error) {
+	this.err = err
+	if f := this.top(); f != nil && err != nil {
+		f.call.Error = err.Error()
+	}
+	for len(this.frames) > 0 {
+		this.finishFrame()
+	}
+}
+
+//TxTrace returns trace of transaction with execution result in notify
+func (this *Tracer) TxTrace(txHash common.Uint256, height uint32, notify *event.ExecuteNotify) *TxTrace {
+	trace := &TxTrace{
+		TxHash:    txHash.ToHexString(),
+		Height:    height,
+		Truncated: this.truncated,
+		Calls:     this.calls,
+	}
+	if notify != nil {
+		trace.State = notify.State
+		trace.GasConsumed = notify.GasConsumed
+	}
+	if this.err != nil {
+		trace.Error = this.err.Error()
+	}
+	return trace
+}
+
+func (this *Tracer) top() *frame {
+	if len(this.frames) == 0 {
+		return nil
+	}
+	return this.frames[len(this.frames)-1]
+}
+
+func (this *Tracer) pushFrame(call *CallTrace, engine *vm.ExecutionEngine) {
+	if f := this.top(); f != nil {
+		f.call.Calls = append(f.call.Calls, call)
+	} else {
+		this.calls = append(this.calls, call)
+	}
+	gas := this.GasLeft()
+	this.frames = append(this.frames, &frame{call: call, engine: engine, gas: gas, lastGas: gas})
+}
+
+func (this *Tracer) finishFrame() {
+	f := this.top()
+	gas := this.GasLeft()
+	if f.step != nil {
+		f.step.Gas = f.lastGas - gas
+	}
+	f.call.GasUsed = f.gas - gas
+	this.frames = this.frames[:len(this.frames)-1]
+}
+
+func itemHex(item types.StackItems) string {
+	data, err := item.GetByteArray()
+	if err != nil {
+		return debugger.FormatStackItem(item)
+	}
+	return hex.EncodeToString(data)
+}
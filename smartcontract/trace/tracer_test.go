@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	"github.com/OnyxPay/OnyxChain-legacy/smartcontract/event"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+//execute runs code of contract the same way as NeoVmService.Invoke drives the engine, each opcode costs 1 gas
+func execute(engine *vm.ExecutionEngine, contract common.Address, code []byte, gas *uint64) error {
+	engine.EnterContract(contract)
+	engine.PushContext(vm.NewExecutionContext(engine, code))
+	for len(engine.Contexts) != 0 && engine.Context.GetInstructionPointer() < len(engine.Context.Code) {
+		if err := engine.ExecuteCode(); err != nil {
+			return err
+		}
+		offset := engine.Context.GetInstructionPointer() - 1
+		if err := engine.ValidateOp(); err != nil {
+			return err
+		}
+		*gas -= 1
+		if err := engine.BeforeOp(offset); err != nil {
+			return err
+		}
+		if err := engine.StepInto(); err != nil {
+			return err
+		}
+		if err := engine.AfterOp(offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTracerSteps(t *testing.T) {
+	gas := uint64(100)
+	tracer := NewTracer()
+	tracer.GasLeft = func() uint64 { return gas }
+	engine := vm.NewExecutionEngine()
+	engine.Hook = tracer
+
+	//contract upgraded in place, whose address is not hash of the code
+	contract := common.Address{0xcc}
+	code := []byte{byte(vm.PUSH1), byte(vm.PUSH2), byte(vm.ADD), byte(vm.RET)}
+	assert.Nil(t, execute(engine, contract, code, &gas))
+	tracer.Finish(nil)
+
+	txTrace := tracer.TxTrace(common.UINT256_EMPTY, 10, &event.ExecuteNotify{State: event.CONTRACT_STATE_SUCCESS, GasConsumed: 4})
+	assert.Equal(t, uint32(10), txTrace.Height)
+	assert.Equal(t, "", txTrace.Error)
+	assert.Equal(t, 1, len(txTrace.Calls))
+	call := txTrace.Calls[0]
+	assert.Equal(t, CALL_NEOVM, call.Type)
+	assert.Equal(t, contract.ToHexString(), call.Contract)
+	assert.Equal(t, uint64(4), call.GasUsed)
+	ops := []string{"PUSH1", "PUSH2", "ADD", "RET"}
+	assert.Equal(t, len(ops), len(call.Steps))
+	for i, step := range call.Steps {
+		assert.Equal(t, i, step.Offset)
+		assert.Equal(t, ops[i], step.Op)
+		assert.Equal(t, uint64(1), step.Gas)
+	}
+}
+
+func TestTracerSysCall(t *testing.T) {
+	//registered by neovm service in node
+	RegisterSysCall("System.Storage.Put", 3, SYSCALL_STORAGE_PUT)
+	RegisterSysCall("OnyxChain.Native.Call", 4, SYSCALL_NATIVE)
+	gas := uint64(100)
+	tracer := NewTracer()
+	tracer.GasLeft = func() uint64 { return gas }
+	engine := vm.NewExecutionEngine()
+	engine.Hook = tracer
+	engine.EnterContract(common.Address{0xcc})
+	engine.PushContext(vm.NewExecutionContext(engine, []byte{byte(vm.RET)}))
+
+	//storage put of key 0x01 value 0x02
+	engine.EvaluationStack.Push(types.NewByteArray([]byte{2}))
+	engine.EvaluationStack.Push(types.NewByteArray([]byte{1}))
+	engine.EvaluationStack.Push(types.NewByteArray(nil))
+	engine.OpCode = vm.SYSCALL
+	assert.Nil(t, engine.BeforeOp(0))
+	assert.Nil(t, engine.SysCall("System.Storage.Put"))
+	assert.Nil(t, engine.AfterOp(0))
+
+	//native call which uses 5 gas
+	addr := common.AddressFromVmCode([]byte{1})
+	engine.EvaluationStack.Push(types.NewArray(nil))
+	engine.EvaluationStack.Push(types.NewByteArray([]byte("transfer")))
+	engine.EvaluationStack.Push(types.NewByteArray(addr[:]))
+	engine.EvaluationStack.Push(types.NewInteger(big.NewInt(0)))
+	assert.Nil(t, engine.BeforeOp(5))
+	assert.Nil(t, engine.SysCall("OnyxChain.Native.Call"))
+	gas -= 5
+	assert.Nil(t, engine.AfterOp(5))
+
+	tracer.Finish(errors.New("aborted"))
+	txTrace := tracer.TxTrace(common.UINT256_EMPTY, 0, nil)
+	assert.Equal(t, "aborted", txTrace.Error)
+	call := txTrace.Calls[0]
+	assert.Equal(t, "aborted", call.Error)
+	assert.Equal(t, 2, len(call.SysCalls))
+	assert.Equal(t, 3, len(call.SysCalls[0].Args))
+	assert.Equal(t, &StorageTrace{Offset: 0, Op: STORAGE_PUT, Key: "01", Value: "02"}, call.Storage[0])
+	assert.Equal(t, uint64(5), call.Steps[1].Gas)
+
+	assert.Equal(t, 1, len(call.Calls))
+	native := call.Calls[0]
+	assert.Equal(t, CALL_NATIVE, native.Type)
+	assert.Equal(t, addr.ToHexString(), native.Contract)
+	assert.Equal(t, "transfer", native.Method)
+	assert.Equal(t, uint64(5), native.GasUsed)
+}
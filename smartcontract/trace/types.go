@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package trace records structured execution traces of transactions
+package trace
+
+//type of call trace
+const (
+	CALL_NEOVM  = "neovm"
+	CALL_NATIVE = "native"
+)
+
+//storage operations of storage trace
+const (
+	STORAGE_GET    = "get"
+	STORAGE_PUT    = "put"
+	STORAGE_DELETE = "delete"
+)
+
+//TxTrace is execution trace of a transaction
+type TxTrace struct {
+	TxHash      string
+	Height      uint32
+	State       byte
+	GasConsumed uint64
+	Error       string `json:",omitempty"`
+	Truncated   bool   `json:",omitempty"` //steps over MAX_TRACE_STEPS are not recorded
+	Calls       []*CallTrace
+}
+
+//CallTrace is execution trace of a neovm or native contract call
+type CallTrace struct {
+	Type     string
+	Contract string
+	Method   string `json:",omitempty"`
+	GasUsed  uint64
+	Steps    []*StepTrace    `json:",omitempty"`
+	SysCalls []*SysCallTrace `json:",omitempty"`
+	Storage  []*StorageTrace `json:",omitempty"`
+	Calls    []*CallTrace    `json:",omitempty"`
+	Error    string          `json:",omitempty"`
+}
+
+//StepTrace is an executed opcode and the gas it used
+type StepTrace struct {
+	Offset int
+	Op     string
+	Gas    uint64
+	Depth  int //depth of CALL in the contract
+}
+
+//SysCallTrace is an interop service call with its arguments from top of stack
+type SysCallTrace struct {
+	Offset int
+	Name   string
+	Args   []string `json:",omitempty"`
+}
+
+//StorageTrace is a storage read or write, key and value are hex encoded
+type StorageTrace struct {
+	Offset int
+	Op     string
+	Key    string
+	Value  string `json:",omitempty"`
+}
@@ -88,6 +88,25 @@ const (
 	MaxStats
 )
 
+func (v TxnStatsType) String() string {
+	switch v {
+	case RcvStats:
+		return "received"
+	case SuccessStats:
+		return "success"
+	case FailureStats:
+		return "failure"
+	case DuplicateStats:
+		return "duplicate"
+	case SigErrStats:
+		return "sig_error"
+	case StateErrStats:
+		return "state_error"
+	default:
+		return "unknown"
+	}
+}
+
 // CheckBlkResult contains a verifed tx list,
 // an unverified tx list and an old tx list
 // to be re-verifed
@@ -28,6 +28,7 @@ import (
 	"github.com/OnyxPay/OnyxChain-legacy/common"
 	"github.com/OnyxPay/OnyxChain-legacy/common/config"
 	"github.com/OnyxPay/OnyxChain-legacy/common/log"
+	"github.com/OnyxPay/OnyxChain-legacy/common/metrics"
 	"github.com/OnyxPay/OnyxChain-legacy/core/ledger"
 	tx "github.com/OnyxPay/OnyxChain-legacy/core/types"
 	"github.com/OnyxPay/OnyxChain-legacy/errors"
@@ -279,6 +280,7 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 	}
 
 	delete(s.allPendingTxs, hash)
+	metrics.TxPoolPending.Set(float64(len(s.allPendingTxs)))
 
 	if len(s.allPendingTxs) < tc.MAX_LIMITATION {
 		select {
@@ -315,6 +317,7 @@ func (s *TXPoolServer) setPendingTx(tx *tx.Transaction,
 	}
 
 	s.allPendingTxs[tx.Hash()] = pt
+	metrics.TxPoolPending.Set(float64(len(s.allPendingTxs)))
 	return true
 }
 
@@ -528,6 +531,7 @@ func (s *TXPoolServer) getPendingTxs(byCount bool) []*tx.Transaction {
 // cleanTransactionList cleans the txs in the block from the ledger
 func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32) {
 	s.txPool.CleanTransactionList(txs)
+	s.updatePoolSize()
 
 	// Check whether to update the gas price and remove txs below the
 	// threshold
@@ -544,6 +548,7 @@ func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32
 
 		if oldGasPrice < gasPrice {
 			s.txPool.RemoveTxsBelowGasPrice(gasPrice)
+			s.updatePoolSize()
 		}
 	}
 	// Cleanup tx pool
@@ -562,6 +567,7 @@ func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32
 // delTransaction deletes a transaction in the tx pool.
 func (s *TXPoolServer) delTransaction(t *tx.Transaction) {
 	s.txPool.DelTxList(t)
+	s.updatePoolSize()
 }
 
 // addTxList adds a valid transaction to the tx pool.
@@ -570,14 +576,21 @@ func (s *TXPoolServer) addTxList(txEntry *tc.TXEntry) bool {
 	if !ret {
 		s.increaseStats(tc.DuplicateStats)
 	}
+	s.updatePoolSize()
 	return ret
 }
 
+// updatePoolSize exports the tx count of the transaction pool to metrics
+func (s *TXPoolServer) updatePoolSize() {
+	metrics.TxPoolSize.Set(float64(s.txPool.GetTransactionCount()))
+}
+
 // increaseStats increases the count with the stats type
 func (s *TXPoolServer) increaseStats(v tc.TxnStatsType) {
 	s.stats.Lock()
 	defer s.stats.Unlock()
 	s.stats.count[v-1]++
+	metrics.TxPoolVerify.Inc(v.String())
 }
 
 // getStats returns the transaction statistics
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package neovm
+
+import (
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+)
+
+//DebugHook observes execution of engine. Returning error from BeforeOp, AfterOp or SysCall aborts the execution.
+type DebugHook interface {
+	//BeforeOp is called after opcode at offset is read and before it is executed
+	BeforeOp(engine *ExecutionEngine, offset int) error
+	//AfterOp is called after opcode at offset is executed
+	AfterOp(engine *ExecutionEngine, offset int) error
+	//SysCall is called before the interop service is invoked
+	SysCall(engine *ExecutionEngine, name string) error
+	//EnterContract is called before the first context of contract is pushed, contract is the address the
+	//contract is invoked by, which differs from hash of the code after in-place upgrade
+	EnterContract(engine *ExecutionEngine, contract common.Address)
+	//PushContext is called after context is pushed, by CALL or a new contract
+	PushContext(engine *ExecutionEngine, context *ExecutionContext)
+	//PopContext is called after context is popped
+	PopContext(engine *ExecutionEngine, context *ExecutionContext)
+}
+
+//EnterContract notifies hook that engine is going to execute code of contract
+func (this *ExecutionEngine) EnterContract(contract common.Address) {
+	if this.Hook != nil {
+		this.Hook.EnterContract(this, contract)
+	}
+}
+
+//BeforeOp notifies hook that opcode at offset is going to be executed
+func (this *ExecutionEngine) BeforeOp(offset int) error {
+	if this.Hook == nil {
+		return nil
+	}
+	return this.Hook.BeforeOp(this, offset)
+}
+
+//AfterOp notifies hook that opcode at offset is executed
+func (this *ExecutionEngine) AfterOp(offset int) error {
+	if this.Hook == nil {
+		return nil
+	}
+	return this.Hook.AfterOp(this, offset)
+}
+
+//SysCall notifies hook that interop service is going to be invoked
+func (this *ExecutionEngine) SysCall(name string) error {
+	if this.Hook == nil {
+		return nil
+	}
+	return this.Hook.SysCall(this, name)
+}
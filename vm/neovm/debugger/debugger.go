@@ -0,0 +1,317 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package debugger implements an interactive neovm debugger on top of vm.DebugHook
+package debugger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+)
+
+//ErrQuit is returned by hook when user quits debugger, which aborts execution
+var ErrQuit = errors.New("debugger quit")
+
+const (
+	MODE_CONTINUE = iota //run until breakpoint
+	MODE_STEP            //pause at every instruction, entering CALL and APPCALL
+	MODE_NEXT            //pause at next instruction of the same or outer context
+)
+
+//Breakpoint pauses execution at offset of contract. Empty contract matches all contracts.
+type Breakpoint struct {
+	Contract common.Address
+	Offset   int
+}
+
+//ParseBreakpoint parses breakpoint of format <offset> or <contract address>:<offset>
+func ParseBreakpoint(str string) (*Breakpoint, error) {
+	bp := &Breakpoint{}
+	offsetStr := str
+	index := strings.LastIndex(str, ":")
+	if index >= 0 {
+		addrStr := str[:index]
+		offsetStr = str[index+1:]
+		var err error
+		if len(addrStr) == common.ADDR_LEN*2 {
+			bp.Contract, err = common.AddressFromHexString(addrStr)
+		} else {
+			bp.Contract, err = common.AddressFromBase58(addrStr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract address:%s", addrStr)
+		}
+	}
+	offset, err := strconv.ParseUint(offsetStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid offset:%s", offsetStr)
+	}
+	bp.Offset = int(offset)
+	return bp, nil
+}
+
+func (this *Breakpoint) String() string {
+	if this.Contract == common.ADDRESS_EMPTY {
+		return strconv.Itoa(this.Offset)
+	}
+	return fmt.Sprintf("%s:%d", this.Contract.ToHexString(), this.Offset)
+}
+
+func (this *Breakpoint) match(contract common.Address, offset int) bool {
+	return this.Offset == offset && (this.Contract == common.ADDRESS_EMPTY || this.Contract == contract)
+}
+
+//Debugger pauses execution at breakpoints and reads commands from input
+type Debugger struct {
+	in          *bufio.Reader
+	out         io.Writer
+	mode        int
+	lastCmd     string
+	breakpoints []*Breakpoint
+	syscalls    map[string]bool
+	depth       int //depth of contexts over all engines
+	nextDepth   int
+	contracts   map[*vm.ExecutionEngine]common.Address
+}
+
+//NewDebugger returns debugger which pauses at the first instruction
+func NewDebugger(in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{
+		in:        bufio.NewReader(in),
+		out:       out,
+		mode:      MODE_STEP,
+		lastCmd:   "s",
+		syscalls:  make(map[string]bool),
+		contracts: make(map[*vm.ExecutionEngine]common.Address),
+	}
+}
+
+//SetMode sets how debugger pauses, e.g. MODE_CONTINUE to run until the first breakpoint
+func (this *Debugger) SetMode(mode int) {
+	this.mode = mode
+}
+
+func (this *Debugger) AddBreakpoint(bp *Breakpoint) {
+	for _, b := range this.breakpoints {
+		if *b == *bp {
+			return
+		}
+	}
+	this.breakpoints = append(this.breakpoints, bp)
+}
+
+func (this *Debugger) RemoveBreakpoint(bp *Breakpoint) bool {
+	for i, b := range this.breakpoints {
+		if *b == *bp {
+			this.breakpoints = append(this.breakpoints[:i], this.breakpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//BreakOnSysCall pauses execution before interop service of name is invoked
+func (this *Debugger) BreakOnSysCall(name string) {
+	this.syscalls[name] = true
+}
+
+func (this *Debugger) BeforeOp(engine *vm.ExecutionEngine, offset int) error {
+	contract := this.contracts[engine]
+	pause := false
+	switch this.mode {
+	case MODE_STEP:
+		pause = true
+	case MODE_NEXT:
+		pause = this.depth <= this.nextDepth
+	}
+	for _, bp := range this.breakpoints {
+		if bp.match(contract, offset) {
+			if !pause {
+				this.printf("Breakpoint %s hit", bp)
+			}
+			pause = true
+			break
+		}
+	}
+	if !pause {
+		return nil
+	}
+	this.printf("[%d] %s %04d: %s", this.depth, contract.ToHexString(), offset, OpName(engine.OpCode))
+	return this.prompt(engine)
+}
+
+func (this *Debugger) AfterOp(engine *vm.ExecutionEngine, offset int) error {
+	if this.mode == MODE_STEP && engine.EvaluationStack.Count() > 0 {
+		this.printf("  => %s", FormatStackItem(engine.EvaluationStack.Peek(0)))
+	}
+	return nil
+}
+
+func (this *Debugger) SysCall(engine *vm.ExecutionEngine, name string) error {
+	if !this.syscalls[name] {
+		if this.mode == MODE_STEP {
+			this.printf("  syscall %s", name)
+		}
+		return nil
+	}
+	this.printf("SysCall %s hit", name)
+	return this.prompt(engine)
+}
+
+func (this *Debugger) EnterContract(engine *vm.ExecutionEngine, contract common.Address) {
+	this.contracts[engine] = contract
+	if this.mode != MODE_CONTINUE {
+		this.printf("Enter contract %s", contract.ToHexString())
+	}
+}
+
+func (this *Debugger) PushContext(engine *vm.ExecutionEngine, context *vm.ExecutionContext) {
+	this.depth++
+}
+
+func (this *Debugger) PopContext(engine *vm.ExecutionEngine, context *vm.ExecutionContext) {
+	this.depth--
+	if len(engine.Contexts) == 0 {
+		if this.mode != MODE_CONTINUE {
+			contract := this.contracts[engine]
+			this.printf("Leave contract %s", contract.ToHexString())
+		}
+		delete(this.contracts, engine)
+	}
+}
+
+func (this *Debugger) prompt(engine *vm.ExecutionEngine) error {
+	for {
+		fmt.Fprint(this.out, "(debug) ")
+		line, err := this.in.ReadString('\n')
+		if err != nil && line == "" {
+			//no more input, run to the end
+			this.mode = MODE_CONTINUE
+			this.breakpoints = nil
+			this.syscalls = make(map[string]bool)
+			return nil
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			line = this.lastCmd
+		}
+		args := strings.Fields(line)
+		switch args[0] {
+		case "s", "step":
+			this.mode = MODE_STEP
+			this.lastCmd = args[0]
+			return nil
+		case "n", "next":
+			this.mode = MODE_NEXT
+			this.nextDepth = this.depth
+			this.lastCmd = args[0]
+			return nil
+		case "c", "continue":
+			this.mode = MODE_CONTINUE
+			this.lastCmd = args[0]
+			return nil
+		case "b", "break", "d", "delete":
+			if len(args) < 2 {
+				this.printf("Missing breakpoint argument")
+				continue
+			}
+			bp, err := ParseBreakpoint(args[1])
+			if err != nil {
+				//not an offset, take as syscall name
+				if args[0] == "b" || args[0] == "break" {
+					this.syscalls[args[1]] = true
+				} else {
+					delete(this.syscalls, args[1])
+				}
+				continue
+			}
+			if args[0] == "b" || args[0] == "break" {
+				this.AddBreakpoint(bp)
+			} else if !this.RemoveBreakpoint(bp) {
+				this.printf("Breakpoint %s not found", bp)
+			}
+		case "l", "list":
+			for _, bp := range this.breakpoints {
+				this.printf("  %s", bp)
+			}
+			for name := range this.syscalls {
+				this.printf("  %s", name)
+			}
+		case "st", "stack":
+			this.printStack("Evaluation stack", engine.EvaluationStack)
+		case "alt":
+			this.printStack("Alt stack", engine.AltStack)
+		case "bt", "backtrace":
+			contract := this.contracts[engine]
+			this.printf("Contract %s, depth %d", contract.ToHexString(), this.depth)
+			for i := len(engine.Contexts) - 1; i >= 0; i-- {
+				this.printf("  #%d offset %d", i, engine.Contexts[i].GetInstructionPointer())
+			}
+		case "q", "quit":
+			return ErrQuit
+		case "h", "help":
+			this.printHelp()
+		default:
+			this.printf("Unknown command %s, type h for help", args[0])
+		}
+	}
+}
+
+func (this *Debugger) printStack(name string, stack *vm.RandomAccessStack) {
+	this.printf("%s (%d items):", name, stack.Count())
+	for _, line := range FormatStack(stack) {
+		this.printf("  %s", line)
+	}
+}
+
+func (this *Debugger) printHelp() {
+	this.printf("Commands:")
+	this.printf("  s, step                      Execute next instruction, entering CALL and APPCALL")
+	this.printf("  n, next                      Execute next instruction, stepping over CALL and APPCALL")
+	this.printf("  c, continue                  Run until next breakpoint")
+	this.printf("  b, break <[address:]offset>  Add breakpoint at offset, or before syscall of name")
+	this.printf("  d, delete <[address:]offset> Delete breakpoint")
+	this.printf("  l, list                      List breakpoints")
+	this.printf("  st, stack                    Print evaluation stack")
+	this.printf("  alt                          Print alt stack")
+	this.printf("  bt, backtrace                Print contexts of current contract")
+	this.printf("  q, quit                      Abort execution")
+	this.printf("Empty line repeats last step, next or continue command.")
+}
+
+func (this *Debugger) printf(format string, a ...interface{}) {
+	fmt.Fprintf(this.out, format+"\n", a...)
+}
+
+//OpName returns mnemonic of opcode
+func OpName(code vm.OpCode) string {
+	if code >= vm.PUSHBYTES1 && code <= vm.PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", code)
+	}
+	if name := vm.OpExecList[code].Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(code))
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package debugger
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/OnyxPay/OnyxChain-legacy/common"
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBreakpoint(t *testing.T) {
+	bp, err := ParseBreakpoint("12")
+	assert.Nil(t, err)
+	assert.Equal(t, &Breakpoint{Offset: 12}, bp)
+	assert.Equal(t, "12", bp.String())
+
+	addr := common.AddressFromVmCode([]byte{1, 2, 3})
+	bp, err = ParseBreakpoint(addr.ToHexString() + ":7")
+	assert.Nil(t, err)
+	assert.Equal(t, &Breakpoint{Contract: addr, Offset: 7}, bp)
+	bp, err = ParseBreakpoint(addr.ToBase58() + ":7")
+	assert.Nil(t, err)
+	assert.Equal(t, addr, bp.Contract)
+	assert.True(t, bp.match(addr, 7))
+	assert.False(t, bp.match(common.ADDRESS_EMPTY, 7))
+
+	_, err = ParseBreakpoint("abc")
+	assert.NotNil(t, err)
+	_, err = ParseBreakpoint("abc:1")
+	assert.NotNil(t, err)
+}
+
+func TestFormatStackItem(t *testing.T) {
+	st := types.NewStruct([]types.StackItems{types.NewBoolean(true)})
+	arr := types.NewArray([]types.StackItems{
+		types.NewInteger(big.NewInt(-5)),
+		types.NewByteArray([]byte{0xab}),
+		st,
+	})
+	assert.Equal(t, "array[integer:-5, bytearray:ab, struct[boolean:true]]", FormatStackItem(arr))
+
+	m := types.NewMap()
+	m.Add(types.NewByteArray([]byte("b")), types.NewInteger(big.NewInt(2)))
+	m.Add(types.NewByteArray([]byte("a")), types.NewInteger(big.NewInt(1)))
+	assert.Equal(t, "map{bytearray:61:integer:1, bytearray:62:integer:2}", FormatStackItem(m))
+
+	//circular reference
+	arr.Add(arr)
+	assert.True(t, strings.Contains(FormatStackItem(arr), "..."))
+}
+
+//execute runs code of contract the same way as NeoVmService.Invoke drives the engine
+func execute(engine *vm.ExecutionEngine, contract common.Address, code []byte) error {
+	engine.EnterContract(contract)
+	engine.PushContext(vm.NewExecutionContext(engine, code))
+	for len(engine.Contexts) != 0 && engine.Context.GetInstructionPointer() < len(engine.Context.Code) {
+		if err := engine.ExecuteCode(); err != nil {
+			return err
+		}
+		offset := engine.Context.GetInstructionPointer() - 1
+		if err := engine.ValidateOp(); err != nil {
+			return err
+		}
+		if err := engine.BeforeOp(offset); err != nil {
+			return err
+		}
+		if err := engine.StepInto(); err != nil {
+			return err
+		}
+		if err := engine.AfterOp(offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDebugger(t *testing.T) {
+	code := []byte{byte(vm.PUSH1), byte(vm.PUSH2), byte(vm.ADD), byte(vm.PUSH5), byte(vm.RET)}
+	//contract upgraded in place, whose address is not hash of the code
+	contract := common.Address{0xcc}
+	out := new(bytes.Buffer)
+	dbg := NewDebugger(strings.NewReader("b "+contract.ToHexString()+":3\nc\nst\nq\n"), out)
+	engine := vm.NewExecutionEngine()
+	engine.Hook = dbg
+	err := execute(engine, contract, code)
+	assert.Equal(t, ErrQuit, err)
+	output := out.String()
+	assert.True(t, strings.Contains(output, "Enter contract "+contract.ToHexString()))
+	assert.True(t, strings.Contains(output, "0000: PUSH1"))
+	assert.True(t, strings.Contains(output, "Breakpoint "+contract.ToHexString()+":3 hit"))
+	assert.True(t, strings.Contains(output, "0003: PUSH5"))
+	assert.True(t, strings.Contains(output, "0: integer:3"))
+	assert.False(t, strings.Contains(output, "0001: PUSH2"))
+
+	//runs to the end without input
+	out.Reset()
+	dbg = NewDebugger(strings.NewReader(""), out)
+	engine = vm.NewExecutionEngine()
+	engine.Hook = dbg
+	assert.Nil(t, execute(engine, contract, code))
+	assert.Equal(t, 0, dbg.depth)
+	assert.Equal(t, 2, engine.EvaluationStack.Count())
+}
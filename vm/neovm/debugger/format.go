@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2019 The onyxchain Authors
+ * This file is part of The onyxchain library.
+ *
+ * The onyxchain is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The onyxchain is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The onyxchain.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package debugger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	vm "github.com/OnyxPay/OnyxChain-legacy/vm/neovm"
+	"github.com/OnyxPay/OnyxChain-legacy/vm/neovm/types"
+)
+
+//max nested level of array, struct and map to format, which also breaks circular reference
+const MAX_FORMAT_DEPTH = 8
+
+//FormatStackItem returns readable string of stack item
+func FormatStackItem(item types.StackItems) string {
+	return formatStackItem(item, 0)
+}
+
+func formatStackItem(item types.StackItems, depth int) string {
+	if item == nil {
+		return "<nil>"
+	}
+	if depth > MAX_FORMAT_DEPTH {
+		return "..."
+	}
+	switch v := item.(type) {
+	case *types.ByteArray:
+		data, _ := v.GetByteArray()
+		return "bytearray:" + hex.EncodeToString(data)
+	case *types.Integer:
+		value, _ := v.GetBigInteger()
+		return "integer:" + value.String()
+	case *types.Boolean:
+		value, _ := v.GetBoolean()
+		return fmt.Sprintf("boolean:%v", value)
+	case *types.Struct:
+		items, _ := v.GetStruct()
+		return "struct" + formatItems(items, depth)
+	case *types.Array:
+		items, _ := v.GetArray()
+		return "array" + formatItems(items, depth)
+	case *types.Map:
+		m, _ := v.GetMap()
+		entries := make([]string, 0, len(m))
+		for key, value := range m {
+			entries = append(entries, formatStackItem(key, depth+1)+":"+formatStackItem(value, depth+1))
+		}
+		sort.Strings(entries)
+		return "map{" + strings.Join(entries, ", ") + "}"
+	case *types.Interop:
+		value, _ := v.GetInterface()
+		return fmt.Sprintf("interop:%T", value)
+	default:
+		return fmt.Sprintf("%T", item)
+	}
+}
+
+func formatItems(items []types.StackItems, depth int) string {
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		strs = append(strs, formatStackItem(item, depth+1))
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
+
+//FormatStack returns readable lines of stack, from top to bottom
+func FormatStack(stack *vm.RandomAccessStack) []string {
+	count := stack.Count()
+	lines := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		lines = append(lines, fmt.Sprintf("%d: %s", i, FormatStackItem(stack.Peek(i))))
+	}
+	return lines
+}
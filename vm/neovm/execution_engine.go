@@ -39,6 +39,7 @@ type ExecutionEngine struct {
 	Context         *ExecutionContext
 	OpCode          OpCode
 	OpExec          OpExec
+	Hook            DebugHook
 }
 
 func (this *ExecutionEngine) CurrentContext() *ExecutionContext {
@@ -46,17 +47,25 @@ func (this *ExecutionEngine) CurrentContext() *ExecutionContext {
 }
 
 func (this *ExecutionEngine) PopContext() {
+	var context *ExecutionContext
 	if len(this.Contexts) != 0 {
+		context = this.Contexts[len(this.Contexts)-1]
 		this.Contexts = this.Contexts[:len(this.Contexts)-1]
 	}
 	if len(this.Contexts) != 0 {
 		this.Context = this.CurrentContext()
 	}
+	if this.Hook != nil && context != nil {
+		this.Hook.PopContext(this, context)
+	}
 }
 
 func (this *ExecutionEngine) PushContext(context *ExecutionContext) {
 	this.Contexts = append(this.Contexts, context)
 	this.Context = this.CurrentContext()
+	if this.Hook != nil {
+		this.Hook.PushContext(this, context)
+	}
 }
 
 func (this *ExecutionEngine) Execute() error {